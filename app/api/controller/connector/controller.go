@@ -15,8 +15,12 @@
 package connector
 
 import (
+	"encoding/json"
+
+	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth/authz"
 	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/check"
 )
 
@@ -25,6 +29,7 @@ type Controller struct {
 	connectorStore store.ConnectorStore
 	authorizer     authz.Authorizer
 	spaceStore     store.SpaceStore
+	repoStore      store.RepoStore
 }
 
 func NewController(
@@ -32,11 +37,31 @@ func NewController(
 	authorizer authz.Authorizer,
 	connectorStore store.ConnectorStore,
 	spaceStore store.SpaceStore,
+	repoStore store.RepoStore,
 ) *Controller {
 	return &Controller{
 		uidCheck:       uidCheck,
 		connectorStore: connectorStore,
 		authorizer:     authorizer,
 		spaceStore:     spaceStore,
+		repoStore:      repoStore,
 	}
 }
+
+// checkData verifies the data of connectors of a known type.
+func checkData(connectorType string, data string) error {
+	if connectorType != types.ConnectorTypeDockerRegistry {
+		return nil
+	}
+
+	registry := types.DockerRegistryConnectorData{}
+	if err := json.Unmarshal([]byte(data), &registry); err != nil {
+		return usererror.BadRequest("Docker registry connector data has to be a valid JSON object.")
+	}
+
+	if registry.URL == "" || registry.Username == "" || registry.PasswordSecret == "" {
+		return usererror.BadRequest("Docker registry connector requires url, username and password_secret.")
+	}
+
+	return nil
+}
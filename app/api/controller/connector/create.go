@@ -33,11 +33,16 @@ var (
 	// errConnectorRequiresParent if the user tries to create a connector without a parent space.
 	errConnectorRequiresParent = usererror.BadRequest(
 		"Parent space required - standalone connector are not supported.")
+
+	// errConnectorRepoNotInParent if the user tries to create a repo connector outside of the space of the repo.
+	errConnectorRepoNotInParent = usererror.BadRequest(
+		"The repository of the connector has to be in the parent space of the connector.")
 )
 
 type CreateInput struct {
 	Description string `json:"description"`
 	SpaceRef    string `json:"space_ref"` // Ref of the parent space
+	RepoRef     string `json:"repo_ref"`  // Optional ref of the repo the connector is restricted to
 	UID         string `json:"uid"`
 	Type        string `json:"type"`
 	Data        string `json:"data"`
@@ -61,12 +66,27 @@ func (c *Controller) Create(
 		return nil, err
 	}
 
+	var repoID *int64
+	if in.RepoRef != "" {
+		repo, err := c.repoStore.FindByRef(ctx, in.RepoRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find repo by ref: %w", err)
+		}
+
+		if repo.ParentID != parentSpace.ID {
+			return nil, errConnectorRepoNotInParent
+		}
+
+		repoID = &repo.ID
+	}
+
 	now := time.Now().UnixMilli()
 	connector := &types.Connector{
 		Description: in.Description,
 		Data:        in.Data,
 		Type:        in.Type,
 		SpaceID:     parentSpace.ID,
+		RepoID:      repoID,
 		UID:         in.UID,
 		Created:     now,
 		Updated:     now,
@@ -91,6 +111,10 @@ func (c *Controller) sanitizeCreateInput(in *CreateInput) error {
 		return err
 	}
 
+	if err := checkData(in.Type, in.Data); err != nil {
+		return err
+	}
+
 	in.Description = strings.TrimSpace(in.Description)
 	return check.Description(in.Description)
 }
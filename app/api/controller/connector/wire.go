@@ -32,6 +32,7 @@ func ProvideController(
 	connectorStore store.ConnectorStore,
 	authorizer authz.Authorizer,
 	spaceStore store.SpaceStore,
+	repoStore store.RepoStore,
 ) *Controller {
-	return NewController(uidCheck, authorizer, connectorStore, spaceStore)
+	return NewController(uidCheck, authorizer, connectorStore, spaceStore, repoStore)
 }
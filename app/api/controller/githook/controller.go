@@ -23,7 +23,9 @@ import (
 	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/app/auth/authz"
 	eventsgit "github.com/harness/gitness/app/events/git"
+	"github.com/harness/gitness/app/services/housekeeping"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/signing"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
 	"github.com/harness/gitness/git"
@@ -64,6 +66,8 @@ type Controller struct {
 	urlProvider       url.Provider
 	protectionManager *protection.Manager
 	resourceLimiter   limiter.ResourceLimiter
+	signatureVerifier *signing.Verifier
+	housekeeping      *housekeeping.Service
 }
 
 func NewController(
@@ -76,6 +80,8 @@ func NewController(
 	urlProvider url.Provider,
 	protectionManager *protection.Manager,
 	limiter limiter.ResourceLimiter,
+	signatureVerifier *signing.Verifier,
+	housekeeping *housekeeping.Service,
 ) *Controller {
 	return &Controller{
 		authorizer:        authorizer,
@@ -87,6 +93,8 @@ func NewController(
 		urlProvider:       urlProvider,
 		protectionManager: protectionManager,
 		resourceLimiter:   limiter,
+		signatureVerifier: signatureVerifier,
+		housekeeping:      housekeeping,
 	}
 }
 
@@ -24,11 +24,13 @@ import (
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/git/hook"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
 	"github.com/gotidy/ptr"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/exp/slices"
 )
 
@@ -53,6 +55,12 @@ func (c *Controller) PreReceive(
 			limiter.ErrMaxRepoSizeReached)
 	}
 
+	// packing the references of the repository competes with the reference updates of the push.
+	// The push proceeds regardless, waiting is best effort and mustn't depend on the lock backend.
+	if err := c.housekeeping.WaitForRefsMaintenance(ctx, repo); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to wait for references maintenance, continue with push")
+	}
+
 	refUpdates := groupRefsByAction(in.RefUpdates)
 
 	if slices.Contains(refUpdates.branches.deleted, repo.DefaultBranch) {
@@ -61,12 +69,7 @@ func (c *Controller) PreReceive(
 		return output, nil
 	}
 
-	if in.Internal {
-		// It's an internal call, so no need to verify protection rules.
-		return output, nil
-	}
-
-	if c.blockPullReqRefUpdate(refUpdates) {
+	if !in.Internal && c.blockPullReqRefUpdate(refUpdates) {
 		output.Error = ptr.String(usererror.ErrPullReqRefsCantBeModified.Error())
 		return output, nil
 	}
@@ -82,7 +85,11 @@ func (c *Controller) PreReceive(
 		Metadata:  nil,
 	}
 
-	err = c.checkProtectionRules(ctx, dummySession, repo, refUpdates, &output)
+	unverifiedCommits := c.unverifiedCommitsFunc(repo, in.RefUpdates, in.Environment)
+
+	// For internal calls the operations verified the protection rules already, but only the pushed commits
+	// reveal whether all commits added to a branch are signed (e.g. the source commits of a merged pull request).
+	err = c.checkProtectionRules(ctx, dummySession, repo, refUpdates, in.Internal, unverifiedCommits, &output)
 	if err != nil {
 		return hook.Output{}, fmt.Errorf("failed to check protection rules: %w", err)
 	}
@@ -105,6 +112,8 @@ func (c *Controller) checkProtectionRules(
 	session *auth.Session,
 	repo *types.Repository,
 	refUpdates changedRefs,
+	internal bool,
+	unverifiedCommits func(ctx context.Context, branch string) ([]string, error),
 	output *hook.Output,
 ) error {
 	isRepoOwner, err := apiauth.IsRepoOwner(ctx, c.authorizer, session, repo)
@@ -133,6 +142,9 @@ func (c *Controller) checkProtectionRules(
 			RefAction:   refAction,
 			RefType:     refType,
 			RefNames:    names,
+			Internal:    internal,
+
+			UnverifiedCommits: unverifiedCommits,
 		})
 		if err != nil {
 			errCheckAction = fmt.Errorf("failed to verify protection rules for git push: %w", err)
@@ -167,6 +179,55 @@ func (c *Controller) checkProtectionRules(
 	return nil
 }
 
+// unverifiedCommitsFunc returns a function that returns the pushed commits of a branch
+// that don't have a verified signature.
+func (c *Controller) unverifiedCommitsFunc(
+	repo *types.Repository,
+	refUpdates []hook.ReferenceUpdate,
+	env hook.Environment,
+) func(ctx context.Context, branch string) ([]string, error) {
+	branchUpdates := make(map[string]hook.ReferenceUpdate)
+	for _, refUpdate := range refUpdates {
+		if strings.HasPrefix(refUpdate.Ref, gitReferenceNamePrefixBranch) {
+			branchUpdates[refUpdate.Ref[len(gitReferenceNamePrefixBranch):]] = refUpdate
+		}
+	}
+
+	verification := c.signatureVerifier.NewSession()
+
+	return func(ctx context.Context, branch string) ([]string, error) {
+		refUpdate, ok := branchUpdates[branch]
+		if !ok || refUpdate.New == types.NilSHA {
+			return nil, nil
+		}
+
+		// for updates all commits added to the branch are checked, even if other branches contain them already.
+		out, err := c.git.ListPushedCommits(ctx, &git.ListPushedCommitsParams{
+			ReadParams:          git.CreateReadParams(repo),
+			OldSHA:              refUpdate.Old,
+			NewSHA:              refUpdate.New,
+			AlternateObjectDirs: env.AlternateObjectDirs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pushed commits: %w", err)
+		}
+
+		var unverified []string
+		for i := range out.Commits {
+			result, err := verification.VerifyCommit(ctx, &out.Commits[i])
+			if err != nil {
+				return nil, err
+			}
+
+			if !result.Verified {
+				unverified = append(unverified, out.Commits[i].SHA)
+			}
+		}
+
+		return unverified, nil
+	}
+}
+
 type changes struct {
 	created []string
 	deleted []string
@@ -0,0 +1,281 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package githook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/limiter"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/housekeeping"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/signing"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/git/adapter"
+	gitenum "github.com/harness/gitness/git/enum"
+	"github.com/harness/gitness/git/hook"
+	"github.com/harness/gitness/git/storage"
+	gittypes "github.com/harness/gitness/git/types"
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/lock"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+type repoStoreMock struct {
+	store.RepoStore
+	repo *types.Repository
+}
+
+func (s repoStoreMock) Find(context.Context, int64) (*types.Repository, error) {
+	return s.repo, nil
+}
+
+type principalStoreMock struct {
+	store.PrincipalStore
+	principals map[int64]*types.Principal
+}
+
+func (s principalStoreMock) Find(_ context.Context, id int64) (*types.Principal, error) {
+	p, ok := s.principals[id]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	return p, nil
+}
+
+func (s principalStoreMock) FindByUID(_ context.Context, uid string) (*types.Principal, error) {
+	for _, p := range s.principals {
+		if p.UID == uid {
+			return p, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+func (s principalStoreMock) FindByEmail(context.Context, string) (*types.Principal, error) {
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+type ruleStoreMock struct {
+	store.RuleStore
+	rules []types.RuleInfoInternal
+}
+
+func (s ruleStoreMock) ListAllRepoRules(context.Context, int64) ([]types.RuleInfoInternal, error) {
+	return s.rules, nil
+}
+
+// mutexManagerMock simulates an unavailable lock backend.
+type mutexManagerMock struct{}
+
+func (mutexManagerMock) NewMutex(string, ...lock.Option) (lock.Mutex, error) {
+	return nil, errors.New("lock backend unavailable")
+}
+
+// authorizerMock denies everything, so no principal is a repo owner.
+type authorizerMock struct{}
+
+func (authorizerMock) Check(context.Context, *auth.Session, *types.Scope, *types.Resource,
+	enum.Permission) (bool, error) {
+	return false, nil
+}
+
+func (authorizerMock) CheckAll(context.Context, *auth.Session, ...types.PermissionCheck) (bool, error) {
+	return false, nil
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	return strings.TrimSpace(string(out))
+}
+
+func TestController_UnverifiedCommits_FastForwardToPushedBranch(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	repo := &types.Repository{GitUID: "abcdefghij"}
+
+	gitAdapter, err := adapter.New(gittypes.Config{}, adapter.NewInMemoryLastCommitCache(time.Minute), nil)
+	require.NoError(t, err)
+	gitService, err := git.New(gittypes.Config{Root: root}, gitAdapter, storage.NewLocalStore())
+	require.NoError(t, err)
+
+	// the local clone of the user.
+	work := filepath.Join(root, "work")
+	runGit(t, root, "init", "-b", "main", work)
+	runGit(t, work, "commit", "--allow-empty", "-m", "base")
+	base := runGit(t, work, "rev-parse", "HEAD")
+	runGit(t, root, "clone", "--bare", work, filepath.Join(root, "repos", "ab", "cd", "efghij.git"))
+
+	// the unsigned commit is pushed to an unprotected branch first ...
+	runGit(t, work, "checkout", "-b", "feature")
+	runGit(t, work, "commit", "--allow-empty", "-m", "unsigned")
+	unsigned := runGit(t, work, "rev-parse", "HEAD")
+	runGit(t, work, "push", filepath.Join(root, "repos", "ab", "cd", "efghij.git"), "feature")
+
+	// ... and then fast-forwarded to the protected branch.
+	c := &Controller{
+		git:               gitService,
+		signatureVerifier: signing.NewVerifier(gitService, nil, nil, "system"),
+	}
+	unverifiedCommits := c.unverifiedCommitsFunc(repo, []hook.ReferenceUpdate{
+		{Ref: "refs/heads/main", Old: base, New: unsigned},
+		{Ref: "refs/heads/copy", Old: types.NilSHA, New: unsigned},
+		{Ref: "refs/heads/feature", Old: unsigned, New: types.NilSHA},
+	}, hook.Environment{})
+
+	unverified, err := unverifiedCommits(ctx, "main")
+	require.NoError(t, err)
+	require.Equal(t, []string{unsigned}, unverified)
+
+	// new branches only contain commits that are part of the repository already.
+	unverified, err = unverifiedCommits(ctx, "copy")
+	require.NoError(t, err)
+	require.Empty(t, unverified)
+
+	unverified, err = unverifiedCommits(ctx, "feature")
+	require.NoError(t, err)
+	require.Empty(t, unverified)
+
+	unverified, err = unverifiedCommits(ctx, "unknown")
+	require.NoError(t, err)
+	require.Empty(t, unverified)
+}
+
+func TestController_PreReceive_InternalRequiresSignedCommits(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	repo := &types.Repository{ID: 1, GitUID: "abcdefghij", Path: "space/repo", DefaultBranch: "main"}
+	repoPath := filepath.Join(root, "repos", "ab", "cd", "efghij.git")
+
+	// commits created by the server are signed with the instance key.
+	keyPath := filepath.Join(root, "signing.key")
+	out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", keyPath).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	gitAdapter, err := adapter.New(gittypes.Config{
+		SigningKey: gittypes.SigningKeyConfig{Path: keyPath},
+	}, adapter.NewInMemoryLastCommitCache(time.Minute), nil)
+	require.NoError(t, err)
+	gitService, err := git.New(gittypes.Config{Root: root, TmpDir: t.TempDir()}, gitAdapter, storage.NewLocalStore())
+	require.NoError(t, err)
+
+	work := filepath.Join(root, "work")
+	runGit(t, root, "init", "-b", "main", work)
+	runGit(t, work, "commit", "--allow-empty", "-m", "base")
+	runGit(t, root, "clone", "--bare", work, repoPath)
+	runGit(t, work, "checkout", "-b", "feature")
+	runGit(t, work, "commit", "--allow-empty", "-m", "unsigned")
+	runGit(t, work, "push", repoPath, "feature")
+
+	manager := protection.NewManager(ruleStoreMock{rules: []types.RuleInfoInternal{{
+		RuleInfo:   types.RuleInfo{ID: 1, UID: "signed", Type: protection.TypeBranch, State: enum.RuleStateActive},
+		Pattern:    json.RawMessage(`{"include":["main"]}`),
+		Definition: json.RawMessage(`{"lifecycle":{"update_forbidden":true,"require_signed_commits":true}}`),
+	}}})
+	require.NoError(t, manager.Register(protection.TypeBranch, func() protection.Definition {
+		return &protection.Branch{}
+	}))
+
+	principals := principalStoreMock{principals: map[int64]*types.Principal{
+		1: {ID: 1, UID: "user", Email: "user@test.com", Type: enum.PrincipalTypeUser},
+		2: {ID: 2, UID: "system", Email: "system@test.com", Type: enum.PrincipalTypeService},
+	}}
+	// pushes aren't rejected if the lock backend fails.
+	housekeepingService, err := housekeeping.NewService(ctx, housekeeping.Config{
+		EventReaderName: "gitness",
+		Concurrency:     1,
+		NumWorkers:      1,
+		RepoLimit:       1,
+		MinPushes:       1,
+		RepoTimeout:     time.Minute,
+		PruneExpiry:     time.Hour,
+	}, nil, gitService, nil, nil, mutexManagerMock{}, nil, job.NewExecutor(nil, nil))
+	require.NoError(t, err)
+
+	c := &Controller{
+		authorizer:        authorizerMock{},
+		principalStore:    principals,
+		repoStore:         repoStoreMock{repo: repo},
+		git:               gitService,
+		protectionManager: manager,
+		resourceLimiter:   limiter.Unlimited{},
+		signatureVerifier: signing.NewVerifier(gitService, principals, nil, "system"),
+		housekeeping:      housekeepingService,
+	}
+
+	writeParams := git.WriteParams{
+		RepoUID: repo.GitUID,
+		Actor:   git.Identity{Name: "user", Email: "user@test.com"},
+	}
+	preReceive := func(oldSHA, newSHA string) hook.Output {
+		output, err := c.PreReceive(ctx, nil, types.GithookPreReceiveInput{
+			GithookInputBase: types.GithookInputBase{RepoID: repo.ID, PrincipalID: 1, Internal: true},
+			PreReceiveInput: hook.PreReceiveInput{
+				RefUpdates: []hook.ReferenceUpdate{{Ref: "refs/heads/main", Old: oldSHA, New: newSHA}},
+			},
+		})
+		require.NoError(t, err)
+		return output
+	}
+
+	// web commits are signed by the server, the forbidden update was verified by the operation itself.
+	commit, err := gitService.CommitFiles(ctx, &git.CommitFilesParams{
+		WriteParams: writeParams,
+		Title:       "web commit",
+		Branch:      "main",
+		Actions:     []git.CommitFileAction{{Action: git.CreateAction, Path: "file.txt", Payload: []byte("data")}},
+	})
+	require.NoError(t, err)
+	output := preReceive(runGit(t, repoPath, "rev-parse", commit.CommitID+"^"), commit.CommitID)
+	require.Nil(t, output.Error, output.Messages)
+
+	// merge commits are signed as well, but bring in the unsigned commits of the source branch.
+	merge, err := gitService.Merge(ctx, &git.MergeParams{
+		WriteParams: writeParams,
+		BaseBranch:  "main",
+		HeadBranch:  "feature",
+		Title:       "merge",
+		RefType:     gitenum.RefTypeBranch,
+		RefName:     "main",
+		Method:      gitenum.MergeMethodMerge,
+	})
+	require.NoError(t, err)
+	output = preReceive(merge.BaseSHA, merge.MergeSHA)
+	require.NotNil(t, output.Error)
+	require.Len(t, output.Messages, 1)
+	require.Contains(t, output.Messages[0], "requires signed commits")
+}
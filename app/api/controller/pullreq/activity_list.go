@@ -49,6 +49,21 @@ func (c *Controller) ActivityList(
 
 	list = removeDeletedComments(list)
 
+	reactions, err := c.reactionStore.Count(ctx, pr.ID, session.Principal.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pull request reactions: %w", err)
+	}
+
+	for _, act := range list {
+		if act.Deleted == nil {
+			act.Reactions = reactions[act.ID]
+		}
+	}
+
+	if err = c.backfillMentions(ctx, list); err != nil {
+		return nil, err
+	}
+
 	return list, nil
 }
 
@@ -0,0 +1,555 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/controller"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/bootstrap"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git"
+	gittypes "github.com/harness/gitness/git/types"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxSuggestionFileSize is the max size of a file that can be modified by applying suggestions.
+	maxSuggestionFileSize = 10 * 1024 * 1024 // 10 MB
+
+	defaultApplySuggestionsTitle = "Apply suggestions from code review"
+)
+
+// SuggestionReference identifies a single suggestion of a code comment.
+type SuggestionReference struct {
+	CommentID int64  `json:"comment_id"`
+	CheckSum  string `json:"check_sum"`
+}
+
+type CommentApplySuggestionsInput struct {
+	Suggestions []SuggestionReference `json:"suggestions"`
+
+	Title   string `json:"title"`
+	Message string `json:"message"`
+
+	DryRunRules bool `json:"dry_run_rules"`
+	BypassRules bool `json:"bypass_rules"`
+}
+
+func (in *CommentApplySuggestionsInput) sanitize() error {
+	if len(in.Suggestions) == 0 {
+		return usererror.BadRequest("No suggestions provided.")
+	}
+
+	commentIDs := make(map[int64]struct{}, len(in.Suggestions))
+	for _, s := range in.Suggestions {
+		if s.CommentID <= 0 {
+			return usererror.BadRequest("A valid comment ID must be provided for each suggestion.")
+		}
+
+		if s.CheckSum == "" {
+			return usererror.BadRequest("A check sum must be provided for each suggestion.")
+		}
+
+		if _, ok := commentIDs[s.CommentID]; ok {
+			return usererror.BadRequestf("Only one suggestion per comment can be applied (comment %d).", s.CommentID)
+		}
+
+		commentIDs[s.CommentID] = struct{}{}
+	}
+
+	in.Title = strings.TrimSpace(in.Title)
+	if in.Title == "" {
+		in.Title = defaultApplySuggestionsTitle
+	}
+
+	in.Message = strings.TrimSpace(in.Message)
+
+	return nil
+}
+
+type CommentApplySuggestionsOutput struct {
+	CommitID       string                 `json:"commit_id"`
+	DryRunRules    bool                   `json:"dry_run_rules,omitempty"`
+	RuleViolations []types.RuleViolations `json:"rule_violations,omitempty"`
+}
+
+// suggestionToApply holds a suggestion together with the code comment it belongs to.
+type suggestionToApply struct {
+	comment    *types.PullReqActivity
+	suggestion suggestion
+}
+
+// CommentApplySuggestions applies the provided code comment suggestions with a single commit
+// on the source branch of the pull request. The code comments get resolved afterwards.
+//
+//nolint:gocognit,gocyclo,cyclop
+func (c *Controller) CommentApplySuggestions(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	prNum int64,
+	in *CommentApplySuggestionsInput,
+) (CommentApplySuggestionsOutput, []types.RuleViolations, error) {
+	if err := in.sanitize(); err != nil {
+		return CommentApplySuggestionsOutput{}, nil, err
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	// the max time we give applying of suggestions to succeed
+	const timeout = 2 * time.Minute
+
+	unlock, err := c.lockPR(ctx, repo.GitUID, prNum, timeout)
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil, err
+	}
+	defer unlock()
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, prNum)
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil, fmt.Errorf("failed to find pull request by number: %w", err)
+	}
+
+	if pr.State != enum.PullReqStateOpen {
+		return CommentApplySuggestionsOutput{}, nil,
+			usererror.BadRequest("Suggestions can only be applied to open pull requests.")
+	}
+
+	sourceRepo := repo
+	if pr.SourceRepoID != pr.TargetRepoID {
+		sourceRepo, err = c.repoStore.Find(ctx, pr.SourceRepoID)
+		if err != nil {
+			return CommentApplySuggestionsOutput{}, nil, fmt.Errorf("failed to get source repository: %w", err)
+		}
+	}
+
+	requiredPermission := enum.PermissionRepoPush
+	if in.DryRunRules {
+		requiredPermission = enum.PermissionRepoView
+	}
+
+	if err = apiauth.CheckRepo(ctx, c.authorizer, session, sourceRepo, requiredPermission, false); err != nil {
+		return CommentApplySuggestionsOutput{}, nil, fmt.Errorf("access check failed: %w", err)
+	}
+
+	suggestions, err := c.getSuggestionsToApply(ctx, pr, in.Suggestions)
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil, err
+	}
+
+	isRepoOwner, err := apiauth.IsRepoOwner(ctx, c.authorizer, session, sourceRepo)
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil,
+			fmt.Errorf("failed to determine if user is repo owner: %w", err)
+	}
+
+	protectionRules, err := c.protectionManager.ForRepository(ctx, sourceRepo.ID)
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil,
+			fmt.Errorf("failed to fetch protection rules for the repository: %w", err)
+	}
+
+	violations, err := protectionRules.RefChangeVerify(ctx, protection.RefChangeVerifyInput{
+		Actor:       &session.Principal,
+		AllowBypass: in.BypassRules,
+		IsRepoOwner: isRepoOwner,
+		Repo:        sourceRepo,
+		RefAction:   protection.RefActionUpdate,
+		RefType:     protection.RefTypeBranch,
+		RefNames:    []string{pr.SourceBranch},
+	})
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil, fmt.Errorf("failed to verify protection rules: %w", err)
+	}
+
+	if in.DryRunRules {
+		return CommentApplySuggestionsOutput{
+			DryRunRules:    true,
+			RuleViolations: violations,
+		}, nil, nil
+	}
+
+	if protection.IsCritical(violations) {
+		return CommentApplySuggestionsOutput{}, violations, nil
+	}
+
+	actions, err := c.suggestionsToActions(ctx, sourceRepo, pr, suggestions)
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil, err
+	}
+
+	// Create internal write params. Note: This will skip the pre-commit protection rules check.
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, sourceRepo)
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil, fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	now := time.Now()
+	commit, err := c.git.CommitFiles(ctx, &git.CommitFilesParams{
+		WriteParams:   writeParams,
+		Title:         in.Title,
+		Message:       suggestionsCommitMessage(in.Message, session.Principal.ID, suggestions),
+		Branch:        pr.SourceBranch,
+		Actions:       actions,
+		Committer:     identityFromPrincipalInfo(*bootstrap.NewSystemServiceSession().Principal.ToPrincipalInfo()),
+		CommitterDate: &now,
+		Author:        identityFromPrincipalInfo(*session.Principal.ToPrincipalInfo()),
+		AuthorDate:    &now,
+	})
+	if err != nil {
+		return CommentApplySuggestionsOutput{}, nil, fmt.Errorf("failed to commit suggestions: %w", err)
+	}
+
+	err = c.resolveAppliedSuggestions(ctx, session, pr, suggestions, commit.CommitID)
+	if err != nil {
+		// non-critical error, the suggestions have already been committed
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to resolve code comments with applied suggestions")
+	}
+
+	if err = c.sseStreamer.Publish(ctx, repo.ParentID, enum.SSETypePullRequestUpdated, pr); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish PR changed event")
+	}
+
+	return CommentApplySuggestionsOutput{
+		CommitID:       commit.CommitID,
+		RuleViolations: violations,
+	}, nil, nil
+}
+
+// getSuggestionsToApply loads and verifies the code comments containing the referenced suggestions.
+// The function fails if a code comment no longer points at the latest source commit of the pull request,
+// as in that case the lines the suggestion was made for might have been moved or changed.
+func (c *Controller) getSuggestionsToApply(
+	ctx context.Context,
+	pr *types.PullReq,
+	refs []SuggestionReference,
+) ([]suggestionToApply, error) {
+	suggestions := make([]suggestionToApply, 0, len(refs))
+
+	for _, ref := range refs {
+		comment, err := c.activityStore.Find(ctx, ref.CommentID)
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return nil, usererror.BadRequestf("Code comment %d not found.", ref.CommentID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find code comment %d: %w", ref.CommentID, err)
+		}
+
+		if comment.Deleted != nil || comment.PullReqID != pr.ID || comment.RepoID != pr.TargetRepoID ||
+			comment.IsReply() || !comment.IsValidCodeComment() {
+			return nil, usererror.BadRequestf("Code comment %d not found.", ref.CommentID)
+		}
+
+		if comment.Metadata != nil && comment.Metadata.Suggestions != nil &&
+			comment.Metadata.Suggestions.AppliedCheckSum != "" {
+			return nil, usererror.BadRequestf("A suggestion of code comment %d has already been applied.",
+				ref.CommentID)
+		}
+
+		payload, err := comment.GetPayload()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get code comment %d payload: %w", ref.CommentID, err)
+		}
+
+		ccPayload, ok := payload.(*types.PullRequestActivityPayloadCodeComment)
+		if !ok || !ccPayload.LineStartNew || !ccPayload.LineEndNew {
+			return nil, usererror.BadRequestf(
+				"Suggestions can only be applied to code comments on new lines (code comment %d).", ref.CommentID)
+		}
+
+		if comment.CodeComment.Outdated || comment.CodeComment.SourceSHA != pr.SourceSHA {
+			return nil, usererror.BadRequestf(
+				"The lines of code comment %d have changed since the suggestion was made.", ref.CommentID)
+		}
+
+		var found *suggestion
+		for _, s := range parseSuggestions(comment.Text) {
+			if s.checkSum == ref.CheckSum {
+				s := s
+				found = &s
+				break
+			}
+		}
+
+		if found == nil {
+			return nil, usererror.BadRequestf("Suggestion not found in code comment %d.", ref.CommentID)
+		}
+
+		suggestions = append(suggestions, suggestionToApply{
+			comment:    comment,
+			suggestion: *found,
+		})
+	}
+
+	return suggestions, nil
+}
+
+// suggestionsToActions applies the suggestions to the file contents at the latest source commit
+// of the pull request and returns the resulting file update actions.
+func (c *Controller) suggestionsToActions(
+	ctx context.Context,
+	repo *types.Repository,
+	pr *types.PullReq,
+	suggestions []suggestionToApply,
+) ([]git.CommitFileAction, error) {
+	fileMap := make(map[string][]suggestionToApply)
+	for _, s := range suggestions {
+		path := s.comment.CodeComment.Path
+		fileMap[path] = append(fileMap[path], s)
+	}
+
+	paths := make([]string, 0, len(fileMap))
+	for path := range fileMap {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	readParams := git.CreateReadParams(repo)
+
+	actions := make([]git.CommitFileAction, 0, len(paths))
+	for _, path := range paths {
+		blobSHA, content, err := c.readSuggestionFile(ctx, readParams, pr.SourceSHA, path)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err = applySuggestionsToContent(content, fileMap[path])
+		if err != nil {
+			return nil, err
+		}
+
+		actions = append(actions, git.CommitFileAction{
+			Action:  git.UpdateAction,
+			Path:    path,
+			Payload: content,
+			SHA:     blobSHA,
+		})
+	}
+
+	return actions, nil
+}
+
+func (c *Controller) readSuggestionFile(
+	ctx context.Context,
+	readParams git.ReadParams,
+	sha string,
+	path string,
+) (string, []byte, error) {
+	node, err := c.git.GetTreeNode(ctx, &git.GetTreeNodeParams{
+		ReadParams: readParams,
+		GitREF:     sha,
+		Path:       path,
+	})
+	if gittypes.IsPathNotFoundError(err) {
+		return "", nil, usererror.BadRequestf("File %q no longer exists.", path)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get tree node of file %q: %w", path, err)
+	}
+
+	if node.Node.Type != git.TreeNodeTypeBlob {
+		return "", nil, usererror.BadRequestf("Path %q is not a file.", path)
+	}
+
+	blob, err := c.git.GetBlob(ctx, &git.GetBlobParams{
+		ReadParams: readParams,
+		SHA:        node.Node.SHA,
+		SizeLimit:  maxSuggestionFileSize,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get content of file %q: %w", path, err)
+	}
+
+	defer func() {
+		if errClose := blob.Content.Close(); errClose != nil {
+			log.Ctx(ctx).Warn().Err(errClose).Msg("failed to close blob content reader")
+		}
+	}()
+
+	if blob.Size > blob.ContentSize {
+		return "", nil, usererror.BadRequestf("File %q is too large to apply suggestions.", path)
+	}
+
+	content, err := io.ReadAll(blob.Content)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read content of file %q: %w", path, err)
+	}
+
+	return node.Node.SHA, content, nil
+}
+
+// applySuggestionsToContent replaces the lines the code comments are pointing at with the suggested code.
+func applySuggestionsToContent(content []byte, suggestions []suggestionToApply) ([]byte, error) {
+	text := string(content)
+
+	eol := "\n"
+	if strings.Contains(text, "\r\n") {
+		eol = "\r\n"
+	}
+
+	hasTrailingEOL := strings.HasSuffix(text, eol)
+	text = strings.TrimSuffix(text, eol)
+
+	var lines []string
+	if text != "" || hasTrailingEOL {
+		lines = strings.Split(text, eol)
+	}
+
+	// apply the suggestions from the bottom of the file to the top so that line numbers remain valid.
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].comment.CodeComment.LineNew > suggestions[j].comment.CodeComment.LineNew
+	})
+
+	prevStart := len(lines) + 1
+	for _, s := range suggestions {
+		cc := s.comment.CodeComment
+
+		start := cc.LineNew
+		end := cc.LineNew + cc.SpanNew - 1
+		if cc.SpanNew <= 0 {
+			end = start
+		}
+
+		if start <= 0 || end > len(lines) {
+			return nil, usererror.BadRequestf(
+				"The lines of code comment %d have changed since the suggestion was made.", s.comment.ID)
+		}
+
+		if end >= prevStart {
+			return nil, usererror.BadRequestf(
+				"Suggestions for file %q are overlapping (code comment %d).", cc.Path, s.comment.ID)
+		}
+
+		var code []string
+		if s.suggestion.code != "" {
+			code = strings.Split(s.suggestion.code, "\n")
+		}
+
+		updated := make([]string, 0, len(lines)-(end-start+1)+len(code))
+		updated = append(updated, lines[:start-1]...)
+		updated = append(updated, code...)
+		updated = append(updated, lines[end:]...)
+		lines = updated
+
+		prevStart = start
+	}
+
+	result := strings.Join(lines, eol)
+	if hasTrailingEOL && len(lines) > 0 {
+		result += eol
+	}
+
+	return []byte(result), nil
+}
+
+// suggestionsCommitMessage returns the commit message with co-author trailers for
+// the authors of all applied suggestions (except for the committing user).
+func suggestionsCommitMessage(message string, principalID int64, suggestions []suggestionToApply) string {
+	coAuthors := make([]string, 0, len(suggestions))
+	seen := map[int64]struct{}{principalID: {}}
+	for _, s := range suggestions {
+		author := s.comment.Author
+		if _, ok := seen[author.ID]; ok {
+			continue
+		}
+		seen[author.ID] = struct{}{}
+
+		coAuthors = append(coAuthors, fmt.Sprintf("Co-authored-by: %s <%s>", author.DisplayName, author.Email))
+	}
+
+	if len(coAuthors) == 0 {
+		return message
+	}
+
+	trailers := strings.Join(coAuthors, "\n")
+	if message == "" {
+		return trailers
+	}
+
+	return message + "\n\n" + trailers
+}
+
+// resolveAppliedSuggestions marks the code comments as resolved and
+// stores the applied suggestion in the code comment metadata.
+func (c *Controller) resolveAppliedSuggestions(
+	ctx context.Context,
+	session *auth.Session,
+	pr *types.PullReq,
+	suggestions []suggestionToApply,
+	commitSHA string,
+) error {
+	return controller.TxOptLock(ctx, c.tx, func(ctx context.Context) error {
+		now := time.Now().UnixMilli()
+
+		for _, s := range suggestions {
+			checkSum := s.suggestion.checkSum
+			_, err := c.activityStore.UpdateOptLock(ctx, s.comment, func(act *types.PullReqActivity) error {
+				if act.Resolved == nil {
+					act.Resolved = &now
+					act.ResolvedBy = &session.Principal.ID
+				}
+
+				if act.Metadata == nil {
+					act.Metadata = &types.PullReqActivityMetadata{}
+				}
+				if act.Metadata.Suggestions == nil {
+					act.Metadata.Suggestions = &types.PullReqActivitySuggestionsMetadata{}
+				}
+
+				act.Metadata.Suggestions.AppliedCheckSum = checkSum
+				act.Metadata.Suggestions.AppliedCommitSHA = commitSHA
+
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update code comment %d: %w", s.comment.ID, err)
+			}
+		}
+
+		unresolvedCount, err := c.activityStore.CountUnresolved(ctx, pr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count unresolved comments: %w", err)
+		}
+
+		prUpd, err := c.pullreqStore.UpdateOptLock(ctx, pr, func(pr *types.PullReq) error {
+			pr.UnresolvedCount = unresolvedCount
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update pull request's unresolved comment count: %w", err)
+		}
+
+		*pr = *prUpd
+
+		return nil
+	})
+}
@@ -109,6 +109,11 @@ func (c *Controller) CommentCreate(
 		return nil, fmt.Errorf("failed to find pull request by number: %w", err)
 	}
 
+	mentions, err := c.getMentions(ctx, repo, in.Text)
+	if err != nil {
+		return nil, err
+	}
+
 	var cut git.DiffCutOutput
 	if in.IsCodeComment() {
 		// fetch code snippet from git for code comments
@@ -130,6 +135,7 @@ func (c *Controller) CommentCreate(
 		}
 
 		act = getCommentActivity(session, pr, in)
+		setMentionsMetadata(act, mentions)
 
 		// In the switch the pull request activity (the code comment)
 		// is written to the DB (as code comment, a reply, or ordinary comment).
@@ -142,6 +148,7 @@ func (c *Controller) CommentCreate(
 				LineStartNew: in.LineStartNew,
 				LineEndNew:   in.LineEndNew,
 			})
+			setSuggestionsMetadata(act)
 
 			err = c.writeActivity(ctx, pr, act)
 
@@ -208,6 +215,8 @@ func (c *Controller) CommentCreate(
 		})
 	}
 
+	c.reportMentions(ctx, pr, &session.Principal, act.ID, mentions, nil)
+
 	return act, nil
 }
 
@@ -71,19 +71,32 @@ func (c *Controller) CommentUpdate(
 		return act, nil
 	}
 
+	mentions, err := c.getMentions(ctx, repo, in.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	oldMentionIDs := getMentionsMetadata(act)
+
 	act, err = c.activityStore.UpdateOptLock(ctx, act, func(act *types.PullReqActivity) error {
 		now := time.Now().UnixMilli()
 		act.Edited = now
 		act.Text = in.Text
+		setSuggestionsMetadata(act)
+		setMentionsMetadata(act, mentions)
 		return nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to update comment: %w", err)
 	}
 
+	act.Mentions = mentions
+
 	if err = c.sseStreamer.Publish(ctx, repo.ParentID, enum.SSETypePullRequestUpdated, pr); err != nil {
 		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish PR changed event")
 	}
 
+	c.reportMentions(ctx, pr, &session.Principal, act.ID, mentions, oldMentionIDs)
+
 	return act, nil
 }
@@ -25,8 +25,10 @@ import (
 	pullreqevents "github.com/harness/gitness/app/events/pullreq"
 	"github.com/harness/gitness/app/services/codecomments"
 	"github.com/harness/gitness/app/services/codeowners"
+	"github.com/harness/gitness/app/services/label"
 	"github.com/harness/gitness/app/services/protection"
 	"github.com/harness/gitness/app/services/pullreq"
+	"github.com/harness/gitness/app/services/signing"
 	"github.com/harness/gitness/app/sse"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
@@ -50,9 +52,11 @@ type Controller struct {
 	reviewerStore       store.PullReqReviewerStore
 	repoStore           store.RepoStore
 	principalStore      store.PrincipalStore
+	principalInfoCache  store.PrincipalInfoCache
 	fileViewStore       store.PullReqFileViewStore
 	membershipStore     store.MembershipStore
 	checkStore          store.CheckStore
+	reactionStore       store.PullReqReactionStore
 	git                 git.Interface
 	eventReporter       *pullreqevents.Reporter
 	mtxManager          lock.MutexManager
@@ -61,6 +65,8 @@ type Controller struct {
 	protectionManager   *protection.Manager
 	sseStreamer         sse.Streamer
 	codeOwners          *codeowners.Service
+	labelService        *label.Service
+	signatureVerifier   *signing.Verifier
 }
 
 func NewController(
@@ -74,9 +80,11 @@ func NewController(
 	pullreqReviewerStore store.PullReqReviewerStore,
 	repoStore store.RepoStore,
 	principalStore store.PrincipalStore,
+	principalInfoCache store.PrincipalInfoCache,
 	fileViewStore store.PullReqFileViewStore,
 	membershipStore store.MembershipStore,
 	checkStore store.CheckStore,
+	reactionStore store.PullReqReactionStore,
 	git git.Interface,
 	eventReporter *pullreqevents.Reporter,
 	mtxManager lock.MutexManager,
@@ -85,6 +93,8 @@ func NewController(
 	protectionManager *protection.Manager,
 	sseStreamer sse.Streamer,
 	codeowners *codeowners.Service,
+	labelService *label.Service,
+	signatureVerifier *signing.Verifier,
 ) *Controller {
 	return &Controller{
 		tx:                  tx,
@@ -97,9 +107,11 @@ func NewController(
 		reviewerStore:       pullreqReviewerStore,
 		repoStore:           repoStore,
 		principalStore:      principalStore,
+		principalInfoCache:  principalInfoCache,
 		fileViewStore:       fileViewStore,
 		membershipStore:     membershipStore,
 		checkStore:          checkStore,
+		reactionStore:       reactionStore,
 		git:                 git,
 		codeCommentMigrator: codeCommentMigrator,
 		eventReporter:       eventReporter,
@@ -108,6 +120,8 @@ func NewController(
 		protectionManager:   protectionManager,
 		sseStreamer:         sseStreamer,
 		codeOwners:          codeowners,
+		labelService:        labelService,
+		signatureVerifier:   signatureVerifier,
 	}
 }
 
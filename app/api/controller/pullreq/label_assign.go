@@ -0,0 +1,136 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+type LabelAssignInput struct {
+	LabelID int64 `json:"label_id"`
+}
+
+func (in *LabelAssignInput) Validate() error {
+	if in.LabelID <= 0 {
+		return usererror.BadRequest("A valid label ID must be provided.")
+	}
+
+	return nil
+}
+
+// LabelAssign assigns a label to the pull request.
+func (c *Controller) LabelAssign(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	in *LabelAssignInput,
+) ([]*types.LabelInfo, error) {
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, pullreqNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pull request by number: %w", err)
+	}
+
+	label, err := c.labelService.FindAvailable(ctx, repo, in.LabelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find label: %w", err)
+	}
+
+	assigned, err := c.labelService.Assign(ctx, session.Principal.ID, pr, label)
+	if err != nil {
+		return nil, err
+	}
+
+	if assigned {
+		c.reportLabelChange(ctx, session, repo, pr, label, enum.PullReqLabelActivityTypeAssign)
+	}
+
+	return c.labelService.ListAssigned(ctx, pr)
+}
+
+// reportLabelChange writes the label change activity, reports the event
+// and publishes the updated pull request to the SSE stream.
+func (c *Controller) reportLabelChange(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	pr *types.PullReq,
+	label *types.Label,
+	activityType enum.PullReqLabelActivityType,
+) {
+	err := func() error {
+		var err error
+		if pr, err = c.pullreqStore.UpdateActivitySeq(ctx, pr); err != nil {
+			return fmt.Errorf("failed to increment pull request activity sequence: %w", err)
+		}
+
+		payload := &types.PullRequestActivityPayloadLabel{
+			Label: label.Name,
+			Color: label.Color,
+			Type:  activityType,
+		}
+		_, err = c.activityStore.CreateWithPayload(ctx, pr, session.Principal.ID, payload)
+		return err
+	}()
+	if err != nil {
+		// non-critical error
+		log.Ctx(ctx).Err(err).Msgf("failed to write pull request activity after label change")
+	}
+
+	switch activityType {
+	case enum.PullReqLabelActivityTypeAssign:
+		c.eventReporter.LabelAdded(ctx, &pullreqevents.LabelAddedPayload{
+			Base:             eventBase(pr, &session.Principal),
+			LabelID:          label.ID,
+			LabelName:        label.Name,
+			LabelDescription: label.Description,
+			LabelColor:       label.Color,
+		})
+	case enum.PullReqLabelActivityTypeUnassign:
+		c.eventReporter.LabelRemoved(ctx, &pullreqevents.LabelRemovedPayload{
+			Base:             eventBase(pr, &session.Principal),
+			LabelID:          label.ID,
+			LabelName:        label.Name,
+			LabelDescription: label.Description,
+			LabelColor:       label.Color,
+		})
+	}
+
+	if err = c.labelService.Backfill(ctx, pr); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to backfill labels of the pull request")
+	}
+
+	if err = c.sseStreamer.Publish(ctx, repo.ParentID, enum.SSETypePullRequestUpdated, pr); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish PR changed event")
+	}
+}
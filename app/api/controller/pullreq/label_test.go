@@ -0,0 +1,262 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"net/http"
+	"strings"
+	"testing"
+
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/events"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s pullreqStoreMock) UpdateActivitySeq(_ context.Context, pr *types.PullReq) (*types.PullReq, error) {
+	pr.ActivitySeq++
+	return pr, nil
+}
+
+type labelStoreMock struct {
+	store.LabelStore
+	labels map[int64]*types.Label
+}
+
+func (s *labelStoreMock) Find(_ context.Context, id int64) (*types.Label, error) {
+	l, ok := s.labels[id]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	return l, nil
+}
+
+type labelAssignStoreMock struct {
+	store.PullReqLabelAssignmentStore
+	labelStore *labelStoreMock
+	assigned   map[int64]*types.PullReqLabel
+}
+
+func (s *labelAssignStoreMock) Find(_ context.Context, _, labelID int64) (*types.PullReqLabel, error) {
+	assignment, ok := s.assigned[labelID]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	return assignment, nil
+}
+
+func (s *labelAssignStoreMock) Assign(_ context.Context, assignment *types.PullReqLabel) error {
+	s.assigned[assignment.LabelID] = assignment
+	return nil
+}
+
+func (s *labelAssignStoreMock) Unassign(_ context.Context, _, labelID int64) error {
+	delete(s.assigned, labelID)
+	return nil
+}
+
+func (s *labelAssignStoreMock) ListAssigned(_ context.Context, _ int64) ([]*types.LabelInfo, error) {
+	labels := []*types.LabelInfo{}
+	for labelID := range s.assigned {
+		labels = append(labels, s.labelStore.labels[labelID].ToInfo())
+	}
+	return labels, nil
+}
+
+func (s *labelAssignStoreMock) ListAssignedByPullReqIDs(
+	ctx context.Context,
+	pullreqIDs []int64,
+) (map[int64][]*types.LabelInfo, error) {
+	labels, _ := s.ListAssigned(ctx, pullreqIDs[0])
+	return map[int64][]*types.LabelInfo{pullreqIDs[0]: labels}, nil
+}
+
+type spaceStoreMock struct {
+	store.SpaceStore
+}
+
+func (spaceStoreMock) GetAncestorIDs(context.Context, int64) ([]int64, error) {
+	return []int64{1}, nil
+}
+
+type activityStoreMock struct {
+	store.PullReqActivityStore
+	payloads []types.PullReqActivityPayload
+}
+
+func (s *activityStoreMock) CreateWithPayload(
+	_ context.Context,
+	_ *types.PullReq,
+	_ int64,
+	payload types.PullReqActivityPayload,
+) (*types.PullReqActivity, error) {
+	s.payloads = append(s.payloads, payload)
+	return &types.PullReqActivity{}, nil
+}
+
+// streamProducerMock keeps the sent events per stream.
+type streamProducerMock struct {
+	sent map[string][][]byte
+}
+
+func (p *streamProducerMock) Send(_ context.Context, streamID string, payload map[string]interface{}) (string, error) {
+	for _, v := range payload {
+		p.sent[streamID] = append(p.sent[streamID], v.([]byte))
+	}
+	return "1", nil
+}
+
+// decodeSentEvents decodes the events that were sent to the stream with the provided suffix.
+func decodeSentEvents[T any](t *testing.T, p *streamProducerMock, streamSuffix string) []T {
+	t.Helper()
+
+	var payloads []T
+	for streamID, sent := range p.sent {
+		if !strings.HasSuffix(streamID, streamSuffix) {
+			continue
+		}
+		for _, raw := range sent {
+			event := events.Event[T]{}
+			require.NoError(t, gob.NewDecoder(bytes.NewReader(raw)).Decode(&event))
+			payloads = append(payloads, event.Payload)
+		}
+	}
+
+	return payloads
+}
+
+func setupLabelController(t *testing.T) (*Controller, *labelStoreMock, *activityStoreMock, *streamProducerMock) {
+	t.Helper()
+
+	spaceID, otherSpaceID, repoID, otherRepoID := int64(1), int64(9), int64(1), int64(9)
+	labelStore := &labelStoreMock{labels: map[int64]*types.Label{
+		1: {ID: 1, SpaceID: &spaceID, Name: "bug", Description: "Something isn't working", Color: enum.LabelColorRed},
+		2: {ID: 2, RepoID: &repoID, Name: "backend", Color: enum.LabelColorBlue},
+		3: {ID: 3, SpaceID: &otherSpaceID, Name: "other-space", Color: enum.LabelColorGray},
+		4: {ID: 4, RepoID: &otherRepoID, Name: "other-repo", Color: enum.LabelColorGray},
+	}}
+	assignStore := &labelAssignStoreMock{labelStore: labelStore, assigned: map[int64]*types.PullReqLabel{}}
+
+	producer := &streamProducerMock{sent: map[string][][]byte{}}
+	system, err := events.NewSystem(func(string, string) (events.StreamConsumer, error) {
+		return nil, nil
+	}, producer)
+	require.NoError(t, err)
+	eventReporter, err := pullreqevents.NewReporter(system)
+	require.NoError(t, err)
+
+	activityStore := &activityStoreMock{}
+
+	repo := &types.Repository{ID: repoID, ParentID: spaceID, Path: "space/repo"}
+	pr := &types.PullReq{ID: 1, Number: 1, SourceRepoID: repoID, TargetRepoID: repoID}
+
+	c := &Controller{
+		authorizer:    authorizerMock{},
+		repoStore:     repoStoreMock{repo: repo},
+		pullreqStore:  pullreqStoreMock{pr: pr},
+		activityStore: activityStore,
+		labelService:  label.NewService(labelStore, assignStore, spaceStoreMock{}),
+		eventReporter: eventReporter,
+		sseStreamer:   sseStreamerMock{},
+	}
+
+	return c, labelStore, activityStore, producer
+}
+
+func TestController_LabelAssign(t *testing.T) {
+	ctx := context.Background()
+	session := testSession()
+	c, labelStore, activityStore, producer := setupLabelController(t)
+
+	labels, err := c.LabelAssign(ctx, session, "space/repo", 1, &LabelAssignInput{LabelID: 1})
+	require.NoError(t, err)
+	require.Equal(t, []*types.LabelInfo{labelStore.labels[1].ToInfo()}, labels)
+
+	// assigning an already assigned label doesn't report the change again.
+	_, err = c.LabelAssign(ctx, session, "space/repo", 1, &LabelAssignInput{LabelID: 1})
+	require.NoError(t, err)
+
+	_, err = c.LabelAssign(ctx, session, "space/repo", 1, &LabelAssignInput{LabelID: 2})
+	require.NoError(t, err)
+
+	require.Equal(t, []types.PullReqActivityPayload{
+		&types.PullRequestActivityPayloadLabel{
+			Label: "bug", Color: enum.LabelColorRed, Type: enum.PullReqLabelActivityTypeAssign,
+		},
+		&types.PullRequestActivityPayloadLabel{
+			Label: "backend", Color: enum.LabelColorBlue, Type: enum.PullReqLabelActivityTypeAssign,
+		},
+	}, activityStore.payloads)
+
+	// the events contain the label as it was at the time of the change.
+	added := decodeSentEvents[*pullreqevents.LabelAddedPayload](t, producer, string(pullreqevents.LabelAddedEvent))
+	require.Len(t, added, 2)
+	require.Equal(t, int64(1), added[0].LabelID)
+	require.Equal(t, "bug", added[0].LabelName)
+	require.Equal(t, "Something isn't working", added[0].LabelDescription)
+	require.Equal(t, enum.LabelColorRed, added[0].LabelColor)
+	require.Equal(t, session.Principal.ID, added[0].PrincipalID)
+	require.Equal(t, "backend", added[1].LabelName)
+
+	// labels of other spaces and repositories aren't available.
+	for _, labelID := range []int64{3, 4, 5} {
+		_, err = c.LabelAssign(ctx, session, "space/repo", 1, &LabelAssignInput{LabelID: labelID})
+		requireUserError(t, err, http.StatusNotFound)
+	}
+
+	_, err = c.LabelAssign(ctx, session, "space/repo", 1, &LabelAssignInput{})
+	requireUserError(t, err, http.StatusBadRequest)
+}
+
+func TestController_LabelUnassign(t *testing.T) {
+	ctx := context.Background()
+	session := testSession()
+	c, labelStore, activityStore, producer := setupLabelController(t)
+
+	// removing a label that isn't assigned is a no-op.
+	labels, err := c.LabelUnassign(ctx, session, "space/repo", 1, 1)
+	require.NoError(t, err)
+	require.Empty(t, labels)
+	require.Empty(t, activityStore.payloads)
+
+	_, err = c.LabelAssign(ctx, session, "space/repo", 1, &LabelAssignInput{LabelID: 1})
+	require.NoError(t, err)
+
+	// the label is renamed before it's removed.
+	labelStore.labels[1].Name = "defect"
+
+	labels, err = c.LabelUnassign(ctx, session, "space/repo", 1, 1)
+	require.NoError(t, err)
+	require.Empty(t, labels)
+
+	require.Equal(t, &types.PullRequestActivityPayloadLabel{
+		Label: "defect", Color: enum.LabelColorRed, Type: enum.PullReqLabelActivityTypeUnassign,
+	}, activityStore.payloads[1])
+
+	removed := decodeSentEvents[*pullreqevents.LabelRemovedPayload](
+		t, producer, string(pullreqevents.LabelRemovedEvent))
+	require.Len(t, removed, 1)
+	require.Equal(t, int64(1), removed[0].LabelID)
+	require.Equal(t, "defect", removed[0].LabelName)
+	require.Equal(t, enum.LabelColorRed, removed[0].LabelColor)
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// LabelUnassign removes a label from the pull request.
+func (c *Controller) LabelUnassign(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	labelID int64,
+) ([]*types.LabelInfo, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoPush)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, pullreqNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pull request by number: %w", err)
+	}
+
+	label, err := c.labelService.FindAvailable(ctx, repo, labelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find label: %w", err)
+	}
+
+	unassigned, err := c.labelService.Unassign(ctx, pr, label.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if unassigned {
+		c.reportLabelChange(ctx, session, repo, pr, label, enum.PullReqLabelActivityTypeUnassign)
+	}
+
+	return c.labelService.ListAssigned(ctx, pr)
+}
@@ -0,0 +1,319 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/auth"
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+// parseMentions returns the UIDs of all principals mentioned in the provided text (in form of @uid).
+// Mentions inside of fenced code blocks and inline code spans are ignored, as well as email addresses.
+// The returned list doesn't contain duplicates (UIDs are compared case-insensitively).
+func parseMentions(text string) []string {
+	var (
+		uids      []string
+		seen      = make(map[string]struct{})
+		inFence   bool
+		fenceChar byte
+		fenceLen  int
+	)
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if len(line)-len(trimmed) > 3 {
+			// lines indented with four or more spaces are code blocks.
+			continue
+		}
+
+		char, n := fenceMarker(trimmed)
+
+		if inFence {
+			if char == fenceChar && n >= fenceLen && strings.TrimSpace(trimmed[n:]) == "" {
+				inFence = false
+			}
+			continue
+		}
+
+		if n > 0 {
+			inFence = true
+			fenceChar = char
+			fenceLen = n
+			continue
+		}
+
+		for _, uid := range parseLineMentions(line) {
+			key := strings.ToLower(uid)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+
+			seen[key] = struct{}{}
+			uids = append(uids, uid)
+		}
+	}
+
+	return uids
+}
+
+// parseLineMentions returns all mentions found in a single line of text, skipping inline code spans.
+func parseLineMentions(line string) []string {
+	var uids []string
+
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '`':
+			n := 1
+			for i+n < len(line) && line[i+n] == '`' {
+				n++
+			}
+
+			end := strings.Index(line[i+n:], line[i:i+n])
+			if end < 0 {
+				i += n - 1
+				continue
+			}
+
+			i += n + end + n - 1
+
+		case c == '@':
+			if i > 0 && (isUIDChar(line[i-1]) || line[i-1] == '@' || line[i-1] == '/') {
+				continue
+			}
+
+			j := i + 1
+			if j >= len(line) || !isUIDStartChar(line[j]) {
+				continue
+			}
+
+			for j < len(line) && isUIDChar(line[j]) {
+				j++
+			}
+
+			// a trailing dot is most likely the end of a sentence
+			uid := strings.TrimRight(line[i+1:j], ".")
+			uids = append(uids, uid)
+
+			i = j - 1
+		}
+	}
+
+	return uids
+}
+
+func isUIDStartChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isUIDChar(c byte) bool {
+	return isUIDStartChar(c) || c >= '0' && c <= '9' || c == '-' || c == '.'
+}
+
+// getMentions resolves the mentions found in the text to users.
+// Mentions of unknown principals, of principals that aren't users
+// and of users that don't have access to the repository are ignored.
+func (c *Controller) getMentions(
+	ctx context.Context,
+	repo *types.Repository,
+	text string,
+) (map[int64]*types.PrincipalInfo, error) {
+	uids := parseMentions(text)
+	if len(uids) == 0 {
+		return nil, nil
+	}
+
+	principals, err := c.principalStore.FindManyByUID(ctx, uids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find mentioned principals: %w", err)
+	}
+
+	mentions := make(map[int64]*types.PrincipalInfo, len(principals))
+	for _, principal := range principals {
+		if principal.Type != enum.PrincipalTypeUser {
+			continue
+		}
+
+		session := &auth.Session{Principal: *principal}
+		err = apiauth.CheckRepo(ctx, c.authorizer, session, repo, enum.PermissionRepoView, true)
+		if errors.Is(err, apiauth.ErrNotAuthorized) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to check repo access of mentioned principal: %w", err)
+		}
+
+		mentions[principal.ID] = principal.ToPrincipalInfo()
+	}
+
+	return mentions, nil
+}
+
+// setMentionsMetadata stores IDs of the mentioned principals in the activity metadata.
+func setMentionsMetadata(act *types.PullReqActivity, mentions map[int64]*types.PrincipalInfo) {
+	act.Mentions = mentions
+
+	if len(mentions) == 0 {
+		if act.Metadata != nil {
+			act.Metadata.Mentions = nil
+		}
+		return
+	}
+
+	if act.Metadata == nil {
+		act.Metadata = &types.PullReqActivityMetadata{}
+	}
+
+	act.Metadata.Mentions = &types.PullReqActivityMentionsMetadata{
+		IDs: mentionIDs(mentions),
+	}
+}
+
+// getMentionsMetadata returns IDs of principals mentioned in the activity.
+func getMentionsMetadata(act *types.PullReqActivity) []int64 {
+	if act.Metadata == nil || act.Metadata.Mentions == nil {
+		return nil
+	}
+
+	return act.Metadata.Mentions.IDs
+}
+
+// backfillMentions fills principal info of the mentioned principals for the provided list of activities.
+func (c *Controller) backfillMentions(ctx context.Context, list []*types.PullReqActivity) error {
+	var ids []int64
+	for _, act := range list {
+		if act.Deleted == nil {
+			ids = append(ids, getMentionsMetadata(act)...)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	infoMap, err := c.principalInfoCache.Map(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load mentioned principals: %w", err)
+	}
+
+	for _, act := range list {
+		if act.Deleted != nil {
+			continue
+		}
+
+		for _, id := range getMentionsMetadata(act) {
+			info, ok := infoMap[id]
+			if !ok {
+				continue
+			}
+
+			if act.Mentions == nil {
+				act.Mentions = make(map[int64]*types.PrincipalInfo)
+			}
+
+			act.Mentions[id] = info
+		}
+	}
+
+	return nil
+}
+
+// reportMentions publishes the user mentioned event for the newly mentioned principals.
+// The author of the text and principals in the alreadyMentioned list are excluded.
+func (c *Controller) reportMentions(
+	ctx context.Context,
+	pr *types.PullReq,
+	author *types.Principal,
+	activityID int64,
+	mentions map[int64]*types.PrincipalInfo,
+	alreadyMentioned []int64,
+) {
+	excluded := make(map[int64]struct{}, len(alreadyMentioned)+1)
+	excluded[author.ID] = struct{}{}
+	for _, id := range alreadyMentioned {
+		excluded[id] = struct{}{}
+	}
+
+	var ids []int64
+	for _, id := range mentionIDs(mentions) {
+		if _, ok := excluded[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return
+	}
+
+	c.eventReporter.UserMentioned(ctx, &pullreqevents.UserMentionedPayload{
+		Base:         eventBase(pr, author),
+		ActivityID:   activityID,
+		MentionedIDs: ids,
+	})
+}
+
+// reportDescriptionMentions resolves mentions in the pull request description
+// and publishes the user mentioned event for principals that weren't mentioned in the old description.
+func (c *Controller) reportDescriptionMentions(
+	ctx context.Context,
+	repo *types.Repository,
+	pr *types.PullReq,
+	author *types.Principal,
+	oldDescription string,
+) {
+	mentions, err := c.getMentions(ctx, repo, pr.Description)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to resolve mentions in pull request description")
+		return
+	}
+
+	if len(mentions) == 0 {
+		return
+	}
+
+	var alreadyMentioned []int64
+	if oldDescription != "" {
+		oldMentions, err := c.getMentions(ctx, repo, oldDescription)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("failed to resolve mentions in old pull request description")
+			return
+		}
+
+		alreadyMentioned = mentionIDs(oldMentions)
+	}
+
+	c.reportMentions(ctx, pr, author, 0, mentions, alreadyMentioned)
+}
+
+func mentionIDs(mentions map[int64]*types.PrincipalInfo) []int64 {
+	ids := make([]int64, 0, len(mentions))
+	for id := range mentions {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/slices"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "no-mentions",
+			text: "just a comment",
+			want: nil,
+		},
+		{
+			name: "single-mention",
+			text: "@john please take a look",
+			want: []string{"john"},
+		},
+		{
+			name: "multiple-mentions",
+			text: "cc @john, @jane_doe and (@bob-1)",
+			want: []string{"john", "jane_doe", "bob-1"},
+		},
+		{
+			name: "duplicates",
+			text: "@john @John\n@john",
+			want: []string{"john"},
+		},
+		{
+			name: "trailing-dot",
+			text: "thanks @john.doe.",
+			want: []string{"john.doe"},
+		},
+		{
+			name: "email",
+			text: "mail john@example.com or see a/@b",
+			want: nil,
+		},
+		{
+			name: "invalid-uid",
+			text: "@ @1abc @@john",
+			want: nil,
+		},
+		{
+			name: "inline-code",
+			text: "`@john` and ``a ` @jane`` but @bob",
+			want: []string{"bob"},
+		},
+		{
+			name: "unterminated-inline-code",
+			text: "`` @john",
+			want: []string{"john"},
+		},
+		{
+			name: "fenced-code",
+			text: "```\n@john\n```\n~~~go\n@jane\n~~~\n@bob",
+			want: []string{"bob"},
+		},
+		{
+			name: "indented-code",
+			text: "    @john\n@jane",
+			want: []string{"jane"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := parseMentions(test.text)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("want=%q got=%q", test.want, got)
+			}
+		})
+	}
+}
+
+type mentionsPrincipalStoreMock struct {
+	store.PrincipalStore
+	principals []*types.Principal
+}
+
+func (s mentionsPrincipalStoreMock) FindManyByUID(_ context.Context, uids []string) ([]*types.Principal, error) {
+	var res []*types.Principal
+	for _, p := range s.principals {
+		if slices.Contains(uids, p.UID) {
+			res = append(res, p)
+		}
+	}
+	return res, nil
+}
+
+// mentionsAuthorizerMock grants repo access only to the listed principals.
+type mentionsAuthorizerMock struct {
+	authorizerMock
+	allowed map[int64]bool
+}
+
+func (a mentionsAuthorizerMock) Check(_ context.Context, session *auth.Session, _ *types.Scope,
+	_ *types.Resource, _ enum.Permission) (bool, error) {
+	return a.allowed[session.Principal.ID], nil
+}
+
+func TestController_GetMentions(t *testing.T) {
+	c := &Controller{
+		principalStore: mentionsPrincipalStoreMock{principals: []*types.Principal{
+			{ID: 1, UID: "john", Type: enum.PrincipalTypeUser},
+			{ID: 2, UID: "jane", Type: enum.PrincipalTypeUser},
+			{ID: 3, UID: "bot", Type: enum.PrincipalTypeService},
+		}},
+		authorizer: mentionsAuthorizerMock{allowed: map[int64]bool{1: true, 3: true}},
+	}
+
+	ctx := context.Background()
+	text := "@john @jane @bot @unknown"
+
+	mentions, err := c.getMentions(ctx, &types.Repository{Path: "space/repo"}, text)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, mentionIDs(mentions))
+
+	mentions, err = c.getMentions(ctx, &types.Repository{Path: "space/repo", IsPublic: true}, text)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, mentionIDs(mentions))
+}
@@ -0,0 +1,324 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/controller"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/bootstrap"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+// MergeConflicts returns the files that conflict when merging the target branch
+// into the source branch of the pull request.
+func (c *Controller) MergeConflicts(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	prNum int64,
+) (*types.MergeConflicts, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, prNum)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find pull request by number: %w", err)
+	}
+
+	if pr.State != enum.PullReqStateOpen {
+		return nil, usererror.BadRequest("Merge conflicts can only be listed for open pull requests.")
+	}
+
+	sourceRepo := repo
+	if pr.SourceRepoID != pr.TargetRepoID {
+		sourceRepo, err = c.repoStore.Find(ctx, pr.SourceRepoID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get source repository: %w", err)
+		}
+	}
+
+	out, err := c.git.GetMergeConflicts(ctx, &git.MergeConflictsParams{
+		ReadParams:   git.CreateReadParams(sourceRepo),
+		SourceBranch: pr.SourceBranch,
+		TargetBranch: pr.TargetBranch,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get merge conflicts: %w", err)
+	}
+
+	files := make([]types.MergeConflictFile, len(out.Files))
+	for i, file := range out.Files {
+		files[i] = types.MergeConflictFile{
+			Path:     file.Path,
+			Base:     mapMergeConflictFileVersion(file.Base),
+			Ours:     mapMergeConflictFileVersion(file.Ours),
+			Theirs:   mapMergeConflictFileVersion(file.Theirs),
+			IsBinary: file.IsBinary,
+			Content:  bytesToStringPtr(file.Content),
+		}
+	}
+
+	return &types.MergeConflicts{
+		SourceSHA:    out.SourceSHA,
+		TargetSHA:    out.TargetSHA,
+		MergeBaseSHA: out.MergeBaseSHA,
+		Files:        files,
+	}, nil
+}
+
+type MergeConflictsResolveInput struct {
+	// SourceSHA is the latest commit of the source branch the conflicts were resolved for.
+	SourceSHA string `json:"source_sha"`
+	// TargetSHA is the latest commit of the target branch the conflicts were resolved for.
+	TargetSHA string                      `json:"target_sha"`
+	Files     []ResolvedMergeConflictFile `json:"files"`
+	Message   string                      `json:"message"`
+
+	DryRunRules bool `json:"dry_run_rules"`
+	BypassRules bool `json:"bypass_rules"`
+}
+
+type ResolvedMergeConflictFile struct {
+	Path     string                   `json:"path"`
+	Payload  string                   `json:"payload"`
+	Encoding enum.ContentEncodingType `json:"encoding"`
+	// Delete resolves the conflict by deleting the file.
+	Delete bool `json:"delete"`
+}
+
+func (in *MergeConflictsResolveInput) sanitize() error {
+	if in.SourceSHA == "" {
+		return usererror.BadRequest("Source SHA must be provided.")
+	}
+
+	if in.TargetSHA == "" {
+		return usererror.BadRequest("Target SHA must be provided.")
+	}
+
+	paths := make(map[string]struct{}, len(in.Files))
+	for i := range in.Files {
+		in.Files[i].Path = strings.Trim(in.Files[i].Path, "/")
+		if in.Files[i].Path == "" {
+			return usererror.BadRequest("A path must be provided for each resolved file.")
+		}
+
+		if _, ok := paths[in.Files[i].Path]; ok {
+			return usererror.BadRequestf("File '%s' can only be resolved once.", in.Files[i].Path)
+		}
+
+		paths[in.Files[i].Path] = struct{}{}
+	}
+
+	in.Message = strings.TrimSpace(in.Message)
+
+	return nil
+}
+
+type MergeConflictsResolveOutput struct {
+	CommitID       string                 `json:"commit_id"`
+	DryRunRules    bool                   `json:"dry_run_rules,omitempty"`
+	RuleViolations []types.RuleViolations `json:"rule_violations,omitempty"`
+}
+
+// MergeConflictsResolve merges the target branch into the source branch of the pull request
+// using the provided resolutions of the conflicted files. The merge commit is pushed to the source branch.
+//
+//nolint:gocognit
+func (c *Controller) MergeConflictsResolve(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	prNum int64,
+	in *MergeConflictsResolveInput,
+) (MergeConflictsResolveOutput, []types.RuleViolations, error) {
+	if err := in.sanitize(); err != nil {
+		return MergeConflictsResolveOutput{}, nil, err
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return MergeConflictsResolveOutput{}, nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	// the max time we give resolving of merge conflicts to succeed
+	const timeout = 2 * time.Minute
+
+	unlock, err := c.lockPR(ctx, repo.GitUID, prNum, timeout)
+	if err != nil {
+		return MergeConflictsResolveOutput{}, nil, err
+	}
+	defer unlock()
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, prNum)
+	if err != nil {
+		return MergeConflictsResolveOutput{}, nil, fmt.Errorf("failed to find pull request by number: %w", err)
+	}
+
+	if pr.State != enum.PullReqStateOpen {
+		return MergeConflictsResolveOutput{}, nil,
+			usererror.BadRequest("Merge conflicts can only be resolved for open pull requests.")
+	}
+
+	if pr.SourceSHA != in.SourceSHA {
+		return MergeConflictsResolveOutput{}, nil,
+			usererror.BadRequest("A newer commit is available. Merge conflicts can only be resolved for the latest commit.")
+	}
+
+	sourceRepo := repo
+	if pr.SourceRepoID != pr.TargetRepoID {
+		sourceRepo, err = c.repoStore.Find(ctx, pr.SourceRepoID)
+		if err != nil {
+			return MergeConflictsResolveOutput{}, nil, fmt.Errorf("failed to get source repository: %w", err)
+		}
+	}
+
+	requiredPermission := enum.PermissionRepoPush
+	if in.DryRunRules {
+		requiredPermission = enum.PermissionRepoView
+	}
+
+	if err = apiauth.CheckRepo(ctx, c.authorizer, session, sourceRepo, requiredPermission, false); err != nil {
+		return MergeConflictsResolveOutput{}, nil, fmt.Errorf("access check failed: %w", err)
+	}
+
+	isRepoOwner, err := apiauth.IsRepoOwner(ctx, c.authorizer, session, sourceRepo)
+	if err != nil {
+		return MergeConflictsResolveOutput{}, nil,
+			fmt.Errorf("failed to determine if user is repo owner: %w", err)
+	}
+
+	protectionRules, err := c.protectionManager.ForRepository(ctx, sourceRepo.ID)
+	if err != nil {
+		return MergeConflictsResolveOutput{}, nil,
+			fmt.Errorf("failed to fetch protection rules for the repository: %w", err)
+	}
+
+	violations, err := protectionRules.RefChangeVerify(ctx, protection.RefChangeVerifyInput{
+		Actor:       &session.Principal,
+		AllowBypass: in.BypassRules,
+		IsRepoOwner: isRepoOwner,
+		Repo:        sourceRepo,
+		RefAction:   protection.RefActionUpdate,
+		RefType:     protection.RefTypeBranch,
+		RefNames:    []string{pr.SourceBranch},
+	})
+	if err != nil {
+		return MergeConflictsResolveOutput{}, nil, fmt.Errorf("failed to verify protection rules: %w", err)
+	}
+
+	if in.DryRunRules {
+		return MergeConflictsResolveOutput{
+			DryRunRules:    true,
+			RuleViolations: violations,
+		}, nil, nil
+	}
+
+	if protection.IsCritical(violations) {
+		return MergeConflictsResolveOutput{}, violations, nil
+	}
+
+	files := make([]git.ResolvedMergeConflictFile, len(in.Files))
+	for i, file := range in.Files {
+		files[i] = git.ResolvedMergeConflictFile{
+			Path:   file.Path,
+			Delete: file.Delete,
+		}
+		if file.Delete {
+			continue
+		}
+
+		switch file.Encoding {
+		case enum.ContentEncodingTypeBase64:
+			files[i].Content, err = base64.StdEncoding.DecodeString(file.Payload)
+			if err != nil {
+				return MergeConflictsResolveOutput{}, nil,
+					usererror.BadRequestf("Failed to decode base64 payload of file '%s'.", file.Path)
+			}
+		case enum.ContentEncodingTypeUTF8:
+			fallthrough
+		default:
+			files[i].Content = []byte(file.Payload)
+		}
+	}
+
+	// Create internal write params. Note: This will skip the pre-commit protection rules check.
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, sourceRepo)
+	if err != nil {
+		return MergeConflictsResolveOutput{}, nil, fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	now := time.Now()
+	out, err := c.git.ResolveMergeConflicts(ctx, &git.ResolveMergeConflictsParams{
+		WriteParams:       writeParams,
+		SourceBranch:      pr.SourceBranch,
+		SourceExpectedSHA: in.SourceSHA,
+		TargetBranch:      pr.TargetBranch,
+		TargetExpectedSHA: in.TargetSHA,
+		Files:             files,
+		Message:           in.Message,
+		Committer:         identityFromPrincipalInfo(*bootstrap.NewSystemServiceSession().Principal.ToPrincipalInfo()),
+		CommitterDate:     &now,
+		Author:            identityFromPrincipalInfo(*session.Principal.ToPrincipalInfo()),
+		AuthorDate:        &now,
+	})
+	if err != nil {
+		return MergeConflictsResolveOutput{}, nil, fmt.Errorf("failed to resolve merge conflicts: %w", err)
+	}
+
+	if err = c.sseStreamer.Publish(ctx, repo.ParentID, enum.SSETypePullRequestUpdated, pr); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish PR changed event")
+	}
+
+	return MergeConflictsResolveOutput{
+		CommitID:       out.CommitSHA,
+		RuleViolations: violations,
+	}, nil, nil
+}
+
+func mapMergeConflictFileVersion(v *git.MergeConflictFileVersion) *types.MergeConflictFileVersion {
+	if v == nil {
+		return nil
+	}
+
+	return &types.MergeConflictFileVersion{
+		SHA:     v.SHA,
+		Size:    v.Size,
+		Content: bytesToStringPtr(v.Content),
+	}
+}
+
+func bytesToStringPtr(b []byte) *string {
+	if b == nil {
+		return nil
+	}
+
+	s := string(b)
+	return &s
+}
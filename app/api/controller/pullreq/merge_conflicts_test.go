@@ -0,0 +1,340 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/service"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/bootstrap"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/sse"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/app/url"
+	gitness_errors "github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/git/adapter"
+	"github.com/harness/gitness/git/storage"
+	gittypes "github.com/harness/gitness/git/types"
+	"github.com/harness/gitness/lock"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+type authorizerMock struct{}
+
+func (authorizerMock) Check(context.Context, *auth.Session, *types.Scope, *types.Resource,
+	enum.Permission) (bool, error) {
+	return true, nil
+}
+
+func (authorizerMock) CheckAll(context.Context, *auth.Session, ...types.PermissionCheck) (bool, error) {
+	return true, nil
+}
+
+type repoStoreMock struct {
+	store.RepoStore
+	repo *types.Repository
+}
+
+func (s repoStoreMock) FindByRef(context.Context, string) (*types.Repository, error) {
+	return s.repo, nil
+}
+
+type pullreqStoreMock struct {
+	store.PullReqStore
+	pr *types.PullReq
+}
+
+func (s pullreqStoreMock) FindByNumber(context.Context, int64, int64) (*types.PullReq, error) {
+	return s.pr, nil
+}
+
+type ruleStoreMock struct {
+	store.RuleStore
+}
+
+func (ruleStoreMock) ListAllRepoRules(context.Context, int64) ([]types.RuleInfoInternal, error) {
+	return nil, nil
+}
+
+type urlProviderMock struct {
+	url.Provider
+}
+
+func (urlProviderMock) GetInternalAPIURL() string {
+	return "http://localhost:3000"
+}
+
+type sseStreamerMock struct{}
+
+func (sseStreamerMock) Publish(context.Context, int64, enum.SSEType, any) error {
+	return nil
+}
+
+func (sseStreamerMock) Stream(context.Context, int64) (<-chan *sse.Event, <-chan error, func(context.Context) error) {
+	return nil, nil, nil
+}
+
+type principalStoreMock struct {
+	store.PrincipalStore
+}
+
+func (principalStoreMock) FindServiceByUID(_ context.Context, uid string) (*types.Service, error) {
+	return &types.Service{ID: 2, UID: uid, DisplayName: "Gitness", Email: "system@gitness.io", Admin: true}, nil
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	return strings.TrimSpace(string(out))
+}
+
+// setupMergeConflictsController returns a controller for a pull request from branch "feature" into "main".
+// a.txt is changed in both branches and b.txt is changed in feature and deleted in main.
+func setupMergeConflictsController(t *testing.T) (*Controller, *types.PullReq, string) {
+	t.Helper()
+
+	ctx := context.Background()
+	root := t.TempDir()
+
+	config := &types.Config{}
+	config.Principal.System.UID = "gitness"
+	require.NoError(t, bootstrap.SystemService(ctx, config, service.NewController(nil, nil, principalStoreMock{})))
+
+	gitAdapter, err := adapter.New(gittypes.Config{}, adapter.NewInMemoryLastCommitCache(time.Minute), nil)
+	require.NoError(t, err)
+	gitService, err := git.New(gittypes.Config{Root: root, TmpDir: t.TempDir()}, gitAdapter, storage.NewLocalStore())
+	require.NoError(t, err)
+
+	work := filepath.Join(root, "work")
+	runTestGit(t, root, "init", "-b", "main", work)
+	write := func(file, content string) {
+		cmd := exec.Command("sh", "-c", "printf '%s' \"$1\" > \"$2\"", "sh", content, file)
+		cmd.Dir = work
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	write("a.txt", "line1\nline2\nline3\n")
+	write("b.txt", "b\n")
+	runTestGit(t, work, "add", "-A")
+	runTestGit(t, work, "commit", "-m", "base")
+
+	runTestGit(t, work, "checkout", "-b", "feature")
+	write("a.txt", "line1\nfeature\nline3\n")
+	write("b.txt", "feature b\n")
+	runTestGit(t, work, "commit", "-am", "feature")
+
+	runTestGit(t, work, "checkout", "main")
+	write("a.txt", "line1\nmain\nline3\n")
+	runTestGit(t, work, "rm", "b.txt")
+	runTestGit(t, work, "commit", "-am", "main")
+
+	repo := &types.Repository{ID: 1, ParentID: 1, Path: "space/repo", GitUID: "mergeconflicts", DefaultBranch: "main"}
+	repoPath := filepath.Join(root, "repos", "me", "rg", "econflicts.git")
+	runTestGit(t, root, "clone", "--bare", work, repoPath)
+
+	pr := &types.PullReq{
+		Number:       1,
+		State:        enum.PullReqStateOpen,
+		SourceRepoID: repo.ID,
+		SourceBranch: "feature",
+		SourceSHA:    runTestGit(t, repoPath, "rev-parse", "feature"),
+		TargetRepoID: repo.ID,
+		TargetBranch: "main",
+	}
+
+	c := &Controller{
+		urlProvider:       urlProviderMock{},
+		authorizer:        authorizerMock{},
+		pullreqStore:      pullreqStoreMock{pr: pr},
+		repoStore:         repoStoreMock{repo: repo},
+		git:               gitService,
+		mtxManager:        lock.NewInMemory(lock.Config{Tries: 1}),
+		protectionManager: protection.NewManager(ruleStoreMock{}),
+		sseStreamer:       sseStreamerMock{},
+	}
+
+	return c, pr, repoPath
+}
+
+func testSession() *auth.Session {
+	return &auth.Session{
+		Principal: types.Principal{
+			ID:          1,
+			UID:         "user",
+			DisplayName: "User",
+			Email:       "user@example.com",
+			Type:        enum.PrincipalTypeUser,
+		},
+	}
+}
+
+func TestController_MergeConflicts(t *testing.T) {
+	c, pr, _ := setupMergeConflictsController(t)
+	ctx := context.Background()
+
+	out, err := c.MergeConflicts(ctx, testSession(), "space/repo", pr.Number)
+	require.NoError(t, err)
+	require.Equal(t, pr.SourceSHA, out.SourceSHA)
+	require.Len(t, out.Files, 2)
+
+	// content conflict
+	require.Equal(t, "a.txt", out.Files[0].Path)
+	require.NotNil(t, out.Files[0].Content)
+	require.Contains(t, *out.Files[0].Content, "<<<<<<< feature\nfeature\n=======\nmain\n>>>>>>> main\n")
+	require.Equal(t, "line1\nfeature\nline3\n", *out.Files[0].Ours.Content)
+	require.Equal(t, "line1\nmain\nline3\n", *out.Files[0].Theirs.Content)
+
+	// delete/modify conflict
+	require.Equal(t, "b.txt", out.Files[1].Path)
+	require.Nil(t, out.Files[1].Content)
+	require.Equal(t, "feature b\n", *out.Files[1].Ours.Content)
+	require.Nil(t, out.Files[1].Theirs)
+
+	pr.State = enum.PullReqStateMerged
+	_, err = c.MergeConflicts(ctx, testSession(), "space/repo", pr.Number)
+	requireUserError(t, err, http.StatusBadRequest)
+}
+
+func TestController_MergeConflictsResolve(t *testing.T) {
+	c, pr, repoPath := setupMergeConflictsController(t)
+	ctx := context.Background()
+	targetSHA := runTestGit(t, repoPath, "rev-parse", "main")
+
+	resolvedA := ResolvedMergeConflictFile{
+		Path:     "/a.txt",
+		Payload:  base64.StdEncoding.EncodeToString([]byte("line1\nresolved\nline3\n")),
+		Encoding: enum.ContentEncodingTypeBase64,
+	}
+	deletedB := ResolvedMergeConflictFile{Path: "b.txt", Delete: true}
+
+	tests := []struct {
+		name      string
+		sourceSHA string
+		files     []ResolvedMergeConflictFile
+		// status is the expected status of the user error, 0 for errors of the git service.
+		status int
+		err    string
+	}{
+		{
+			name:   "missing source sha",
+			files:  []ResolvedMergeConflictFile{resolvedA, deletedB},
+			status: http.StatusBadRequest,
+			err:    "Source SHA must be provided.",
+		},
+		{
+			name:      "outdated source sha",
+			sourceSHA: targetSHA,
+			files:     []ResolvedMergeConflictFile{resolvedA, deletedB},
+			status:    http.StatusBadRequest,
+			err:       "A newer commit is available.",
+		},
+		{
+			name:      "file resolved twice",
+			sourceSHA: pr.SourceSHA,
+			files:     []ResolvedMergeConflictFile{resolvedA, {Path: "a.txt", Payload: "a"}},
+			status:    http.StatusBadRequest,
+			err:       "File 'a.txt' can only be resolved once.",
+		},
+		{
+			name:      "invalid base64 payload",
+			sourceSHA: pr.SourceSHA,
+			files:     []ResolvedMergeConflictFile{{Path: "a.txt", Payload: "%", Encoding: enum.ContentEncodingTypeBase64}},
+			status:    http.StatusBadRequest,
+			err:       "Failed to decode base64 payload of file 'a.txt'.",
+		},
+		{
+			name:      "missing resolution",
+			sourceSHA: pr.SourceSHA,
+			files:     []ResolvedMergeConflictFile{resolvedA},
+			err:       "merge conflicts of files b.txt are not resolved",
+		},
+		{
+			name:      "conflict markers",
+			sourceSHA: pr.SourceSHA,
+			files: []ResolvedMergeConflictFile{
+				{Path: "a.txt", Payload: "line1\n<<<<<<< feature\nfeature\n=======\nmain\n>>>>>>> main\nline3\n"},
+				deletedB,
+			},
+			err: "resolution of file 'a.txt' still contains conflict markers",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := c.MergeConflictsResolve(ctx, testSession(), "space/repo", pr.Number,
+				&MergeConflictsResolveInput{
+					SourceSHA: test.sourceSHA,
+					TargetSHA: targetSHA,
+					Files:     test.files,
+				})
+			if test.status != 0 {
+				requireUserError(t, err, test.status)
+			} else {
+				require.True(t, gitness_errors.IsInvalidArgument(err), "unexpected error: %v", err)
+			}
+			require.ErrorContains(t, err, test.err)
+			require.Equal(t, pr.SourceSHA, runTestGit(t, repoPath, "rev-parse", "feature"))
+		})
+	}
+
+	out, violations, err := c.MergeConflictsResolve(ctx, testSession(), "space/repo", pr.Number,
+		&MergeConflictsResolveInput{
+			SourceSHA: pr.SourceSHA,
+			TargetSHA: targetSHA,
+			Files:     []ResolvedMergeConflictFile{resolvedA, deletedB},
+		})
+	require.NoError(t, err)
+	require.Empty(t, violations)
+	require.Equal(t, runTestGit(t, repoPath, "rev-parse", "feature"), out.CommitID)
+	require.Equal(t, pr.SourceSHA+"\n"+targetSHA, runTestGit(t, repoPath, "rev-parse", "feature^1", "feature^2"))
+	require.Equal(t, "line1\nresolved\nline3", runTestGit(t, repoPath, "show", "feature:a.txt"))
+	require.Equal(t, "a.txt", runTestGit(t, repoPath, "ls-tree", "--name-only", "feature"))
+
+	// the merge commit is authored by the user and committed by the system.
+	require.Equal(t, "User <user@example.com>\nGitness <system@gitness.io>",
+		runTestGit(t, repoPath, "log", "-1", "--format=%an <%ae>%n%cn <%ce>", "feature"))
+}
+
+func requireUserError(t *testing.T, err error, status int) {
+	t.Helper()
+
+	var uErr *usererror.Error
+	require.True(t, errors.As(err, &uErr), "unexpected error: %v", err)
+	require.Equal(t, status, uErr.Status)
+}
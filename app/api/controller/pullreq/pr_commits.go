@@ -57,6 +57,7 @@ func (c *Controller) Commits(
 		return nil, err
 	}
 
+	verification := c.signatureVerifier.NewSession()
 	commits := make([]types.Commit, len(output.Commits))
 	for i := range output.Commits {
 		var commit *types.Commit
@@ -64,6 +65,12 @@ func (c *Controller) Commits(
 		if err != nil {
 			return nil, fmt.Errorf("failed to map commit: %w", err)
 		}
+
+		commit.Verification, err = verification.VerifyCommit(ctx, &output.Commits[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify commit signature: %w", err)
+		}
+
 		commits[i] = *commit
 	}
 
@@ -121,6 +121,8 @@ func (c *Controller) Create(
 		SourceSHA:    sourceSHA,
 	})
 
+	c.reportDescriptionMentions(ctx, targetRepo, pr, &session.Principal, "")
+
 	if err = c.sseStreamer.Publish(ctx, targetRepo.ParentID, enum.SSETypePullRequestUpdated, pr); err != nil {
 		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish PR changed event")
 	}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/harness/gitness/app/api/controller"
 	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/types"
@@ -32,6 +33,7 @@ func (c *Controller) RawDiff(
 	repoRef string,
 	pullreqNum int64,
 	setSHAs func(sourceSHA, mergeBaseSHA string),
+	diffOptions *types.DiffOptions,
 	w io.Writer,
 ) error {
 	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
@@ -49,10 +51,11 @@ func (c *Controller) RawDiff(
 	}
 
 	return c.git.RawDiff(ctx, &git.DiffParams{
-		ReadParams: git.CreateReadParams(repo),
-		BaseRef:    pr.MergeBaseSHA,
-		HeadRef:    pr.SourceSHA,
-		MergeBase:  true,
+		ReadParams:  git.CreateReadParams(repo),
+		DiffOptions: controller.MapDiffOptions(diffOptions),
+		BaseRef:     pr.MergeBaseSHA,
+		HeadRef:     pr.SourceSHA,
+		MergeBase:   true,
 	}, w)
 }
 
@@ -63,6 +66,7 @@ func (c *Controller) Diff(
 	pullreqNum int64,
 	setSHAs func(sourceSHA, mergeBaseSHA string),
 	includePatch bool,
+	diffOptions *types.DiffOptions,
 ) (types.Stream[*git.FileDiff], error) {
 	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
 	if err != nil {
@@ -80,6 +84,7 @@ func (c *Controller) Diff(
 
 	reader := git.NewStreamReader(c.git.Diff(ctx, &git.DiffParams{
 		ReadParams:   git.CreateReadParams(repo),
+		DiffOptions:  controller.MapDiffOptions(diffOptions),
 		BaseRef:      pr.MergeBaseSHA,
 		HeadRef:      pr.SourceSHA,
 		MergeBase:    true,
@@ -62,5 +62,14 @@ func (c *Controller) Find(
 		pr.Stats.DiffStats = types.NewDiffStats(output.Commits, output.FilesChanged)
 	}
 
+	if err = c.labelService.Backfill(ctx, pr); err != nil {
+		return nil, err
+	}
+
+	pr.Reactions, err = c.reactionStore.CountForTarget(ctx, pr.ID, nil, session.Principal.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pull request reactions: %w", err)
+	}
+
 	return pr, nil
 }
@@ -85,6 +85,7 @@ func (c *Controller) Update(ctx context.Context,
 
 	needToWriteActivity := in.Title != pr.Title
 	oldTitle := pr.Title
+	oldDescription := pr.Description
 
 	pr, err = c.pullreqStore.UpdateOptLock(ctx, pr, func(pr *types.PullReq) error {
 		pr.Title = in.Title
@@ -110,6 +111,10 @@ func (c *Controller) Update(ctx context.Context,
 		}
 	}
 
+	if pr.Description != oldDescription {
+		c.reportDescriptionMentions(ctx, targetRepo, pr, &session.Principal, oldDescription)
+	}
+
 	if err = c.sseStreamer.Publish(ctx, targetRepo.ParentID, enum.SSETypePullRequestUpdated, pr); err != nil {
 		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish PR changed event")
 	}
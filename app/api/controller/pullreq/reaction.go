@@ -0,0 +1,231 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+type ReactionInput struct {
+	Emoji enum.PullReqReaction `json:"emoji"`
+}
+
+func (in *ReactionInput) Sanitize() error {
+	emoji, ok := in.Emoji.Sanitize()
+	if !ok {
+		return usererror.BadRequestf("Invalid reaction '%s'.", in.Emoji)
+	}
+	in.Emoji = emoji
+
+	return nil
+}
+
+// ReactionAdd adds a reaction of the current principal to the pull request description.
+func (c *Controller) ReactionAdd(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	in *ReactionInput,
+) ([]*types.PullReqReactionCount, error) {
+	if err := in.Sanitize(); err != nil {
+		return nil, err
+	}
+
+	repo, pr, err := c.getReactionTarget(ctx, session, repoRef, pullreqNum, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.changeReaction(ctx, session, repo, pr, nil, in.Emoji, true)
+}
+
+// ReactionRemove removes a reaction of the current principal from the pull request description.
+func (c *Controller) ReactionRemove(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	emoji enum.PullReqReaction,
+) ([]*types.PullReqReactionCount, error) {
+	in := &ReactionInput{Emoji: emoji}
+	if err := in.Sanitize(); err != nil {
+		return nil, err
+	}
+
+	repo, pr, err := c.getReactionTarget(ctx, session, repoRef, pullreqNum, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.changeReaction(ctx, session, repo, pr, nil, in.Emoji, false)
+}
+
+// CommentReactionAdd adds a reaction of the current principal to a pull request comment.
+func (c *Controller) CommentReactionAdd(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	commentID int64,
+	in *ReactionInput,
+) ([]*types.PullReqReactionCount, error) {
+	if err := in.Sanitize(); err != nil {
+		return nil, err
+	}
+
+	repo, pr, err := c.getReactionTarget(ctx, session, repoRef, pullreqNum, &commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.changeReaction(ctx, session, repo, pr, &commentID, in.Emoji, true)
+}
+
+// CommentReactionRemove removes a reaction of the current principal from a pull request comment.
+func (c *Controller) CommentReactionRemove(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	commentID int64,
+	emoji enum.PullReqReaction,
+) ([]*types.PullReqReactionCount, error) {
+	in := &ReactionInput{Emoji: emoji}
+	if err := in.Sanitize(); err != nil {
+		return nil, err
+	}
+
+	repo, pr, err := c.getReactionTarget(ctx, session, repoRef, pullreqNum, &commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.changeReaction(ctx, session, repo, pr, &commentID, in.Emoji, false)
+}
+
+// getReactionTarget verifies that the pull request (and the comment, if provided) can be reacted to.
+func (c *Controller) getReactionTarget(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	pullreqNum int64,
+	commentID *int64,
+) (*types.Repository, *types.PullReq, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to acquire access to repo: %w", err)
+	}
+
+	pr, err := c.pullreqStore.FindByNumber(ctx, repo.ID, pullreqNum)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find pull request by number: %w", err)
+	}
+
+	if commentID == nil {
+		return repo, pr, nil
+	}
+
+	if *commentID <= 0 {
+		return nil, nil, usererror.BadRequest("A valid comment ID must be provided.")
+	}
+
+	comment, err := c.activityStore.Find(ctx, *commentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to find comment by ID: %w", err)
+	}
+
+	if comment.Deleted != nil || comment.RepoID != pr.TargetRepoID || comment.PullReqID != pr.ID {
+		return nil, nil, usererror.ErrNotFound
+	}
+
+	if comment.Kind == enum.PullReqActivityKindSystem ||
+		(comment.Type != enum.PullReqActivityTypeComment && comment.Type != enum.PullReqActivityTypeCodeComment) {
+		return nil, nil, usererror.BadRequest("Only comments and code comments can be reacted to.")
+	}
+
+	return repo, pr, nil
+}
+
+// changeReaction adds or removes the reaction, returns the updated reaction counts
+// and publishes the change to the SSE stream.
+func (c *Controller) changeReaction(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	pr *types.PullReq,
+	activityID *int64,
+	emoji enum.PullReqReaction,
+	add bool,
+) ([]*types.PullReqReactionCount, error) {
+	var changed bool
+	var err error
+
+	if add {
+		changed, err = c.reactionStore.Add(ctx, &types.PullReqReaction{
+			PullReqID:   pr.ID,
+			ActivityID:  activityID,
+			PrincipalID: session.Principal.ID,
+			Emoji:       emoji,
+			Created:     time.Now().UnixMilli(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add reaction: %w", err)
+		}
+	} else {
+		changed, err = c.reactionStore.Remove(ctx, pr.ID, activityID, session.Principal.ID, emoji)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove reaction: %w", err)
+		}
+	}
+
+	reactions, err := c.reactionStore.CountForTarget(ctx, pr.ID, activityID, session.Principal.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reactions: %w", err)
+	}
+
+	if !changed {
+		return reactions, nil
+	}
+
+	update := &types.PullReqReactionsUpdate{
+		PullReqID:   pr.ID,
+		Number:      pr.Number,
+		ActivityID:  activityID,
+		PrincipalID: session.Principal.ID,
+		Emoji:       emoji,
+		Added:       add,
+		Reactions:   make([]*types.PullReqReactionCount, len(reactions)),
+	}
+	for i, r := range reactions {
+		// the event is sent to all the users, so it shouldn't contain the reacted flag of the current principal
+		update.Reactions[i] = &types.PullReqReactionCount{Emoji: r.Emoji, Count: r.Count}
+	}
+
+	if err = c.sseStreamer.Publish(ctx, repo.ParentID, enum.SSETypePullRequestReactionsUpdated, update); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to publish PR reactions changed event")
+	}
+
+	return reactions, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// suggestionFenceInfo is the info string of a fenced code block that marks a code suggestion.
+const suggestionFenceInfo = "suggestion"
+
+type suggestion struct {
+	checkSum string
+	code     string
+}
+
+// parseSuggestions returns all code suggestions found in the provided comment text.
+// A suggestion is a fenced code block (``` or ~~~) with the info string "suggestion".
+func parseSuggestions(text string) []suggestion {
+	var suggestions []suggestion
+
+	var (
+		inFence    bool
+		isSugg     bool
+		fenceChar  byte
+		fenceLen   int
+		codeLines  []string
+		linesInput = strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	)
+
+	for _, line := range linesInput {
+		trimmed := strings.TrimLeft(line, " ")
+		if len(line)-len(trimmed) > 3 {
+			// lines indented with four or more spaces can't be fences.
+			if isSugg {
+				codeLines = append(codeLines, line)
+			}
+			continue
+		}
+
+		char, n := fenceMarker(trimmed)
+
+		if !inFence {
+			if n == 0 {
+				continue
+			}
+
+			inFence = true
+			fenceChar = char
+			fenceLen = n
+			isSugg = strings.TrimSpace(trimmed[n:]) == suggestionFenceInfo
+			codeLines = codeLines[:0]
+
+			continue
+		}
+
+		if char == fenceChar && n >= fenceLen && strings.TrimSpace(trimmed[n:]) == "" {
+			if isSugg {
+				suggestions = append(suggestions, newSuggestion(strings.Join(codeLines, "\n")))
+			}
+
+			inFence = false
+			isSugg = false
+
+			continue
+		}
+
+		if isSugg {
+			codeLines = append(codeLines, line)
+		}
+	}
+
+	return suggestions
+}
+
+// fenceMarker returns the fence character and its count if the line starts with a code fence.
+func fenceMarker(line string) (byte, int) {
+	if line == "" || (line[0] != '`' && line[0] != '~') {
+		return 0, 0
+	}
+
+	char := line[0]
+	n := 0
+	for n < len(line) && line[n] == char {
+		n++
+	}
+
+	if n < 3 {
+		return 0, 0
+	}
+
+	return char, n
+}
+
+func newSuggestion(code string) suggestion {
+	sum := sha256.Sum256([]byte(code))
+	return suggestion{
+		checkSum: hex.EncodeToString(sum[:]),
+		code:     code,
+	}
+}
+
+// setSuggestionsMetadata updates the suggestion check sums in the activity metadata from the activity text.
+// Only code comments can contain suggestions, for any other activity the function is a no-op.
+func setSuggestionsMetadata(act *types.PullReqActivity) {
+	if act.Type != enum.PullReqActivityTypeCodeComment {
+		return
+	}
+
+	suggestions := parseSuggestions(act.Text)
+
+	if len(suggestions) == 0 {
+		if act.Metadata != nil && act.Metadata.Suggestions != nil {
+			act.Metadata.Suggestions.CheckSums = nil
+		}
+		return
+	}
+
+	checkSums := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		checkSums[i] = s.checkSum
+	}
+
+	if act.Metadata == nil {
+		act.Metadata = &types.PullReqActivityMetadata{}
+	}
+	if act.Metadata.Suggestions == nil {
+		act.Metadata.Suggestions = &types.PullReqActivitySuggestionsMetadata{}
+	}
+
+	act.Metadata.Suggestions.CheckSums = checkSums
+}
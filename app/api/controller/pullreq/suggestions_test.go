@@ -0,0 +1,158 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pullreq
+
+import (
+	"testing"
+
+	"github.com/harness/gitness/types"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestParseSuggestions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "no-suggestions",
+			text: "just a comment\n```go\nfoo()\n```",
+			want: nil,
+		},
+		{
+			name: "single-suggestion",
+			text: "try this:\n```suggestion\nfoo()\nbar()\n```\nthanks",
+			want: []string{"foo()\nbar()"},
+		},
+		{
+			name: "empty-suggestion",
+			text: "```suggestion\n```",
+			want: []string{""},
+		},
+		{
+			name: "multiple-suggestions",
+			text: "```suggestion\na\n```\n~~~~ suggestion \nb\n```\nc\n~~~~",
+			want: []string{"a", "b\n```\nc"},
+		},
+		{
+			name: "unterminated-suggestion",
+			text: "```suggestion\na",
+			want: nil,
+		},
+		{
+			name: "crlf",
+			text: "```suggestion\r\na\r\n```",
+			want: []string{"a"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			for _, s := range parseSuggestions(test.text) {
+				got = append(got, s.code)
+				if s.checkSum != newSuggestion(s.code).checkSum {
+					t.Errorf("check sum mismatch for %q", s.code)
+				}
+			}
+
+			if !slices.Equal(test.want, got) {
+				t.Errorf("want=%q, got=%q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestApplySuggestionsToContent(t *testing.T) {
+	cc := func(id int64, line, span int, code string) suggestionToApply {
+		return suggestionToApply{
+			comment: &types.PullReqActivity{
+				ID: id,
+				CodeComment: &types.CodeCommentFields{
+					Path:    "file.txt",
+					LineNew: line,
+					SpanNew: span,
+				},
+			},
+			suggestion: newSuggestion(code),
+		}
+	}
+
+	tests := []struct {
+		name        string
+		content     string
+		suggestions []suggestionToApply
+		want        string
+		wantErr     bool
+	}{
+		{
+			name:        "replace-single-line",
+			content:     "a\nb\nc\n",
+			suggestions: []suggestionToApply{cc(1, 2, 1, "B")},
+			want:        "a\nB\nc\n",
+		},
+		{
+			name:        "replace-multiple-lines",
+			content:     "a\nb\nc\nd",
+			suggestions: []suggestionToApply{cc(1, 2, 2, "x")},
+			want:        "a\nx\nd",
+		},
+		{
+			name:        "remove-lines",
+			content:     "a\nb\nc\n",
+			suggestions: []suggestionToApply{cc(1, 1, 2, "")},
+			want:        "c\n",
+		},
+		{
+			name:        "multiple-suggestions",
+			content:     "a\r\nb\r\nc\r\nd\r\n",
+			suggestions: []suggestionToApply{cc(1, 1, 1, "A1\nA2"), cc(2, 4, 1, "D")},
+			want:        "A1\r\nA2\r\nb\r\nc\r\nD\r\n",
+		},
+		{
+			name:        "overlapping-suggestions",
+			content:     "a\nb\nc\n",
+			suggestions: []suggestionToApply{cc(1, 1, 2, "x"), cc(2, 2, 1, "y")},
+			wantErr:     true,
+		},
+		{
+			name:        "lines-out-of-range",
+			content:     "a\nb\n",
+			suggestions: []suggestionToApply{cc(1, 2, 2, "x")},
+			wantErr:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := applySuggestionsToContent([]byte(test.content), test.suggestions)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got content %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(got) != test.want {
+				t.Errorf("want=%q, got=%q", test.want, string(got))
+			}
+		})
+	}
+}
@@ -19,8 +19,10 @@ import (
 	pullreqevents "github.com/harness/gitness/app/events/pullreq"
 	"github.com/harness/gitness/app/services/codecomments"
 	"github.com/harness/gitness/app/services/codeowners"
+	"github.com/harness/gitness/app/services/label"
 	"github.com/harness/gitness/app/services/protection"
 	"github.com/harness/gitness/app/services/pullreq"
+	"github.com/harness/gitness/app/services/signing"
 	"github.com/harness/gitness/app/sse"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
@@ -40,22 +42,23 @@ func ProvideController(tx dbtx.Transactor, urlProvider url.Provider, authorizer
 	pullReqStore store.PullReqStore, pullReqActivityStore store.PullReqActivityStore,
 	codeCommentsView store.CodeCommentView,
 	pullReqReviewStore store.PullReqReviewStore, pullReqReviewerStore store.PullReqReviewerStore,
-	repoStore store.RepoStore, principalStore store.PrincipalStore,
+	repoStore store.RepoStore, principalStore store.PrincipalStore, principalInfoCache store.PrincipalInfoCache,
 	fileViewStore store.PullReqFileViewStore, membershipStore store.MembershipStore,
-	checkStore store.CheckStore,
+	checkStore store.CheckStore, reactionStore store.PullReqReactionStore,
 	rpcClient git.Interface, eventReporter *pullreqevents.Reporter,
 	mtxManager lock.MutexManager, codeCommentMigrator *codecomments.Migrator,
 	pullreqService *pullreq.Service, ruleManager *protection.Manager, sseStreamer sse.Streamer,
-	codeOwners *codeowners.Service,
+	codeOwners *codeowners.Service, labelService *label.Service, signatureVerifier *signing.Verifier,
 ) *Controller {
 	return NewController(tx, urlProvider, authorizer,
 		pullReqStore, pullReqActivityStore,
 		codeCommentsView,
 		pullReqReviewStore, pullReqReviewerStore,
-		repoStore, principalStore,
+		repoStore, principalStore, principalInfoCache,
 		fileViewStore, membershipStore,
-		checkStore,
+		checkStore, reactionStore,
 		rpcClient, eventReporter,
 		mtxManager, codeCommentMigrator,
-		pullreqService, ruleManager, sseStreamer, codeOwners)
+		pullreqService, ruleManager, sseStreamer, codeOwners, labelService,
+		signatureVerifier)
 }
@@ -0,0 +1,237 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller"
+	"github.com/harness/gitness/app/api/controller/pullreq"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/bootstrap"
+	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// ApplyCommitInput holds the data for cherry-picking or reverting a commit.
+type ApplyCommitInput struct {
+	// Branch is the branch the changes are applied to (optional, default: default branch).
+	Branch string `json:"branch"`
+	// NewBranch (optional) is created from Branch and receives the new commit instead of Branch.
+	NewBranch string `json:"new_branch"`
+	// Mainline is the parent number (starting from 1) that's used as base in case of a merge commit.
+	Mainline int `json:"mainline"`
+
+	Title   string `json:"title"`
+	Message string `json:"message"`
+
+	// OpenPullReq opens a pull request from the new branch into Branch instead of committing to Branch directly.
+	OpenPullReq bool `json:"open_pullreq"`
+
+	DryRunRules bool `json:"dry_run_rules"`
+	BypassRules bool `json:"bypass_rules"`
+}
+
+func (in *ApplyCommitInput) sanitize(repo *types.Repository, operation string, sha string) error {
+	in.Branch = strings.TrimSpace(in.Branch)
+	in.NewBranch = strings.TrimSpace(in.NewBranch)
+
+	if in.Branch == "" {
+		in.Branch = repo.DefaultBranch
+	}
+
+	if in.Mainline < 0 {
+		return usererror.BadRequest("Mainline has to be a positive number.")
+	}
+
+	if !in.OpenPullReq {
+		return nil
+	}
+
+	if in.NewBranch == "" {
+		shortSHA := sha
+		if len(shortSHA) > 8 {
+			shortSHA = shortSHA[:8]
+		}
+		in.NewBranch = fmt.Sprintf("%s-%s", operation, shortSHA)
+	}
+
+	if in.NewBranch == in.Branch {
+		return usererror.BadRequest("The new branch has to be different from the target branch of the pull request.")
+	}
+
+	return nil
+}
+
+// CherryPick applies the changes of a commit on top of a branch.
+func (c *Controller) CherryPick(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	sha string,
+	in *ApplyCommitInput,
+) (*types.ApplyCommitResponse, *types.MergeViolations, error) {
+	return c.applyCommit(ctx, session, repoRef, sha, in, false)
+}
+
+// Revert reverts the changes of a commit on top of a branch.
+func (c *Controller) Revert(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	sha string,
+	in *ApplyCommitInput,
+) (*types.ApplyCommitResponse, *types.MergeViolations, error) {
+	return c.applyCommit(ctx, session, repoRef, sha, in, true)
+}
+
+//nolint:gocognit,funlen
+func (c *Controller) applyCommit(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	sha string,
+	in *ApplyCommitInput,
+	revert bool,
+) (*types.ApplyCommitResponse, *types.MergeViolations, error) {
+	requiredPermission := enum.PermissionRepoPush
+	if in.DryRunRules {
+		requiredPermission = enum.PermissionRepoView
+	}
+
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, requiredPermission, false)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	operation := "cherry-pick"
+	if revert {
+		operation = "revert"
+	}
+
+	if err = in.sanitize(repo, operation, sha); err != nil {
+		return nil, nil, err
+	}
+
+	rules, isRepoOwner, err := c.fetchRules(ctx, session, repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refAction := protection.RefActionUpdate
+	branchName := in.Branch
+	if in.NewBranch != "" {
+		refAction = protection.RefActionCreate
+		branchName = in.NewBranch
+	}
+
+	violations, err := rules.RefChangeVerify(ctx, protection.RefChangeVerifyInput{
+		Actor:       &session.Principal,
+		AllowBypass: in.BypassRules,
+		IsRepoOwner: isRepoOwner,
+		Repo:        repo,
+		RefAction:   refAction,
+		RefType:     protection.RefTypeBranch,
+		RefNames:    []string{branchName},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to verify protection rules: %w", err)
+	}
+
+	if in.DryRunRules {
+		return &types.ApplyCommitResponse{
+			DryRunRules:    true,
+			RuleViolations: violations,
+		}, nil, nil
+	}
+
+	if protection.IsCritical(violations) {
+		return nil, &types.MergeViolations{RuleViolations: violations}, nil
+	}
+
+	// Create internal write params. Note: This will skip the pre-commit protection rules check.
+	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, repo)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create RPC write params: %w", err)
+	}
+
+	now := time.Now()
+	params := &git.ApplyCommitParams{
+		WriteParams:   writeParams,
+		SHA:           sha,
+		Branch:        in.Branch,
+		NewBranch:     in.NewBranch,
+		Mainline:      in.Mainline,
+		Title:         in.Title,
+		Message:       in.Message,
+		Committer:     identityFromPrincipal(bootstrap.NewSystemServiceSession().Principal),
+		CommitterDate: &now,
+	}
+
+	var output git.ApplyCommitOutput
+	if revert {
+		params.Author = identityFromPrincipal(session.Principal)
+		params.AuthorDate = &now
+		output, err = c.git.Revert(ctx, params)
+	} else {
+		output, err = c.git.CherryPick(ctx, params)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to %s commit: %w", operation, err)
+	}
+
+	if len(output.ConflictFiles) > 0 {
+		return nil, &types.MergeViolations{
+			ConflictFiles:  output.ConflictFiles,
+			RuleViolations: violations,
+		}, nil
+	}
+
+	response := &types.ApplyCommitResponse{
+		CommitID:       output.CommitSHA,
+		Branch:         output.Branch,
+		RuleViolations: violations,
+	}
+
+	if !in.OpenPullReq {
+		return response, nil, nil
+	}
+
+	commitOut, err := c.git.GetCommit(ctx, &git.GetCommitParams{
+		ReadParams: git.CreateReadParams(repo),
+		SHA:        output.CommitSHA,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get %s commit: %w", operation, err)
+	}
+
+	response.PullReq, err = c.pullreqCtrl.Create(ctx, session, repoRef, &pullreq.CreateInput{
+		Title:        commitOut.Commit.Title,
+		Description:  strings.TrimSpace(strings.TrimPrefix(commitOut.Commit.Message, commitOut.Commit.Title)),
+		SourceBranch: output.Branch,
+		TargetBranch: in.Branch,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open pull request for %s: %w", operation, err)
+	}
+
+	return response, nil, nil
+}
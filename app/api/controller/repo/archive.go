@@ -0,0 +1,221 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/blob"
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git"
+	gitenum "github.com/harness/gitness/git/enum"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const archiveBucketPathFmt = "archives/%d/%s/%s.%s"
+
+type ArchiveInput struct {
+	GitRef string
+	Format gitenum.ArchiveFormat
+	// Prefix is prepended to all file paths in the archive.
+	Prefix string
+	// Paths optionally limits the archive content to the provided paths.
+	Paths []string
+}
+
+func (in *ArchiveInput) sanitize() error {
+	if _, ok := in.Format.Sanitize(); !ok {
+		return usererror.BadRequestf("Unsupported archive format '%s'.", in.Format)
+	}
+
+	if strings.HasPrefix(in.Prefix, "/") || strings.Contains(in.Prefix, "..") {
+		return usererror.BadRequest("Archive prefix must be a relative path.")
+	}
+
+	if in.Prefix != "" && !strings.HasSuffix(in.Prefix, "/") {
+		in.Prefix += "/"
+	}
+
+	for i := range in.Paths {
+		in.Paths[i] = strings.Trim(in.Paths[i], "/")
+		if in.Paths[i] == "" {
+			return usererror.BadRequest("Archive path can't be empty.")
+		}
+	}
+
+	return nil
+}
+
+// Archive writes an archive of the repository content at the provided git reference to the writer.
+// If no git reference is provided, the archive is created from the default branch.
+// Archives of tags are cached in the blob store.
+func (c *Controller) Archive(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *ArchiveInput,
+	w io.Writer,
+) error {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView, true)
+	if err != nil {
+		return err
+	}
+
+	if err = in.sanitize(); err != nil {
+		return err
+	}
+
+	if in.GitRef == "" {
+		in.GitRef = repo.DefaultBranch
+	}
+
+	readParams := git.CreateReadParams(repo)
+
+	commits, err := c.git.ListCommits(ctx, &git.ListCommitsParams{
+		ReadParams: readParams,
+		GitREF:     in.GitRef,
+		Page:       1,
+		Limit:      1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to resolve git reference '%s': %w", in.GitRef, err)
+	}
+	if len(commits.Commits) == 0 {
+		return usererror.NotFound(fmt.Sprintf("Git reference '%s' not found.", in.GitRef))
+	}
+
+	commitSHA := commits.Commits[0].SHA
+
+	// make sure all paths exist, because git archive would fail in the middle of streaming otherwise.
+	for _, path := range in.Paths {
+		_, err = c.git.GetTreeNode(ctx, &git.GetTreeNodeParams{
+			ReadParams: readParams,
+			GitREF:     commitSHA,
+			Path:       path,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to find path '%s': %w", path, err)
+		}
+	}
+
+	params := &git.ArchiveParams{
+		ReadParams: readParams,
+		Treeish:    commitSHA,
+		Format:     in.Format,
+		Prefix:     in.Prefix,
+		Paths:      in.Paths,
+	}
+
+	if !c.isTag(ctx, repo, in.GitRef) {
+		return c.git.Archive(ctx, params, w)
+	}
+
+	return c.archiveCached(ctx, repo, params, w)
+}
+
+// archiveCached writes the archive from the blob store if it's present there,
+// otherwise the archive is generated and stored in the blob store for subsequent requests.
+func (c *Controller) archiveCached(
+	ctx context.Context,
+	repo *types.Repository,
+	params *git.ArchiveParams,
+	w io.Writer,
+) error {
+	bucketPath := getArchiveBucketPath(repo.ID, params)
+
+	cached, err := c.blobStore.Download(ctx, bucketPath)
+	if err == nil {
+		defer func() {
+			if errClose := cached.Close(); errClose != nil {
+				log.Ctx(ctx).Warn().Err(errClose).Msgf("failed to close cached archive reader")
+			}
+		}()
+
+		if _, err = io.Copy(w, cached); err != nil {
+			return fmt.Errorf("failed to copy cached archive: %w", err)
+		}
+
+		return nil
+	}
+	if !errors.Is(err, blob.ErrNotFound) {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to download cached archive '%s'", bucketPath)
+	}
+
+	file, err := os.CreateTemp("", "gitness-archive-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary archive file: %w", err)
+	}
+
+	defer func() {
+		_ = file.Close()
+		if errRemove := os.Remove(file.Name()); errRemove != nil {
+			log.Ctx(ctx).Warn().Err(errRemove).Msgf("failed to remove temporary archive file")
+		}
+	}()
+
+	if err = c.git.Archive(ctx, params, io.MultiWriter(w, file)); err != nil {
+		return err
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to rewind temporary archive file")
+		return nil
+	}
+
+	if err = c.blobStore.Upload(ctx, file, bucketPath); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to upload archive '%s' to blob store", bucketPath)
+	}
+
+	return nil
+}
+
+func (c *Controller) isTag(ctx context.Context, repo *types.Repository, gitRef string) bool {
+	_, err := c.git.GetRef(ctx, git.GetRefParams{
+		ReadParams: git.CreateReadParams(repo),
+		Name:       strings.TrimPrefix(gitRef, "refs/tags/"),
+		Type:       gitenum.RefTypeTag,
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		log.Ctx(ctx).Warn().Err(err).Msgf("failed to check if '%s' is a tag", gitRef)
+	}
+
+	return err == nil
+}
+
+// getArchiveBucketPath returns the blob store path of an archive. The path depends on
+// the commit and all archive options, so that different archives of the same commit don't collide.
+func getArchiveBucketPath(repoID int64, params *git.ArchiveParams) string {
+	h := sha256.New()
+	h.Write([]byte(params.Prefix))
+	for _, path := range params.Paths {
+		h.Write([]byte{0})
+		h.Write([]byte(path))
+	}
+
+	optionsHash := hex.EncodeToString(h.Sum(nil)[:16])
+
+	return fmt.Sprintf(archiveBucketPathFmt, repoID, params.Treeish, optionsHash, params.Format)
+}
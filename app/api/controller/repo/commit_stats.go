@@ -0,0 +1,124 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// ListContributors lists the commit authors of a repo ordered by their number of commits, the most active first.
+func (c *Controller) ListContributors(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	gitRef string,
+	filter *types.CommitStatsFilter,
+) ([]types.ContributorStats, int, error) {
+	stats, err := c.getCommitStats(ctx, session, repoRef, gitRef, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := len(stats.Contributors)
+
+	start := (filter.Page - 1) * filter.Limit
+	if start > total {
+		start = total
+	}
+	end := start + filter.Limit
+	if end > total {
+		end = total
+	}
+
+	contributors := make([]types.ContributorStats, 0, end-start)
+	for i := start; i < end; i++ {
+		contributor := &stats.Contributors[i]
+		contributors = append(contributors, types.ContributorStats{
+			Author: types.Identity{
+				Name:  contributor.Author.Name,
+				Email: contributor.Author.Email,
+			},
+			Commits:   contributor.Commits,
+			Additions: contributor.Additions,
+			Deletions: contributor.Deletions,
+			Weeks:     mapWeeklyCommitStats(contributor.Weeks),
+		})
+	}
+
+	return contributors, total, nil
+}
+
+// CommitActivity returns the number of commits, added and deleted lines per week of a repo.
+func (c *Controller) CommitActivity(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	gitRef string,
+	filter *types.CommitStatsFilter,
+) ([]types.WeeklyCommitStats, error) {
+	stats, err := c.getCommitStats(ctx, session, repoRef, gitRef, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapWeeklyCommitStats(stats.Weeks), nil
+}
+
+func (c *Controller) getCommitStats(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	gitRef string,
+	filter *types.CommitStatsFilter,
+) (*git.CommitStatsOutput, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// set gitRef to default branch in case an empty reference was provided
+	if gitRef == "" {
+		gitRef = repo.DefaultBranch
+	}
+
+	stats, err := c.git.GetCommitStats(ctx, &git.CommitStatsParams{
+		ReadParams: git.CreateReadParams(repo),
+		GitREF:     gitRef,
+		Since:      filter.Since,
+		Until:      filter.Until,
+		Committer:  filter.Committer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+func mapWeeklyCommitStats(weeks []git.WeeklyCommitStats) []types.WeeklyCommitStats {
+	out := make([]types.WeeklyCommitStats, len(weeks))
+	for i := range weeks {
+		out[i] = types.WeeklyCommitStats{
+			Week:      weeks[i].Week,
+			Commits:   weeks[i].Commits,
+			Additions: weeks[i].Additions,
+			Deletions: weeks[i].Deletions,
+		}
+	}
+	return out
+}
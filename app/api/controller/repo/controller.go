@@ -22,16 +22,22 @@ import (
 
 	apiauth "github.com/harness/gitness/app/api/auth"
 	"github.com/harness/gitness/app/api/controller/limiter"
+	"github.com/harness/gitness/app/api/controller/pullreq"
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/app/auth/authz"
 	repoevents "github.com/harness/gitness/app/events/repo"
 	"github.com/harness/gitness/app/services/codeowners"
+	"github.com/harness/gitness/app/services/housekeeping"
 	"github.com/harness/gitness/app/services/importer"
 	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/app/services/languagestats"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/signing"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/blob"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/store/database/dbtx"
 	"github.com/harness/gitness/types"
@@ -64,6 +70,12 @@ type Controller struct {
 	eventReporter      *repoevents.Reporter
 	indexer            keywordsearch.Indexer
 	resourceLimiter    limiter.ResourceLimiter
+	labelService       *label.Service
+	blobStore          blob.Store
+	signatureVerifier  *signing.Verifier
+	pullreqCtrl        *pullreq.Controller
+	languageStats      *languagestats.Service
+	housekeeping       *housekeeping.Service
 }
 
 func NewController(
@@ -85,6 +97,12 @@ func NewController(
 	eventReporter *repoevents.Reporter,
 	indexer keywordsearch.Indexer,
 	limiter limiter.ResourceLimiter,
+	labelService *label.Service,
+	blobStore blob.Store,
+	signatureVerifier *signing.Verifier,
+	pullreqCtrl *pullreq.Controller,
+	languageStats *languagestats.Service,
+	housekeeping *housekeeping.Service,
 ) *Controller {
 	return &Controller{
 		defaultBranch:                 config.Git.DefaultBranch,
@@ -106,6 +124,12 @@ func NewController(
 		eventReporter:                 eventReporter,
 		indexer:                       indexer,
 		resourceLimiter:               limiter,
+		labelService:                  labelService,
+		blobStore:                     blobStore,
+		signatureVerifier:             signatureVerifier,
+		pullreqCtrl:                   pullreqCtrl,
+		languageStats:                 languageStats,
+		housekeeping:                  housekeeping,
 	}
 }
 
@@ -16,10 +16,12 @@ package repo
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"strings"
 
 	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/controller"
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/git"
@@ -32,6 +34,7 @@ func (c *Controller) RawDiff(
 	session *auth.Session,
 	repoRef string,
 	path string,
+	diffOptions *types.DiffOptions,
 	w io.Writer,
 ) error {
 	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView, true)
@@ -44,11 +47,18 @@ func (c *Controller) RawDiff(
 		return err
 	}
 
+	headRepo, err := c.getHeadRepoCheckAccess(ctx, session, repo, info)
+	if err != nil {
+		return err
+	}
+
 	return c.git.RawDiff(ctx, &git.DiffParams{
-		ReadParams: git.CreateReadParams(repo),
-		BaseRef:    info.BaseRef,
-		HeadRef:    info.HeadRef,
-		MergeBase:  info.MergeBase,
+		ReadParams:  git.CreateReadParams(repo),
+		DiffOptions: controller.MapDiffOptions(diffOptions),
+		BaseRef:     info.BaseRef,
+		HeadRepoUID: headRepo.GitUID,
+		HeadRef:     info.HeadRef,
+		MergeBase:   info.MergeBase,
 	}, w)
 }
 
@@ -57,6 +67,7 @@ func (c *Controller) CommitDiff(
 	session *auth.Session,
 	repoRef string,
 	sha string,
+	diffOptions *types.DiffOptions,
 	w io.Writer,
 ) error {
 	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView, true)
@@ -64,18 +75,23 @@ func (c *Controller) CommitDiff(
 		return err
 	}
 
-	return c.git.CommitDiff(ctx, &git.GetCommitParams{
-		ReadParams: git.CreateReadParams(repo),
-		SHA:        sha,
+	return c.git.CommitDiff(ctx, &git.CommitDiffParams{
+		ReadParams:  git.CreateReadParams(repo),
+		DiffOptions: controller.MapDiffOptions(diffOptions),
+		SHA:         sha,
 	}, w)
 }
 
 type CompareInfo struct {
-	BaseRef   string
-	HeadRef   string
-	MergeBase bool
+	BaseRef string
+	// HeadRepoRef is the reference of the repo containing the head ref (empty if it's the same repo).
+	HeadRepoRef string
+	HeadRef     string
+	MergeBase   bool
 }
 
+// parseDiffPath parses a diff path of the format `base..head` or `base...head`.
+// The head can be prefixed with the reference of another repo, e.g. `main...space/fork:feature`.
 func parseDiffPath(path string) (CompareInfo, error) {
 	infos := strings.SplitN(path, "...", 2)
 	if len(infos) != 2 {
@@ -84,13 +100,43 @@ func parseDiffPath(path string) (CompareInfo, error) {
 	if len(infos) != 2 {
 		return CompareInfo{}, usererror.BadRequestf("invalid format \"%s\"", path)
 	}
+
+	headRepoRef, headRef, found := strings.Cut(infos[1], ":")
+	if !found {
+		headRepoRef, headRef = "", infos[1]
+	}
+	if found && (headRepoRef == "" || headRef == "") {
+		return CompareInfo{}, usererror.BadRequestf("invalid format \"%s\"", path)
+	}
+
 	return CompareInfo{
-		BaseRef:   infos[0],
-		HeadRef:   infos[1],
-		MergeBase: strings.Contains(path, "..."),
+		BaseRef:     infos[0],
+		HeadRepoRef: headRepoRef,
+		HeadRef:     headRef,
+		MergeBase:   strings.Contains(path, "..."),
 	}, nil
 }
 
+// getHeadRepoCheckAccess returns the repo containing the head ref of the comparison
+// after verifying that the session has read access to it.
+func (c *Controller) getHeadRepoCheckAccess(
+	ctx context.Context,
+	session *auth.Session,
+	repo *types.Repository,
+	info CompareInfo,
+) (*types.Repository, error) {
+	if info.HeadRepoRef == "" {
+		return repo, nil
+	}
+
+	headRepo, err := c.getRepoCheckAccess(ctx, session, info.HeadRepoRef, enum.PermissionRepoView, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access head repository: %w", err)
+	}
+
+	return headRepo, nil
+}
+
 func (c *Controller) DiffStats(
 	ctx context.Context,
 	session *auth.Session,
@@ -111,11 +157,17 @@ func (c *Controller) DiffStats(
 		return types.DiffStats{}, err
 	}
 
+	headRepo, err := c.getHeadRepoCheckAccess(ctx, session, repo, info)
+	if err != nil {
+		return types.DiffStats{}, err
+	}
+
 	output, err := c.git.DiffStats(ctx, &git.DiffParams{
-		ReadParams: git.CreateReadParams(repo),
-		BaseRef:    info.BaseRef,
-		HeadRef:    info.HeadRef,
-		MergeBase:  info.MergeBase,
+		ReadParams:  git.CreateReadParams(repo),
+		BaseRef:     info.BaseRef,
+		HeadRepoUID: headRepo.GitUID,
+		HeadRef:     info.HeadRef,
+		MergeBase:   info.MergeBase,
 	})
 	if err != nil {
 		return types.DiffStats{}, err
@@ -130,6 +182,7 @@ func (c *Controller) Diff(
 	repoRef string,
 	path string,
 	includePatch bool,
+	diffOptions *types.DiffOptions,
 ) (types.Stream[*git.FileDiff], error) {
 	repo, err := c.repoStore.FindByRef(ctx, repoRef)
 	if err != nil {
@@ -145,9 +198,16 @@ func (c *Controller) Diff(
 		return nil, err
 	}
 
+	headRepo, err := c.getHeadRepoCheckAccess(ctx, session, repo, info)
+	if err != nil {
+		return nil, err
+	}
+
 	reader := git.NewStreamReader(c.git.Diff(ctx, &git.DiffParams{
 		ReadParams:   git.CreateReadParams(repo),
+		DiffOptions:  controller.MapDiffOptions(diffOptions),
 		BaseRef:      info.BaseRef,
+		HeadRepoUID:  headRepo.GitUID,
 		HeadRef:      info.HeadRef,
 		MergeBase:    info.MergeBase,
 		IncludePatch: includePatch,
@@ -155,3 +215,41 @@ func (c *Controller) Diff(
 
 	return reader, nil
 }
+
+// DiffCommits lists the commits of the head ref that aren't part of the base ref.
+func (c *Controller) DiffCommits(
+	ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	path string,
+	pagination types.Pagination,
+) (types.ListCommitResponse, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView, true)
+	if err != nil {
+		return types.ListCommitResponse{}, err
+	}
+
+	info, err := parseDiffPath(path)
+	if err != nil {
+		return types.ListCommitResponse{}, err
+	}
+
+	headRepo, err := c.getHeadRepoCheckAccess(ctx, session, repo, info)
+	if err != nil {
+		return types.ListCommitResponse{}, err
+	}
+
+	rpcOut, err := c.git.ListCommits(ctx, &git.ListCommitsParams{
+		ReadParams:    git.CreateReadParams(repo),
+		GitREF:        info.HeadRef,
+		GitREFRepoUID: headRepo.GitUID,
+		After:         info.BaseRef,
+		Page:          int32(pagination.Page),
+		Limit:         int32(pagination.Size),
+	})
+	if err != nil {
+		return types.ListCommitResponse{}, err
+	}
+
+	return c.mapListCommitsOutput(ctx, rpcOut)
+}
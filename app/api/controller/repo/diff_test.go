@@ -0,0 +1,159 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/services/signing"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/git/adapter"
+	"github.com/harness/gitness/git/storage"
+	gittypes "github.com/harness/gitness/git/types"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiffPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    CompareInfo
+		wantErr bool
+	}{
+		{path: "main..feature", want: CompareInfo{BaseRef: "main", HeadRef: "feature"}},
+		{path: "main...feature", want: CompareInfo{BaseRef: "main", HeadRef: "feature", MergeBase: true}},
+		{
+			path: "main...space/fork:feature",
+			want: CompareInfo{BaseRef: "main", HeadRepoRef: "space/fork", HeadRef: "feature", MergeBase: true},
+		},
+		{
+			path: "main..space/fork:feature/a",
+			want: CompareInfo{BaseRef: "main", HeadRepoRef: "space/fork", HeadRef: "feature/a"},
+		},
+		{path: "main", wantErr: true},
+		{path: "main...:feature", wantErr: true},
+		{path: "main...space/fork:", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			info, err := parseDiffPath(test.path)
+			if test.wantErr {
+				requireUserError(t, err, http.StatusBadRequest)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.want, info)
+		})
+	}
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	return strings.TrimSpace(string(out))
+}
+
+func TestController_CompareForks(t *testing.T) {
+	ctx := context.Background()
+	session := testSession()
+	root := t.TempDir()
+
+	gitAdapter, err := adapter.New(gittypes.Config{}, adapter.NewInMemoryLastCommitCache(time.Minute), nil)
+	require.NoError(t, err)
+	gitService, err := git.New(gittypes.Config{Root: root, TmpDir: t.TempDir()}, gitAdapter,
+		storage.NewLocalStore())
+	require.NoError(t, err)
+
+	// the fork contains a commit on the feature branch that doesn't exist in the upstream repo.
+	work := filepath.Join(root, "work")
+	runGit(t, root, "init", "-b", "main", work)
+	require.NoError(t, os.WriteFile(filepath.Join(work, "file.txt"), []byte("base\n"), 0o600))
+	runGit(t, work, "add", "file.txt")
+	runGit(t, work, "commit", "-m", "base")
+	runGit(t, root, "clone", "--bare", work, filepath.Join(root, "repos", "ab", "cd", "efghij.git"))
+	runGit(t, work, "checkout", "-b", "feature")
+	require.NoError(t, os.WriteFile(filepath.Join(work, "file.txt"), []byte("fork\n"), 0o600))
+	runGit(t, work, "commit", "-am", "fork change")
+	forkSHA := runGit(t, work, "rev-parse", "HEAD")
+	runGit(t, root, "clone", "--bare", work, filepath.Join(root, "repos", "kl", "mn", "opqrst.git"))
+
+	c := &Controller{
+		authorizer: authorizerMock{denied: map[string]bool{"private": true}},
+		repoStore: repoStoreMock{repos: map[string]*types.Repository{
+			"space/repo":    {ID: 1, GitUID: "abcdefghij", Path: "space/repo"},
+			"space/fork":    {ID: 2, GitUID: "klmnopqrst", Path: "space/fork"},
+			"space/private": {ID: 3, GitUID: "klmnopqrst", Path: "space/private"},
+		}},
+		git:               gitService,
+		signatureVerifier: signing.NewVerifier(gitService, nil, nil, "system"),
+	}
+
+	commits, err := c.DiffCommits(ctx, session, "space/repo", "main...space/fork:feature",
+		types.Pagination{Page: 1, Size: 10})
+	require.NoError(t, err)
+	require.Len(t, commits.Commits, 1)
+	require.Equal(t, forkSHA, commits.Commits[0].SHA)
+	require.Equal(t, "fork change", commits.Commits[0].Title)
+
+	// the head ref is resolved in the fork, not in the upstream repo.
+	_, err = c.DiffCommits(ctx, session, "space/repo", "main...feature", types.Pagination{Page: 1, Size: 10})
+	require.Error(t, err)
+
+	w := &bytes.Buffer{}
+	err = c.RawDiff(ctx, session, "space/repo", "main...space/fork:feature", nil, w)
+	require.NoError(t, err)
+	require.Contains(t, w.String(), "-base\n+fork\n")
+
+	stats, err := c.DiffStats(ctx, session, "space/repo", "main...space/fork:feature")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, *stats.Commits)
+	require.EqualValues(t, 1, *stats.FilesChanged)
+
+	// the head repo requires read access as well.
+	_, err = c.DiffCommits(ctx, session, "space/repo", "main...space/private:feature",
+		types.Pagination{Page: 1, Size: 10})
+	require.ErrorIs(t, err, apiauth.ErrNotAuthorized)
+	require.Equal(t, http.StatusForbidden, usererror.Translate(err).Status)
+
+	err = c.RawDiff(ctx, session, "space/repo", "main...space/private:feature", nil, &bytes.Buffer{})
+	require.ErrorIs(t, err, apiauth.ErrNotAuthorized)
+
+	_, err = c.DiffStats(ctx, session, "space/repo", "main...space/unknown:feature")
+	require.ErrorIs(t, err, gitness_store.ErrResourceNotFound)
+	require.Equal(t, http.StatusNotFound, usererror.Translate(err).Status)
+}
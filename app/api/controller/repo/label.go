@@ -0,0 +1,96 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// LabelDefine defines a new repository-level label.
+func (c *Controller) LabelDefine(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	in *label.DefineInput,
+) (*types.Label, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.labelService.Define(ctx, session.Principal.ID, nil, &repo.ID, in)
+}
+
+// LabelFind returns a repository-level label by name.
+func (c *Controller) LabelFind(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	name string,
+) (*types.Label, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.labelService.Find(ctx, nil, &repo.ID, name)
+}
+
+// LabelUpdate updates a repository-level label.
+func (c *Controller) LabelUpdate(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	name string,
+	in *label.UpdateInput,
+) (*types.Label, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.labelService.Update(ctx, session.Principal.ID, nil, &repo.ID, name, in)
+}
+
+// LabelDelete deletes a repository-level label.
+func (c *Controller) LabelDelete(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	name string,
+) error {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoEdit, false)
+	if err != nil {
+		return err
+	}
+
+	return c.labelService.Delete(ctx, nil, &repo.ID, name)
+}
+
+// LabelList returns labels available in a repository.
+// If requested, the list includes labels inherited from the parent spaces.
+func (c *Controller) LabelList(ctx context.Context,
+	session *auth.Session,
+	repoRef string,
+	filter *types.LabelFilter,
+) ([]*types.Label, error) {
+	repo, err := c.getRepoCheckAccess(ctx, session, repoRef, enum.PermissionRepoView, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.labelService.ListRepo(ctx, repo, filter)
+}
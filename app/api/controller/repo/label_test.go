@@ -0,0 +1,262 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/require"
+)
+
+// authorizerMock grants all permissions except for the denied repositories (by name).
+type authorizerMock struct {
+	denied map[string]bool
+}
+
+func (a authorizerMock) Check(_ context.Context, _ *auth.Session, _ *types.Scope, resource *types.Resource,
+	_ enum.Permission) (bool, error) {
+	return !a.denied[resource.Name], nil
+}
+
+func (a authorizerMock) CheckAll(ctx context.Context, session *auth.Session,
+	checks ...types.PermissionCheck) (bool, error) {
+	for _, check := range checks {
+		if ok, _ := a.Check(ctx, session, &check.Scope, &check.Resource, check.Permission); !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+type repoStoreMock struct {
+	store.RepoStore
+	repos map[string]*types.Repository
+}
+
+func (s repoStoreMock) FindByRef(_ context.Context, repoRef string) (*types.Repository, error) {
+	repo, ok := s.repos[repoRef]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	return repo, nil
+}
+
+type spaceStoreMock struct {
+	store.SpaceStore
+}
+
+func (spaceStoreMock) GetAncestorIDs(_ context.Context, spaceID int64) ([]int64, error) {
+	return []int64{spaceID}, nil
+}
+
+// labelStoreMock is an in-memory label store with the same uniqueness constraints as the database.
+type labelStoreMock struct {
+	store.LabelStore
+	labels []*types.Label
+}
+
+func (s *labelStoreMock) inScope(l *types.Label, spaceID, repoID *int64) bool {
+	return (spaceID == nil || (l.SpaceID != nil && *l.SpaceID == *spaceID)) &&
+		(repoID == nil || (l.RepoID != nil && *l.RepoID == *repoID))
+}
+
+func (s *labelStoreMock) FindByName(_ context.Context, spaceID, repoID *int64, name string) (*types.Label, error) {
+	for _, l := range s.labels {
+		if s.inScope(l, spaceID, repoID) && strings.EqualFold(l.Name, name) {
+			c := *l
+			return &c, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+func (s *labelStoreMock) Create(ctx context.Context, l *types.Label) error {
+	if _, err := s.FindByName(ctx, l.SpaceID, l.RepoID, l.Name); err == nil {
+		return gitness_store.ErrDuplicate
+	}
+	l.ID = int64(len(s.labels) + 1)
+	c := *l
+	s.labels = append(s.labels, &c)
+	return nil
+}
+
+func (s *labelStoreMock) Update(ctx context.Context, l *types.Label) error {
+	if existing, err := s.FindByName(ctx, l.SpaceID, l.RepoID, l.Name); err == nil && existing.ID != l.ID {
+		return gitness_store.ErrDuplicate
+	}
+	for i := range s.labels {
+		if s.labels[i].ID == l.ID {
+			c := *l
+			s.labels[i] = &c
+		}
+	}
+	return nil
+}
+
+func (s *labelStoreMock) Delete(_ context.Context, spaceID, repoID *int64, name string) error {
+	for i, l := range s.labels {
+		if s.inScope(l, spaceID, repoID) && strings.EqualFold(l.Name, name) {
+			s.labels = append(s.labels[:i], s.labels[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *labelStoreMock) list(match func(l *types.Label) bool) []*types.Label {
+	labels := []*types.Label{}
+	for _, l := range s.labels {
+		if match(l) {
+			labels = append(labels, l)
+		}
+	}
+	sort.Slice(labels, func(i, j int) bool { return strings.ToLower(labels[i].Name) < strings.ToLower(labels[j].Name) })
+	return labels
+}
+
+func (s *labelStoreMock) List(_ context.Context, spaceID, repoID *int64, _ *types.LabelFilter) ([]*types.Label, error) {
+	return s.list(func(l *types.Label) bool { return s.inScope(l, spaceID, repoID) }), nil
+}
+
+func (s *labelStoreMock) ListInScopes(_ context.Context,
+	repoID int64, spaceIDs []int64, _ *types.LabelFilter) ([]*types.Label, error) {
+	return s.list(func(l *types.Label) bool {
+		if l.RepoID != nil {
+			return *l.RepoID == repoID
+		}
+		for _, spaceID := range spaceIDs {
+			if *l.SpaceID == spaceID {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+func testSession() *auth.Session {
+	return &auth.Session{
+		Principal: types.Principal{ID: 1, UID: "user", Type: enum.PrincipalTypeUser},
+	}
+}
+
+func requireUserError(t *testing.T, err error, status int) {
+	t.Helper()
+
+	var uErr *usererror.Error
+	require.True(t, errors.As(err, &uErr), "expected user error, got: %v", err)
+	require.Equal(t, status, uErr.Status)
+}
+
+func TestController_Labels(t *testing.T) {
+	ctx := context.Background()
+	session := testSession()
+
+	spaceID := int64(1)
+	labelStore := &labelStoreMock{labels: []*types.Label{
+		{ID: 100, SpaceID: &spaceID, Name: "inherited", Color: enum.LabelColorGray},
+	}}
+	c := &Controller{
+		authorizer: authorizerMock{denied: map[string]bool{"private": true}},
+		repoStore: repoStoreMock{repos: map[string]*types.Repository{
+			"space/repo":    {ID: 1, ParentID: spaceID, Path: "space/repo"},
+			"space/private": {ID: 2, ParentID: spaceID, Path: "space/private"},
+		}},
+		labelService: label.NewService(labelStore, nil, spaceStoreMock{}),
+	}
+
+	created, err := c.LabelDefine(ctx, session, "space/repo", &label.DefineInput{
+		Name:        " bug ",
+		Description: "Something isn't working",
+		Color:       enum.LabelColorRed,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "bug", created.Name)
+	require.Equal(t, int64(1), *created.RepoID)
+	require.Nil(t, created.SpaceID)
+	require.Equal(t, session.Principal.ID, created.CreatedBy)
+
+	// the color defaults to gray.
+	created, err = c.LabelDefine(ctx, session, "space/repo", &label.DefineInput{Name: "feature"})
+	require.NoError(t, err)
+	require.Equal(t, enum.LabelColorGray, created.Color)
+
+	_, err = c.LabelDefine(ctx, session, "space/repo", &label.DefineInput{Name: "BUG"})
+	requireUserError(t, err, http.StatusConflict)
+
+	_, err = c.LabelDefine(ctx, session, "space/repo", &label.DefineInput{Name: "a/b"})
+	requireUserError(t, err, http.StatusBadRequest)
+
+	_, err = c.LabelDefine(ctx, session, "space/repo", &label.DefineInput{Name: "x", Color: "black"})
+	requireUserError(t, err, http.StatusBadRequest)
+
+	// labels of repositories without access can't be managed.
+	_, err = c.LabelDefine(ctx, session, "space/private", &label.DefineInput{Name: "bug"})
+	require.ErrorIs(t, err, apiauth.ErrNotAuthorized)
+
+	found, err := c.LabelFind(ctx, session, "space/repo", "Bug")
+	require.NoError(t, err)
+	require.Equal(t, "Something isn't working", found.Description)
+
+	updated, err := c.LabelUpdate(ctx, session, "space/repo", "bug", &label.UpdateInput{
+		Name:  ptr.String("defect"),
+		Color: ptr.Of(enum.LabelColorOrange),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "defect", updated.Name)
+	require.Equal(t, enum.LabelColorOrange, updated.Color)
+	require.Equal(t, "Something isn't working", updated.Description)
+
+	_, err = c.LabelUpdate(ctx, session, "space/repo", "defect", &label.UpdateInput{Name: ptr.String("Feature")})
+	requireUserError(t, err, http.StatusConflict)
+
+	labelNames := func(labels []*types.Label) []string {
+		names := make([]string, len(labels))
+		for i, l := range labels {
+			names[i] = l.Name
+		}
+		return names
+	}
+
+	labels, err := c.LabelList(ctx, session, "space/repo", &types.LabelFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"defect", "feature"}, labelNames(labels))
+
+	labels, err = c.LabelList(ctx, session, "space/repo", &types.LabelFilter{Inherited: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"defect", "feature", "inherited"}, labelNames(labels))
+
+	require.NoError(t, c.LabelDelete(ctx, session, "space/repo", "DEFECT"))
+
+	_, err = c.LabelFind(ctx, session, "space/repo", "defect")
+	require.ErrorIs(t, err, gitness_store.ErrResourceNotFound)
+
+	err = c.LabelDelete(ctx, session, "space/repo", "defect")
+	require.ErrorIs(t, err, gitness_store.ErrResourceNotFound)
+}
@@ -33,6 +33,8 @@ type CommitTag struct {
 	Message     string           `json:"message,omitempty"`
 	Tagger      *types.Signature `json:"tagger,omitempty"`
 	Commit      *types.Commit    `json:"commit,omitempty"`
+	// Verification is the signature verification of annotated tags.
+	Verification *types.CommitVerification `json:"verification,omitempty"`
 }
 
 // ListCommitTags lists the commit tags of a repo.
@@ -60,12 +62,27 @@ func (c *Controller) ListCommitTags(ctx context.Context,
 		return nil, err
 	}
 
+	verification := c.signatureVerifier.NewSession()
 	tags := make([]CommitTag, len(rpcOut.Tags))
 	for i := range rpcOut.Tags {
 		tags[i], err = mapCommitTag(rpcOut.Tags[i])
 		if err != nil {
 			return nil, fmt.Errorf("failed to map CommitTag: %w", err)
 		}
+
+		if rpcOut.Tags[i].IsAnnotated {
+			tags[i].Verification, err = verification.VerifyTag(ctx, &rpcOut.Tags[i])
+			if err != nil {
+				return nil, fmt.Errorf("failed to verify tag signature: %w", err)
+			}
+		}
+
+		if rpcOut.Tags[i].Commit != nil {
+			tags[i].Commit.Verification, err = verification.VerifyCommit(ctx, rpcOut.Tags[i].Commit)
+			if err != nil {
+				return nil, fmt.Errorf("failed to verify commit signature: %w", err)
+			}
+		}
 	}
 
 	return tags, nil
@@ -43,20 +43,30 @@ func (c *Controller) ListCommits(ctx context.Context,
 	}
 
 	rpcOut, err := c.git.ListCommits(ctx, &git.ListCommitsParams{
-		ReadParams: git.CreateReadParams(repo),
-		GitREF:     gitRef,
-		After:      filter.After,
-		Page:       int32(filter.Page),
-		Limit:      int32(filter.Limit),
-		Path:       filter.Path,
-		Since:      filter.Since,
-		Until:      filter.Until,
-		Committer:  filter.Committer,
+		ReadParams:    git.CreateReadParams(repo),
+		GitREF:        gitRef,
+		After:         filter.After,
+		Page:          int32(filter.Page),
+		Limit:         int32(filter.Limit),
+		Path:          filter.Path,
+		Since:         filter.Since,
+		Until:         filter.Until,
+		Committer:     filter.Committer,
+		FollowRenames: filter.Follow,
 	})
 	if err != nil {
 		return types.ListCommitResponse{}, err
 	}
 
+	return c.mapListCommitsOutput(ctx, rpcOut)
+}
+
+func (c *Controller) mapListCommitsOutput(
+	ctx context.Context,
+	rpcOut *git.ListCommitsOutput,
+) (types.ListCommitResponse, error) {
+	var err error
+	verification := c.signatureVerifier.NewSession()
 	commits := make([]types.Commit, len(rpcOut.Commits))
 	for i := range rpcOut.Commits {
 		var commit *types.Commit
@@ -64,6 +74,16 @@ func (c *Controller) ListCommits(ctx context.Context,
 		if err != nil {
 			return types.ListCommitResponse{}, fmt.Errorf("failed to map commit: %w", err)
 		}
+
+		commit.Verification, err = verification.VerifyCommit(ctx, &rpcOut.Commits[i])
+		if err != nil {
+			return types.ListCommitResponse{}, fmt.Errorf("failed to verify commit signature: %w", err)
+		}
+
+		if len(rpcOut.Paths) > i {
+			commit.Path = rpcOut.Paths[i]
+		}
+
 		commits[i] = *commit
 	}
 
@@ -45,6 +45,11 @@ func (c *Controller) MergeCheck(
 		return MergeCheck{}, err
 	}
 
+	headRepo, err := c.getHeadRepoCheckAccess(ctx, session, repo, info)
+	if err != nil {
+		return MergeCheck{}, err
+	}
+
 	writeParams, err := controller.CreateRPCInternalWriteParams(ctx, c.urlProvider, session, repo)
 	if err != nil {
 		return MergeCheck{}, fmt.Errorf("failed to create rpc write params: %w", err)
@@ -53,7 +58,7 @@ func (c *Controller) MergeCheck(
 	mergeOutput, err := c.git.Merge(ctx, &git.MergeParams{
 		WriteParams: writeParams,
 		BaseBranch:  info.BaseRef,
-		HeadRepoUID: writeParams.RepoUID, // forks are not supported for now
+		HeadRepoUID: headRepo.GitUID,
 		HeadBranch:  info.HeadRef,
 	})
 	if err != nil {
@@ -16,14 +16,20 @@ package repo
 
 import (
 	"github.com/harness/gitness/app/api/controller/limiter"
+	"github.com/harness/gitness/app/api/controller/pullreq"
 	"github.com/harness/gitness/app/auth/authz"
 	repoevents "github.com/harness/gitness/app/events/repo"
 	"github.com/harness/gitness/app/services/codeowners"
+	"github.com/harness/gitness/app/services/housekeeping"
 	"github.com/harness/gitness/app/services/importer"
 	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/app/services/languagestats"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/signing"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/blob"
 	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/store/database/dbtx"
 	"github.com/harness/gitness/types"
@@ -56,10 +62,17 @@ func ProvideController(
 	reporeporter *repoevents.Reporter,
 	indexer keywordsearch.Indexer,
 	limiter limiter.ResourceLimiter,
+	labelService *label.Service,
+	blobStore blob.Store,
+	signatureVerifier *signing.Verifier,
+	pullreqCtrl *pullreq.Controller,
+	languageStats *languagestats.Service,
+	housekeeping *housekeeping.Service,
 ) *Controller {
 	return NewController(config, tx, urlProvider,
 		uidCheck, authorizer, repoStore,
 		spaceStore, pipelineStore,
 		principalStore, ruleStore, principalInfoCache, protectionManager,
-		rpcClient, importer, codeOwners, reporeporter, indexer, limiter)
+		rpcClient, importer, codeOwners, reporeporter, indexer, limiter, labelService, blobStore,
+		signatureVerifier, pullreqCtrl, languageStats, housekeeping)
 }
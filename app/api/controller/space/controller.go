@@ -19,8 +19,10 @@ import (
 	"github.com/harness/gitness/app/api/controller/repo"
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/pipeline/environ"
 	"github.com/harness/gitness/app/services/exporter"
 	"github.com/harness/gitness/app/services/importer"
+	"github.com/harness/gitness/app/services/label"
 	"github.com/harness/gitness/app/sse"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
@@ -57,6 +59,9 @@ type Controller struct {
 	importer        *importer.Repository
 	exporter        *exporter.Repository
 	resourceLimiter limiter.ResourceLimiter
+	labelService    *label.Service
+	envVarStore     store.SpaceEnvVarStore
+	environService  *environ.Service
 }
 
 func NewController(config *types.Config, tx dbtx.Transactor, urlProvider url.Provider,
@@ -65,7 +70,8 @@ func NewController(config *types.Config, tx dbtx.Transactor, urlProvider url.Pro
 	connectorStore store.ConnectorStore, templateStore store.TemplateStore, spaceStore store.SpaceStore,
 	repoStore store.RepoStore, principalStore store.PrincipalStore, repoCtrl *repo.Controller,
 	membershipStore store.MembershipStore, importer *importer.Repository, exporter *exporter.Repository,
-	limiter limiter.ResourceLimiter,
+	limiter limiter.ResourceLimiter, labelService *label.Service,
+	envVarStore store.SpaceEnvVarStore, environService *environ.Service,
 ) *Controller {
 	return &Controller{
 		nestedSpacesEnabled:           config.NestedSpacesEnabled,
@@ -88,5 +94,8 @@ func NewController(config *types.Config, tx dbtx.Transactor, urlProvider url.Pro
 		importer:                      importer,
 		exporter:                      exporter,
 		resourceLimiter:               limiter,
+		labelService:                  labelService,
+		envVarStore:                   envVarStore,
+		environService:                environService,
 	}
 }
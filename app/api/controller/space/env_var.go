@@ -0,0 +1,139 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+const envVarKeyMaxLength = 255
+
+var envVarKeyRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// EnvVarSetInput is used for setting a space environment variable.
+// Exactly one of Value and SecretUID has to be provided.
+type EnvVarSetInput struct {
+	Value     string `json:"value"`
+	SecretUID string `json:"secret_uid"`
+}
+
+func (in *EnvVarSetInput) sanitize(key string) error {
+	if len(key) > envVarKeyMaxLength || !envVarKeyRegex.MatchString(key) {
+		return usererror.BadRequestf(
+			"Environment variable key has to consist of letters, digits and underscores"+
+				" and can't start with a digit or be longer than %d characters.", envVarKeyMaxLength)
+	}
+
+	if strings.HasPrefix(strings.ToUpper(key), "DRONE_") || strings.HasPrefix(strings.ToUpper(key), "CI_") {
+		return usererror.BadRequest("Environment variable keys starting with DRONE_ or CI_ are reserved.")
+	}
+
+	in.SecretUID = strings.TrimSpace(in.SecretUID)
+
+	if (in.Value == "") == (in.SecretUID == "") {
+		return usererror.BadRequest("Either value or secret_uid has to be provided.")
+	}
+
+	return nil
+}
+
+// EnvVarList returns the pipeline environment variables of a space.
+// If requested, the list includes the variables inherited from the parent spaces.
+func (c *Controller) EnvVarList(ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	filter *types.SpaceEnvVarFilter,
+) ([]*types.SpaceEnvVar, error) {
+	space, err := c.getSpaceCheckAccess(ctx, session, spaceRef, enum.PermissionSpaceView, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Inherited {
+		return c.environService.ListInherited(ctx, space.ID)
+	}
+
+	return c.envVarStore.List(ctx, space.ID)
+}
+
+// EnvVarSet creates or updates a pipeline environment variable of a space.
+func (c *Controller) EnvVarSet(ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	key string,
+	in *EnvVarSetInput,
+) (*types.SpaceEnvVar, error) {
+	space, err := c.getSpaceCheckAccess(ctx, session, spaceRef, enum.PermissionSpaceEdit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = in.sanitize(key); err != nil {
+		return nil, err
+	}
+
+	if in.SecretUID != "" {
+		// the secret becomes available to the pipelines of all sub spaces.
+		err = apiauth.CheckSecret(ctx, c.authorizer, session, space.Path, in.SecretUID, enum.PermissionSecretAccess)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = c.secretStore.FindByUID(ctx, space.ID, in.SecretUID); err != nil {
+			return nil, fmt.Errorf("failed to find secret: %w", err)
+		}
+	}
+
+	now := time.Now().UnixMilli()
+	envVar := &types.SpaceEnvVar{
+		SpaceID:   space.ID,
+		Key:       key,
+		Value:     in.Value,
+		SecretUID: in.SecretUID,
+		CreatedBy: session.Principal.ID,
+		Created:   now,
+		Updated:   now,
+	}
+
+	if err = c.envVarStore.Upsert(ctx, envVar); err != nil {
+		return nil, fmt.Errorf("failed to save environment variable: %w", err)
+	}
+
+	return envVar, nil
+}
+
+// EnvVarDelete deletes a pipeline environment variable of a space.
+func (c *Controller) EnvVarDelete(ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	key string,
+) error {
+	space, err := c.getSpaceCheckAccess(ctx, session, spaceRef, enum.PermissionSpaceEdit, false)
+	if err != nil {
+		return err
+	}
+
+	return c.envVarStore.Delete(ctx, space.ID, key)
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"context"
+	"fmt"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+// LabelDefine defines a new space-level label.
+func (c *Controller) LabelDefine(ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	in *label.DefineInput,
+) (*types.Label, error) {
+	space, err := c.getSpaceCheckAccess(ctx, session, spaceRef, enum.PermissionSpaceEdit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.labelService.Define(ctx, session.Principal.ID, &space.ID, nil, in)
+}
+
+// LabelFind returns a space-level label by name.
+func (c *Controller) LabelFind(ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	name string,
+) (*types.Label, error) {
+	space, err := c.getSpaceCheckAccess(ctx, session, spaceRef, enum.PermissionSpaceView, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.labelService.Find(ctx, &space.ID, nil, name)
+}
+
+// LabelUpdate updates a space-level label.
+func (c *Controller) LabelUpdate(ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	name string,
+	in *label.UpdateInput,
+) (*types.Label, error) {
+	space, err := c.getSpaceCheckAccess(ctx, session, spaceRef, enum.PermissionSpaceEdit, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.labelService.Update(ctx, session.Principal.ID, &space.ID, nil, name, in)
+}
+
+// LabelDelete deletes a space-level label.
+func (c *Controller) LabelDelete(ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	name string,
+) error {
+	space, err := c.getSpaceCheckAccess(ctx, session, spaceRef, enum.PermissionSpaceEdit, false)
+	if err != nil {
+		return err
+	}
+
+	return c.labelService.Delete(ctx, &space.ID, nil, name)
+}
+
+// LabelList returns labels defined in a space.
+// If requested, the list includes labels inherited from the parent spaces.
+func (c *Controller) LabelList(ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	filter *types.LabelFilter,
+) ([]*types.Label, error) {
+	space, err := c.getSpaceCheckAccess(ctx, session, spaceRef, enum.PermissionSpaceView, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.labelService.ListSpace(ctx, space.ID, filter)
+}
+
+func (c *Controller) getSpaceCheckAccess(ctx context.Context,
+	session *auth.Session,
+	spaceRef string,
+	permission enum.Permission,
+	orPublic bool,
+) (*types.Space, error) {
+	space, err := c.spaceStore.FindByRef(ctx, spaceRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find space: %w", err)
+	}
+
+	if err = apiauth.CheckSpace(ctx, c.authorizer, session, space, permission, orPublic); err != nil {
+		return nil, fmt.Errorf("access check failed: %w", err)
+	}
+
+	return space, nil
+}
@@ -18,18 +18,22 @@ import (
 	"context"
 
 	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
 )
 
 type Controller struct {
 	principalStore store.PrincipalStore
 	config         *types.Config
+	git            git.Interface
 }
 
-func NewController(principalStore store.PrincipalStore, config *types.Config) *Controller {
+func NewController(principalStore store.PrincipalStore, config *types.Config, git git.Interface) *Controller {
 	return &Controller{
 		principalStore: principalStore,
 		config:         config,
+		git:            git,
 	}
 }
 
@@ -41,3 +45,16 @@ func (c *Controller) IsUserSignupAllowed(ctx context.Context) (bool, error) {
 
 	return usrCount == 0 || c.config.UserSignupEnabled, nil
 }
+
+// GetCommitSigningKey returns the public key used to sign commits created by the server (nil if not configured).
+func (c *Controller) GetCommitSigningKey() *types.CommitSigningKey {
+	key := c.git.GetSigningKey()
+	if key == nil {
+		return nil
+	}
+
+	return &types.CommitSigningKey{
+		Type:      enum.SigningKeyType(key.Type),
+		PublicKey: key.Content,
+	}
+}
@@ -16,6 +16,7 @@ package system
 
 import (
 	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/git"
 	"github.com/harness/gitness/types"
 
 	"github.com/google/wire"
@@ -26,6 +27,6 @@ var WireSet = wire.NewSet(
 	NewController,
 )
 
-func ProvideController(principalStore store.PrincipalStore, config *types.Config) *Controller {
-	return NewController(principalStore, config)
+func ProvideController(principalStore store.PrincipalStore, config *types.Config, git git.Interface) *Controller {
+	return NewController(principalStore, config, git)
 }
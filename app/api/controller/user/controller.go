@@ -17,7 +17,10 @@ package user
 import (
 	"context"
 
+	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/auth/oidc"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/store/database/dbtx"
 	"github.com/harness/gitness/types"
@@ -34,6 +37,14 @@ type Controller struct {
 	principalStore    store.PrincipalStore
 	tokenStore        store.TokenStore
 	membershipStore   store.MembershipStore
+	spaceStore        store.SpaceStore
+	identityStore     store.PrincipalIdentityStore
+	signingKeyStore   store.SigningKeyStore
+	config            *types.Config
+	oidcProvider      *oidc.Provider
+	oidcMemberships   []auth.GroupMembership
+	ldapDirectory     ldap.Directory
+	ldapMemberships   []auth.GroupMembership
 }
 
 func NewController(
@@ -43,6 +54,14 @@ func NewController(
 	principalStore store.PrincipalStore,
 	tokenStore store.TokenStore,
 	membershipStore store.MembershipStore,
+	spaceStore store.SpaceStore,
+	identityStore store.PrincipalIdentityStore,
+	signingKeyStore store.SigningKeyStore,
+	config *types.Config,
+	oidcProvider *oidc.Provider,
+	oidcMemberships []auth.GroupMembership,
+	ldapDirectory ldap.Directory,
+	ldapMemberships []auth.GroupMembership,
 ) *Controller {
 	return &Controller{
 		tx:                tx,
@@ -51,6 +70,14 @@ func NewController(
 		principalStore:    principalStore,
 		tokenStore:        tokenStore,
 		membershipStore:   membershipStore,
+		spaceStore:        spaceStore,
+		identityStore:     identityStore,
+		signingKeyStore:   signingKeyStore,
+		config:            config,
+		oidcProvider:      oidcProvider,
+		oidcMemberships:   oidcMemberships,
+		ldapDirectory:     ldapDirectory,
+		ldapMemberships:   ldapMemberships,
 	}
 }
 
@@ -0,0 +1,95 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/services/signing"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+	"github.com/harness/gitness/types/enum"
+)
+
+type CreateSigningKeyInput struct {
+	Type       enum.SigningKeyType `json:"type"`
+	Identifier string              `json:"identifier"`
+	Content    string              `json:"content"`
+}
+
+/*
+ * CreateSigningKey registers a new GPG or SSH key used to verify the signatures of the user's commits.
+ */
+func (c *Controller) CreateSigningKey(
+	ctx context.Context,
+	session *auth.Session,
+	userUID string,
+	in *CreateSigningKeyInput,
+) (*types.SigningKey, error) {
+	user, err := findUserFromUID(ctx, c.principalStore, userUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure principal has required permissions on parent
+	if err = apiauth.CheckUser(ctx, c.authorizer, session, user, enum.PermissionUserEdit); err != nil {
+		return nil, err
+	}
+
+	if err = check.UID(in.Identifier); err != nil {
+		return nil, err
+	}
+
+	keyType, ok := in.Type.Sanitize()
+	if !ok {
+		return nil, usererror.BadRequestf("Unknown signing key type '%s'.", in.Type)
+	}
+
+	content := strings.TrimSpace(in.Content)
+	fingerprint, err := signing.GetKeyFingerprint(keyType, content)
+	if errors.Is(err, signing.ErrInvalidKey) {
+		return nil, usererror.BadRequestf("Invalid %s public key: %s", keyType, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &types.SigningKey{
+		PrincipalID: user.ID,
+		Type:        keyType,
+		Identifier:  in.Identifier,
+		Fingerprint: fingerprint,
+		Content:     content,
+		Created:     time.Now().UnixMilli(),
+	}
+
+	err = c.signingKeyStore.Create(ctx, key)
+	if errors.Is(err, gitness_store.ErrDuplicate) {
+		return nil, usererror.Conflict("The signing key is already registered.")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	return key, nil
+}
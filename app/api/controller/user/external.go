@@ -0,0 +1,212 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/auth/oidc"
+	"github.com/harness/gitness/app/token"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxUIDAttempts is the max number of suffixes tried to find an unused UID for a new external user.
+	maxUIDAttempts = 100
+	// fallbackUID is used in case no valid UID can be derived from the external identity.
+	fallbackUID = "user"
+)
+
+var invalidUIDCharsRegex = regexp.MustCompile(`[^a-zA-Z0-9-_.]+`)
+
+// externalUser is a user authenticated by an external identity provider.
+type externalUser struct {
+	// Subject is the unique identifier of the user at the identity provider.
+	Subject string
+	Email   string
+	// EmailVerified specifies whether the identity provider verified the email of the user.
+	EmailVerified bool
+	UID           string
+	DisplayName   string
+	Groups        []string
+}
+
+// externalLoginOptions defines how users of an external identity provider are logged in.
+type externalLoginOptions struct {
+	// Provider uniquely identifies the identity provider.
+	Provider       string
+	AutoCreate     bool
+	AllowedDomains []string
+	Memberships    []auth.GroupMembership
+}
+
+// loginExternal logs in a user authenticated by an external identity provider and returns the session token.
+// Unknown identities are linked to the user with the same email, or if none exists a new user is created.
+// NOTE: Unknown identities are never linked to an existing user if the email isn't verified by the provider.
+func (c *Controller) loginExternal(
+	ctx context.Context,
+	opts externalLoginOptions,
+	in *externalUser,
+) (*types.TokenResponse, error) {
+	if err := checkEmailDomain(in.Email, opts.AllowedDomains); err != nil {
+		return nil, err
+	}
+
+	user, err := c.findOrCreateExternalUser(ctx, opts, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Blocked {
+		return nil, usererror.Forbidden("The user is blocked")
+	}
+
+	c.addGroupMemberships(ctx, opts.Provider, opts.Memberships, user, in.Groups)
+
+	tokenUID, err := generateSessionTokenUID()
+	if err != nil {
+		return nil, err
+	}
+	token, jwtToken, err := token.CreateUserSession(ctx, c.tokenStore, user, tokenUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.TokenResponse{Token: *token, AccessToken: jwtToken}, nil
+}
+
+func (c *Controller) findOrCreateExternalUser(
+	ctx context.Context,
+	opts externalLoginOptions,
+	in *externalUser,
+) (*types.User, error) {
+	now := time.Now().UnixMilli()
+
+	identity, err := c.identityStore.Find(ctx, opts.Provider, in.Subject)
+	if err == nil {
+		if err = c.identityStore.UpdateLastLogin(ctx, opts.Provider, in.Subject, now); err != nil {
+			return nil, fmt.Errorf("failed to update identity: %w", err)
+		}
+
+		return c.principalStore.FindUser(ctx, identity.PrincipalID)
+	}
+	if !errors.Is(err, store.ErrResourceNotFound) {
+		return nil, fmt.Errorf("failed to find identity: %w", err)
+	}
+
+	var user *types.User
+	err = c.tx.WithTx(ctx, func(ctx context.Context) error {
+		user, err = c.principalStore.FindUserByEmail(ctx, in.Email)
+		if errors.Is(err, store.ErrResourceNotFound) {
+			if !opts.AutoCreate {
+				return usererror.Forbidden("The user doesn't exist")
+			}
+
+			user, err = c.createExternalUser(ctx, in)
+		} else if err == nil && !in.EmailVerified {
+			// otherwise anyone able to set the email at the provider could take over the existing user.
+			return usererror.Forbidden("The email of the user isn't verified")
+		}
+		if err != nil {
+			return err
+		}
+
+		return c.identityStore.Create(ctx, &types.PrincipalIdentity{
+			Provider:    opts.Provider,
+			Subject:     in.Subject,
+			PrincipalID: user.ID,
+			Created:     now,
+			LastLogin:   now,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("user_uid", user.UID).
+		Str("identity_provider", opts.Provider).
+		Msg("linked external identity to user")
+
+	return user, nil
+}
+
+func (c *Controller) createExternalUser(ctx context.Context, in *externalUser) (*types.User, error) {
+	uid, err := c.availableUID(ctx, in.UID)
+	if err != nil {
+		return nil, err
+	}
+
+	displayName := strings.TrimSpace(in.DisplayName)
+	if check.DisplayName(displayName) != nil {
+		displayName = uid
+	}
+
+	// external users authenticate via their identity provider, the password is never handed out.
+	password, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := c.CreateNoAuth(ctx, &CreateInput{
+		UID:         uid,
+		Email:       in.Email,
+		DisplayName: displayName,
+		Password:    password,
+	}, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return user, nil
+}
+
+// availableUID derives a valid UID that isn't used yet from the provided one.
+func (c *Controller) availableUID(ctx context.Context, uid string) (string, error) {
+	uid = invalidUIDCharsRegex.ReplaceAllString(uid, "-")
+	uid = strings.TrimLeft(uid, "-.0123456789")
+	if len(uid) > check.MaxUIDLength-4 {
+		uid = uid[:check.MaxUIDLength-4]
+	}
+	if c.principalUIDCheck(uid) != nil {
+		uid = fallbackUID
+	}
+
+	candidate := uid
+	for i := 1; i <= maxUIDAttempts; i++ {
+		_, err := c.principalStore.FindByUID(ctx, candidate)
+		if errors.Is(err, store.ErrResourceNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to find principal: %w", err)
+		}
+
+		candidate = fmt.Sprintf("%s-%d", uid, i)
+	}
+
+	return "", usererror.Conflict("Unable to find an available user identifier")
+}
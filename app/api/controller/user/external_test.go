@@ -0,0 +1,361 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+
+	"github.com/stretchr/testify/require"
+)
+
+type principalStoreMock struct {
+	store.PrincipalStore
+	users []*types.User
+}
+
+func (s *principalStoreMock) FindUser(_ context.Context, id int64) (*types.User, error) {
+	for _, user := range s.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+func (s *principalStoreMock) FindUserByEmail(_ context.Context, email string) (*types.User, error) {
+	for _, user := range s.users {
+		if strings.EqualFold(user.Email, email) {
+			return user, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+func (s *principalStoreMock) FindByUID(_ context.Context, uid string) (*types.Principal, error) {
+	for _, user := range s.users {
+		if strings.EqualFold(user.UID, uid) {
+			return user.ToPrincipal(), nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+func (s *principalStoreMock) CreateUser(_ context.Context, user *types.User) error {
+	user.ID = int64(len(s.users) + 1)
+	s.users = append(s.users, user)
+	return nil
+}
+
+func (s *principalStoreMock) UpdateUser(context.Context, *types.User) error {
+	return nil
+}
+
+func (s *principalStoreMock) CountUsers(context.Context, *types.UserFilter) (int64, error) {
+	return int64(len(s.users)), nil
+}
+
+type identityStoreMock struct {
+	store.PrincipalIdentityStore
+	identities []*types.PrincipalIdentity
+}
+
+func (s *identityStoreMock) Find(_ context.Context, provider, subject string) (*types.PrincipalIdentity, error) {
+	for _, identity := range s.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, gitness_store.ErrResourceNotFound
+}
+
+func (s *identityStoreMock) Create(_ context.Context, identity *types.PrincipalIdentity) error {
+	s.identities = append(s.identities, identity)
+	return nil
+}
+
+func (s *identityStoreMock) UpdateLastLogin(_ context.Context, provider, subject string, lastLogin int64) error {
+	identity, err := s.Find(context.Background(), provider, subject)
+	if err != nil {
+		return err
+	}
+	identity.LastLogin = lastLogin
+	return nil
+}
+
+type tokenStoreMock struct {
+	store.TokenStore
+}
+
+func (tokenStoreMock) Create(context.Context, *types.Token) error {
+	return nil
+}
+
+type transactorMock struct{}
+
+func (transactorMock) WithTx(ctx context.Context, txFn func(ctx context.Context) error, _ ...interface{}) error {
+	return txFn(ctx)
+}
+
+const testProvider = "oidc:https://idp.example.com"
+
+func newExternalTestController() (*Controller, *principalStoreMock, *identityStoreMock) {
+	principalStore := &principalStoreMock{
+		users: []*types.User{
+			{ID: 1, UID: "admin", Email: "admin@example.com", Salt: "salt", Admin: true},
+			{ID: 2, UID: "john", Email: "john@example.com", Salt: "salt"},
+			{ID: 3, UID: "blocked", Email: "blocked@example.com", Salt: "salt", Blocked: true},
+		},
+	}
+	identityStore := &identityStoreMock{
+		identities: []*types.PrincipalIdentity{
+			{Provider: testProvider, Subject: "known", PrincipalID: 2},
+		},
+	}
+
+	c := &Controller{
+		tx:                transactorMock{},
+		principalUIDCheck: check.PrincipalUIDDefault,
+		principalStore:    principalStore,
+		tokenStore:        tokenStoreMock{},
+		identityStore:     identityStore,
+		config:            &types.Config{},
+	}
+
+	return c, principalStore, identityStore
+}
+
+func requireUserError(t *testing.T, err error, status int) {
+	t.Helper()
+
+	var uErr *usererror.Error
+	require.True(t, errors.As(err, &uErr), "unexpected error: %v", err)
+	require.Equal(t, status, uErr.Status)
+}
+
+func TestController_LoginExternal(t *testing.T) {
+	autoCreate := externalLoginOptions{Provider: testProvider, AutoCreate: true}
+
+	tests := []struct {
+		name string
+		opts externalLoginOptions
+		in   externalUser
+		// expectedUID is the UID of the logged in user, empty if the login is expected to fail.
+		expectedUID    string
+		expectedStatus int
+		expectedUsers  int
+		expectedLinked bool
+	}{
+		{
+			name: "known identity",
+			opts: autoCreate,
+			// the identity is used independent of the current email of the user.
+			in:            externalUser{Subject: "known", Email: "changed@example.com"},
+			expectedUID:   "john",
+			expectedUsers: 3,
+		},
+		{
+			name:           "unknown identity is linked to user with verified email",
+			opts:           autoCreate,
+			in:             externalUser{Subject: "new", Email: "JOHN@example.com", EmailVerified: true},
+			expectedUID:    "john",
+			expectedUsers:  3,
+			expectedLinked: true,
+		},
+		{
+			name:           "unknown identity isn't linked to user with unverified email",
+			opts:           autoCreate,
+			in:             externalUser{Subject: "new", Email: "john@example.com"},
+			expectedStatus: http.StatusForbidden,
+			expectedUsers:  3,
+		},
+		{
+			name: "unknown identity creates new user",
+			opts: autoCreate,
+			in: externalUser{
+				Subject:     "new",
+				Email:       "john@other.com",
+				UID:         "john",
+				DisplayName: "John Doe",
+			},
+			expectedUID:    "john-1",
+			expectedUsers:  4,
+			expectedLinked: true,
+		},
+		{
+			name:           "unknown identity without auto create",
+			opts:           externalLoginOptions{Provider: testProvider},
+			in:             externalUser{Subject: "new", Email: "jane@example.com", UID: "jane", EmailVerified: true},
+			expectedStatus: http.StatusForbidden,
+			expectedUsers:  3,
+		},
+		{
+			name: "email domain not allowed",
+			opts: externalLoginOptions{Provider: testProvider, AutoCreate: true, AllowedDomains: []string{"other.com"}},
+			in: externalUser{
+				Subject:       "known",
+				Email:         "john@example.com",
+				EmailVerified: true,
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedUsers:  3,
+		},
+		{
+			name:           "blocked user",
+			opts:           autoCreate,
+			in:             externalUser{Subject: "new", Email: "blocked@example.com", EmailVerified: true},
+			expectedStatus: http.StatusForbidden,
+			expectedUsers:  3,
+			expectedLinked: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, principalStore, identityStore := newExternalTestController()
+			in := test.in
+
+			out, err := c.loginExternal(context.Background(), test.opts, &in)
+			require.Len(t, principalStore.users, test.expectedUsers)
+
+			identity, findErr := identityStore.Find(context.Background(), testProvider, in.Subject)
+			if test.expectedLinked {
+				require.NoError(t, findErr)
+				require.Len(t, identityStore.identities, 2)
+			} else {
+				require.Len(t, identityStore.identities, 1)
+			}
+
+			if test.expectedUID == "" {
+				requireUserError(t, err, test.expectedStatus)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotEmpty(t, out.AccessToken)
+
+			user, err := principalStore.FindUser(context.Background(), identity.PrincipalID)
+			require.NoError(t, err)
+			require.Equal(t, test.expectedUID, user.UID)
+			require.Equal(t, user.ID, out.Token.PrincipalID)
+			require.NotZero(t, identity.LastLogin)
+		})
+	}
+}
+
+func TestController_LoginExternal_CreatedUser(t *testing.T) {
+	c, principalStore, _ := newExternalTestController()
+
+	_, err := c.loginExternal(context.Background(),
+		externalLoginOptions{Provider: testProvider, AutoCreate: true},
+		&externalUser{Subject: "new", Email: "jane@example.com", UID: "Jane Doe", DisplayName: " "})
+	require.NoError(t, err)
+
+	user := principalStore.users[len(principalStore.users)-1]
+	require.Equal(t, "Jane-Doe", user.UID)
+	require.Equal(t, "jane@example.com", user.Email)
+	// the display name falls back to the uid if invalid.
+	require.Equal(t, "Jane-Doe", user.DisplayName)
+	require.False(t, user.Admin)
+	require.NotEmpty(t, user.Password)
+}
+
+func TestController_AvailableUID(t *testing.T) {
+	c, principalStore, _ := newExternalTestController()
+	for i := 1; i <= 2; i++ {
+		principalStore.users = append(principalStore.users, &types.User{UID: "taken-" + strconv.Itoa(i)})
+	}
+	principalStore.users = append(principalStore.users, &types.User{UID: "taken"}, &types.User{UID: "user"})
+
+	tests := []struct {
+		uid      string
+		expected string
+	}{
+		{uid: "jane", expected: "jane"},
+		{uid: "john", expected: "john-1"},
+		{uid: "JOHN", expected: "JOHN-1"},
+		{uid: "taken", expected: "taken-3"},
+		{uid: "jane.doe_1", expected: "jane.doe_1"},
+		{uid: "jane doe@example", expected: "jane-doe-example"},
+		{uid: "123jane", expected: "jane"},
+		{uid: "-.jane", expected: "jane"},
+		{uid: strings.Repeat("a", check.MaxUIDLength+10), expected: strings.Repeat("a", check.MaxUIDLength-4)},
+		// falls back to the default uid if nothing valid remains.
+		{uid: "", expected: "user-1"},
+		{uid: "1234", expected: "user-1"},
+		{uid: "élan", expected: "lan"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.uid, func(t *testing.T) {
+			uid, err := c.availableUID(context.Background(), test.uid)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, uid)
+		})
+	}
+}
+
+func TestController_AvailableUID_Exhausted(t *testing.T) {
+	c, principalStore, _ := newExternalTestController()
+	principalStore.users = append(principalStore.users, &types.User{UID: "jane"})
+	for i := 1; i < maxUIDAttempts; i++ {
+		principalStore.users = append(principalStore.users, &types.User{UID: "jane-" + strconv.Itoa(i)})
+	}
+
+	_, err := c.availableUID(context.Background(), "jane")
+	requireUserError(t, err, http.StatusConflict)
+}
+
+func TestCheckEmailDomain(t *testing.T) {
+	tests := []struct {
+		name           string
+		email          string
+		allowedDomains []string
+		allowed        bool
+	}{
+		{name: "no restriction", email: "jane@example.com", allowed: true},
+		{name: "no restriction invalid email", email: "jane", allowed: true},
+		{name: "allowed", email: "jane@example.com", allowedDomains: []string{"other.com", "example.com"}, allowed: true},
+		{name: "case insensitive", email: "jane@Example.COM", allowedDomains: []string{"example.com"}, allowed: true},
+		{name: "whitespace", email: "jane@example.com", allowedDomains: []string{" example.com "}, allowed: true},
+		{name: "last at sign", email: "jane@other.com@example.com", allowedDomains: []string{"example.com"}, allowed: true},
+		{name: "not allowed", email: "jane@example.com", allowedDomains: []string{"other.com"}},
+		{name: "sub domain", email: "jane@sub.example.com", allowedDomains: []string{"example.com"}},
+		{name: "suffix", email: "jane@evilexample.com", allowedDomains: []string{"example.com"}},
+		{name: "domain in local part", email: "example.com@other.com", allowedDomains: []string{"example.com"}},
+		{name: "invalid email", email: "example.com", allowedDomains: []string{"example.com"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkEmailDomain(test.email, test.allowedDomains)
+			if test.allowed {
+				require.NoError(t, err)
+				return
+			}
+			requireUserError(t, err, http.StatusForbidden)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// addGroupMemberships adds the memberships mapped to the groups of the user.
+// Existing memberships are never modified or removed, errors are logged and don't fail the login.
+// NOTE: The memberships are added by the system principal, and the source marks them as managed
+// by the group mappings of the identity provider.
+func (c *Controller) addGroupMemberships(
+	ctx context.Context,
+	source string,
+	mappings []auth.GroupMembership,
+	user *types.User,
+	groups []string,
+) {
+	if len(mappings) == 0 || len(groups) == 0 {
+		return
+	}
+
+	systemPrincipal, err := c.principalStore.FindByUID(ctx, c.config.Principal.System.UID)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to find system principal to add group memberships")
+		return
+	}
+
+	userGroups := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		userGroups[group] = struct{}{}
+	}
+
+	for _, mapping := range mappings {
+		if _, ok := userGroups[mapping.Group]; !ok {
+			continue
+		}
+
+		if err := c.addGroupMembership(ctx, source, mapping, user, systemPrincipal.ID); err != nil {
+			log.Ctx(ctx).Warn().Err(err).
+				Str("user_uid", user.UID).
+				Str("group", mapping.Group).
+				Str("space_ref", mapping.SpaceRef).
+				Msg("failed to add group membership")
+		}
+	}
+}
+
+func (c *Controller) addGroupMembership(
+	ctx context.Context,
+	source string,
+	mapping auth.GroupMembership,
+	user *types.User,
+	createdBy int64,
+) error {
+	space, err := c.spaceStore.FindByRef(ctx, mapping.SpaceRef)
+	if err != nil {
+		return fmt.Errorf("failed to find space: %w", err)
+	}
+
+	key := types.MembershipKey{
+		SpaceID:     space.ID,
+		PrincipalID: user.ID,
+	}
+
+	_, err = c.membershipStore.Find(ctx, key)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, store.ErrResourceNotFound) {
+		return fmt.Errorf("failed to find membership: %w", err)
+	}
+
+	now := time.Now().UnixMilli()
+	err = c.membershipStore.Create(ctx, &types.Membership{
+		MembershipKey: key,
+		CreatedBy:     createdBy,
+		Created:       now,
+		Updated:       now,
+		Role:          mapping.Role,
+		Source:        source,
+	})
+	if err != nil && !errors.Is(err, store.ErrDuplicate) {
+		return fmt.Errorf("failed to create membership: %w", err)
+	}
+
+	return nil
+}
+
+// checkEmailDomain verifies the email of an externally authenticated user belongs to one of the allowed domains.
+func checkEmailDomain(email string, allowedDomains []string) error {
+	if len(allowedDomains) == 0 {
+		return nil
+	}
+
+	idx := strings.LastIndex(email, "@")
+	if idx < 0 {
+		return usererror.Forbidden("The email of the user is invalid")
+	}
+
+	domain := email[idx+1:]
+	for _, allowed := range allowedDomains {
+		if strings.EqualFold(domain, strings.TrimSpace(allowed)) {
+			return nil
+		}
+	}
+
+	return usererror.Forbidden("The email domain of the user is not allowed")
+}
@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth/ldap"
 	"github.com/harness/gitness/app/token"
 	"github.com/harness/gitness/store"
 	"github.com/harness/gitness/types"
@@ -45,6 +46,22 @@ func (c *Controller) Login(
 ) (*types.TokenResponse, error) {
 	// no auth check required, password is used for it.
 
+	if c.ldapDirectory != nil {
+		tokenResponse, err := c.loginLDAP(ctx, in)
+		if !errors.Is(err, ldap.ErrNotFound) {
+			return tokenResponse, err
+		}
+
+		// users that don't exist in the directory fall back to the password login.
+		if !c.config.PasswordLoginEnabled {
+			return nil, usererror.ErrNotFound
+		}
+	}
+
+	if !c.config.PasswordLoginEnabled {
+		return nil, usererror.Forbidden("Password login is disabled")
+	}
+
 	user, err := findUserFromUID(ctx, c.principalStore, in.LoginIdentifier)
 	if errors.Is(err, store.ErrResourceNotFound) {
 		user, err = findUserFromEmail(ctx, c.principalStore, in.LoginIdentifier)
@@ -70,6 +87,10 @@ func (c *Controller) Login(
 		return nil, usererror.ErrNotFound
 	}
 
+	if user.Blocked {
+		return nil, usererror.Forbidden("The user is blocked")
+	}
+
 	tokenUID, err := generateSessionTokenUID()
 	if err != nil {
 		return nil, err
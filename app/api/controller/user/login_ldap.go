@@ -0,0 +1,64 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// loginLDAP authenticates the user against the LDAP directory - returns the session token if successful.
+// Returns ldap.ErrNotFound in case the user isn't known to the directory (or the directory isn't available).
+func (c *Controller) loginLDAP(ctx context.Context, in *LoginInput) (*types.TokenResponse, error) {
+	entry, err := c.ldapDirectory.Authenticate(ctx, in.LoginIdentifier, in.Password)
+	switch {
+	case errors.Is(err, ldap.ErrNotFound):
+		return nil, err
+	case errors.Is(err, ldap.ErrInvalidCredentials):
+		// always return not found for security reasons.
+		return nil, usererror.ErrNotFound
+	case err != nil:
+		log.Ctx(ctx).Warn().Err(err).
+			Str("user_uid", in.LoginIdentifier).
+			Msg("failed to authenticate user with ldap directory")
+		return nil, ldap.ErrNotFound
+	}
+
+	if entry.Email == "" {
+		return nil, usererror.Forbidden("The directory doesn't provide an email for the user")
+	}
+
+	return c.loginExternal(ctx,
+		externalLoginOptions{
+			Provider:    ldap.IdentityProvider,
+			AutoCreate:  c.config.LDAP.AutoCreateUsers,
+			Memberships: c.ldapMemberships,
+		},
+		&externalUser{
+			Subject: ldap.NormalizeDN(entry.DN),
+			Email:   entry.Email,
+			// the directory is managed by the administrators, its emails are considered verified.
+			EmailVerified: true,
+			UID:           entry.UID,
+			DisplayName:   entry.DisplayName,
+			Groups:        entry.Groups,
+		})
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/auth/oidc"
+	"github.com/harness/gitness/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+// OIDCLoginOutput contains the data required to start the OpenID Connect login flow.
+// NOTE: State, nonce and verifier have to be stored by the client and provided again during the callback.
+type OIDCLoginOutput struct {
+	URL      string
+	State    string
+	Nonce    string
+	Verifier string
+}
+
+// OIDCCallbackInput is the input of the OpenID Connect login callback.
+type OIDCCallbackInput struct {
+	Code     string
+	Nonce    string
+	Verifier string
+}
+
+// IsOIDCEnabled returns true iff the OpenID Connect login is configured.
+func (c *Controller) IsOIDCEnabled() bool {
+	return c.oidcProvider != nil
+}
+
+// OIDCLogin starts the OpenID Connect login flow and returns the URL of the provider to redirect to.
+func (c *Controller) OIDCLogin(ctx context.Context) (*OIDCLoginOutput, error) {
+	if c.oidcProvider == nil {
+		return nil, usererror.NotFound("OpenID Connect login is not configured")
+	}
+
+	out := &OIDCLoginOutput{}
+	for _, value := range []*string{&out.State, &out.Nonce, &out.Verifier} {
+		var err error
+		if *value, err = oidc.RandomString(); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	out.URL, err = c.oidcProvider.AuthCodeURL(ctx, out.State, out.Nonce, out.Verifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate authorization url: %w", err)
+	}
+
+	return out, nil
+}
+
+// OIDCCallback completes the OpenID Connect login flow - returns the session token if successful.
+func (c *Controller) OIDCCallback(ctx context.Context, in *OIDCCallbackInput) (*types.TokenResponse, error) {
+	if c.oidcProvider == nil {
+		return nil, usererror.NotFound("OpenID Connect login is not configured")
+	}
+
+	if in.Code == "" {
+		return nil, usererror.BadRequest("Authorization code is missing")
+	}
+
+	claims, err := c.oidcProvider.Exchange(ctx, in.Code, in.Verifier, in.Nonce)
+	if errors.Is(err, oidc.ErrInvalidIDToken) {
+		log.Ctx(ctx).Warn().Err(err).Msg("received invalid oidc id token")
+		return nil, usererror.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with oidc provider: %w", err)
+	}
+
+	if claims.Email == "" {
+		return nil, usererror.Forbidden("The identity provider didn't provide an email for the user")
+	}
+	if c.config.OIDC.RequireVerifiedEmail && !claims.EmailVerified {
+		return nil, usererror.Forbidden("The email of the user isn't verified")
+	}
+
+	uid := claims.PreferredUsername
+	if uid == "" {
+		uid, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	return c.loginExternal(ctx,
+		externalLoginOptions{
+			Provider:       "oidc:" + c.oidcProvider.Issuer(),
+			AutoCreate:     c.config.OIDC.AutoCreateUsers,
+			AllowedDomains: c.config.OIDC.AllowedDomains,
+			Memberships:    c.oidcMemberships,
+		},
+		&externalUser{
+			Subject:       claims.Subject,
+			Email:         claims.Email,
+			EmailVerified: claims.EmailVerified,
+			UID:           uid,
+			DisplayName:   claims.Name,
+			Groups:        claims.Groups,
+		})
+}
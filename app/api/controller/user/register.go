@@ -35,6 +35,10 @@ type RegisterInput struct {
 // This doesn't require auth, but has limited functionalities (unable to create admin user for example).
 func (c *Controller) Register(ctx context.Context, sysCtrl *system.Controller,
 	in *RegisterInput) (*types.TokenResponse, error) {
+	if !c.config.PasswordLoginEnabled {
+		return nil, usererror.Forbidden("Password login is disabled")
+	}
+
 	signUpAllowed, err := sysCtrl.IsUserSignupAllowed(ctx)
 	if err != nil {
 		return nil, err
@@ -15,9 +15,16 @@
 package user
 
 import (
+	"fmt"
+
+	"github.com/harness/gitness/app/auth"
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/auth/oidc"
 	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/app/url"
 	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/check"
 
 	"github.com/google/wire"
@@ -35,12 +42,48 @@ func ProvideController(
 	principalStore store.PrincipalStore,
 	tokenStore store.TokenStore,
 	membershipStore store.MembershipStore,
-) *Controller {
+	spaceStore store.SpaceStore,
+	identityStore store.PrincipalIdentityStore,
+	signingKeyStore store.SigningKeyStore,
+	config *types.Config,
+	urlProvider url.Provider,
+	ldapDirectory ldap.Directory,
+) (*Controller, error) {
+	oidcMemberships, err := auth.ParseGroupMemberships(config.OIDC.GroupMemberships)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse oidc group memberships: %w", err)
+	}
+
+	ldapMemberships, err := auth.ParseGroupMemberships(config.LDAP.GroupMemberships)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ldap group memberships: %w", err)
+	}
+
+	var oidcProvider *oidc.Provider
+	if config.OIDC.Issuer != "" {
+		oidcProvider = oidc.NewProvider(oidc.Config{
+			Issuer:       config.OIDC.Issuer,
+			ClientID:     config.OIDC.ClientID,
+			ClientSecret: config.OIDC.ClientSecret,
+			RedirectURL:  urlProvider.GenerateOIDCCallbackURL(),
+			Scopes:       config.OIDC.Scopes,
+			GroupsClaim:  config.OIDC.GroupsClaim,
+		})
+	}
+
 	return NewController(
 		tx,
 		principalUIDCheck,
 		authorizer,
 		principalStore,
 		tokenStore,
-		membershipStore)
+		membershipStore,
+		spaceStore,
+		identityStore,
+		signingKeyStore,
+		config,
+		oidcProvider,
+		oidcMemberships,
+		ldapDirectory,
+		ldapMemberships), nil
 }
@@ -128,3 +128,15 @@ func MapSignature(s *git.Signature) (*types.Signature, error) {
 		When: s.When,
 	}, nil
 }
+
+func MapDiffOptions(o *types.DiffOptions) git.DiffOptions {
+	if o == nil {
+		return git.DiffOptions{}
+	}
+	return git.DiffOptions{
+		IgnoreWhitespace: o.IgnoreWhitespace,
+		ContextLines:     o.ContextLines,
+		RenameThreshold:  o.RenameThreshold,
+		DetectCopies:     o.DetectCopies,
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/controller/user"
+	"github.com/harness/gitness/app/api/render"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	oidcStateCookieName = "gitness_oidc_state"
+	// oidcStateCookieLifetime is the max duration the user has to login with the identity provider.
+	oidcStateCookieLifetime = 10 * time.Minute
+)
+
+// oidcState is stored in a cookie for the duration of the login flow.
+type oidcState struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Redirect string `json:"redirect,omitempty"`
+}
+
+// HandleOIDCLogin returns an http.HandlerFunc that redirects the user to the OpenID Connect provider.
+func HandleOIDCLogin(userCtrl *user.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		out, err := userCtrl.OIDCLogin(ctx)
+		if err != nil {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
+		raw, err := json.Marshal(oidcState{
+			State:    out.State,
+			Nonce:    out.Nonce,
+			Verifier: out.Verifier,
+			Redirect: sanitizeRedirect(r.URL.Query().Get("redirect")),
+		})
+		if err != nil {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
+		cookie := newOIDCStateCookie(r)
+		cookie.Value = base64.RawURLEncoding.EncodeToString(raw)
+		cookie.Expires = time.Now().Add(oidcStateCookieLifetime)
+		http.SetCookie(w, cookie)
+
+		http.Redirect(w, r, out.URL, http.StatusFound)
+	}
+}
+
+// HandleOIDCCallback returns an http.HandlerFunc that completes the OpenID Connect login
+// and redirects the user to the UI.
+func HandleOIDCCallback(userCtrl *user.Controller, cookieName string, uiURL string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		state, ok := readOIDCState(r)
+
+		// the state cookie is only valid for a single login attempt.
+		cookie := newOIDCStateCookie(r)
+		cookie.Expires = time.UnixMilli(0)
+		http.SetCookie(w, cookie)
+
+		if !ok || state.State != r.URL.Query().Get("state") {
+			render.Unauthorized(w)
+			return
+		}
+
+		if errCode := r.URL.Query().Get("error"); errCode != "" {
+			log.Ctx(ctx).Warn().
+				Str("error", errCode).
+				Str("error_description", r.URL.Query().Get("error_description")).
+				Msg("oidc provider returned an error")
+			render.Unauthorized(w)
+			return
+		}
+
+		tokenResponse, err := userCtrl.OIDCCallback(ctx, &user.OIDCCallbackInput{
+			Code:     r.URL.Query().Get("code"),
+			Nonce:    state.Nonce,
+			Verifier: state.Verifier,
+		})
+		if err != nil {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
+		if cookieName != "" {
+			includeTokenCookie(r, w, tokenResponse, cookieName)
+		}
+
+		http.Redirect(w, r, strings.TrimRight(uiURL, "/")+state.Redirect, http.StatusFound)
+	}
+}
+
+func readOIDCState(r *http.Request) (oidcState, bool) {
+	state := oidcState{}
+
+	cookie, err := r.Cookie(oidcStateCookieName)
+	if err != nil {
+		return state, false
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+	if err != nil {
+		return state, false
+	}
+
+	if err = json.Unmarshal(raw, &state); err != nil || state.State == "" {
+		return state, false
+	}
+
+	return state, true
+}
+
+// newOIDCStateCookie returns the cookie storing the state of the login flow.
+// NOTE: SameSite has to be lax as the cookie is required on the redirect from the identity provider.
+func newOIDCStateCookie(r *http.Request) *http.Cookie {
+	return &http.Cookie{
+		Name:     oidcStateCookieName,
+		SameSite: http.SameSiteLaxMode,
+		HttpOnly: true,
+		Path:     "/",
+		Domain:   r.URL.Hostname(),
+		Secure:   r.URL.Scheme == "https",
+	}
+}
+
+// sanitizeRedirect only allows relative redirects to prevent redirecting users to other sites.
+func sanitizeRedirect(redirect string) string {
+	if !strings.HasPrefix(redirect, "/") ||
+		strings.HasPrefix(redirect, "//") ||
+		strings.ContainsAny(redirect, "\\\r\n") {
+		return ""
+	}
+
+	return redirect
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizeRedirect(t *testing.T) {
+	tests := []struct {
+		redirect string
+		expected string
+	}{
+		{redirect: "", expected: ""},
+		{redirect: "/", expected: "/"},
+		{redirect: "/space/repo?tab=files#readme", expected: "/space/repo?tab=files#readme"},
+		{redirect: "space/repo", expected: ""},
+		{redirect: "https://evil.example.com", expected: ""},
+		{redirect: "//evil.example.com", expected: ""},
+		{redirect: "/\\evil.example.com", expected: ""},
+		{redirect: "/path\r\nSet-Cookie: x=y", expected: ""},
+		{redirect: "javascript:alert(1)", expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.redirect, func(t *testing.T) {
+			require.Equal(t, test.expected, sanitizeRedirect(test.redirect))
+		})
+	}
+}
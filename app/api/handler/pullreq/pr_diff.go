@@ -41,13 +41,19 @@ func HandleDiff(pullreqCtrl *pullreq.Controller) http.HandlerFunc {
 			return
 		}
 
+		diffOptions, err := request.ParseDiffOptions(r)
+		if err != nil {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
 		setSHAs := func(sourceSHA, mergeBaseSHA string) {
 			w.Header().Set("X-Source-Sha", sourceSHA)
 			w.Header().Set("X-Merge-Base-Sha", mergeBaseSHA)
 		}
 
 		if strings.HasPrefix(r.Header.Get("Accept"), "text/plain") {
-			err := pullreqCtrl.RawDiff(ctx, session, repoRef, pullreqNumber, setSHAs, w)
+			err := pullreqCtrl.RawDiff(ctx, session, repoRef, pullreqNumber, setSHAs, diffOptions, w)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusOK)
 			}
@@ -55,7 +61,7 @@ func HandleDiff(pullreqCtrl *pullreq.Controller) http.HandlerFunc {
 		}
 
 		_, includePatch := request.QueryParam(r, "include_patch")
-		stream, err := pullreqCtrl.Diff(ctx, session, repoRef, pullreqNumber, setSHAs, includePatch)
+		stream, err := pullreqCtrl.Diff(ctx, session, repoRef, pullreqNumber, setSHAs, includePatch, diffOptions)
 		if err != nil {
 			render.TranslatedUserError(w, err)
 			return
@@ -0,0 +1,118 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/harness/gitness/app/api/controller/repo"
+	"github.com/harness/gitness/app/api/render"
+	"github.com/harness/gitness/app/api/request"
+	"github.com/harness/gitness/app/api/usererror"
+	gitenum "github.com/harness/gitness/git/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+// HandleArchive streams an archive of the repository content at the requested git reference.
+func HandleArchive(repoCtrl *repo.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		repoRef, err := request.GetRepoRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
+		format, gitRef, ok := gitenum.ParseArchiveFormatFromFileName(request.GetOptionalRemainderFromPath(r))
+		if !ok {
+			render.TranslatedUserError(w, usererror.BadRequestf(
+				"Archive must be requested as '{git_ref}.{format}', supported formats are %v.",
+				gitenum.ArchiveFormats))
+			return
+		}
+
+		paths, _ := request.QueryParamList(r, request.QueryParamPath)
+
+		in := &repo.ArchiveInput{
+			GitRef: gitRef,
+			Format: format,
+			Prefix: request.QueryParamOrDefault(r, request.QueryParamPrefix, ""),
+			Paths:  paths,
+		}
+
+		fileName := fmt.Sprintf("%s-%s.%s", path.Base(repoRef), strings.ReplaceAll(gitRef, "/", "-"), format)
+
+		aw := &archiveWriter{
+			w:           w,
+			contentType: format.ContentType(),
+			fileName:    fileName,
+		}
+
+		err = repoCtrl.Archive(ctx, session, repoRef, in, aw)
+		if err == nil {
+			return
+		}
+
+		if !aw.started {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
+		// the response status has already been sent, the best we can do is to make sure the client
+		// doesn't mistake the truncated archive for a successful download.
+		log.Ctx(ctx).Error().Err(err).
+			Str("repo_ref", repoRef).
+			Str("git_ref", gitRef).
+			Msg("failed to stream repository archive, aborting the connection")
+
+		abortResponse(w)
+	}
+}
+
+// abortResponse closes the underlying connection without completing the response.
+// Connections that don't support hijacking (e.g. HTTP/2) are aborted by the http server instead.
+func abortResponse(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	_ = conn.Close()
+}
+
+// archiveWriter postpones writing of the archive response headers until the archive data starts arriving,
+// so that errors that happen before the streaming starts can still be rendered as ordinary API errors.
+type archiveWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (aw *archiveWriter) Write(p []byte) (int, error) {
+	if !aw.started {
+		aw.started = true
+		aw.w.Header().Set("Content-Type", aw.contentType)
+		aw.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", aw.fileName))
+		aw.w.WriteHeader(http.StatusOK)
+	}
+
+	return aw.w.Write(p)
+}
@@ -36,8 +36,14 @@ func HandleDiff(repoCtrl *repo.Controller) http.HandlerFunc {
 
 		path := request.GetOptionalRemainderFromPath(r)
 
+		diffOptions, err := request.ParseDiffOptions(r)
+		if err != nil {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
 		if strings.HasPrefix(r.Header.Get("Accept"), "text/plain") {
-			err := repoCtrl.RawDiff(ctx, session, repoRef, path, w)
+			err := repoCtrl.RawDiff(ctx, session, repoRef, path, diffOptions, w)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusOK)
 			}
@@ -45,7 +51,7 @@ func HandleDiff(repoCtrl *repo.Controller) http.HandlerFunc {
 		}
 
 		_, includePatch := request.QueryParam(r, "include_patch")
-		stream, err := repoCtrl.Diff(ctx, session, repoRef, path, includePatch)
+		stream, err := repoCtrl.Diff(ctx, session, repoRef, path, includePatch, diffOptions)
 		if err != nil {
 			render.TranslatedUserError(w, err)
 			return
@@ -72,7 +78,13 @@ func HandleCommitDiff(repoCtrl *repo.Controller) http.HandlerFunc {
 			return
 		}
 
-		err = repoCtrl.CommitDiff(ctx, session, repoRef, commitSHA, w)
+		diffOptions, err := request.ParseDiffOptions(r)
+		if err != nil {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
+		err = repoCtrl.CommitDiff(ctx, session, repoRef, commitSHA, diffOptions, w)
 		if err != nil {
 			render.TranslatedUserError(w, err)
 			return
@@ -102,3 +114,28 @@ func HandleDiffStats(repoCtrl *repo.Controller) http.HandlerFunc {
 		render.JSON(w, http.StatusOK, output)
 	}
 }
+
+// HandleDiffCommits returns the commits of the head that aren't part of the base of a diff.
+func HandleDiffCommits(repoCtrl *repo.Controller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		session, _ := request.AuthSessionFrom(ctx)
+		repoRef, err := request.GetRepoRefFromPath(r)
+		if err != nil {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
+		path := request.GetOptionalRemainderFromPath(r)
+		pagination := request.ParsePaginationFromRequest(r)
+
+		list, err := repoCtrl.DiffCommits(ctx, session, repoRef, path, pagination)
+		if err != nil {
+			render.TranslatedUserError(w, err)
+			return
+		}
+
+		render.Pagination(r, w, pagination.Page, pagination.Size, list.TotalCommits)
+		render.JSON(w, http.StatusOK, list)
+	}
+}
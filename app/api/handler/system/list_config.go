@@ -25,6 +25,11 @@ import (
 type ConfigOutput struct {
 	UserSignupAllowed             bool `json:"user_signup_allowed"`
 	PublicResourceCreationEnabled bool `json:"public_resource_creation_enabled"`
+	PasswordLoginEnabled          bool `json:"password_login_enabled"`
+	OIDCEnabled                   bool `json:"oidc_enabled"`
+
+	// CommitSigningKey is the public key used to sign commits created by the server (merges, web edits).
+	CommitSigningKey *types.CommitSigningKey `json:"commit_signing_key,omitempty"`
 }
 
 // HandleGetConfig returns an http.HandlerFunc that processes an http.Request
@@ -40,8 +45,11 @@ func HandleGetConfig(config *types.Config, sysCtrl *system.Controller) http.Hand
 		}
 
 		render.JSON(w, http.StatusOK, ConfigOutput{
-			UserSignupAllowed:             userSignupAllowed,
+			UserSignupAllowed:             userSignupAllowed && config.PasswordLoginEnabled,
 			PublicResourceCreationEnabled: config.PublicResourceCreationEnabled,
+			PasswordLoginEnabled:          config.PasswordLoginEnabled,
+			OIDCEnabled:                   config.OIDC.Issuer != "",
+			CommitSigningKey:              sysCtrl.GetCommitSigningKey(),
 		})
 	}
 }
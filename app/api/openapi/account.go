@@ -52,6 +52,17 @@ type registerRequest struct {
 }
 
 // helper function that constructs the openapi specification
+// request to start the OpenID Connect login.
+type oidcLoginRequest struct {
+	Redirect string `query:"redirect" description:"Relative path of the UI to redirect to after the login."`
+}
+
+// callback of the OpenID Connect provider.
+type oidcCallbackRequest struct {
+	Code  string `query:"code"`
+	State string `query:"state"`
+}
+
 // for the account registration and login endpoints.
 func buildAccount(reflector *openapi3.Reflector) {
 	onLogin := openapi3.Operation{}
@@ -84,4 +95,24 @@ func buildAccount(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&onRegister, new(usererror.Error), http.StatusInternalServerError)
 	_ = reflector.SetJSONResponse(&onRegister, new(usererror.Error), http.StatusBadRequest)
 	_ = reflector.Spec.AddOperation(http.MethodPost, "/register", onRegister)
+
+	onOIDCLogin := openapi3.Operation{}
+	onOIDCLogin.WithTags("account")
+	onOIDCLogin.WithMapOfAnything(map[string]interface{}{"operationId": "onOIDCLogin"})
+	_ = reflector.SetRequest(&onOIDCLogin, new(oidcLoginRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&onOIDCLogin, nil, http.StatusFound)
+	_ = reflector.SetJSONResponse(&onOIDCLogin, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&onOIDCLogin, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/oidc/login", onOIDCLogin)
+
+	onOIDCCallback := openapi3.Operation{}
+	onOIDCCallback.WithTags("account")
+	onOIDCCallback.WithMapOfAnything(map[string]interface{}{"operationId": "onOIDCCallback"})
+	_ = reflector.SetRequest(&onOIDCCallback, new(oidcCallbackRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&onOIDCCallback, nil, http.StatusFound)
+	_ = reflector.SetJSONResponse(&onOIDCCallback, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&onOIDCCallback, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&onOIDCCallback, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&onOIDCCallback, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/oidc/callback", onOIDCCallback)
 }
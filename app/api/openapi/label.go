@@ -0,0 +1,278 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/harness/gitness/app/api/controller/pullreq"
+	"github.com/harness/gitness/app/api/request"
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/types"
+
+	"github.com/gotidy/ptr"
+	"github.com/swaggest/openapi-go/openapi3"
+)
+
+type repoLabelRequest struct {
+	repoRequest
+	LabelName string `path:"label_name"`
+}
+
+type spaceLabelRequest struct {
+	spaceRequest
+	LabelName string `path:"label_name"`
+}
+
+type labelAssignPullReqRequest struct {
+	pullReqRequest
+	pullreq.LabelAssignInput
+}
+
+type labelUnassignPullReqRequest struct {
+	pullReqRequest
+	LabelID int64 `path:"label_id"`
+}
+
+var queryParameterQueryLabel = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamQuery,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("The substring by which the labels are filtered."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type: ptrSchemaType(openapi3.SchemaTypeString),
+			},
+		},
+	},
+}
+
+var queryParameterInheritedLabel = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamInherited,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("The result should include labels defined in the parent spaces."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type:    ptrSchemaType(openapi3.SchemaTypeBoolean),
+				Default: ptrptr(false),
+			},
+		},
+	},
+}
+
+var queryParameterLabelIDPullRequest = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamLabelID,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("List of label IDs. Only pull requests with all of the labels are included."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type: ptrSchemaType(openapi3.SchemaTypeArray),
+				Items: &openapi3.SchemaOrRef{
+					Schema: &openapi3.Schema{
+						Type: ptrSchemaType(openapi3.SchemaTypeInteger),
+					},
+				},
+			},
+		},
+	},
+}
+
+//nolint:funlen // api spec generation no need for checking func complexity
+func labelOperations(reflector *openapi3.Reflector) {
+	opRepoLabelDefine := openapi3.Operation{}
+	opRepoLabelDefine.WithTags("repository")
+	opRepoLabelDefine.WithMapOfAnything(map[string]interface{}{"operationId": "repoLabelDefine"})
+	_ = reflector.SetRequest(&opRepoLabelDefine, &struct {
+		repoRequest
+		label.DefineInput
+	}{}, http.MethodPost)
+	_ = reflector.SetJSONResponse(&opRepoLabelDefine, new(types.Label), http.StatusCreated)
+	_ = reflector.SetJSONResponse(&opRepoLabelDefine, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opRepoLabelDefine, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&opRepoLabelDefine, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opRepoLabelDefine, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opRepoLabelDefine, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opRepoLabelDefine, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/repos/{repo_ref}/labels", opRepoLabelDefine)
+
+	opRepoLabelList := openapi3.Operation{}
+	opRepoLabelList.WithTags("repository")
+	opRepoLabelList.WithMapOfAnything(map[string]interface{}{"operationId": "repoLabelList"})
+	opRepoLabelList.WithParameters(queryParameterQueryLabel, queryParameterInheritedLabel,
+		queryParameterPage, queryParameterLimit)
+	_ = reflector.SetRequest(&opRepoLabelList, new(repoRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opRepoLabelList, new([]*types.Label), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opRepoLabelList, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opRepoLabelList, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opRepoLabelList, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opRepoLabelList, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opRepoLabelList, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/labels", opRepoLabelList)
+
+	opRepoLabelFind := openapi3.Operation{}
+	opRepoLabelFind.WithTags("repository")
+	opRepoLabelFind.WithMapOfAnything(map[string]interface{}{"operationId": "repoLabelFind"})
+	_ = reflector.SetRequest(&opRepoLabelFind, new(repoLabelRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opRepoLabelFind, new(types.Label), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opRepoLabelFind, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opRepoLabelFind, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opRepoLabelFind, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opRepoLabelFind, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opRepoLabelFind, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/labels/{label_name}", opRepoLabelFind)
+
+	opRepoLabelUpdate := openapi3.Operation{}
+	opRepoLabelUpdate.WithTags("repository")
+	opRepoLabelUpdate.WithMapOfAnything(map[string]interface{}{"operationId": "repoLabelUpdate"})
+	_ = reflector.SetRequest(&opRepoLabelUpdate, &struct {
+		repoLabelRequest
+		label.UpdateInput
+	}{}, http.MethodPatch)
+	_ = reflector.SetJSONResponse(&opRepoLabelUpdate, new(types.Label), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opRepoLabelUpdate, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opRepoLabelUpdate, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&opRepoLabelUpdate, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opRepoLabelUpdate, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opRepoLabelUpdate, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opRepoLabelUpdate, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPatch, "/repos/{repo_ref}/labels/{label_name}", opRepoLabelUpdate)
+
+	opRepoLabelDelete := openapi3.Operation{}
+	opRepoLabelDelete.WithTags("repository")
+	opRepoLabelDelete.WithMapOfAnything(map[string]interface{}{"operationId": "repoLabelDelete"})
+	_ = reflector.SetRequest(&opRepoLabelDelete, new(repoLabelRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opRepoLabelDelete, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opRepoLabelDelete, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opRepoLabelDelete, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opRepoLabelDelete, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opRepoLabelDelete, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opRepoLabelDelete, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/repos/{repo_ref}/labels/{label_name}", opRepoLabelDelete)
+
+	opSpaceLabelDefine := openapi3.Operation{}
+	opSpaceLabelDefine.WithTags("space")
+	opSpaceLabelDefine.WithMapOfAnything(map[string]interface{}{"operationId": "spaceLabelDefine"})
+	_ = reflector.SetRequest(&opSpaceLabelDefine, &struct {
+		spaceRequest
+		label.DefineInput
+	}{}, http.MethodPost)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDefine, new(types.Label), http.StatusCreated)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDefine, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDefine, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDefine, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDefine, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDefine, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDefine, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/spaces/{space_ref}/labels", opSpaceLabelDefine)
+
+	opSpaceLabelList := openapi3.Operation{}
+	opSpaceLabelList.WithTags("space")
+	opSpaceLabelList.WithMapOfAnything(map[string]interface{}{"operationId": "spaceLabelList"})
+	opSpaceLabelList.WithParameters(queryParameterQueryLabel, queryParameterInheritedLabel,
+		queryParameterPage, queryParameterLimit)
+	_ = reflector.SetRequest(&opSpaceLabelList, new(spaceRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opSpaceLabelList, new([]*types.Label), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opSpaceLabelList, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opSpaceLabelList, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opSpaceLabelList, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opSpaceLabelList, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opSpaceLabelList, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/spaces/{space_ref}/labels", opSpaceLabelList)
+
+	opSpaceLabelFind := openapi3.Operation{}
+	opSpaceLabelFind.WithTags("space")
+	opSpaceLabelFind.WithMapOfAnything(map[string]interface{}{"operationId": "spaceLabelFind"})
+	_ = reflector.SetRequest(&opSpaceLabelFind, new(spaceLabelRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opSpaceLabelFind, new(types.Label), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opSpaceLabelFind, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opSpaceLabelFind, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opSpaceLabelFind, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opSpaceLabelFind, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opSpaceLabelFind, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/spaces/{space_ref}/labels/{label_name}", opSpaceLabelFind)
+
+	opSpaceLabelUpdate := openapi3.Operation{}
+	opSpaceLabelUpdate.WithTags("space")
+	opSpaceLabelUpdate.WithMapOfAnything(map[string]interface{}{"operationId": "spaceLabelUpdate"})
+	_ = reflector.SetRequest(&opSpaceLabelUpdate, &struct {
+		spaceLabelRequest
+		label.UpdateInput
+	}{}, http.MethodPatch)
+	_ = reflector.SetJSONResponse(&opSpaceLabelUpdate, new(types.Label), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opSpaceLabelUpdate, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opSpaceLabelUpdate, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&opSpaceLabelUpdate, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opSpaceLabelUpdate, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opSpaceLabelUpdate, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opSpaceLabelUpdate, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPatch, "/spaces/{space_ref}/labels/{label_name}", opSpaceLabelUpdate)
+
+	opSpaceLabelDelete := openapi3.Operation{}
+	opSpaceLabelDelete.WithTags("space")
+	opSpaceLabelDelete.WithMapOfAnything(map[string]interface{}{"operationId": "spaceLabelDelete"})
+	_ = reflector.SetRequest(&opSpaceLabelDelete, new(spaceLabelRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDelete, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDelete, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDelete, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDelete, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDelete, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opSpaceLabelDelete, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/spaces/{space_ref}/labels/{label_name}", opSpaceLabelDelete)
+
+	labelAssign := openapi3.Operation{}
+	labelAssign.WithTags("pullreq")
+	labelAssign.WithMapOfAnything(map[string]interface{}{"operationId": "labelAssignPullReq"})
+	_ = reflector.SetRequest(&labelAssign, new(labelAssignPullReqRequest), http.MethodPut)
+	_ = reflector.SetJSONResponse(&labelAssign, new([]*types.LabelInfo), http.StatusOK)
+	_ = reflector.SetJSONResponse(&labelAssign, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&labelAssign, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&labelAssign, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&labelAssign, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&labelAssign, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPut,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/labels", labelAssign)
+
+	labelList := openapi3.Operation{}
+	labelList.WithTags("pullreq")
+	labelList.WithMapOfAnything(map[string]interface{}{"operationId": "labelListPullReq"})
+	_ = reflector.SetRequest(&labelList, new(pullReqRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&labelList, new([]*types.LabelInfo), http.StatusOK)
+	_ = reflector.SetJSONResponse(&labelList, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&labelList, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&labelList, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&labelList, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&labelList, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/labels", labelList)
+
+	labelUnassign := openapi3.Operation{}
+	labelUnassign.WithTags("pullreq")
+	labelUnassign.WithMapOfAnything(map[string]interface{}{"operationId": "labelUnassignPullReq"})
+	_ = reflector.SetRequest(&labelUnassign, new(labelUnassignPullReqRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&labelUnassign, new([]*types.LabelInfo), http.StatusOK)
+	_ = reflector.SetJSONResponse(&labelUnassign, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&labelUnassign, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&labelUnassign, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&labelUnassign, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&labelUnassign, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/labels/{label_id}", labelUnassign)
+}
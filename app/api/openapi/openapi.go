@@ -59,6 +59,7 @@ func Generate() *openapi3.Spec {
 	secretOperations(&reflector)
 	resourceOperations(&reflector)
 	pullReqOperations(&reflector)
+	labelOperations(&reflector)
 	webhookOperations(&reflector)
 	checkOperations(&reflector)
 	uploadOperations(&reflector)
@@ -89,6 +89,36 @@ type commentStatusPullReqRequest struct {
 	pullreq.CommentStatusInput
 }
 
+type commentApplySuggestionsRequest struct {
+	pullReqRequest
+	pullreq.CommentApplySuggestionsInput
+}
+
+type mergeConflictsResolveRequest struct {
+	pullReqRequest
+	pullreq.MergeConflictsResolveInput
+}
+
+type reactionAddPullReqRequest struct {
+	pullReqRequest
+	pullreq.ReactionInput
+}
+
+type reactionRemovePullReqRequest struct {
+	pullReqRequest
+	Emoji enum.PullReqReaction `path:"pullreq_reaction_emoji"`
+}
+
+type commentReactionAddPullReqRequest struct {
+	pullReqCommentRequest
+	pullreq.ReactionInput
+}
+
+type commentReactionRemovePullReqRequest struct {
+	pullReqCommentRequest
+	Emoji enum.PullReqReaction `path:"pullreq_reaction_emoji"`
+}
+
 type reviewerListPullReqRequest struct {
 	pullReqRequest
 }
@@ -304,6 +334,7 @@ func pullReqOperations(reflector *openapi3.Reflector) {
 		queryParameterStatePullRequest, queryParameterSourceRepoRefPullRequest,
 		queryParameterSourceBranchPullRequest, queryParameterTargetBranchPullRequest,
 		queryParameterQueryPullRequest, queryParameterCreatedByPullRequest,
+		queryParameterLabelIDPullRequest,
 		queryParameterOrder, queryParameterSortPullRequest,
 		queryParameterPage, queryParameterLimit)
 	_ = reflector.SetRequest(&listPullReq, new(listPullReqRequest), http.MethodGet)
@@ -410,6 +441,72 @@ func pullReqOperations(reflector *openapi3.Reflector) {
 	_ = reflector.Spec.AddOperation(http.MethodPut,
 		"/repos/{repo_ref}/pullreq/{pullreq_number}/comments/{pullreq_comment_id}/status", commentStatusPullReq)
 
+	commentApplySuggestions := openapi3.Operation{}
+	commentApplySuggestions.WithTags("pullreq")
+	commentApplySuggestions.WithMapOfAnything(map[string]interface{}{"operationId": "commentApplySuggestions"})
+	_ = reflector.SetRequest(&commentApplySuggestions, new(commentApplySuggestionsRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&commentApplySuggestions, new(pullreq.CommentApplySuggestionsOutput), http.StatusOK)
+	_ = reflector.SetJSONResponse(&commentApplySuggestions, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&commentApplySuggestions, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&commentApplySuggestions, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&commentApplySuggestions, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&commentApplySuggestions, new(types.RulesViolations), http.StatusUnprocessableEntity)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/comments/apply-suggestions", commentApplySuggestions)
+
+	reactionAdd := openapi3.Operation{}
+	reactionAdd.WithTags("pullreq")
+	reactionAdd.WithMapOfAnything(map[string]interface{}{"operationId": "reactionAddPullReq"})
+	_ = reflector.SetRequest(&reactionAdd, new(reactionAddPullReqRequest), http.MethodPut)
+	_ = reflector.SetJSONResponse(&reactionAdd, new([]*types.PullReqReactionCount), http.StatusOK)
+	_ = reflector.SetJSONResponse(&reactionAdd, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&reactionAdd, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&reactionAdd, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&reactionAdd, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&reactionAdd, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPut,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/reactions", reactionAdd)
+
+	reactionRemove := openapi3.Operation{}
+	reactionRemove.WithTags("pullreq")
+	reactionRemove.WithMapOfAnything(map[string]interface{}{"operationId": "reactionRemovePullReq"})
+	_ = reflector.SetRequest(&reactionRemove, new(reactionRemovePullReqRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&reactionRemove, new([]*types.PullReqReactionCount), http.StatusOK)
+	_ = reflector.SetJSONResponse(&reactionRemove, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&reactionRemove, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&reactionRemove, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&reactionRemove, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&reactionRemove, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/reactions/{pullreq_reaction_emoji}", reactionRemove)
+
+	commentReactionAdd := openapi3.Operation{}
+	commentReactionAdd.WithTags("pullreq")
+	commentReactionAdd.WithMapOfAnything(map[string]interface{}{"operationId": "commentReactionAddPullReq"})
+	_ = reflector.SetRequest(&commentReactionAdd, new(commentReactionAddPullReqRequest), http.MethodPut)
+	_ = reflector.SetJSONResponse(&commentReactionAdd, new([]*types.PullReqReactionCount), http.StatusOK)
+	_ = reflector.SetJSONResponse(&commentReactionAdd, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&commentReactionAdd, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&commentReactionAdd, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&commentReactionAdd, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&commentReactionAdd, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPut,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/comments/{pullreq_comment_id}/reactions", commentReactionAdd)
+
+	commentReactionRemove := openapi3.Operation{}
+	commentReactionRemove.WithTags("pullreq")
+	commentReactionRemove.WithMapOfAnything(map[string]interface{}{"operationId": "commentReactionRemovePullReq"})
+	_ = reflector.SetRequest(&commentReactionRemove, new(commentReactionRemovePullReqRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&commentReactionRemove, new([]*types.PullReqReactionCount), http.StatusOK)
+	_ = reflector.SetJSONResponse(&commentReactionRemove, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&commentReactionRemove, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&commentReactionRemove, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&commentReactionRemove, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&commentReactionRemove, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/comments/{pullreq_comment_id}/reactions/{pullreq_reaction_emoji}",
+		commentReactionRemove)
+
 	reviewerAdd := openapi3.Operation{}
 	reviewerAdd.WithTags("pullreq")
 	reviewerAdd.WithMapOfAnything(map[string]interface{}{"operationId": "reviewerAddPullReq"})
@@ -473,6 +570,34 @@ func pullReqOperations(reflector *openapi3.Reflector) {
 	_ = reflector.Spec.AddOperation(http.MethodPost,
 		"/repos/{repo_ref}/pullreq/{pullreq_number}/merge", mergePullReqOp)
 
+	mergeConflicts := openapi3.Operation{}
+	mergeConflicts.WithTags("pullreq")
+	mergeConflicts.WithMapOfAnything(map[string]interface{}{"operationId": "mergeConflictsPullReq"})
+	_ = reflector.SetRequest(&mergeConflicts, new(pullReqRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&mergeConflicts, new(types.MergeConflicts), http.StatusOK)
+	_ = reflector.SetJSONResponse(&mergeConflicts, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&mergeConflicts, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&mergeConflicts, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&mergeConflicts, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&mergeConflicts, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.SetJSONResponse(&mergeConflicts, new(usererror.Error), http.StatusPreconditionFailed)
+	_ = reflector.Spec.AddOperation(http.MethodGet,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/merge-conflicts", mergeConflicts)
+
+	mergeConflictsResolve := openapi3.Operation{}
+	mergeConflictsResolve.WithTags("pullreq")
+	mergeConflictsResolve.WithMapOfAnything(map[string]interface{}{"operationId": "mergeConflictsResolvePullReq"})
+	_ = reflector.SetRequest(&mergeConflictsResolve, new(mergeConflictsResolveRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&mergeConflictsResolve, new(pullreq.MergeConflictsResolveOutput), http.StatusOK)
+	_ = reflector.SetJSONResponse(&mergeConflictsResolve, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&mergeConflictsResolve, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&mergeConflictsResolve, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&mergeConflictsResolve, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&mergeConflictsResolve, new(usererror.Error), http.StatusPreconditionFailed)
+	_ = reflector.SetJSONResponse(&mergeConflictsResolve, new(types.RulesViolations), http.StatusUnprocessableEntity)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/repos/{repo_ref}/pullreq/{pullreq_number}/merge-conflicts/resolve", mergeConflictsResolve)
+
 	opListCommits := openapi3.Operation{}
 	opListCommits.WithTags("pullreq")
 	opListCommits.WithMapOfAnything(map[string]interface{}{"operationId": "listPullReqCommits"})
@@ -549,6 +674,8 @@ func pullReqOperations(reflector *openapi3.Reflector) {
 	opDiff := openapi3.Operation{}
 	opDiff.WithTags("pullreq")
 	opDiff.WithMapOfAnything(map[string]interface{}{"operationId": "diffPullReq"})
+	opDiff.WithParameters(queryParameterIgnoreWhitespace, queryParameterContextLines,
+		queryParameterRenameThreshold, queryParameterDetectCopies)
 	_ = reflector.SetStringResponse(&opDiff, http.StatusOK, "text/plain")
 	_ = reflector.SetJSONResponse(&opDiff, new([]git.FileDiff), http.StatusOK)
 	_ = reflector.SetJSONResponse(&opDiff, new(usererror.Error), http.StatusInternalServerError)
@@ -22,6 +22,7 @@ import (
 	"github.com/harness/gitness/app/api/usererror"
 	"github.com/harness/gitness/app/services/protection"
 	"github.com/harness/gitness/git"
+	gitenum "github.com/harness/gitness/git/enum"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
@@ -58,6 +59,12 @@ type getContentRequest struct {
 	Path string `path:"path"`
 }
 
+type archiveRequest struct {
+	repoRequest
+	GitRef string `path:"git_ref"`
+	Format string `path:"format" enum:"tar,zip,tar.gz,tgz"`
+}
+
 type pathsDetailsRequest struct {
 	repoRequest
 	repo.PathsDetailsInput
@@ -73,6 +80,12 @@ type commitFilesRequest struct {
 	repo.CommitFilesOptions
 }
 
+type applyCommitRequest struct {
+	repoRequest
+	CommitSHA string `path:"commit_sha"`
+	repo.ApplyCommitInput
+}
+
 // contentType is a plugin for repo.ContentType to allow using oneof.
 type contentType string
 
@@ -154,6 +167,8 @@ type deleteTagRequest struct {
 
 type getRawDiffRequest struct {
 	repoRequest
+	// Range is the range of refs to compare (base..head or base...head),
+	// the head can be prefixed with another repo that shares history (e.g. main...space/fork:dev).
 	Range string `path:"range" example:"main..dev"`
 }
 
@@ -217,6 +232,39 @@ var queryParameterPath = openapi3.ParameterOrRef{
 	},
 }
 
+var queryParameterArchivePath = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamPath,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("Path to include in the archive. Can be provided multiple times."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type: ptrSchemaType(openapi3.SchemaTypeArray),
+				Items: &openapi3.SchemaOrRef{
+					Schema: &openapi3.Schema{
+						Type: ptrSchemaType(openapi3.SchemaTypeString),
+					},
+				},
+			},
+		},
+	},
+}
+
+var queryParameterArchivePrefix = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamPrefix,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("Prefix that is prepended to all file paths in the archive."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type: ptrSchemaType(openapi3.SchemaTypeString),
+			},
+		},
+	},
+}
+
 var queryParameterSince = openapi3.ParameterOrRef{
 	Parameter: &openapi3.Parameter{
 		Name:        request.QueryParamSince,
@@ -245,6 +293,21 @@ var queryParameterUntil = openapi3.ParameterOrRef{
 	},
 }
 
+var queryParameterFollow = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamFollow,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("Continue listing the history of the file beyond renames. Requires the path to be set."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type:    ptrSchemaType(openapi3.SchemaTypeBoolean),
+				Default: ptrptr(false),
+			},
+		},
+	},
+}
+
 var queryParameterIncludeCommit = openapi3.ParameterOrRef{
 	Parameter: &openapi3.Parameter{
 		Name:        request.QueryParamIncludeCommit,
@@ -260,6 +323,74 @@ var queryParameterIncludeCommit = openapi3.ParameterOrRef{
 	},
 }
 
+var queryParameterIgnoreWhitespace = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamIgnoreWhitespace,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("Indicates which whitespace changes should be ignored in the diff."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type:    ptrSchemaType(openapi3.SchemaTypeString),
+				Default: ptrptr(gitenum.DiffIgnoreWhitespaceNone),
+				Enum: []interface{}{
+					gitenum.DiffIgnoreWhitespaceNone,
+					gitenum.DiffIgnoreWhitespaceAll,
+					gitenum.DiffIgnoreWhitespaceChange,
+					gitenum.DiffIgnoreWhitespaceEOL,
+				},
+			},
+		},
+	},
+}
+
+var queryParameterContextLines = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamContextLines,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("The number of context lines shown around each change (git default if not provided)."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type:    ptrSchemaType(openapi3.SchemaTypeInteger),
+				Minimum: ptr.Float64(0),
+			},
+		},
+	},
+}
+
+var queryParameterRenameThreshold = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name: request.QueryParamRenameThreshold,
+		In:   openapi3.ParameterInQuery,
+		Description: ptr.String("The similarity percentage required to detect a rename or copy " +
+			"(git default if not provided)."),
+		Required: ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type:    ptrSchemaType(openapi3.SchemaTypeInteger),
+				Minimum: ptr.Float64(1),
+				Maximum: ptr.Float64(100),
+			},
+		},
+	},
+}
+
+var queryParameterDetectCopies = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamDetectCopies,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("Indicates whether copied files should be detected in the diff."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type:    ptrSchemaType(openapi3.SchemaTypeBoolean),
+				Default: ptrptr(false),
+			},
+		},
+	},
+}
+
 var queryParameterLineFrom = openapi3.ParameterOrRef{
 	Parameter: &openapi3.Parameter{
 		Name:        request.QueryParamLineFrom,
@@ -527,6 +658,28 @@ func repoOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opServiceAccounts, new(usererror.Error), http.StatusNotFound)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/service-accounts", opServiceAccounts)
 
+	opLanguages := openapi3.Operation{}
+	opLanguages.WithTags("repository")
+	opLanguages.WithMapOfAnything(map[string]interface{}{"operationId": "getRepositoryLanguages"})
+	_ = reflector.SetRequest(&opLanguages, new(repoRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opLanguages, new(types.RepoLanguageStats), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opLanguages, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opLanguages, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opLanguages, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opLanguages, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/languages", opLanguages)
+
+	opHousekeeping := openapi3.Operation{}
+	opHousekeeping.WithTags("admin")
+	opHousekeeping.WithMapOfAnything(map[string]interface{}{"operationId": "adminRepositoryHousekeeping"})
+	_ = reflector.SetRequest(&opHousekeeping, new(repoRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opHousekeeping, nil, http.StatusAccepted)
+	_ = reflector.SetJSONResponse(&opHousekeeping, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opHousekeeping, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opHousekeeping, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opHousekeeping, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/admin/repos/{repo_ref}/housekeeping", opHousekeeping)
+
 	opGetContent := openapi3.Operation{}
 	opGetContent.WithTags("repository")
 	opGetContent.WithMapOfAnything(map[string]interface{}{"operationId": "getContent"})
@@ -564,6 +717,19 @@ func repoOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opGetRaw, new(usererror.Error), http.StatusNotFound)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/raw/{path}", opGetRaw)
 
+	opArchive := openapi3.Operation{}
+	opArchive.WithTags("repository")
+	opArchive.WithMapOfAnything(map[string]interface{}{"operationId": "archive"})
+	opArchive.WithParameters(queryParameterArchivePath, queryParameterArchivePrefix)
+	_ = reflector.SetRequest(&opArchive, new(archiveRequest), http.MethodGet)
+	_ = reflector.SetStringResponse(&opArchive, http.StatusOK, "application/octet-stream")
+	_ = reflector.SetJSONResponse(&opArchive, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opArchive, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opArchive, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opArchive, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opArchive, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/archive/{git_ref}.{format}", opArchive)
+
 	opGetBlame := openapi3.Operation{}
 	opGetBlame.WithTags("repository")
 	opGetBlame.WithMapOfAnything(map[string]interface{}{"operationId": "getBlame"})
@@ -581,7 +747,8 @@ func repoOperations(reflector *openapi3.Reflector) {
 	opListCommits.WithTags("repository")
 	opListCommits.WithMapOfAnything(map[string]interface{}{"operationId": "listCommits"})
 	opListCommits.WithParameters(queryParameterGitRef, queryParameterAfterCommits, queryParameterPath,
-		queryParameterSince, queryParameterUntil, queryParameterCommitter, queryParameterPage, queryParameterLimit)
+		queryParameterSince, queryParameterUntil, queryParameterCommitter, queryParameterFollow,
+		queryParameterPage, queryParameterLimit)
 	_ = reflector.SetRequest(&opListCommits, new(listCommitsRequest), http.MethodGet)
 	_ = reflector.SetJSONResponse(&opListCommits, []types.ListCommitResponse{}, http.StatusOK)
 	_ = reflector.SetJSONResponse(&opListCommits, new(usererror.Error), http.StatusInternalServerError)
@@ -590,6 +757,32 @@ func repoOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opListCommits, new(usererror.Error), http.StatusNotFound)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/commits", opListCommits)
 
+	opListContributors := openapi3.Operation{}
+	opListContributors.WithTags("repository")
+	opListContributors.WithMapOfAnything(map[string]interface{}{"operationId": "listContributors"})
+	opListContributors.WithParameters(queryParameterGitRef,
+		queryParameterSince, queryParameterUntil, queryParameterCommitter, queryParameterPage, queryParameterLimit)
+	_ = reflector.SetRequest(&opListContributors, new(repoRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opListContributors, []types.ContributorStats{}, http.StatusOK)
+	_ = reflector.SetJSONResponse(&opListContributors, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opListContributors, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opListContributors, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opListContributors, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/stats/contributors", opListContributors)
+
+	opCommitActivity := openapi3.Operation{}
+	opCommitActivity.WithTags("repository")
+	opCommitActivity.WithMapOfAnything(map[string]interface{}{"operationId": "getCommitActivity"})
+	opCommitActivity.WithParameters(queryParameterGitRef,
+		queryParameterSince, queryParameterUntil, queryParameterCommitter)
+	_ = reflector.SetRequest(&opCommitActivity, new(repoRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opCommitActivity, []types.WeeklyCommitStats{}, http.StatusOK)
+	_ = reflector.SetJSONResponse(&opCommitActivity, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opCommitActivity, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opCommitActivity, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opCommitActivity, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/stats/commit-activity", opCommitActivity)
+
 	opGetCommit := openapi3.Operation{}
 	opGetCommit.WithTags("repository")
 	opGetCommit.WithMapOfAnything(map[string]interface{}{"operationId": "getCommit"})
@@ -720,6 +913,8 @@ func repoOperations(reflector *openapi3.Reflector) {
 	opDiff := openapi3.Operation{}
 	opDiff.WithTags("repository")
 	opDiff.WithMapOfAnything(map[string]interface{}{"operationId": "rawDiff"})
+	opDiff.WithParameters(queryParameterIgnoreWhitespace, queryParameterContextLines,
+		queryParameterRenameThreshold, queryParameterDetectCopies)
 	_ = reflector.SetRequest(&opDiff, new(getRawDiffRequest), http.MethodGet)
 	_ = reflector.SetStringResponse(&opDiff, http.StatusOK, "text/plain")
 	_ = reflector.SetJSONResponse(&opDiff, []git.FileDiff{}, http.StatusOK)
@@ -731,6 +926,8 @@ func repoOperations(reflector *openapi3.Reflector) {
 	opCommitDiff := openapi3.Operation{}
 	opCommitDiff.WithTags("repository")
 	opCommitDiff.WithMapOfAnything(map[string]interface{}{"operationId": "getCommitDiff"})
+	opCommitDiff.WithParameters(queryParameterIgnoreWhitespace, queryParameterContextLines,
+		queryParameterRenameThreshold, queryParameterDetectCopies)
 	_ = reflector.SetRequest(&opCommitDiff, new(GetCommitRequest), http.MethodGet)
 	_ = reflector.SetStringResponse(&opCommitDiff, http.StatusOK, "text/plain")
 	_ = reflector.SetJSONResponse(&opCommitDiff, new(usererror.Error), http.StatusInternalServerError)
@@ -739,6 +936,33 @@ func repoOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opCommitDiff, new(usererror.Error), http.StatusNotFound)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/commits/{commit_sha}/diff", opCommitDiff)
 
+	opCherryPick := openapi3.Operation{}
+	opCherryPick.WithTags("repository")
+	opCherryPick.WithMapOfAnything(map[string]interface{}{"operationId": "cherryPickCommit"})
+	_ = reflector.SetRequest(&opCherryPick, new(applyCommitRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opCherryPick, new(types.ApplyCommitResponse), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opCherryPick, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opCherryPick, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opCherryPick, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opCherryPick, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opCherryPick, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.SetJSONResponse(&opCherryPick, new(types.MergeViolations), http.StatusUnprocessableEntity)
+	_ = reflector.Spec.AddOperation(http.MethodPost,
+		"/repos/{repo_ref}/commits/{commit_sha}/cherry-pick", opCherryPick)
+
+	opRevert := openapi3.Operation{}
+	opRevert.WithTags("repository")
+	opRevert.WithMapOfAnything(map[string]interface{}{"operationId": "revertCommit"})
+	_ = reflector.SetRequest(&opRevert, new(applyCommitRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opRevert, new(types.ApplyCommitResponse), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opRevert, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opRevert, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opRevert, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opRevert, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opRevert, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.SetJSONResponse(&opRevert, new(types.MergeViolations), http.StatusUnprocessableEntity)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/repos/{repo_ref}/commits/{commit_sha}/revert", opRevert)
+
 	opDiffStats := openapi3.Operation{}
 	opDiffStats.WithTags("repository")
 	opDiffStats.WithMapOfAnything(map[string]interface{}{"operationId": "diffStats"})
@@ -749,6 +973,18 @@ func repoOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opDiffStats, new(usererror.Error), http.StatusForbidden)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/diff-stats/{range}", opDiffStats)
 
+	opDiffCommits := openapi3.Operation{}
+	opDiffCommits.WithTags("repository")
+	opDiffCommits.WithMapOfAnything(map[string]interface{}{"operationId": "diffCommits"})
+	opDiffCommits.WithParameters(queryParameterPage, queryParameterLimit)
+	_ = reflector.SetRequest(&opDiffCommits, new(getRawDiffRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opDiffCommits, new(types.ListCommitResponse), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opDiffCommits, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opDiffCommits, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opDiffCommits, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opDiffCommits, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/repos/{repo_ref}/diff-commits/{range}", opDiffCommits)
+
 	opMergeCheck := openapi3.Operation{}
 	opMergeCheck.WithTags("repository")
 	opMergeCheck.WithMapOfAnything(map[string]interface{}{"operationId": "mergeCheck"})
@@ -45,11 +45,31 @@ type moveSpaceRequest struct {
 	space.MoveInput
 }
 
+type spaceEnvVarRequest struct {
+	spaceRequest
+	Key string `path:"env_var_key"`
+}
+
 type exportSpaceRequest struct {
 	spaceRequest
 	space.ExportInput
 }
 
+var queryParameterInheritedEnvVar = openapi3.ParameterOrRef{
+	Parameter: &openapi3.Parameter{
+		Name:        request.QueryParamInherited,
+		In:          openapi3.ParameterInQuery,
+		Description: ptr.String("The result should include environment variables defined in the parent spaces."),
+		Required:    ptr.Bool(false),
+		Schema: &openapi3.SchemaOrRef{
+			Schema: &openapi3.Schema{
+				Type:    ptrSchemaType(openapi3.SchemaTypeBoolean),
+				Default: ptrptr(false),
+			},
+		},
+	},
+}
+
 var queryParameterSortRepo = openapi3.ParameterOrRef{
 	Parameter: &openapi3.Parameter{
 		Name:        request.QueryParamSort,
@@ -383,4 +403,43 @@ func spaceOperations(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opMembershipList, new(usererror.Error), http.StatusForbidden)
 	_ = reflector.SetJSONResponse(&opMembershipList, new(usererror.Error), http.StatusNotFound)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/spaces/{space_ref}/members", opMembershipList)
+
+	opEnvVarList := openapi3.Operation{}
+	opEnvVarList.WithTags("space")
+	opEnvVarList.WithMapOfAnything(map[string]interface{}{"operationId": "listSpaceEnvVars"})
+	opEnvVarList.WithParameters(queryParameterInheritedEnvVar)
+	_ = reflector.SetRequest(&opEnvVarList, new(spaceRequest), http.MethodGet)
+	_ = reflector.SetJSONResponse(&opEnvVarList, []types.SpaceEnvVar{}, http.StatusOK)
+	_ = reflector.SetJSONResponse(&opEnvVarList, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opEnvVarList, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opEnvVarList, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opEnvVarList, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opEnvVarList, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/spaces/{space_ref}/env-vars", opEnvVarList)
+
+	opEnvVarSet := openapi3.Operation{}
+	opEnvVarSet.WithTags("space")
+	opEnvVarSet.WithMapOfAnything(map[string]interface{}{"operationId": "setSpaceEnvVar"})
+	_ = reflector.SetRequest(&opEnvVarSet, &struct {
+		spaceEnvVarRequest
+		space.EnvVarSetInput
+	}{}, http.MethodPut)
+	_ = reflector.SetJSONResponse(&opEnvVarSet, new(types.SpaceEnvVar), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opEnvVarSet, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opEnvVarSet, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opEnvVarSet, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opEnvVarSet, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opEnvVarSet, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodPut, "/spaces/{space_ref}/env-vars/{env_var_key}", opEnvVarSet)
+
+	opEnvVarDelete := openapi3.Operation{}
+	opEnvVarDelete.WithTags("space")
+	opEnvVarDelete.WithMapOfAnything(map[string]interface{}{"operationId": "deleteSpaceEnvVar"})
+	_ = reflector.SetRequest(&opEnvVarDelete, new(spaceEnvVarRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opEnvVarDelete, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opEnvVarDelete, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.SetJSONResponse(&opEnvVarDelete, new(usererror.Error), http.StatusUnauthorized)
+	_ = reflector.SetJSONResponse(&opEnvVarDelete, new(usererror.Error), http.StatusForbidden)
+	_ = reflector.SetJSONResponse(&opEnvVarDelete, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/spaces/{space_ref}/env-vars/{env_var_key}", opEnvVarDelete)
 }
@@ -31,6 +31,14 @@ type createTokenRequest struct {
 	user.CreateTokenInput
 }
 
+type createSigningKeyRequest struct {
+	user.CreateSigningKeyInput
+}
+
+type deleteSigningKeyRequest struct {
+	ID int64 `path:"signing_key_id"`
+}
+
 var queryParameterMembershipSpaces = openapi3.ParameterOrRef{
 	Parameter: &openapi3.Parameter{
 		Name:        request.QueryParamQuery,
@@ -99,4 +107,31 @@ func buildUser(reflector *openapi3.Reflector) {
 	_ = reflector.SetJSONResponse(&opMemberSpaces, new([]types.MembershipSpace), http.StatusOK)
 	_ = reflector.SetJSONResponse(&opMemberSpaces, new(usererror.Error), http.StatusInternalServerError)
 	_ = reflector.Spec.AddOperation(http.MethodGet, "/user/memberships", opMemberSpaces)
+
+	opListSigningKeys := openapi3.Operation{}
+	opListSigningKeys.WithTags("user")
+	opListSigningKeys.WithMapOfAnything(map[string]interface{}{"operationId": "listSigningKeys"})
+	_ = reflector.SetRequest(&opListSigningKeys, nil, http.MethodGet)
+	_ = reflector.SetJSONResponse(&opListSigningKeys, new([]types.SigningKey), http.StatusOK)
+	_ = reflector.SetJSONResponse(&opListSigningKeys, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodGet, "/user/signing-keys", opListSigningKeys)
+
+	opCreateSigningKey := openapi3.Operation{}
+	opCreateSigningKey.WithTags("user")
+	opCreateSigningKey.WithMapOfAnything(map[string]interface{}{"operationId": "createSigningKey"})
+	_ = reflector.SetRequest(&opCreateSigningKey, new(createSigningKeyRequest), http.MethodPost)
+	_ = reflector.SetJSONResponse(&opCreateSigningKey, new(types.SigningKey), http.StatusCreated)
+	_ = reflector.SetJSONResponse(&opCreateSigningKey, new(usererror.Error), http.StatusBadRequest)
+	_ = reflector.SetJSONResponse(&opCreateSigningKey, new(usererror.Error), http.StatusConflict)
+	_ = reflector.SetJSONResponse(&opCreateSigningKey, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodPost, "/user/signing-keys", opCreateSigningKey)
+
+	opDeleteSigningKey := openapi3.Operation{}
+	opDeleteSigningKey.WithTags("user")
+	opDeleteSigningKey.WithMapOfAnything(map[string]interface{}{"operationId": "deleteSigningKey"})
+	_ = reflector.SetRequest(&opDeleteSigningKey, new(deleteSigningKeyRequest), http.MethodDelete)
+	_ = reflector.SetJSONResponse(&opDeleteSigningKey, nil, http.StatusNoContent)
+	_ = reflector.SetJSONResponse(&opDeleteSigningKey, new(usererror.Error), http.StatusNotFound)
+	_ = reflector.SetJSONResponse(&opDeleteSigningKey, new(usererror.Error), http.StatusInternalServerError)
+	_ = reflector.Spec.AddOperation(http.MethodDelete, "/user/signing-keys/{signing_key_id}", opDeleteSigningKey)
 }
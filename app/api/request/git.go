@@ -17,9 +17,11 @@ package request
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/harness/gitness/app/api/usererror"
+	gitenum "github.com/harness/gitness/git/enum"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 )
@@ -31,12 +33,19 @@ const (
 	QueryParamLineFrom      = "line_from"
 	QueryParamLineTo        = "line_to"
 	QueryParamPath          = "path"
+	QueryParamPrefix        = "prefix"
 	QueryParamSince         = "since"
 	QueryParamUntil         = "until"
 	QueryParamCommitter     = "committer"
+	QueryParamFollow        = "follow"
 	QueryParamInternal      = "internal"
 	QueryParamService       = "service"
 	HeaderParamGitProtocol  = "Git-Protocol"
+
+	QueryParamIgnoreWhitespace = "ignore_whitespace"
+	QueryParamContextLines     = "context_lines"
+	QueryParamRenameThreshold  = "rename_threshold"
+	QueryParamDetectCopies     = "detect_copies"
 )
 
 func GetGitRefFromQueryOrDefault(r *http.Request, deflt string) string {
@@ -99,6 +108,10 @@ func ParseCommitFilter(r *http.Request) (*types.CommitFilter, error) {
 	if err != nil {
 		return nil, err
 	}
+	follow, err := QueryParamAsBoolOrDefault(r, QueryParamFollow, false)
+	if err != nil {
+		return nil, err
+	}
 	return &types.CommitFilter{
 		After: QueryParamOrDefault(r, QueryParamAfter, ""),
 		PaginationFilter: types.PaginationFilter{
@@ -109,6 +122,74 @@ func ParseCommitFilter(r *http.Request) (*types.CommitFilter, error) {
 		Since:     since,
 		Until:     until,
 		Committer: QueryParamOrDefault(r, QueryParamCommitter, ""),
+		Follow:    follow,
+	}, nil
+}
+
+// ParseCommitStatsFilter extracts the commit statistics filter from the url.
+func ParseCommitStatsFilter(r *http.Request) (*types.CommitStatsFilter, error) {
+	// since is optional, skipped if set to 0
+	since, err := QueryParamAsPositiveInt64OrDefault(r, QueryParamSince, 0)
+	if err != nil {
+		return nil, err
+	}
+	// until is optional, skipped if set to 0
+	until, err := QueryParamAsPositiveInt64OrDefault(r, QueryParamUntil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &types.CommitStatsFilter{
+		PaginationFilter: types.PaginationFilter{
+			Page:  ParsePage(r),
+			Limit: ParseLimit(r),
+		},
+		Since:     since,
+		Until:     until,
+		Committer: QueryParamOrDefault(r, QueryParamCommitter, ""),
+	}, nil
+}
+
+// ParseDiffOptions extracts the diff options from the url.
+func ParseDiffOptions(r *http.Request) (*types.DiffOptions, error) {
+	ignoreWhitespace, ok := gitenum.DiffIgnoreWhitespace(
+		QueryParamOrDefault(r, QueryParamIgnoreWhitespace, ""),
+	).Sanitize()
+	if !ok {
+		return nil, usererror.BadRequestf("Parameter '%s' must be one of %v.",
+			QueryParamIgnoreWhitespace, gitenum.DiffIgnoreWhitespaces)
+	}
+
+	var contextLines *int
+	if value, ok := QueryParam(r, QueryParamContextLines); ok && value != "" {
+		lines, err := strconv.Atoi(value)
+		if err != nil || lines < 0 {
+			return nil, usererror.BadRequestf("Parameter '%s' must be a non-negative integer.",
+				QueryParamContextLines)
+		}
+		contextLines = &lines
+	}
+
+	// rename threshold is optional, git default is used if not provided (0)
+	var renameThreshold int
+	if value, ok := QueryParam(r, QueryParamRenameThreshold); ok && value != "" {
+		threshold, err := strconv.Atoi(value)
+		if err != nil || threshold < 1 || threshold > 100 {
+			return nil, usererror.BadRequestf("Parameter '%s' must be a percentage between 1 and 100.",
+				QueryParamRenameThreshold)
+		}
+		renameThreshold = threshold
+	}
+
+	detectCopies, err := QueryParamAsBoolOrDefault(r, QueryParamDetectCopies, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.DiffOptions{
+		IgnoreWhitespace: ignoreWhitespace,
+		ContextLines:     contextLines,
+		RenameThreshold:  renameThreshold,
+		DetectCopies:     detectCopies,
 	}, nil
 }
 
@@ -0,0 +1,49 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDiffOptions_RenameThreshold(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    int
+		wantErr bool
+	}{
+		{query: "", want: 0},
+		{query: "?rename_threshold=1", want: 1},
+		{query: "?rename_threshold=100", want: 100},
+		{query: "?rename_threshold=0", wantErr: true},
+		{query: "?rename_threshold=101", wantErr: true},
+		{query: "?rename_threshold=abc", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			opts, err := ParseDiffOptions(httptest.NewRequest("GET", "/diff"+test.query, nil))
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.want, opts.RenameThreshold)
+		})
+	}
+}
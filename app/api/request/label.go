@@ -0,0 +1,82 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/types"
+)
+
+const (
+	PathParamLabelName = "label_name"
+	PathParamLabelID   = "label_id"
+
+	QueryParamLabelID   = "label_id"
+	QueryParamInherited = "inherited"
+)
+
+// GetLabelNameFromPath extracts the label name from the URL.
+func GetLabelNameFromPath(r *http.Request) (string, error) {
+	rawName, err := PathParamOrError(r, PathParamLabelName)
+	if err != nil {
+		return "", err
+	}
+
+	// paths are unescaped
+	return url.PathUnescape(rawName)
+}
+
+// GetLabelIDFromPath extracts the label ID from the URL.
+func GetLabelIDFromPath(r *http.Request) (int64, error) {
+	return PathParamAsPositiveInt64(r, PathParamLabelID)
+}
+
+// ParseLabelFilter extracts the label query parameters from the url.
+func ParseLabelFilter(r *http.Request) (*types.LabelFilter, error) {
+	inherited, err := QueryParamAsBoolOrDefault(r, QueryParamInherited, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.LabelFilter{
+		ListQueryFilter: ParseListQueryFilterFromRequest(r),
+		Inherited:       inherited,
+	}, nil
+}
+
+// parseLabelIDs extracts the list of label IDs from the url.
+func parseLabelIDs(r *http.Request) ([]int64, error) {
+	strIDs, _ := QueryParamList(r, QueryParamLabelID)
+	m := make(map[int64]struct{}) // use map to eliminate duplicates
+	for _, s := range strIDs {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || id <= 0 {
+			return nil, usererror.BadRequestf("Parameter '%s' must be a list of positive integers.",
+				QueryParamLabelID)
+		}
+		m[id] = struct{}{}
+	}
+
+	ids := make([]int64, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
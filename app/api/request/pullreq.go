@@ -25,6 +25,7 @@ const (
 	PathParamPullReqNumber    = "pullreq_number"
 	PathParamPullReqCommentID = "pullreq_comment_id"
 	PathParamReviewerID       = "pullreq_reviewer_id"
+	PathParamReactionEmoji    = "pullreq_reaction_emoji"
 )
 
 func GetPullReqNumberFromPath(r *http.Request) (int64, error) {
@@ -39,6 +40,15 @@ func GetPullReqCommentIDPath(r *http.Request) (int64, error) {
 	return PathParamAsPositiveInt64(r, PathParamPullReqCommentID)
 }
 
+func GetReactionEmojiFromPath(r *http.Request) (enum.PullReqReaction, error) {
+	emoji, err := PathParamOrError(r, PathParamReactionEmoji)
+	if err != nil {
+		return "", err
+	}
+
+	return enum.PullReqReaction(emoji), nil
+}
+
 // ParseSortPullReq extracts the pull request sort parameter from the url.
 func ParseSortPullReq(r *http.Request) enum.PullReqSort {
 	result, _ := enum.PullReqSort(r.URL.Query().Get(QueryParamSort)).Sanitize()
@@ -70,6 +80,10 @@ func ParsePullReqFilter(r *http.Request) (*types.PullReqFilter, error) {
 	if err != nil {
 		return nil, err
 	}
+	labelIDs, err := parseLabelIDs(r)
+	if err != nil {
+		return nil, err
+	}
 	return &types.PullReqFilter{
 		Page:          ParsePage(r),
 		Size:          ParseLimit(r),
@@ -79,6 +93,7 @@ func ParsePullReqFilter(r *http.Request) (*types.PullReqFilter, error) {
 		SourceBranch:  r.URL.Query().Get("source_branch"),
 		TargetBranch:  r.URL.Query().Get("target_branch"),
 		States:        parsePullReqStates(r),
+		LabelIDs:      labelIDs,
 		Sort:          ParseSortPullReq(r),
 		Order:         ParseOrder(r),
 	}, nil
@@ -0,0 +1,42 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package request
+
+import (
+	"net/http"
+
+	"github.com/harness/gitness/types"
+)
+
+const (
+	PathParamEnvVarKey = "env_var_key"
+)
+
+// GetEnvVarKeyFromPath extracts the environment variable key from the URL.
+func GetEnvVarKeyFromPath(r *http.Request) (string, error) {
+	return PathParamOrError(r, PathParamEnvVarKey)
+}
+
+// ParseSpaceEnvVarFilter extracts the space environment variable query parameters from the url.
+func ParseSpaceEnvVarFilter(r *http.Request) (*types.SpaceEnvVarFilter, error) {
+	inherited, err := QueryParamAsBoolOrDefault(r, QueryParamInherited, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.SpaceEnvVarFilter{
+		Inherited: inherited,
+	}, nil
+}
@@ -19,9 +19,14 @@ import (
 )
 
 const (
-	PathParamTokenUID = "token_uid"
+	PathParamTokenUID     = "token_uid"
+	PathParamSigningKeyID = "signing_key_id"
 )
 
 func GetTokenUIDFromPath(r *http.Request) (string, error) {
 	return PathParamOrError(r, PathParamTokenUID)
 }
+
+func GetSigningKeyIDFromPath(r *http.Request) (int64, error) {
+	return PathParamAsPositiveInt64(r, PathParamSigningKeyID)
+}
@@ -81,6 +81,10 @@ func (a *JWTAuthenticator) Authenticate(r *http.Request) (*auth.Session, error)
 		return nil, errors.New("invalid HMAC signature for JWT")
 	}
 
+	if principal.Blocked {
+		return nil, errors.New("principal is blocked")
+	}
+
 	var metadata auth.Metadata
 	switch {
 	case claims.Token != nil:
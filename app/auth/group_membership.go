@@ -0,0 +1,65 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/harness/gitness/types/enum"
+)
+
+// GroupMembership is a space membership granted to all members of a group of an external identity provider.
+type GroupMembership struct {
+	Group    string
+	SpaceRef string
+	Role     enum.MembershipRole
+}
+
+// ParseGroupMemberships parses group memberships in the format "group:spaceRef:role".
+// NOTE: The group is allowed to contain colons, the space reference and the role are not.
+func ParseGroupMemberships(raw []string) ([]GroupMembership, error) {
+	memberships := make([]GroupMembership, 0, len(raw))
+	for _, entry := range raw {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		idxRole := strings.LastIndex(entry, ":")
+		if idxRole <= 0 {
+			return nil, fmt.Errorf("group membership '%s' isn't of format 'group:spaceRef:role'", entry)
+		}
+
+		idxSpace := strings.LastIndex(entry[:idxRole], ":")
+		if idxSpace <= 0 || idxSpace+1 == idxRole {
+			return nil, fmt.Errorf("group membership '%s' isn't of format 'group:spaceRef:role'", entry)
+		}
+
+		role, ok := enum.MembershipRole(entry[idxRole+1:]).Sanitize()
+		if !ok {
+			return nil, fmt.Errorf("group membership '%s' has unknown role, valid values are: %v",
+				entry, enum.MembershipRoles)
+		}
+
+		memberships = append(memberships, GroupMembership{
+			Group:    entry[:idxSpace],
+			SpaceRef: entry[idxSpace+1 : idxRole],
+			Role:     role,
+		})
+	}
+
+	return memberships, nil
+}
@@ -0,0 +1,299 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+const (
+	// IdentityProvider identifies the LDAP directory in the identities of principals.
+	IdentityProvider = "ldap"
+
+	requestTimeout = 30 * time.Second
+	// pageSize is the number of entries requested per page when listing the directory.
+	pageSize = 500
+)
+
+var (
+	ErrNotFound           = errors.New("user not found in directory")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Config defines the parameters of an LDAP directory.
+type Config struct {
+	// URL of the directory server, e.g. "ldaps://ldap.example.com:636".
+	URL                string
+	StartTLS           bool
+	InsecureSkipVerify bool
+
+	// BindDN and BindPassword are the credentials of the service account used to search the directory.
+	BindDN       string
+	BindPassword string
+
+	UserBaseDN           string
+	UserFilter           string
+	UIDAttribute         string
+	EmailAttribute       string
+	DisplayNameAttribute string
+
+	GroupBaseDN          string
+	GroupFilter          string
+	GroupMemberAttribute string
+	GroupNameAttribute   string
+}
+
+// Entry is a user of the directory.
+type Entry struct {
+	DN          string
+	UID         string
+	Email       string
+	DisplayName string
+	Groups      []string
+}
+
+// Directory is an abstraction of an LDAP directory.
+// NOTE: Abstract to allow for using a stand-in directory in tests.
+type Directory interface {
+	// Authenticate verifies the password of the user with the provided uid or email.
+	// Returns ErrNotFound if the user doesn't exist and ErrInvalidCredentials if the password is invalid.
+	Authenticate(ctx context.Context, login, password string) (*Entry, error)
+
+	// ListUsers returns all users of the directory (including their groups).
+	ListUsers(ctx context.Context) ([]*Entry, error)
+}
+
+// Client implements Directory using an LDAP server.
+type Client struct {
+	config Config
+}
+
+func NewClient(config Config) *Client {
+	return &Client{
+		config: config,
+	}
+}
+
+// Authenticate verifies the password of the user with the provided uid or email.
+func (c *Client) Authenticate(_ context.Context, login, password string) (*Entry, error) {
+	conn, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	filter := fmt.Sprintf("(&%s(|(%s=%s)(%s=%s)))", c.config.UserFilter,
+		c.config.UIDAttribute, ldap.EscapeFilter(login),
+		c.config.EmailAttribute, ldap.EscapeFilter(login))
+
+	result, err := conn.Search(c.userSearchRequest(filter, 2))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search user: %w", err)
+	}
+
+	if len(result.Entries) == 0 {
+		return nil, ErrNotFound
+	}
+	if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("login '%s' matches multiple users", login)
+	}
+
+	// an empty password would result in an unauthenticated bind that always succeeds.
+	if password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	userDN := result.Entries[0].DN
+	if err = conn.Bind(userDN, password); ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to bind as user: %w", err)
+	}
+
+	// the groups are searched using the service account, users might not be allowed to.
+	if err = c.bind(conn); err != nil {
+		return nil, err
+	}
+
+	entry := c.toEntry(result.Entries[0])
+
+	groups, err := c.listGroups(conn, fmt.Sprintf("(%s=%s)",
+		c.config.GroupMemberAttribute, ldap.EscapeFilter(userDN)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, group := range groups {
+		entry.Groups = append(entry.Groups, group.GetAttributeValue(c.config.GroupNameAttribute))
+	}
+
+	return entry, nil
+}
+
+// ListUsers returns all users of the directory (including their groups).
+func (c *Client) ListUsers(_ context.Context) ([]*Entry, error) {
+	conn, err := c.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result, err := conn.SearchWithPaging(c.userSearchRequest(c.config.UserFilter, 0), pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	groups, err := c.listGroups(conn, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// index the groups of the users by their normalized DN.
+	userGroups := make(map[string][]string)
+	for _, group := range groups {
+		name := group.GetAttributeValue(c.config.GroupNameAttribute)
+		for _, member := range group.GetEqualFoldAttributeValues(c.config.GroupMemberAttribute) {
+			memberDN := NormalizeDN(member)
+			userGroups[memberDN] = append(userGroups[memberDN], name)
+		}
+	}
+
+	entries := make([]*Entry, len(result.Entries))
+	for i, ldapEntry := range result.Entries {
+		entries[i] = c.toEntry(ldapEntry)
+		entries[i].Groups = userGroups[NormalizeDN(ldapEntry.DN)]
+	}
+
+	return entries, nil
+}
+
+func (c *Client) userSearchRequest(filter string, sizeLimit int) *ldap.SearchRequest {
+	return ldap.NewSearchRequest(
+		c.config.UserBaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, sizeLimit, 0, false,
+		filter,
+		[]string{c.config.UIDAttribute, c.config.EmailAttribute, c.config.DisplayNameAttribute},
+		nil,
+	)
+}
+
+// listGroups returns the groups matching the filter, or all groups if the filter is empty.
+func (c *Client) listGroups(conn *ldap.Conn, filter string) ([]*ldap.Entry, error) {
+	if c.config.GroupBaseDN == "" {
+		return nil, nil
+	}
+
+	searchRequest := ldap.NewSearchRequest(
+		c.config.GroupBaseDN,
+		ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf("(&%s%s)", c.config.GroupFilter, filter),
+		[]string{c.config.GroupNameAttribute, c.config.GroupMemberAttribute},
+		nil,
+	)
+
+	result, err := conn.SearchWithPaging(searchRequest, pageSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list groups: %w", err)
+	}
+
+	return result.Entries, nil
+}
+
+func (c *Client) toEntry(ldapEntry *ldap.Entry) *Entry {
+	return &Entry{
+		DN:          ldapEntry.DN,
+		UID:         ldapEntry.GetEqualFoldAttributeValue(c.config.UIDAttribute),
+		Email:       ldapEntry.GetEqualFoldAttributeValue(c.config.EmailAttribute),
+		DisplayName: ldapEntry.GetEqualFoldAttributeValue(c.config.DisplayNameAttribute),
+	}
+}
+
+// connect opens a connection to the directory server bound as the service account.
+func (c *Client) connect() (*ldap.Conn, error) {
+	serverURL, err := url.Parse(c.config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ldap url: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         serverURL.Hostname(),
+		InsecureSkipVerify: c.config.InsecureSkipVerify, //nolint:gosec // explicitly configured by the admin
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	conn, err := ldap.DialURL(c.config.URL, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ldap server: %w", err)
+	}
+
+	conn.SetTimeout(requestTimeout)
+
+	if c.config.StartTLS {
+		if err = conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if err = c.bind(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *Client) bind(conn *ldap.Conn) error {
+	if c.config.BindDN == "" {
+		if err := conn.UnauthenticatedBind(""); err != nil {
+			return fmt.Errorf("failed to bind anonymously: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := conn.Bind(c.config.BindDN, c.config.BindPassword); err != nil {
+		return fmt.Errorf("failed to bind as service account: %w", err)
+	}
+
+	return nil
+}
+
+// NormalizeDN returns the normalized DN used to identify users of the directory.
+func NormalizeDN(dn string) string {
+	parsed, err := ldap.ParseDN(dn)
+	if err != nil {
+		return strings.ToLower(dn)
+	}
+
+	parts := make([]string, len(parsed.RDNs))
+	for i, rdn := range parsed.RDNs {
+		attrs := make([]string, len(rdn.Attributes))
+		for j, attr := range rdn.Attributes {
+			attrs[j] = strings.ToLower(attr.Type) + "=" + strings.ToLower(attr.Value)
+		}
+		parts[i] = strings.Join(attrs, "+")
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"github.com/harness/gitness/types"
+
+	"github.com/google/wire"
+)
+
+// WireSet provides a wire set for this package.
+var WireSet = wire.NewSet(
+	ProvideDirectory,
+)
+
+// ProvideDirectory provides the LDAP directory, or nil if LDAP isn't configured.
+func ProvideDirectory(config *types.Config) Directory {
+	if config.LDAP.URL == "" {
+		return nil
+	}
+
+	return NewClient(Config{
+		URL:                  config.LDAP.URL,
+		StartTLS:             config.LDAP.StartTLS,
+		InsecureSkipVerify:   config.LDAP.InsecureSkipVerify,
+		BindDN:               config.LDAP.BindDN,
+		BindPassword:         config.LDAP.BindPassword,
+		UserBaseDN:           config.LDAP.UserBaseDN,
+		UserFilter:           config.LDAP.UserFilter,
+		UIDAttribute:         config.LDAP.UIDAttribute,
+		EmailAttribute:       config.LDAP.EmailAttribute,
+		DisplayNameAttribute: config.LDAP.DisplayNameAttribute,
+		GroupBaseDN:          config.LDAP.GroupBaseDN,
+		GroupFilter:          config.LDAP.GroupFilter,
+		GroupMemberAttribute: config.LDAP.GroupMemberAttribute,
+		GroupNameAttribute:   config.LDAP.GroupNameAttribute,
+	})
+}
@@ -0,0 +1,348 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"golang.org/x/oauth2"
+)
+
+const (
+	discoveryPath     = "/.well-known/openid-configuration"
+	discoveryCacheTTL = time.Hour
+	requestTimeout    = 30 * time.Second
+	// clockSkew is the tolerated clock difference when validating the expiry of ID tokens.
+	clockSkew = 2 * time.Minute
+)
+
+var (
+	ErrInvalidIDToken = errors.New("invalid ID token")
+)
+
+// Config defines the parameters of an OpenID Connect provider.
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	// GroupsClaim is the name of the claim containing the groups of the user.
+	GroupsClaim string
+}
+
+// Claims contains the identity information of an authenticated user.
+type Claims struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+	Groups            []string
+}
+
+// Provider implements the authorization code flow (with PKCE) of an OpenID Connect provider.
+type Provider struct {
+	config Config
+	client *http.Client
+
+	mx           sync.Mutex
+	discovery    *discoveryDocument
+	discoveredAt time.Time
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+func NewProvider(config Config) *Provider {
+	config.Issuer = strings.TrimSuffix(config.Issuer, "/")
+
+	return &Provider{
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Issuer returns the issuer identifier of the provider.
+func (p *Provider) Issuer() string {
+	return p.config.Issuer
+}
+
+// AuthCodeURL returns the URL of the provider the user has to be redirected to in order to authenticate.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	oauthConfig, _, err := p.oauthConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return oauthConfig.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("nonce", nonce),
+		oauth2.SetAuthURLParam("code_challenge", challengeS256(verifier)),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+// Exchange exchanges the authorization code for the tokens of the user and returns the validated claims.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	oauthConfig, discovery, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+
+	token, err := oauthConfig.Exchange(ctx, code, oauth2.SetAuthURLParam("code_verifier", verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, fmt.Errorf("%w: token response doesn't contain an ID token", ErrInvalidIDToken)
+	}
+
+	// The ID token was received directly from the token endpoint over TLS,
+	// which allows to skip the validation of the signature (OpenID Connect Core 1.0, 3.1.3.7).
+	idToken := jwt.MapClaims{}
+	if _, _, err = new(jwt.Parser).ParseUnverified(rawIDToken, idToken); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIDToken, err)
+	}
+
+	if err = p.validate(idToken, discovery.Issuer, nonce); err != nil {
+		return nil, err
+	}
+
+	// fall back to the userinfo endpoint in case the ID token doesn't contain the profile of the user.
+	if (stringClaim(idToken, "email") == "" || p.groupsMissing(idToken)) && discovery.UserinfoEndpoint != "" {
+		userinfo, err := p.userinfo(ctx, discovery.UserinfoEndpoint, token)
+		if err != nil {
+			return nil, err
+		}
+
+		// the subject of the userinfo response has to match the subject of the ID token.
+		if stringClaim(userinfo, "sub") == stringClaim(idToken, "sub") {
+			for k, v := range userinfo {
+				if _, ok := idToken[k]; !ok {
+					idToken[k] = v
+				}
+			}
+		}
+	}
+
+	return &Claims{
+		Issuer:            stringClaim(idToken, "iss"),
+		Subject:           stringClaim(idToken, "sub"),
+		Email:             stringClaim(idToken, "email"),
+		EmailVerified:     boolClaim(idToken, "email_verified"),
+		Name:              stringClaim(idToken, "name"),
+		PreferredUsername: stringClaim(idToken, "preferred_username"),
+		Groups:            stringsClaim(idToken, p.config.GroupsClaim),
+	}, nil
+}
+
+func (p *Provider) validate(idToken jwt.MapClaims, issuer, nonce string) error {
+	if stringClaim(idToken, "iss") != issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidIDToken)
+	}
+
+	if stringClaim(idToken, "sub") == "" {
+		return fmt.Errorf("%w: missing subject", ErrInvalidIDToken)
+	}
+
+	audienceValid := false
+	for _, aud := range stringsClaim(idToken, "aud") {
+		if aud == p.config.ClientID {
+			audienceValid = true
+			break
+		}
+	}
+	if !audienceValid {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidIDToken)
+	}
+
+	exp, ok := idToken["exp"].(float64)
+	if !ok || time.Unix(int64(exp), 0).Add(clockSkew).Before(time.Now()) {
+		return fmt.Errorf("%w: token is expired", ErrInvalidIDToken)
+	}
+
+	if stringClaim(idToken, "nonce") != nonce {
+		return fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+
+	return nil
+}
+
+func (p *Provider) groupsMissing(claims jwt.MapClaims) bool {
+	if p.config.GroupsClaim == "" {
+		return false
+	}
+
+	_, ok := claims[p.config.GroupsClaim]
+	return !ok
+}
+
+func (p *Provider) userinfo(ctx context.Context, endpoint string, token *oauth2.Token) (jwt.MapClaims, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create userinfo request: %w", err)
+	}
+
+	token.SetAuthHeader(req)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request userinfo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("userinfo endpoint responded with status %d", resp.StatusCode)
+	}
+
+	userinfo := jwt.MapClaims{}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&userinfo); err != nil {
+		return nil, fmt.Errorf("failed to decode userinfo: %w", err)
+	}
+
+	return userinfo, nil
+}
+
+func (p *Provider) oauthConfig(ctx context.Context) (*oauth2.Config, *discoveryDocument, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       p.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, discovery, nil
+}
+
+// discover returns the (cached) discovery document of the provider.
+func (p *Provider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mx.Lock()
+	defer p.mx.Unlock()
+
+	if p.discovery != nil && time.Since(p.discoveredAt) < discoveryCacheTTL {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.Issuer+discoveryPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("discovery endpoint responded with status %d", resp.StatusCode)
+	}
+
+	discovery := &discoveryDocument{}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(discovery); err != nil {
+		return nil, fmt.Errorf("failed to decode discovery document: %w", err)
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("discovery document issuer %q doesn't match the configured issuer", discovery.Issuer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" {
+		return nil, errors.New("discovery document is missing the authorization or token endpoint")
+	}
+
+	p.discovery = discovery
+	p.discoveredAt = time.Now()
+
+	return discovery, nil
+}
+
+// RandomString returns a cryptographically secure random string usable as state, nonce or PKCE code verifier.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random bytes: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func challengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
+
+func boolClaim(claims jwt.MapClaims, name string) bool {
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		// some providers return boolean claims as strings.
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+// stringsClaim returns the values of a claim that is either a single string or a list of strings.
+func stringsClaim(claims jwt.MapClaims, name string) []string {
+	switch v := claims[name].(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testClientID = "gitness"
+	testNonce    = "nonce"
+)
+
+func validIDToken(issuer string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   issuer,
+		"sub":   "subject",
+		"aud":   testClientID,
+		"exp":   float64(time.Now().Add(time.Hour).Unix()),
+		"nonce": testNonce,
+	}
+}
+
+func TestProvider_Validate(t *testing.T) {
+	const issuer = "https://idp.example.com"
+
+	tests := []struct {
+		name   string
+		modify func(claims jwt.MapClaims)
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			modify: func(jwt.MapClaims) {},
+			valid:  true,
+		},
+		{
+			name:   "audience list containing the client",
+			modify: func(claims jwt.MapClaims) { claims["aud"] = []any{"other", testClientID} },
+			valid:  true,
+		},
+		{
+			name:   "expired within the clock skew",
+			modify: func(claims jwt.MapClaims) { claims["exp"] = float64(time.Now().Add(-time.Minute).Unix()) },
+			valid:  true,
+		},
+		{
+			name:   "unexpected issuer",
+			modify: func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+		},
+		{
+			name:   "missing issuer",
+			modify: func(claims jwt.MapClaims) { delete(claims, "iss") },
+		},
+		{
+			name:   "missing subject",
+			modify: func(claims jwt.MapClaims) { delete(claims, "sub") },
+		},
+		{
+			name:   "unexpected audience",
+			modify: func(claims jwt.MapClaims) { claims["aud"] = "other" },
+		},
+		{
+			name:   "audience list without the client",
+			modify: func(claims jwt.MapClaims) { claims["aud"] = []any{"other", "another"} },
+		},
+		{
+			name:   "missing audience",
+			modify: func(claims jwt.MapClaims) { delete(claims, "aud") },
+		},
+		{
+			name:   "expired",
+			modify: func(claims jwt.MapClaims) { claims["exp"] = float64(time.Now().Add(-time.Hour).Unix()) },
+		},
+		{
+			name:   "missing expiry",
+			modify: func(claims jwt.MapClaims) { delete(claims, "exp") },
+		},
+		{
+			name:   "nonce mismatch",
+			modify: func(claims jwt.MapClaims) { claims["nonce"] = "other" },
+		},
+		{
+			name:   "missing nonce",
+			modify: func(claims jwt.MapClaims) { delete(claims, "nonce") },
+		},
+	}
+
+	p := NewProvider(Config{Issuer: issuer, ClientID: testClientID})
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims := validIDToken(issuer)
+			test.modify(claims)
+
+			err := p.validate(claims, issuer, testNonce)
+			if test.valid {
+				require.NoError(t, err)
+				return
+			}
+
+			require.True(t, errors.Is(err, ErrInvalidIDToken), "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	var (
+		server     *httptest.Server
+		idToken    jwt.MapClaims
+		verifier   string
+		authHeader string
+	)
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case discoveryPath:
+			_ = json.NewEncoder(w).Encode(discoveryDocument{
+				Issuer:                server.URL,
+				AuthorizationEndpoint: server.URL + "/authorize",
+				TokenEndpoint:         server.URL + "/token",
+				UserinfoEndpoint:      server.URL + "/userinfo",
+			})
+		case "/token":
+			_ = r.ParseForm()
+			verifier = r.PostForm.Get("code_verifier")
+
+			raw, err := jwt.NewWithClaims(jwt.SigningMethodHS256, idToken).SignedString([]byte("secret"))
+			require.NoError(t, err)
+
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "access",
+				"token_type":   "Bearer",
+				"id_token":     raw,
+			})
+		case "/userinfo":
+			authHeader = r.Header.Get("Authorization")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"sub":            "subject",
+				"email":          "user@example.com",
+				"email_verified": "true",
+				"groups":         []string{"developers", "admins"},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewProvider(Config{Issuer: server.URL + "/", ClientID: testClientID, GroupsClaim: "groups"})
+	ctx := context.Background()
+
+	url, err := p.AuthCodeURL(ctx, "state", testNonce, "verifier")
+	require.NoError(t, err)
+	require.Contains(t, url, server.URL+"/authorize?")
+	require.Contains(t, url, "code_challenge="+challengeS256("verifier"))
+
+	// the profile of the user is read from the userinfo endpoint if missing in the ID token.
+	idToken = validIDToken(server.URL)
+	idToken["name"] = "User"
+
+	claims, err := p.Exchange(ctx, "code", "verifier", testNonce)
+	require.NoError(t, err)
+	require.Equal(t, "verifier", verifier)
+	require.Equal(t, "Bearer access", authHeader)
+	require.Equal(t, &Claims{
+		Issuer:        server.URL,
+		Subject:       "subject",
+		Email:         "user@example.com",
+		EmailVerified: true,
+		Name:          "User",
+		Groups:        []string{"developers", "admins"},
+	}, claims)
+
+	// the userinfo of another subject is ignored.
+	idToken = validIDToken(server.URL)
+	idToken["sub"] = "other"
+
+	claims, err = p.Exchange(ctx, "code", "verifier", testNonce)
+	require.NoError(t, err)
+	require.Equal(t, "other", claims.Subject)
+	require.Equal(t, "", claims.Email)
+	require.False(t, claims.EmailVerified)
+
+	idToken = validIDToken(server.URL)
+	_, err = p.Exchange(ctx, "code", "verifier", "other")
+	require.True(t, errors.Is(err, ErrInvalidIDToken))
+}
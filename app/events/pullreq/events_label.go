@@ -0,0 +1,96 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const LabelAddedEvent events.EventType = "label-added"
+
+type LabelAddedPayload struct {
+	Base
+	LabelID int64 `json:"label_id"`
+	// LabelName, LabelDescription and LabelColor describe the label at the time of the change,
+	// as the label might be updated or deleted before the event is processed.
+	LabelName        string          `json:"label_name"`
+	LabelDescription string          `json:"label_description"`
+	LabelColor       enum.LabelColor `json:"label_color"`
+}
+
+func (r *Reporter) LabelAdded(
+	ctx context.Context,
+	payload *LabelAddedPayload,
+) {
+	if payload == nil {
+		return
+	}
+
+	eventID, err := events.ReporterSendEvent(r.innerReporter, ctx, LabelAddedEvent, payload)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msgf("failed to send pull request label added event")
+		return
+	}
+
+	log.Ctx(ctx).Debug().Msgf("reported pull request label added event with id '%s'", eventID)
+}
+
+func (r *Reader) RegisterLabelAdded(
+	fn events.HandlerFunc[*LabelAddedPayload],
+	opts ...events.HandlerOption,
+) error {
+	return events.ReaderRegisterEvent(r.innerReader, LabelAddedEvent, fn, opts...)
+}
+
+const LabelRemovedEvent events.EventType = "label-removed"
+
+type LabelRemovedPayload struct {
+	Base
+	LabelID int64 `json:"label_id"`
+	// LabelName, LabelDescription and LabelColor describe the label at the time of the change,
+	// as the label might be updated or deleted before the event is processed.
+	LabelName        string          `json:"label_name"`
+	LabelDescription string          `json:"label_description"`
+	LabelColor       enum.LabelColor `json:"label_color"`
+}
+
+func (r *Reporter) LabelRemoved(
+	ctx context.Context,
+	payload *LabelRemovedPayload,
+) {
+	if payload == nil {
+		return
+	}
+
+	eventID, err := events.ReporterSendEvent(r.innerReporter, ctx, LabelRemovedEvent, payload)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msgf("failed to send pull request label removed event")
+		return
+	}
+
+	log.Ctx(ctx).Debug().Msgf("reported pull request label removed event with id '%s'", eventID)
+}
+
+func (r *Reader) RegisterLabelRemoved(
+	fn events.HandlerFunc[*LabelRemovedPayload],
+	opts ...events.HandlerOption,
+) error {
+	return events.ReaderRegisterEvent(r.innerReader, LabelRemovedEvent, fn, opts...)
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/harness/gitness/events"
+
+	"github.com/rs/zerolog/log"
+)
+
+const UserMentionedEvent events.EventType = "user-mentioned"
+
+type UserMentionedPayload struct {
+	Base
+	// ActivityID is the ID of the comment in which the principals got mentioned.
+	// It's zero if the principals got mentioned in the pull request description.
+	ActivityID   int64   `json:"activity_id,omitempty"`
+	MentionedIDs []int64 `json:"mentioned_ids"`
+}
+
+func (r *Reporter) UserMentioned(
+	ctx context.Context,
+	payload *UserMentionedPayload,
+) {
+	if payload == nil || len(payload.MentionedIDs) == 0 {
+		return
+	}
+
+	eventID, err := events.ReporterSendEvent(r.innerReporter, ctx, UserMentionedEvent, payload)
+	if err != nil {
+		log.Ctx(ctx).Err(err).Msgf("failed to send pull request user mentioned event")
+		return
+	}
+
+	log.Ctx(ctx).Debug().Msgf("reported pull request user mentioned event with id '%s'", eventID)
+}
+
+func (r *Reader) RegisterUserMentioned(
+	fn events.HandlerFunc[*UserMentionedPayload],
+	opts ...events.HandlerOption,
+) error {
+	return events.ReaderRegisterEvent(r.innerReader, UserMentionedEvent, fn, opts...)
+}
@@ -19,7 +19,9 @@ import (
 	"github.com/harness/gitness/app/api/controller/limiter"
 	"github.com/harness/gitness/app/auth/authz"
 	eventsgit "github.com/harness/gitness/app/events/git"
+	"github.com/harness/gitness/app/services/housekeeping"
 	"github.com/harness/gitness/app/services/protection"
+	"github.com/harness/gitness/app/services/signing"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
 	"github.com/harness/gitness/git"
@@ -52,6 +54,8 @@ func ProvideController(
 	protectionManager *protection.Manager,
 	githookFactory hook.ClientFactory,
 	limiter limiter.ResourceLimiter,
+	signatureVerifier *signing.Verifier,
+	housekeeping *housekeeping.Service,
 ) *githook.Controller {
 	ctrl := githook.NewController(
 		authorizer,
@@ -62,7 +66,9 @@ func ProvideController(
 		pullreqStore,
 		urlProvider,
 		protectionManager,
-		limiter)
+		limiter,
+		signatureVerifier,
+		housekeeping)
 
 	// TODO: improve wiring if possible
 	if fct, ok := githookFactory.(*ControllerClientFactory); ok {
@@ -0,0 +1,77 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package environ
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+)
+
+// Service provides the pipeline environment variables of spaces.
+type Service struct {
+	spaceStore  store.SpaceStore
+	envVarStore store.SpaceEnvVarStore
+}
+
+func NewService(spaceStore store.SpaceStore, envVarStore store.SpaceEnvVarStore) *Service {
+	return &Service{
+		spaceStore:  spaceStore,
+		envVarStore: envVarStore,
+	}
+}
+
+// ListInherited returns the effective environment variables of the space, ordered by key.
+// It includes the variables of all its parent spaces, where variables of sub spaces
+// override variables with the same key of their parent spaces.
+func (s *Service) ListInherited(ctx context.Context, spaceID int64) ([]*types.SpaceEnvVar, error) {
+	spaceIDs, err := s.spaceStore.GetAncestorIDs(ctx, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent spaces: %w", err)
+	}
+
+	envVars, err := s.envVarStore.List(ctx, spaceIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environment variables: %w", err)
+	}
+
+	// spaceIDs starts with the space itself, followed by its ancestors.
+	depth := make(map[int64]int, len(spaceIDs))
+	for i, id := range spaceIDs {
+		depth[id] = i
+	}
+
+	effective := make(map[string]*types.SpaceEnvVar)
+	for _, envVar := range envVars {
+		current, ok := effective[envVar.Key]
+		if !ok || depth[envVar.SpaceID] < depth[current.SpaceID] {
+			effective[envVar.Key] = envVar
+		}
+	}
+
+	result := make([]*types.SpaceEnvVar, 0, len(effective))
+	for _, envVar := range effective {
+		result = append(result, envVar)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result, nil
+}
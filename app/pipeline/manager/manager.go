@@ -16,29 +16,46 @@ package manager
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/harness/gitness/app/bootstrap"
 	"github.com/harness/gitness/app/jwt"
 	"github.com/harness/gitness/app/pipeline/converter"
+	"github.com/harness/gitness/app/pipeline/environ"
 	"github.com/harness/gitness/app/pipeline/file"
 	"github.com/harness/gitness/app/pipeline/scheduler"
+	"github.com/harness/gitness/app/pipeline/secret"
 	"github.com/harness/gitness/app/sse"
 	"github.com/harness/gitness/app/store"
 	urlprovider "github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/encrypt"
 	"github.com/harness/gitness/livelog"
 	gitness_store "github.com/harness/gitness/store"
 	"github.com/harness/gitness/types"
 	"github.com/harness/gitness/types/enum"
 
+	"github.com/drone/drone-go/drone"
+	"github.com/drone/runner-go/environ/provider"
+	"github.com/drone/runner-go/registry/auths"
 	"github.com/rs/zerolog/log"
 )
 
 const (
+	// RegistryCredentialsSecret is the name of the secret containing the docker config with the credentials
+	// of the docker registries available to the stage. It's not a valid secret UID to avoid conflicts.
+	RegistryCredentialsSecret = "$gitness_registry_credentials"
+
+	// EnvironSecret is the name of the secret containing the environment variables (as JSON encoded
+	// list of environ variables) defined in the space of the repo and its parent spaces.
+	EnvironSecret = "$gitness_environ"
+
 	// pipelineJWTLifetime specifies the max lifetime of an ephemeral pipeline jwt token.
 	pipelineJWTLifetime = 72 * time.Hour
 	// pipelineJWTRole specifies the role of an ephemeral pipeline jwt token.
@@ -47,6 +64,13 @@ const (
 
 var noContext = context.Background()
 
+// IsReservedSecret returns true if the name is reserved for a secret injected by the manager.
+// Reserved secrets are only meant for the runner and mustn't be exposed to the steps of the stage.
+// Names are compared case-insensitively, the same way the runner looks up secrets.
+func IsReservedSecret(name string) bool {
+	return strings.EqualFold(name, RegistryCredentialsSecret) || strings.EqualFold(name, EnvironSecret)
+}
+
 var _ ExecutionManager = (*Manager)(nil)
 
 type (
@@ -133,6 +157,7 @@ type Manager struct {
 	Pipelines        store.PipelineStore
 	urlProvider      urlprovider.Provider
 	Checks           store.CheckStore
+	Connectors       store.ConnectorStore
 	// Converter  store.ConvertService
 	SSEStreamer sse.Streamer
 	// Globals    store.GlobalSecretStore
@@ -142,6 +167,12 @@ type Manager struct {
 	Repos     store.RepoStore
 	Scheduler scheduler.Scheduler
 	Secrets   store.SecretStore
+	Spaces    store.SpaceStore
+	// EnvironService provides the environment variables of spaces.
+	EnvironService *environ.Service
+	// SecretService resolves secrets stored in external secret providers.
+	SecretService secret.Service
+	Encrypter     encrypt.Encrypter
 	// Status  store.StatusService
 	Stages store.StageStore
 	Steps  store.StepStore
@@ -161,9 +192,14 @@ func New(
 	logStore store.LogStore,
 	logStream livelog.LogStream,
 	checkStore store.CheckStore,
+	connectorStore store.ConnectorStore,
 	repoStore store.RepoStore,
 	scheduler scheduler.Scheduler,
 	secretStore store.SecretStore,
+	spaceStore store.SpaceStore,
+	secretService secret.Service,
+	encrypter encrypt.Encrypter,
+	environService *environ.Service,
 	stageStore store.StageStore,
 	stepStore store.StepStore,
 	userStore store.PrincipalStore,
@@ -179,9 +215,14 @@ func New(
 		Logs:             logStore,
 		Logz:             logStream,
 		Checks:           checkStore,
+		Connectors:       connectorStore,
 		Repos:            repoStore,
 		Scheduler:        scheduler,
 		Secrets:          secretStore,
+		Spaces:           spaceStore,
+		SecretService:    secretService,
+		Encrypter:        encrypter,
+		EnvironService:   environService,
 		Stages:           stageStore,
 		Steps:            stepStore,
 		Users:            userStore,
@@ -274,7 +315,7 @@ func (m *Manager) UploadLogs(ctx context.Context, step int64, r io.Reader) error
 }
 
 // Details provides details about the stage.
-func (m *Manager) Details(_ context.Context, stageID int64) (*ExecutionContext, error) {
+func (m *Manager) Details(ctx context.Context, stageID int64) (*ExecutionContext, error) {
 	log := log.With().
 		Int64("stage-id", stageID).
 		Logger()
@@ -322,6 +363,30 @@ func (m *Manager) Details(_ context.Context, stageID int64) (*ExecutionContext,
 		return nil, err
 	}
 
+	secrets, err = m.resolveSecrets(ctx, secrets)
+	if err != nil {
+		log.Warn().Err(err).Msg("manager: cannot resolve secrets")
+		return nil, err
+	}
+
+	environVars, err := m.environSecret(ctx, repo)
+	if err != nil {
+		log.Warn().Err(err).Msg("manager: cannot get environment variables")
+		return nil, err
+	}
+
+	registries, err := m.registrySecret(ctx, repo)
+	if err != nil {
+		log.Warn().Err(err).Msg("manager: cannot get registry credentials")
+		return nil, err
+	}
+	if registries != nil {
+		secrets = append(secrets, registries)
+	}
+	if environVars != nil {
+		secrets = append(secrets, environVars)
+	}
+
 	// Fetch contents of YAML from the execution ref at the pipeline config path.
 	file, err := m.FileService.Get(noContext, repo, pipeline.ConfigPath, execution.After)
 	if err != nil {
@@ -358,6 +423,180 @@ func (m *Manager) Details(_ context.Context, stageID int64) (*ExecutionContext,
 	}, nil
 }
 
+// resolveSecrets decrypts the secrets and resolves the ones referencing external secret providers.
+func (m *Manager) resolveSecrets(ctx context.Context, secrets []*types.Secret) ([]*types.Secret, error) {
+	resolved := make([]*types.Secret, len(secrets))
+	for i, s := range secrets {
+		data, err := m.resolveSecret(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+
+		resolvedSecret := *s
+		resolvedSecret.Data = data
+		resolved[i] = &resolvedSecret
+	}
+
+	return resolved, nil
+}
+
+// resolveSecret returns the decrypted data of the secret,
+// resolved in the scope of the space of the secret in case it references an external secret provider.
+func (m *Manager) resolveSecret(ctx context.Context, s *types.Secret) (string, error) {
+	data, err := m.Encrypter.Decrypt([]byte(s.Data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret %q: %w", s.UID, err)
+	}
+
+	data, err = m.SecretService.Resolve(ctx, s.SpaceID, data)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %q: %w", s.UID, err)
+	}
+
+	return data, nil
+}
+
+// environSecret returns a secret with the environment variables of the space of the repo
+// (including the inherited ones), or nil in case there aren't any.
+func (m *Manager) environSecret(ctx context.Context, repo *types.Repository) (*types.Secret, error) {
+	envVars, err := m.EnvironService.ListInherited(ctx, repo.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environment variables: %w", err)
+	}
+
+	if len(envVars) == 0 {
+		return nil, nil
+	}
+
+	variables := make([]*provider.Variable, len(envVars))
+	for i, envVar := range envVars {
+		if envVar.SecretUID == "" {
+			variables[i] = &provider.Variable{Name: envVar.Key, Data: envVar.Value}
+			continue
+		}
+
+		s, err := m.Secrets.FindByUID(ctx, envVar.SpaceID, envVar.SecretUID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find secret %q of environment variable %q: %w",
+				envVar.SecretUID, envVar.Key, err)
+		}
+
+		data, err := m.resolveSecret(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+
+		variables[i] = &provider.Variable{Name: envVar.Key, Data: data, Mask: true}
+	}
+
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal environment variables: %w", err)
+	}
+
+	return &types.Secret{
+		UID:  EnvironSecret,
+		Data: string(data),
+	}, nil
+}
+
+// registrySecret returns a secret with the docker config of all docker registry connectors
+// of the repo, the space of the repo and its parent spaces, or nil in case there aren't any.
+// Connectors of the repo override connectors of the spaces,
+// and connectors of sub spaces override connectors of their parent spaces for the same registry.
+// Connectors with invalid data or password secret are skipped, so they don't fail the pipelines.
+func (m *Manager) registrySecret(ctx context.Context, repo *types.Repository) (*types.Secret, error) {
+	spaceIDs, err := m.Spaces.GetAncestorIDs(ctx, repo.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent spaces: %w", err)
+	}
+
+	connectors, err := m.Connectors.ListAll(ctx, spaceIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connectors: %w", err)
+	}
+
+	// spaceIDs starts with the space itself, followed by its ancestors. Connectors of the repo come first.
+	depth := make(map[int64]int, len(spaceIDs))
+	for i, id := range spaceIDs {
+		depth[id] = i + 1
+	}
+
+	connectorDepth := func(connector *types.Connector) int {
+		if connector.RepoID != nil {
+			return 0
+		}
+		return depth[connector.SpaceID]
+	}
+
+	sort.SliceStable(connectors, func(i, j int) bool {
+		return connectorDepth(connectors[i]) < connectorDepth(connectors[j])
+	})
+
+	var registries []*drone.Registry
+	addresses := map[string]struct{}{}
+	for _, connector := range connectors {
+		if connector.Type != types.ConnectorTypeDockerRegistry {
+			continue
+		}
+
+		// connectors of other repositories of the spaces aren't available to the pipelines of the repo.
+		if connector.RepoID != nil && *connector.RepoID != repo.ID {
+			continue
+		}
+
+		registry, err := m.registryFromConnector(ctx, connector)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).
+				Int64("connector.id", connector.ID).
+				Int64("connector.space_id", connector.SpaceID).
+				Msgf("manager: skipping docker registry connector %q", connector.UID)
+			continue
+		}
+
+		if _, ok := addresses[registry.Address]; ok {
+			continue
+		}
+
+		addresses[registry.Address] = struct{}{}
+		registries = append(registries, registry)
+	}
+
+	if len(registries) == 0 {
+		return nil, nil
+	}
+
+	return &types.Secret{
+		UID:  RegistryCredentialsSecret,
+		Data: auths.Encode(registries...),
+	}, nil
+}
+
+// registryFromConnector returns the docker registry credentials of the connector,
+// using the password secret of the space of the connector.
+func (m *Manager) registryFromConnector(ctx context.Context, connector *types.Connector) (*drone.Registry, error) {
+	data := types.DockerRegistryConnectorData{}
+	if err := json.Unmarshal([]byte(connector.Data), &data); err != nil {
+		return nil, fmt.Errorf("failed to parse connector data: %w", err)
+	}
+
+	s, err := m.Secrets.FindByUID(ctx, connector.SpaceID, data.PasswordSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find password secret %q: %w", data.PasswordSecret, err)
+	}
+
+	password, err := m.resolveSecret(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &drone.Registry{
+		Address:  data.URL,
+		Username: data.Username,
+		Password: password,
+	}, nil
+}
+
 func (m *Manager) createNetrc(repo *types.Repository) (*Netrc, error) {
 	pipelinePrincipal := bootstrap.NewPipelineServiceSession().Principal
 	jwt, err := jwt.GenerateWithMembership(
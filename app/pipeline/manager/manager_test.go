@@ -0,0 +1,129 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/harness/gitness/app/pipeline/secret"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/drone/runner-go/registry/auths"
+	"github.com/stretchr/testify/require"
+)
+
+type spaceStoreMock struct {
+	store.SpaceStore
+	ancestorIDs []int64
+}
+
+func (s spaceStoreMock) GetAncestorIDs(context.Context, int64) ([]int64, error) {
+	return s.ancestorIDs, nil
+}
+
+type connectorStoreMock struct {
+	store.ConnectorStore
+	connectors []*types.Connector
+}
+
+func (s connectorStoreMock) ListAll(_ context.Context, spaceIDs ...int64) ([]*types.Connector, error) {
+	var res []*types.Connector
+	for _, connector := range s.connectors {
+		for _, id := range spaceIDs {
+			if connector.SpaceID == id {
+				res = append(res, connector)
+			}
+		}
+	}
+	return res, nil
+}
+
+type secretStoreMock struct {
+	store.SecretStore
+}
+
+func (secretStoreMock) FindByUID(_ context.Context, spaceID int64, uid string) (*types.Secret, error) {
+	return &types.Secret{SpaceID: spaceID, UID: uid, Data: uid + "-password"}, nil
+}
+
+type encrypterMock struct{}
+
+func (encrypterMock) Encrypt(plaintext string) ([]byte, error) {
+	return []byte(plaintext), nil
+}
+
+func (encrypterMock) Decrypt(ciphertext []byte) (string, error) {
+	return string(ciphertext), nil
+}
+
+func dockerConnector(t *testing.T, spaceID int64, repoID *int64, url, user string) *types.Connector {
+	t.Helper()
+
+	data, err := json.Marshal(types.DockerRegistryConnectorData{URL: url, Username: user, PasswordSecret: user})
+	require.NoError(t, err)
+
+	return &types.Connector{
+		SpaceID: spaceID,
+		RepoID:  repoID,
+		UID:     user,
+		Type:    types.ConnectorTypeDockerRegistry,
+		Data:    string(data),
+	}
+}
+
+func TestManager_RegistrySecret(t *testing.T) {
+	repoID := int64(10)
+	otherRepoID := int64(11)
+
+	m := &Manager{
+		Spaces: spaceStoreMock{ancestorIDs: []int64{2, 1}},
+		Connectors: connectorStoreMock{connectors: []*types.Connector{
+			dockerConnector(t, 1, nil, "docker.io", "root"),
+			dockerConnector(t, 1, nil, "registry.example.com", "root2"),
+			dockerConnector(t, 1, nil, "quay.io", "root3"),
+			dockerConnector(t, 2, nil, "docker.io", "space"),
+			dockerConnector(t, 2, &repoID, "registry.example.com", "repo"),
+			dockerConnector(t, 2, &otherRepoID, "ghcr.io", "other"),
+			{SpaceID: 2, UID: "github", Type: "github", Data: "{}"},
+		}},
+		Secrets:       secretStoreMock{},
+		SecretService: secret.NewService(nil, nil, false),
+		Encrypter:     encrypterMock{},
+	}
+
+	s, err := m.registrySecret(context.Background(), &types.Repository{ID: repoID, ParentID: 2})
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	require.Equal(t, RegistryCredentialsSecret, s.UID)
+
+	registries, err := auths.ParseString(s.Data)
+	require.NoError(t, err)
+
+	users := map[string]string{}
+	for _, r := range registries {
+		users[r.Address] = r.Username + ":" + r.Password
+	}
+
+	// connectors of the repo override connectors of the spaces, which override connectors of parent spaces.
+	// connectors of other repos aren't available.
+	require.Equal(t, map[string]string{
+		"docker.io":            "space:space-password",
+		"registry.example.com": "repo:repo-password",
+		"quay.io":              "root3:root3-password",
+	}, users)
+}
@@ -99,6 +99,11 @@ func (t *teardown) do(ctx context.Context, stage *types.Stage) error {
 		return err
 	}
 
+	if stage.Started > 0 && stage.Stopped >= stage.Started {
+		stageDuration.WithLabelValues(string(stage.Status)).
+			Observe(float64(stage.Stopped-stage.Started) / 1000)
+	}
+
 	for _, step := range stage.Steps {
 		err = t.Logs.Delete(noContext, step.ID)
 		if err != nil && !errors.Is(err, livelog.ErrStreamNotFound) {
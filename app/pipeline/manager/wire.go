@@ -16,11 +16,14 @@ package manager
 
 import (
 	"github.com/harness/gitness/app/pipeline/converter"
+	"github.com/harness/gitness/app/pipeline/environ"
 	"github.com/harness/gitness/app/pipeline/file"
 	"github.com/harness/gitness/app/pipeline/scheduler"
+	"github.com/harness/gitness/app/pipeline/secret"
 	"github.com/harness/gitness/app/sse"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/encrypt"
 	"github.com/harness/gitness/livelog"
 	"github.com/harness/gitness/types"
 
@@ -46,14 +49,20 @@ func ProvideExecutionManager(
 	logStore store.LogStore,
 	logStream livelog.LogStream,
 	checkStore store.CheckStore,
+	connectorStore store.ConnectorStore,
 	repoStore store.RepoStore,
 	scheduler scheduler.Scheduler,
 	secretStore store.SecretStore,
+	spaceStore store.SpaceStore,
+	secretService secret.Service,
+	encrypter encrypt.Encrypter,
+	environService *environ.Service,
 	stageStore store.StageStore,
 	stepStore store.StepStore,
 	userStore store.PrincipalStore) ExecutionManager {
 	return New(config, executionStore, pipelineStore, urlProvider, sseStreamer, fileService, converterService,
-		logStore, logStream, checkStore, repoStore, scheduler, secretStore, stageStore, stepStore, userStore)
+		logStore, logStream, checkStore, connectorStore, repoStore, scheduler, secretStore, spaceStore, secretService,
+		encrypter, environService, stageStore, stepStore, userStore)
 }
 
 // ProvideExecutionClient provides a client implementation to interact with the execution manager.
@@ -0,0 +1,194 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/harness/gitness/app/pipeline/manager"
+
+	"github.com/drone-runners/drone-runner-docker/engine/compiler"
+	compiler2 "github.com/drone-runners/drone-runner-docker/engine2/compiler"
+	engine2 "github.com/drone-runners/drone-runner-docker/engine2/engine"
+	"github.com/drone/drone-go/drone"
+	"github.com/drone/runner-go/environ/provider"
+	"github.com/drone/runner-go/manifest"
+	"github.com/drone/runner-go/pipeline/runtime"
+	"github.com/drone/runner-go/registry"
+	"github.com/drone/runner-go/registry/auths"
+	"github.com/drone/runner-go/secret"
+)
+
+var (
+	_ runtime.Compiler   = (*stageCompiler)(nil)
+	_ compiler2.Compiler = (*stageCompiler2)(nil)
+)
+
+// stageCompiler wraps the compiler of the legacy engine to add the registry credentials
+// and environment variables the manager injected into the secrets of the stage.
+// Registry credentials referenced via image_pull_secrets in the yaml are handled by the compiler itself.
+type stageCompiler struct {
+	compiler compiler.Compiler
+}
+
+func (c *stageCompiler) Compile(ctx context.Context, args runtime.CompilerArgs) runtime.Spec {
+	stage := stageSecrets{secret: args.Secret, repo: args.Repo, build: args.Build}
+
+	// copy the compiler, as the providers are specific to the stage.
+	sc := c.compiler
+	sc.Registry = registry.Combine(c.compiler.Registry, stage.registryProvider())
+	sc.Environ = provider.Combine(c.compiler.Environ, stage.environProvider())
+
+	args.Secret = hideReservedSecrets(args.Secret)
+
+	return sc.Compile(ctx, args)
+}
+
+// stageCompiler2 wraps the compiler of the v1 engine to add the registry credentials
+// and environment variables the manager injected into the secrets of the stage.
+type stageCompiler2 struct {
+	compiler compiler2.CompilerImpl
+}
+
+func (c *stageCompiler2) Compile(ctx context.Context, args compiler2.Args) (*engine2.Spec, error) {
+	stage := stageSecrets{secret: args.Secret, repo: args.Repo, build: args.Build}
+
+	sc := c.compiler
+	sc.Registry = registry.Combine(c.compiler.Registry, stage.registryProvider())
+	sc.Environ = provider.Combine(c.compiler.Environ, stage.environProvider())
+
+	args.Secret = hideReservedSecrets(args.Secret)
+
+	spec, err := sc.Compile(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	// the v1 compiler ignores masked environment variables, add them as secrets to all steps.
+	variables, err := stage.environ(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	masked := provider.FilterMasked(variables)
+	for _, step := range spec.Steps {
+		for _, v := range masked {
+			step.Secrets = append(step.Secrets, &engine2.Secret{
+				Name: v.Name,
+				Env:  v.Name,
+				Data: []byte(v.Data),
+				Mask: true,
+			})
+		}
+	}
+
+	return spec, nil
+}
+
+// stageSecrets provides the data the manager injected into the secrets of a stage.
+type stageSecrets struct {
+	secret secret.Provider
+	repo   *drone.Repo
+	build  *drone.Build
+}
+
+func (s stageSecrets) find(ctx context.Context, name string) (string, bool, error) {
+	if s.secret == nil {
+		return "", false, nil
+	}
+
+	found, err := s.secret.Find(ctx, &secret.Request{
+		Name:  name,
+		Repo:  s.repo,
+		Build: s.build,
+		Conf:  &manifest.Manifest{},
+	})
+	if err != nil || found == nil {
+		return "", false, err
+	}
+
+	return found.Data, true, nil
+}
+
+func (s stageSecrets) registries(ctx context.Context) ([]*drone.Registry, error) {
+	data, ok, err := s.find(ctx, manager.RegistryCredentialsSecret)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	return auths.ParseString(data)
+}
+
+func (s stageSecrets) environ(ctx context.Context) ([]*provider.Variable, error) {
+	data, ok, err := s.find(ctx, manager.EnvironSecret)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	var variables []*provider.Variable
+	if err = json.Unmarshal([]byte(data), &variables); err != nil {
+		return nil, fmt.Errorf("failed to parse environment variables: %w", err)
+	}
+
+	return variables, nil
+}
+
+func (s stageSecrets) registryProvider() registry.Provider {
+	return registryFunc(func(ctx context.Context, _ *registry.Request) ([]*drone.Registry, error) {
+		return s.registries(ctx)
+	})
+}
+
+func (s stageSecrets) environProvider() provider.Provider {
+	return environFunc(func(ctx context.Context, _ *provider.Request) ([]*provider.Variable, error) {
+		return s.environ(ctx)
+	})
+}
+
+// reservedSecretsFilter hides the secrets reserved by the manager from the compiler,
+// so they can't be referenced by the pipeline (e.g. via from_secret or image_pull_secrets).
+type reservedSecretsFilter struct {
+	secret secret.Provider
+}
+
+func hideReservedSecrets(p secret.Provider) secret.Provider {
+	if p == nil {
+		return nil
+	}
+
+	return reservedSecretsFilter{secret: p}
+}
+
+func (f reservedSecretsFilter) Find(ctx context.Context, req *secret.Request) (*drone.Secret, error) {
+	if manager.IsReservedSecret(req.Name) {
+		return nil, nil
+	}
+
+	return f.secret.Find(ctx, req)
+}
+
+type registryFunc func(context.Context, *registry.Request) ([]*drone.Registry, error)
+
+func (f registryFunc) List(ctx context.Context, req *registry.Request) ([]*drone.Registry, error) {
+	return f(ctx, req)
+}
+
+type environFunc func(context.Context, *provider.Request) ([]*provider.Variable, error)
+
+func (f environFunc) List(ctx context.Context, req *provider.Request) ([]*provider.Variable, error) {
+	return f(ctx, req)
+}
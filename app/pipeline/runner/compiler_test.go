@@ -0,0 +1,57 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/app/pipeline/manager"
+
+	"github.com/drone/drone-go/drone"
+	"github.com/drone/runner-go/secret"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHideReservedSecrets(t *testing.T) {
+	ctx := context.Background()
+
+	stage := secret.Static([]*drone.Secret{
+		{Name: "password", Data: "secret"},
+		{Name: manager.RegistryCredentialsSecret, Data: "credentials"},
+		{Name: manager.EnvironSecret, Data: "[]"},
+	})
+
+	filtered := hideReservedSecrets(stage)
+	build := &drone.Build{}
+
+	found, err := filtered.Find(ctx, &secret.Request{Name: "password", Build: build})
+	require.NoError(t, err)
+	require.Equal(t, "secret", found.Data)
+
+	for _, name := range []string{manager.RegistryCredentialsSecret, manager.EnvironSecret, "$GITNESS_ENVIRON"} {
+		found, err = filtered.Find(ctx, &secret.Request{Name: name, Build: build})
+		require.NoError(t, err)
+		require.Nil(t, found, name)
+	}
+
+	// the reserved secrets are still available to the runner itself.
+	data, ok, err := stageSecrets{secret: stage, build: build}.find(ctx, manager.RegistryCredentialsSecret)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "credentials", data)
+
+	require.Nil(t, hideReservedSecrets(nil))
+}
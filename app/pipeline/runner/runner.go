@@ -56,7 +56,7 @@ func NewExecutionRunner(
 	// the gitness container.
 	extraHosts := []string{"host.docker.internal:host-gateway"}
 	compiler := &compiler.Compiler{
-		Environ:    provider.Static(map[string]string{}),
+		Environ:    provider.Static(config.CI.Environ),
 		Registry:   registry.Static([]*drone.Registry{}),
 		Secret:     secret.Encrypted(),
 		ExtraHosts: extraHosts,
@@ -81,7 +81,7 @@ func NewExecutionRunner(
 		Reporter: tracer,
 		Lookup:   resource.Lookup,
 		Lint:     linter.New().Lint,
-		Compiler: compiler,
+		Compiler: &stageCompiler{compiler: *compiler},
 		Exec:     exec.Exec,
 	}
 
@@ -93,7 +93,7 @@ func NewExecutionRunner(
 	exec2 := runtime2.NewExecer(tracer, remote, upload, engine2, int64(config.CI.ParallelWorkers))
 
 	compiler2 := &compiler2.CompilerImpl{
-		Environ:    provider.Static(map[string]string{}),
+		Environ:    provider.Static(config.CI.Environ),
 		Registry:   registry.Static([]*drone.Registry{}),
 		Secret:     secret.Encrypted(),
 		ExtraHosts: extraHosts,
@@ -106,7 +106,7 @@ func NewExecutionRunner(
 		Client:       client,
 		Resolver:     resolver.GetLookupFn(),
 		Reporter:     tracer,
-		Compiler:     compiler2,
+		Compiler:     &stageCompiler2{compiler: *compiler2},
 		Exec:         exec2.Exec,
 		LegacyRunner: legacyRunner,
 	}
@@ -133,6 +133,8 @@ func (q *queue) signal(ctx context.Context) error {
 		return err
 	}
 
+	queueLength.Set(float64(countPending(items)))
+
 	q.Lock()
 	defer q.Unlock()
 	for _, item := range items {
@@ -317,3 +319,15 @@ func matchResource(kinda, typea, kindb, typeb string) bool {
 	}
 	return kinda == kindb && typea == typeb
 }
+
+// countPending returns the number of stages that are neither running
+// nor assigned to a machine.
+func countPending(items []*types.Stage) int {
+	count := 0
+	for _, item := range items {
+		if item.Status != enum.CIStatusRunning && item.Machine == "" {
+			count++
+		}
+	}
+	return count
+}
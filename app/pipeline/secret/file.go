@@ -0,0 +1,61 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+var _ Provider = (*File)(nil)
+
+// File is a secret provider reading secrets from a local JSON file, mainly intended for testing.
+// The file contains an object with the secret paths as keys and objects of secret key/value pairs as values:
+//
+//	{
+//	  "space1/ci/docker": {"username": "user", "password": "pass"}
+//	}
+//
+// The file is read on every access, so changes are picked up without a restart.
+type File struct {
+	path string
+}
+
+func NewFile(path string) *File {
+	return &File{
+		path: path,
+	}
+}
+
+func (f *File) Resolve(_ context.Context, path, key string) (string, error) {
+	raw, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	secrets := map[string]map[string]any{}
+	if err = json.Unmarshal(raw, &secrets); err != nil {
+		return "", fmt.Errorf("failed to parse secrets file: %w", err)
+	}
+
+	value, ok := secrets[path][key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return stringValue(value)
+}
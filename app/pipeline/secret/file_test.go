@@ -0,0 +1,79 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Resolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	err := os.WriteFile(path, []byte(`{
+		"root/ci/docker": {"username": "user", "password": "pass", "port": 5000, "config": {"a": true}}
+	}`), 0o600)
+	require.NoError(t, err)
+
+	f := NewFile(path)
+	ctx := context.Background()
+
+	value, err := f.Resolve(ctx, "root/ci/docker", "password")
+	require.NoError(t, err)
+	require.Equal(t, "pass", value)
+
+	// non-string values are returned as json
+	value, err = f.Resolve(ctx, "root/ci/docker", "port")
+	require.NoError(t, err)
+	require.Equal(t, "5000", value)
+
+	value, err = f.Resolve(ctx, "root/ci/docker", "config")
+	require.NoError(t, err)
+	require.Equal(t, `{"a":true}`, value)
+
+	_, err = f.Resolve(ctx, "root/ci/docker", "unknown")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = f.Resolve(ctx, "root/ci/unknown", "password")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	// the file is read on every access
+	err = os.WriteFile(path, []byte(`{"root/ci/docker": {"password": "changed"}}`), 0o600)
+	require.NoError(t, err)
+
+	value, err = f.Resolve(ctx, "root/ci/docker", "password")
+	require.NoError(t, err)
+	require.Equal(t, "changed", value)
+}
+
+func TestFile_Resolve_InvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	ctx := context.Background()
+
+	_, err := NewFile(path).Resolve(ctx, "root/ci/docker", "password")
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrNotFound))
+
+	err = os.WriteFile(path, []byte(`not json`), 0o600)
+	require.NoError(t, err)
+
+	_, err = NewFile(path).Resolve(ctx, "root/ci/docker", "password")
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrNotFound))
+}
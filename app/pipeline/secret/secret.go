@@ -0,0 +1,73 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrNotFound = errors.New("secret not found")
+)
+
+type (
+	// Reference is a reference to a secret stored in an external secret provider.
+	// It has the format "<provider>://<path>#<key>", e.g. "vault://ci/docker#password".
+	Reference struct {
+		Provider string
+		Path     string
+		Key      string
+	}
+
+	// Provider provides access to the secrets of an external secret provider.
+	Provider interface {
+		// Resolve returns the value stored under the key of the secret at the given path.
+		Resolve(ctx context.Context, path, key string) (string, error)
+	}
+
+	// Service resolves pipeline secrets that reference external secret providers.
+	Service interface {
+		// Resolve returns the value of a secret of the space with the given id.
+		// Values that don't reference a configured secret provider are returned as-is.
+		Resolve(ctx context.Context, spaceID int64, value string) (string, error)
+	}
+)
+
+// ParseReference parses the provided value as secret reference.
+// It returns false in case the value doesn't have the format of a secret reference.
+func ParseReference(value string) (Reference, bool) {
+	provider, rest, ok := strings.Cut(value, "://")
+	if !ok || provider == "" || strings.ContainsAny(provider, " \t\r\n/#") {
+		return Reference{}, false
+	}
+
+	path, key, ok := strings.Cut(rest, "#")
+	if !ok || path == "" || key == "" || strings.ContainsAny(rest, " \t\r\n") {
+		return Reference{}, false
+	}
+
+	return Reference{
+		Provider: provider,
+		Path:     strings.Trim(path, "/"),
+		Key:      key,
+	}, true
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("%s://%s#%s", r.Provider, r.Path, r.Key)
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Reference
+		ok       bool
+	}{
+		{
+			value:    "vault://ci/docker#password",
+			expected: Reference{Provider: "vault", Path: "ci/docker", Key: "password"},
+			ok:       true,
+		},
+		{
+			value:    "file:///ci/docker/#user",
+			expected: Reference{Provider: "file", Path: "ci/docker", Key: "user"},
+			ok:       true,
+		},
+		{value: "plain secret value"},
+		{value: "vault://ci/docker"},
+		{value: "vault://#password"},
+		{value: "vault://ci/docker#"},
+		{value: "://ci/docker#password"},
+		{value: "va/ult://ci/docker#password"},
+		{value: "vault://ci/docker#pass word"},
+		{value: "vault://ci/docker#password\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			ref, ok := ParseReference(test.value)
+			require.Equal(t, test.ok, ok)
+			require.Equal(t, test.expected, ref)
+		})
+	}
+}
+
+func TestReference_String(t *testing.T) {
+	ref := Reference{Provider: "vault", Path: "ci/docker", Key: "password"}
+	require.Equal(t, "vault://ci/docker#password", ref.String())
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/harness/gitness/app/paths"
+	"github.com/harness/gitness/app/store"
+)
+
+var _ Service = (*service)(nil)
+
+type service struct {
+	spacePathStore store.SpacePathStore
+	providers      map[string]Provider
+	spaceScoped    bool
+}
+
+// NewService returns a new secret service using the provided secret providers (by reference scheme).
+// If spaceScoped is true, the paths of secret references are relative to the path of the space of the secret.
+func NewService(spacePathStore store.SpacePathStore, providers map[string]Provider, spaceScoped bool) Service {
+	return &service{
+		spacePathStore: spacePathStore,
+		providers:      providers,
+		spaceScoped:    spaceScoped,
+	}
+}
+
+func (s *service) Resolve(ctx context.Context, spaceID int64, value string) (string, error) {
+	ref, ok := ParseReference(value)
+	if !ok {
+		return value, nil
+	}
+
+	provider, ok := s.providers[ref.Provider]
+	if !ok {
+		// not a reference to any of the configured providers, treat it as a regular secret.
+		return value, nil
+	}
+
+	path, err := s.providerPath(ctx, spaceID, ref.Path)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := provider.Resolve(ctx, path, ref.Key)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret reference %q: %w", ref.String(), err)
+	}
+
+	return data, nil
+}
+
+// providerPath returns the path of the referenced secret in the secret provider.
+// With space scoping, the path is prefixed with the path of the space of the secret,
+// so that a space can only read its own secrets and the ones of its sub spaces.
+func (s *service) providerPath(ctx context.Context, spaceID int64, path string) (string, error) {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", fmt.Errorf("secret reference path %q is invalid", path)
+		}
+	}
+
+	if !s.spaceScoped {
+		return path, nil
+	}
+
+	spacePath, err := s.spacePathStore.FindPrimaryBySpaceID(ctx, spaceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find path of space %d: %w", spaceID, err)
+	}
+
+	return paths.Concatinate(spacePath.Value, path), nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+type spacePathStoreMock struct {
+	store.SpacePathStore
+	paths map[int64]string
+}
+
+func (s spacePathStoreMock) FindPrimaryBySpaceID(_ context.Context, spaceID int64) (*types.SpacePath, error) {
+	path, ok := s.paths[spaceID]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+
+	return &types.SpacePath{Value: path, IsPrimary: true, SpaceID: spaceID}, nil
+}
+
+// providerMock returns the secrets stored under the full path of the secret.
+type providerMock map[string]string
+
+func (p providerMock) Resolve(_ context.Context, path, key string) (string, error) {
+	value, ok := p[path+"#"+key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return value, nil
+}
+
+func TestService_Resolve(t *testing.T) {
+	spacePaths := spacePathStoreMock{
+		paths: map[int64]string{
+			1: "root",
+			2: "root/team1",
+			3: "root/team2",
+		},
+	}
+
+	provider := providerMock{
+		"root/ci/docker#password":       "root-password",
+		"root/team1/ci/docker#password": "team1-password",
+		"root/team2/ci/docker#password": "team2-password",
+		"ci/docker#password":            "global-password",
+	}
+
+	tests := []struct {
+		name        string
+		spaceScoped bool
+		spaceID     int64
+		value       string
+		expected    string
+		expectedErr bool
+	}{
+		{
+			name:        "plain value",
+			spaceScoped: true,
+			spaceID:     2,
+			value:       "plain",
+			expected:    "plain",
+		},
+		{
+			name:        "unknown provider",
+			spaceScoped: true,
+			spaceID:     2,
+			value:       "other://ci/docker#password",
+			expected:    "other://ci/docker#password",
+		},
+		{
+			name:        "root space",
+			spaceScoped: true,
+			spaceID:     1,
+			value:       "test://ci/docker#password",
+			expected:    "root-password",
+		},
+		{
+			name:        "sub space is scoped to its own path",
+			spaceScoped: true,
+			spaceID:     2,
+			value:       "test://ci/docker#password",
+			expected:    "team1-password",
+		},
+		{
+			name:        "parent space can read secrets of sub spaces",
+			spaceScoped: true,
+			spaceID:     1,
+			value:       "test://team2/ci/docker#password",
+			expected:    "team2-password",
+		},
+		{
+			name:        "sibling space can't be read",
+			spaceScoped: true,
+			spaceID:     2,
+			value:       "test://../team2/ci/docker#password",
+			expectedErr: true,
+		},
+		{
+			name:        "sibling space can't be read using the full path",
+			spaceScoped: true,
+			spaceID:     2,
+			value:       "test://root/team2/ci/docker#password",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid path segment",
+			spaceScoped: true,
+			spaceID:     2,
+			value:       "test://ci/./docker#password",
+			expectedErr: true,
+		},
+		{
+			name:        "unknown space",
+			spaceScoped: true,
+			spaceID:     4,
+			value:       "test://ci/docker#password",
+			expectedErr: true,
+		},
+		{
+			name:        "unknown key",
+			spaceScoped: true,
+			spaceID:     2,
+			value:       "test://ci/docker#username",
+			expectedErr: true,
+		},
+		{
+			name:        "without space scope",
+			spaceScoped: false,
+			spaceID:     2,
+			value:       "test://ci/docker#password",
+			expected:    "global-password",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewService(spacePaths, map[string]Provider{"test": provider}, test.spaceScoped)
+
+			value, err := s.Resolve(context.Background(), test.spaceID, test.value)
+			if test.expectedErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, test.expected, value)
+		})
+	}
+}
+
+func TestService_Resolve_NotFound(t *testing.T) {
+	s := NewService(spacePathStoreMock{paths: map[int64]string{1: "root"}},
+		map[string]Provider{"test": providerMock{}}, true)
+
+	_, err := s.Resolve(context.Background(), 1, "test://ci/docker#password")
+	require.True(t, errors.Is(err, ErrNotFound))
+}
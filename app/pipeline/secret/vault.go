@@ -0,0 +1,119 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const vaultRequestTimeout = 30 * time.Second
+
+var _ Provider = (*Vault)(nil)
+
+// Vault is a secret provider reading secrets from a HashiCorp Vault KV v2 secrets engine.
+type Vault struct {
+	client    *http.Client
+	address   string
+	token     string
+	namespace string
+	mount     string
+}
+
+func NewVault(address, token, namespace, mount string) *Vault {
+	return &Vault{
+		client:    &http.Client{Timeout: vaultRequestTimeout},
+		address:   strings.TrimSuffix(address, "/"),
+		token:     token,
+		namespace: namespace,
+		mount:     strings.Trim(mount, "/"),
+	}
+}
+
+type vaultKVResponse struct {
+	Data struct {
+		Data map[string]any `json:"data"`
+	} `json:"data"`
+}
+
+func (v *Vault) Resolve(ctx context.Context, path, key string) (string, error) {
+	secretURL := v.address + "/v1/" + url.PathEscape(v.mount) + "/data/" + escapePath(path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, secretURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+
+	req.Header.Set("X-Vault-Token", v.token)
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret from vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", ErrNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf("vault responded with status %d: %s", resp.StatusCode, body)
+	}
+
+	kv := vaultKVResponse{}
+	if err = json.NewDecoder(resp.Body).Decode(&kv); err != nil {
+		return "", fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	value, ok := kv.Data.Data[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	return stringValue(value)
+}
+
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
+
+// stringValue converts a value of a secret to its string representation.
+// Non-string values (e.g. numbers or objects) are returned as JSON.
+func stringValue(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal secret value: %w", err)
+	}
+
+	return string(raw), nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVault_Resolve(t *testing.T) {
+	var (
+		requestPath      string
+		requestToken     string
+		requestNamespace string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPath = r.URL.EscapedPath()
+		requestToken = r.Header.Get("X-Vault-Token")
+		requestNamespace = r.Header.Get("X-Vault-Namespace")
+
+		switch r.URL.Path {
+		case "/v1/kv/data/root/ci/docker":
+			_, _ = w.Write([]byte(`{"data": {"data": {"password": "pass", "port": 5000}, "metadata": {}}}`))
+		case "/v1/kv/data/root/ci/error":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	v := NewVault(server.URL+"/", "token", "ns", "/kv/")
+	ctx := context.Background()
+
+	value, err := v.Resolve(ctx, "root/ci/docker", "password")
+	require.NoError(t, err)
+	require.Equal(t, "pass", value)
+	require.Equal(t, "/v1/kv/data/root/ci/docker", requestPath)
+	require.Equal(t, "token", requestToken)
+	require.Equal(t, "ns", requestNamespace)
+
+	// non-string values are returned as json
+	value, err = v.Resolve(ctx, "root/ci/docker", "port")
+	require.NoError(t, err)
+	require.Equal(t, "5000", value)
+
+	_, err = v.Resolve(ctx, "root/ci/docker", "unknown")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = v.Resolve(ctx, "root/ci/unknown", "password")
+	require.True(t, errors.Is(err, ErrNotFound))
+
+	_, err = v.Resolve(ctx, "root/ci/error", "password")
+	require.ErrorContains(t, err, "permission denied")
+	require.False(t, errors.Is(err, ErrNotFound))
+
+	// path segments are escaped
+	_, err = v.Resolve(ctx, "root/ci?x=1/docker", "password")
+	require.True(t, errors.Is(err, ErrNotFound))
+	require.Equal(t, "/v1/kv/data/root/ci%3Fx=1/docker", requestPath)
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"errors"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/google/wire"
+)
+
+const (
+	ProviderVault = "vault"
+	ProviderFile  = "file"
+)
+
+// WireSet provides a wire set for this package.
+var WireSet = wire.NewSet(
+	ProvideService,
+)
+
+// ProvideService provides a service resolving secrets stored in external secret providers.
+func ProvideService(config *types.Config, spacePathStore store.SpacePathStore) (Service, error) {
+	providers := map[string]Provider{}
+
+	vaultConfig := config.SecretProviders.Vault
+	if vaultConfig.Address != "" {
+		if vaultConfig.Token == "" {
+			return nil, errors.New("vault secret provider requires a token")
+		}
+
+		providers[ProviderVault] = NewVault(vaultConfig.Address, vaultConfig.Token,
+			vaultConfig.Namespace, vaultConfig.Mount)
+	}
+
+	if config.SecretProviders.File.Path != "" {
+		providers[ProviderFile] = NewFile(config.SecretProviders.File.Path)
+	}
+
+	return NewService(spacePathStore, providers, config.SecretProviders.SpaceScoped), nil
+}
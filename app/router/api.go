@@ -66,6 +66,7 @@ import (
 	middlewareauthn "github.com/harness/gitness/app/api/middleware/authn"
 	"github.com/harness/gitness/app/api/middleware/encode"
 	"github.com/harness/gitness/app/api/middleware/logging"
+	"github.com/harness/gitness/app/api/middleware/metrics"
 	middlewareprincipal "github.com/harness/gitness/app/api/middleware/principal"
 	"github.com/harness/gitness/app/api/request"
 	"github.com/harness/gitness/app/auth/authn"
@@ -121,7 +122,7 @@ func NewAPIHandler(
 
 	// Apply common api middleware.
 	r.Use(middleware.NoCache)
-	r.Use(middleware.Recoverer)
+	r.Use(recoverer)
 
 	// configure logging middleware.
 	r.Use(hlog.URLHandler("http.url"))
@@ -130,6 +131,9 @@ func NewAPIHandler(
 	r.Use(logging.HLogAccessLogHandler())
 	r.Use(address.Handler("", ""))
 
+	// configure metrics middleware.
+	r.Use(metrics.Handler("api"))
+
 	// configure cors middleware
 	r.Use(corsHandler(config))
 
@@ -195,7 +199,7 @@ func setupRoutesV1(r chi.Router,
 	setupServiceAccounts(r, saCtrl)
 	setupPrincipals(r, principalCtrl)
 	setupInternal(r, githookCtrl)
-	setupAdmin(r, userCtrl)
+	setupAdmin(r, userCtrl, repoCtrl)
 	setupAccount(r, userCtrl, sysCtrl, config)
 	setupSystem(r, config, sysCtrl)
 	setupResources(r)
@@ -237,6 +241,24 @@ func setupSpaces(r chi.Router, appCtx context.Context, spaceCtrl *space.Controll
 					r.Patch("/", handlerspace.HandleMembershipUpdate(spaceCtrl))
 				})
 			})
+
+			r.Route("/env-vars", func(r chi.Router) {
+				r.Get("/", handlerspace.HandleEnvVarList(spaceCtrl))
+				r.Route(fmt.Sprintf("/{%s}", request.PathParamEnvVarKey), func(r chi.Router) {
+					r.Put("/", handlerspace.HandleEnvVarSet(spaceCtrl))
+					r.Delete("/", handlerspace.HandleEnvVarDelete(spaceCtrl))
+				})
+			})
+
+			r.Route("/labels", func(r chi.Router) {
+				r.Post("/", handlerspace.HandleLabelDefine(spaceCtrl))
+				r.Get("/", handlerspace.HandleLabelList(spaceCtrl))
+				r.Route(fmt.Sprintf("/{%s}", request.PathParamLabelName), func(r chi.Router) {
+					r.Get("/", handlerspace.HandleLabelFind(spaceCtrl))
+					r.Patch("/", handlerspace.HandleLabelUpdate(spaceCtrl))
+					r.Delete("/", handlerspace.HandleLabelDelete(spaceCtrl))
+				})
+			})
 		})
 	})
 }
@@ -267,6 +289,13 @@ func setupRepos(r chi.Router,
 
 			r.Get("/import-progress", handlerrepo.HandleImportProgress(repoCtrl))
 
+			r.Get("/languages", handlerrepo.HandleLanguages(repoCtrl))
+
+			r.Route("/stats", func(r chi.Router) {
+				r.Get("/contributors", handlerrepo.HandleListContributors(repoCtrl))
+				r.Get("/commit-activity", handlerrepo.HandleCommitActivity(repoCtrl))
+			})
+
 			// content operations
 			// NOTE: this allows /content and /content/ to both be valid (without any other tricks.)
 			// We don't expect there to be any other operations in that route (as that could overlap with file names)
@@ -284,6 +313,10 @@ func setupRepos(r chi.Router,
 				r.Get("/*", handlerrepo.HandleRaw(repoCtrl))
 			})
 
+			r.Route("/archive", func(r chi.Router) {
+				r.Get("/*", handlerrepo.HandleArchive(repoCtrl))
+			})
+
 			// commit operations
 			r.Route("/commits", func(r chi.Router) {
 				r.Get("/", handlerrepo.HandleListCommits(repoCtrl))
@@ -295,6 +328,8 @@ func setupRepos(r chi.Router,
 				r.Route(fmt.Sprintf("/{%s}", request.PathParamCommitSHA), func(r chi.Router) {
 					r.Get("/", handlerrepo.HandleGetCommit(repoCtrl))
 					r.Get("/diff", handlerrepo.HandleCommitDiff(repoCtrl))
+					r.Post("/cherry-pick", handlerrepo.HandleCherryPick(repoCtrl))
+					r.Post("/revert", handlerrepo.HandleRevert(repoCtrl))
 				})
 			})
 
@@ -322,6 +357,9 @@ func setupRepos(r chi.Router,
 			r.Route("/diff-stats", func(r chi.Router) {
 				r.Get("/*", handlerrepo.HandleDiffStats(repoCtrl))
 			})
+			r.Route("/diff-commits", func(r chi.Router) {
+				r.Get("/*", handlerrepo.HandleDiffCommits(repoCtrl))
+			})
 			r.Route("/merge-check", func(r chi.Router) {
 				r.Post("/*", handlerrepo.HandleMergeCheck(repoCtrl))
 			})
@@ -339,6 +377,8 @@ func setupRepos(r chi.Router,
 			SetupUploads(r, uploadCtrl)
 
 			SetupRules(r, repoCtrl)
+
+			SetupLabels(r, repoCtrl)
 		})
 	})
 }
@@ -489,12 +529,23 @@ func SetupPullReq(r chi.Router, pullreqCtrl *pullreq.Controller) {
 			r.Get("/activities", handlerpullreq.HandleListActivities(pullreqCtrl))
 			r.Route("/comments", func(r chi.Router) {
 				r.Post("/", handlerpullreq.HandleCommentCreate(pullreqCtrl))
+				r.Post("/apply-suggestions", handlerpullreq.HandleCommentApplySuggestions(pullreqCtrl))
 				r.Route(fmt.Sprintf("/{%s}", request.PathParamPullReqCommentID), func(r chi.Router) {
 					r.Patch("/", handlerpullreq.HandleCommentUpdate(pullreqCtrl))
 					r.Delete("/", handlerpullreq.HandleCommentDelete(pullreqCtrl))
 					r.Put("/status", handlerpullreq.HandleCommentStatus(pullreqCtrl))
+					r.Route("/reactions", func(r chi.Router) {
+						r.Put("/", handlerpullreq.HandleCommentReactionAdd(pullreqCtrl))
+						r.Delete(fmt.Sprintf("/{%s}", request.PathParamReactionEmoji),
+							handlerpullreq.HandleCommentReactionRemove(pullreqCtrl))
+					})
 				})
 			})
+			r.Route("/reactions", func(r chi.Router) {
+				r.Put("/", handlerpullreq.HandleReactionAdd(pullreqCtrl))
+				r.Delete(fmt.Sprintf("/{%s}", request.PathParamReactionEmoji),
+					handlerpullreq.HandleReactionRemove(pullreqCtrl))
+			})
 			r.Route("/reviewers", func(r chi.Router) {
 				r.Get("/", handlerpullreq.HandleReviewerList(pullreqCtrl))
 				r.Put("/", handlerpullreq.HandleReviewerAdd(pullreqCtrl))
@@ -502,10 +553,21 @@ func SetupPullReq(r chi.Router, pullreqCtrl *pullreq.Controller) {
 					r.Delete("/", handlerpullreq.HandleReviewerDelete(pullreqCtrl))
 				})
 			})
+			r.Route("/labels", func(r chi.Router) {
+				r.Get("/", handlerpullreq.HandleLabelList(pullreqCtrl))
+				r.Put("/", handlerpullreq.HandleLabelAssign(pullreqCtrl))
+				r.Route(fmt.Sprintf("/{%s}", request.PathParamLabelID), func(r chi.Router) {
+					r.Delete("/", handlerpullreq.HandleLabelUnassign(pullreqCtrl))
+				})
+			})
 			r.Route("/reviews", func(r chi.Router) {
 				r.Post("/", handlerpullreq.HandleReviewSubmit(pullreqCtrl))
 			})
 			r.Post("/merge", handlerpullreq.HandleMerge(pullreqCtrl))
+			r.Route("/merge-conflicts", func(r chi.Router) {
+				r.Get("/", handlerpullreq.HandleMergeConflicts(pullreqCtrl))
+				r.Post("/resolve", handlerpullreq.HandleMergeConflictsResolve(pullreqCtrl))
+			})
 			r.Get("/commits", handlerpullreq.HandleCommits(pullreqCtrl))
 			r.Get("/metadata", handlerpullreq.HandleMetadata(pullreqCtrl))
 
@@ -564,6 +626,18 @@ func SetupRules(r chi.Router, repoCtrl *repo.Controller) {
 	})
 }
 
+func SetupLabels(r chi.Router, repoCtrl *repo.Controller) {
+	r.Route("/labels", func(r chi.Router) {
+		r.Post("/", handlerrepo.HandleLabelDefine(repoCtrl))
+		r.Get("/", handlerrepo.HandleLabelList(repoCtrl))
+		r.Route(fmt.Sprintf("/{%s}", request.PathParamLabelName), func(r chi.Router) {
+			r.Get("/", handlerrepo.HandleLabelFind(repoCtrl))
+			r.Patch("/", handlerrepo.HandleLabelUpdate(repoCtrl))
+			r.Delete("/", handlerrepo.HandleLabelDelete(repoCtrl))
+		})
+	})
+}
+
 func setupUser(r chi.Router, userCtrl *user.Controller) {
 	r.Route("/user", func(r chi.Router) {
 		// enforce principal authenticated and it's a user
@@ -592,6 +666,16 @@ func setupUser(r chi.Router, userCtrl *user.Controller) {
 				r.Delete("/", handleruser.HandleDeleteToken(userCtrl, enum.TokenTypeSession))
 			})
 		})
+
+		// GPG and SSH keys used for verifying commit signatures
+		r.Route("/signing-keys", func(r chi.Router) {
+			r.Get("/", handleruser.HandleListSigningKeys(userCtrl))
+			r.Post("/", handleruser.HandleCreateSigningKey(userCtrl))
+
+			r.Route(fmt.Sprintf("/{%s}", request.PathParamSigningKeyID), func(r chi.Router) {
+				r.Delete("/", handleruser.HandleDeleteSigningKey(userCtrl))
+			})
+		})
 	})
 }
 
@@ -643,7 +727,7 @@ func setupKeywordSearch(r chi.Router, searchCtrl *keywordsearch.Controller) {
 	r.Post("/search", handlerkeywordsearch.HandleSearch(searchCtrl))
 }
 
-func setupAdmin(r chi.Router, userCtrl *user.Controller) {
+func setupAdmin(r chi.Router, userCtrl *user.Controller, repoCtrl *repo.Controller) {
 	r.Route("/admin", func(r chi.Router) {
 		r.Use(middlewareprincipal.RestrictToAdmin())
 		r.Route("/users", func(r chi.Router) {
@@ -657,6 +741,11 @@ func setupAdmin(r chi.Router, userCtrl *user.Controller) {
 				r.Patch("/admin", handleruser.HandleUpdateAdmin(userCtrl))
 			})
 		})
+		r.Route("/repos", func(r chi.Router) {
+			r.Route(fmt.Sprintf("/{%s}", request.PathParamRepoRef), func(r chi.Router) {
+				r.Post("/housekeeping", handlerrepo.HandleHousekeeping(repoCtrl))
+			})
+		})
 	})
 }
 
@@ -665,4 +754,9 @@ func setupAccount(r chi.Router, userCtrl *user.Controller, sysCtrl *system.Contr
 	r.Post("/login", account.HandleLogin(userCtrl, cookieName))
 	r.Post("/register", account.HandleRegister(userCtrl, sysCtrl, cookieName))
 	r.Post("/logout", account.HandleLogout(userCtrl, cookieName))
+
+	r.Route("/oidc", func(r chi.Router) {
+		r.Get("/login", account.HandleOIDCLogin(userCtrl))
+		r.Get("/callback", account.HandleOIDCCallback(userCtrl, cookieName, config.URL.UI))
+	})
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/harness/gitness/app/api/render"
 	"github.com/harness/gitness/app/request"
 
+	"github.com/go-chi/chi/middleware"
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zerologr"
 	"github.com/rs/zerolog"
@@ -29,8 +30,9 @@ import (
 )
 
 const (
-	APIMount = "/api"
-	GitMount = "/git"
+	APIMount     = "/api"
+	GitMount     = "/git"
+	MetricsMount = "/metrics"
 )
 
 type Router struct {
@@ -38,6 +40,9 @@ type Router struct {
 	git GitHandler
 	web WebHandler
 
+	// metrics is the optional handler of the prometheus metrics endpoint.
+	metrics http.Handler
+
 	// gitHost describes the optional host via which git traffic is identified.
 	// Note: always stored as lowercase.
 	gitHost string
@@ -49,12 +54,14 @@ func NewRouter(
 	api APIHandler,
 	git GitHandler,
 	web WebHandler,
+	metrics http.Handler,
 	gitHost string,
 ) *Router {
 	return &Router{
-		api: api,
-		git: git,
-		web: web,
+		api:     api,
+		git:     git,
+		web:     web,
+		metrics: metrics,
 
 		gitHost: strings.ToLower(gitHost),
 	}
@@ -73,6 +80,20 @@ func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 			Str("http.original_url", req.URL.String())
 	})
 
+	/*
+	 * 0. METRICS
+	 *
+	 * Prometheus metrics are exposed on "/metrics" (if enabled).
+	 */
+	if r.metrics != nil && req.URL.Path == MetricsMount {
+		log.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("http.handler", "metrics")
+		})
+
+		r.metrics.ServeHTTP(w, req)
+		return
+	}
+
 	/*
 	 * 1. GIT
 	 *
@@ -164,3 +185,31 @@ func (r *Router) isAPITraffic(req *http.Request) bool {
 	p := req.URL.Path
 	return strings.HasPrefix(p, APIMount)
 }
+
+// recoverer recovers from panics the same way as middleware.Recoverer, except for http.ErrAbortHandler,
+// which is passed on to the http server so that it aborts the response instead of completing it.
+func recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		aborted := false
+
+		middleware.Recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rvr := recover()
+				//nolint:errorlint // the panic value is compared, not an error chain
+				if rvr == http.ErrAbortHandler {
+					aborted = true
+					return
+				}
+				if rvr != nil {
+					panic(rvr)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})).ServeHTTP(w, r)
+
+		if aborted {
+			panic(http.ErrAbortHandler)
+		}
+	})
+}
@@ -14,7 +14,11 @@
 
 package router
 
-import "testing"
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
 
 // this unit test ensures routes that require authorization
 // return a 401 unauthorized if no token, or an invalid token
@@ -36,3 +40,18 @@ func TestPipelineGate(t *testing.T) {
 func TestSystemGate(t *testing.T) {
 	t.Skip()
 }
+
+func TestRecoverer(t *testing.T) {
+	h := recoverer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		//nolint:errorlint // the panic value is compared, not an error chain
+		if rvr := recover(); rvr != http.ErrAbortHandler {
+			t.Errorf("expected the abort panic to be passed on, got %v", rvr)
+		}
+	}()
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -16,6 +16,7 @@ package router
 
 import (
 	"context"
+	"net/http"
 	"strings"
 
 	"github.com/harness/gitness/app/api/controller/check"
@@ -54,6 +55,7 @@ var WireSet = wire.NewSet(
 )
 
 func ProvideRouter(
+	config *types.Config,
 	api APIHandler,
 	git GitHandler,
 	web WebHandler,
@@ -70,7 +72,12 @@ func ProvideRouter(
 		gitRoutingHost = gitHostname
 	}
 
-	return NewRouter(api, git, web, gitRoutingHost)
+	var metrics http.Handler
+	if config.Prometheus.Enabled {
+		metrics = NewMetricsHandler(config.Prometheus.Token)
+	}
+
+	return NewRouter(api, git, web, metrics, gitRoutingHost)
 }
 
 func ProvideGitHandler(
@@ -78,13 +78,14 @@ const (
 	exportJobMaxDuration = 45 * time.Minute
 	exportRepoJobUID     = "export_repo_%d"
 	exportSpaceJobUID    = "export_space_%d"
-	jobType              = "repository_export"
+	// JobType is the type of the repository export job. The job data is encrypted.
+	JobType = "repository_export"
 )
 
 var ErrJobRunning = errors.New("an export job is already running")
 
 func (r *Repository) Register(executor *job.Executor) error {
-	return executor.Register(jobType, r)
+	return executor.Register(JobType, r)
 }
 
 func (r *Repository) RunManyForSpace(
@@ -136,7 +137,7 @@ func (r *Repository) RunManyForSpace(
 
 		jobDefinitions[i] = job.Definition{
 			UID:        jobUID,
-			Type:       jobType,
+			Type:       JobType,
 			MaxRetries: exportJobMaxRetries,
 			Timeout:    exportJobMaxDuration,
 			Data:       base64.StdEncoding.EncodeToString(encryptedData),
@@ -47,7 +47,7 @@ func ProvideSpaceExporter(
 		sseStreamer: sseStreamer,
 	}
 
-	err := executor.Register(jobType, exporter)
+	err := executor.Register(JobType, exporter)
 	if err != nil {
 		return nil, err
 	}
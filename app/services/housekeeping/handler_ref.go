@@ -0,0 +1,64 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package housekeeping
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gitevents "github.com/harness/gitness/app/events/git"
+	"github.com/harness/gitness/events"
+)
+
+func (s *Service) handleEventBranchCreated(ctx context.Context,
+	event *events.Event[*gitevents.BranchCreatedPayload]) error {
+	return s.recordPush(ctx, event.Payload.RepoID, event.Timestamp)
+}
+
+func (s *Service) handleEventBranchUpdated(ctx context.Context,
+	event *events.Event[*gitevents.BranchUpdatedPayload]) error {
+	return s.recordPush(ctx, event.Payload.RepoID, event.Timestamp)
+}
+
+func (s *Service) handleEventBranchDeleted(ctx context.Context,
+	event *events.Event[*gitevents.BranchDeletedPayload]) error {
+	return s.recordPush(ctx, event.Payload.RepoID, event.Timestamp)
+}
+
+func (s *Service) handleEventTagCreated(ctx context.Context,
+	event *events.Event[*gitevents.TagCreatedPayload]) error {
+	return s.recordPush(ctx, event.Payload.RepoID, event.Timestamp)
+}
+
+func (s *Service) handleEventTagUpdated(ctx context.Context,
+	event *events.Event[*gitevents.TagUpdatedPayload]) error {
+	return s.recordPush(ctx, event.Payload.RepoID, event.Timestamp)
+}
+
+func (s *Service) handleEventTagDeleted(ctx context.Context,
+	event *events.Event[*gitevents.TagDeletedPayload]) error {
+	return s.recordPush(ctx, event.Payload.RepoID, event.Timestamp)
+}
+
+// recordPush counts the ref update as push to the repository.
+// NOTE: A push updating multiple refs is counted once per ref, which better reflects the amount of changes.
+func (s *Service) recordPush(ctx context.Context, repoID int64, pushed time.Time) error {
+	if err := s.repoHousekeepingStore.RecordPush(ctx, repoID, pushed.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to record push for repo %d: %w", repoID, err)
+	}
+
+	return nil
+}
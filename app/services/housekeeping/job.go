@@ -0,0 +1,165 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package housekeeping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	jobType = "repo-housekeeping"
+
+	// repoJobType is the type of the jobs that maintain a single repository on request.
+	repoJobType = "repo-housekeeping-single"
+	// repoJobTimeoutOverhead is added to the repo timeout for waiting on locks and recording the maintenance.
+	repoJobTimeoutOverhead = time.Minute
+)
+
+type repoJobInput struct {
+	RepoID int64 `json:"repo_id"`
+}
+
+// Register registers the recurring housekeeping job.
+func (s *Service) Register(ctx context.Context) error {
+	if !s.config.Enabled {
+		return nil
+	}
+
+	err := s.scheduler.AddRecurring(ctx, jobType, jobType, s.config.CRON, s.config.MaxDuration)
+	if err != nil {
+		return fmt.Errorf("failed to register recurring job for housekeeping: %w", err)
+	}
+
+	return nil
+}
+
+// Handle maintains the repositories with the most pushes since their last maintenance.
+func (s *Service) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	if !s.config.Enabled {
+		return "", nil
+	}
+
+	pending, err := s.repoHousekeepingStore.ListPending(ctx, s.config.MinPushes, s.config.RepoLimit)
+	if err != nil {
+		return "", fmt.Errorf("failed to list repositories pending housekeeping: %w", err)
+	}
+
+	log.Ctx(ctx).Info().Msgf(
+		"start housekeeping of %d repositories (operation timeout: %s)",
+		len(pending),
+		time.Now().Add(s.config.MaxDuration).Format(time.RFC3339Nano),
+	)
+
+	var wg sync.WaitGroup
+	taskCh := make(chan *types.RepoHousekeeping)
+	for i := 0; i < s.config.NumWorkers; i++ {
+		wg.Add(1)
+		go s.worker(ctx, &wg, taskCh)
+	}
+loop:
+	for _, housekeeping := range pending {
+		select {
+		case <-ctx.Done():
+			break loop
+		case taskCh <- housekeeping:
+		}
+	}
+	close(taskCh)
+	wg.Wait()
+
+	return "", nil
+}
+
+func (s *Service) worker(ctx context.Context, wg *sync.WaitGroup, taskCh <-chan *types.RepoHousekeeping) {
+	defer wg.Done()
+
+	for housekeeping := range taskCh {
+		log := log.Ctx(ctx).With().Int64("repo_id", housekeeping.RepoID).Logger()
+
+		repo, err := s.repoStore.Find(ctx, housekeeping.RepoID)
+		if err != nil {
+			log.Error().Err(err).Msg("failed to find repo")
+			continue
+		}
+
+		if repo.Importing {
+			log.Debug().Msg("skip housekeeping of repo that is being imported")
+			continue
+		}
+
+		log.Debug().Msgf("start housekeeping of repo (pushes: %d)", housekeeping.Pushes)
+
+		if _, err = s.Maintain(ctx, repo); err != nil {
+			log.Error().Err(err).Msg("failed to maintain repo")
+			continue
+		}
+
+		log.Debug().Msg("completed housekeeping of repo")
+	}
+}
+
+// Schedule starts a background job that runs the housekeeping tasks on the repository.
+func (s *Service) Schedule(ctx context.Context, repo *types.Repository) error {
+	data, err := json.Marshal(repoJobInput{RepoID: repo.ID})
+	if err != nil {
+		return fmt.Errorf("failed to marshal repository housekeeping job input: %w", err)
+	}
+
+	err = s.scheduler.RunJob(ctx, job.Definition{
+		UID:        repoJobType + "-" + strconv.FormatInt(repo.ID, 10) + "-" + strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Type:       repoJobType,
+		MaxRetries: 0,
+		Timeout:    s.config.RepoTimeout + repoJobTimeoutOverhead,
+		Data:       string(data),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to run repository housekeeping job: %w", err)
+	}
+
+	return nil
+}
+
+// repoJobHandler maintains the repository of a job started by Schedule.
+type repoJobHandler struct {
+	service *Service
+}
+
+func (h repoJobHandler) Handle(ctx context.Context, data string, _ job.ProgressReporter) (string, error) {
+	var input repoJobInput
+	if err := json.Unmarshal([]byte(data), &input); err != nil {
+		return "", fmt.Errorf("failed to unmarshal repository housekeeping job input: %w", err)
+	}
+
+	repo, err := h.service.repoStore.Find(ctx, input.RepoID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find repo: %w", err)
+	}
+
+	if _, err = h.service.Maintain(ctx, repo); err != nil {
+		return "", err
+	}
+
+	return "", nil
+}
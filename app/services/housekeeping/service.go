@@ -0,0 +1,320 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package housekeeping
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gitevents "github.com/harness/gitness/app/events/git"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/contextutil"
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/lock"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/stream"
+	"github.com/harness/gitness/types"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	eventsReaderGroupName = "gitness:housekeeping"
+
+	// lockNamespace and lockKeySuffix define the mutex that prevents concurrent maintenance of a repository.
+	lockNamespace = "repo"
+	lockKeySuffix = "/housekeeping"
+
+	// refsLockKeySuffix defines the mutex that is held while the references of a repository are packed.
+	// Pushes wait for it, as packing the references competes with their reference updates.
+	refsLockKeySuffix = "/housekeeping-refs"
+	refsLockExpiry    = time.Minute
+
+	// pushWaitTries and pushWaitRetryDelay define how long a push waits for the references to be packed.
+	pushWaitTries      = 20
+	pushWaitRetryDelay = 500 * time.Millisecond
+
+	// minPruneExpiry is the minimum age of unreachable objects before they are pruned.
+	// Pushes aren't blocked during the object maintenance, so the objects of an in-flight push have to be kept.
+	minPruneExpiry = time.Hour
+)
+
+type Config struct {
+	EventReaderName string
+	Concurrency     int
+	MaxRetries      int
+
+	Enabled     bool
+	CRON        string
+	MaxDuration time.Duration
+	NumWorkers  int
+	// RepoLimit is the maximum number of repositories that are maintained by a single run.
+	RepoLimit int
+	// MinPushes is the minimum number of pushes since the last maintenance for a repository to be maintained.
+	MinPushes int64
+	// RepoTimeout is the maximum duration of the maintenance of a single repository.
+	RepoTimeout time.Duration
+	// PruneExpiry is the minimum age of unreachable objects before they are pruned.
+	PruneExpiry time.Duration
+}
+
+func (c *Config) Prepare() error {
+	if c == nil {
+		return errors.New("config is required")
+	}
+	if c.EventReaderName == "" {
+		return errors.New("config.EventReaderName is required")
+	}
+	if c.Concurrency < 1 {
+		return errors.New("config.Concurrency has to be a positive number")
+	}
+	if c.MaxRetries < 0 {
+		return errors.New("config.MaxRetries can't be negative")
+	}
+	if c.NumWorkers < 1 {
+		return errors.New("config.NumWorkers has to be a positive number")
+	}
+	if c.RepoLimit < 1 {
+		return errors.New("config.RepoLimit has to be a positive number")
+	}
+	if c.MinPushes < 1 {
+		return errors.New("config.MinPushes has to be a positive number")
+	}
+	if c.RepoTimeout <= 0 {
+		return errors.New("config.RepoTimeout has to be provided")
+	}
+	if c.PruneExpiry < minPruneExpiry {
+		return fmt.Errorf("config.PruneExpiry has to be at least %s", minPruneExpiry)
+	}
+	return nil
+}
+
+// Service is responsible for the maintenance of the git repositories.
+// It counts the pushes to each repository and periodically runs the git housekeeping tasks
+// (repack, prune, pack-refs, commit-graph) on the repositories with the most pushes.
+type Service struct {
+	config                Config
+	git                   git.Interface
+	repoStore             store.RepoStore
+	repoHousekeepingStore store.RepoHousekeepingStore
+	mtxManager            lock.MutexManager
+	scheduler             *job.Scheduler
+}
+
+func NewService(
+	ctx context.Context,
+	config Config,
+	gitReaderFactory *events.ReaderFactory[*gitevents.Reader],
+	git git.Interface,
+	repoStore store.RepoStore,
+	repoHousekeepingStore store.RepoHousekeepingStore,
+	mtxManager lock.MutexManager,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+) (*Service, error) {
+	if err := config.Prepare(); err != nil {
+		return nil, fmt.Errorf("provided housekeeping service config is invalid: %w", err)
+	}
+	service := &Service{
+		config:                config,
+		git:                   git,
+		repoStore:             repoStore,
+		repoHousekeepingStore: repoHousekeepingStore,
+		mtxManager:            mtxManager,
+		scheduler:             scheduler,
+	}
+
+	// the maintenance of single repositories can be requested by admins even if the housekeeping is disabled.
+	if err := executor.Register(repoJobType, repoJobHandler{service: service}); err != nil {
+		return nil, fmt.Errorf("failed to register repository housekeeping job handler: %w", err)
+	}
+
+	if !config.Enabled {
+		return service, nil
+	}
+
+	if err := executor.Register(jobType, service); err != nil {
+		return nil, fmt.Errorf("failed to register housekeeping job handler: %w", err)
+	}
+
+	_, err := gitReaderFactory.Launch(ctx, eventsReaderGroupName, config.EventReaderName,
+		func(r *gitevents.Reader) error {
+			const idleTimeout = 1 * time.Minute
+			r.Configure(
+				stream.WithConcurrency(config.Concurrency),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(config.MaxRetries),
+				))
+
+			// register events
+			_ = r.RegisterBranchCreated(service.handleEventBranchCreated)
+			_ = r.RegisterBranchUpdated(service.handleEventBranchUpdated)
+			_ = r.RegisterBranchDeleted(service.handleEventBranchDeleted)
+			_ = r.RegisterTagCreated(service.handleEventTagCreated)
+			_ = r.RegisterTagUpdated(service.handleEventTagUpdated)
+			_ = r.RegisterTagDeleted(service.handleEventTagDeleted)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch git event reader for housekeeping: %w", err)
+	}
+
+	return service, nil
+}
+
+// Maintain runs the housekeeping tasks on the repository and records the time of the maintenance.
+// The object housekeeping tasks are safe to run alongside pushes (incremental repack, prune with expiry,
+// split commit-graph), only packing the references is coordinated with pushes (see WaitForRefsMaintenance).
+func (s *Service) Maintain(ctx context.Context, repo *types.Repository) (*types.RepoHousekeeping, error) {
+	unlock, err := s.lockRepo(ctx, repo.GitUID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// the pushes are read while holding the lock to not deduct them twice in case of concurrent triggers.
+	var pushes int64
+	housekeeping, err := s.repoHousekeepingStore.Find(ctx, repo.ID)
+	if err != nil && !errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil, fmt.Errorf("failed to find housekeeping state of repo: %w", err)
+	}
+	if housekeeping != nil {
+		pushes = housekeeping.Pushes
+	}
+
+	gitCtx, cancel := context.WithTimeout(ctx, s.config.RepoTimeout)
+	defer cancel()
+
+	err = s.git.MaintainRepository(gitCtx, &git.MaintainRepositoryParams{
+		ReadParams:  git.CreateReadParams(repo),
+		PruneExpiry: s.config.PruneExpiry,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to maintain repository: %w", err)
+	}
+
+	if err = s.packRefs(gitCtx, repo); err != nil {
+		return nil, err
+	}
+
+	err = s.repoHousekeepingStore.RecordMaintenance(ctx, repo.ID, pushes, time.Now().UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("failed to record maintenance of repo: %w", err)
+	}
+
+	housekeeping, err = s.repoHousekeepingStore.Find(ctx, repo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find housekeeping state of repo: %w", err)
+	}
+
+	return housekeeping, nil
+}
+
+// packRefs packs the references of the repository while holding the references mutex of the repository.
+func (s *Service) packRefs(ctx context.Context, repo *types.Repository) error {
+	mutex, err := s.mtxManager.NewMutex(
+		repo.GitUID+refsLockKeySuffix,
+		lock.WithNamespace(lockNamespace),
+		lock.WithExpiry(refsLockExpiry),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create references mutex for repo %q: %w", repo.GitUID, err)
+	}
+
+	if err = mutex.Lock(ctx); err != nil {
+		return fmt.Errorf("failed to lock references mutex for repo %q: %w", repo.GitUID, err)
+	}
+
+	defer func() {
+		// always unlock independent of whether source context got canceled or not
+		ctx, cancel := context.WithTimeout(contextutil.WithNewValues(context.Background(), ctx), 30*time.Second)
+		defer cancel()
+
+		if err := mutex.Unlock(ctx); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to unlock references mutex of repo %q", repo.GitUID)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, refsLockExpiry)
+	defer cancel()
+
+	if err = s.git.PackRefs(ctx, &git.PackRefsParams{ReadParams: git.CreateReadParams(repo)}); err != nil {
+		return fmt.Errorf("failed to pack references: %w", err)
+	}
+
+	return nil
+}
+
+// WaitForRefsMaintenance blocks until the references of the repository aren't being packed anymore.
+// Pushes call it before updating references; the wait is short, as only packing the references holds the mutex.
+// Errors (e.g. the lock backend is unavailable or the wait timed out) are for logging only, pushes shouldn't fail.
+func (s *Service) WaitForRefsMaintenance(ctx context.Context, repo *types.Repository) error {
+	mutex, err := s.mtxManager.NewMutex(
+		repo.GitUID+refsLockKeySuffix,
+		lock.WithNamespace(lockNamespace),
+		lock.WithExpiry(refsLockExpiry),
+		lock.WithTries(pushWaitTries),
+		lock.WithRetryDelay(pushWaitRetryDelay),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create references mutex for repo %q: %w", repo.GitUID, err)
+	}
+
+	if err = mutex.Lock(ctx); err != nil {
+		return fmt.Errorf("failed to wait for references maintenance of repo %q: %w", repo.GitUID, err)
+	}
+
+	if err = mutex.Unlock(ctx); err != nil {
+		return fmt.Errorf("failed to unlock references mutex of repo %q: %w", repo.GitUID, err)
+	}
+
+	return nil
+}
+
+func (s *Service) lockRepo(ctx context.Context, repoUID string) (func(), error) {
+	mutex, err := s.mtxManager.NewMutex(
+		repoUID+lockKeySuffix,
+		lock.WithNamespace(lockNamespace),
+		lock.WithExpiry(s.config.RepoTimeout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create housekeeping mutex for repo %q: %w", repoUID, err)
+	}
+
+	if err = mutex.Lock(ctx); err != nil {
+		return nil, fmt.Errorf("failed to lock housekeeping mutex for repo %q: %w", repoUID, err)
+	}
+
+	unlockFn := func() {
+		// always unlock independent of whether source context got canceled or not
+		ctx, cancel := context.WithTimeout(
+			contextutil.WithNewValues(context.Background(), ctx),
+			30*time.Second,
+		)
+		defer cancel()
+
+		if err := mutex.Unlock(ctx); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msgf("failed to unlock housekeeping mutex of repo %q", repoUID)
+		}
+	}
+
+	return unlockFn, nil
+}
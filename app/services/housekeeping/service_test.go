@@ -0,0 +1,301 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package housekeeping
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gitevents "github.com/harness/gitness/app/events/git"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/lock"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+type gitMock struct {
+	git.Interface
+	maintain func(ctx context.Context, params *git.MaintainRepositoryParams) error
+	packRefs func(ctx context.Context, params *git.PackRefsParams) error
+}
+
+func (g *gitMock) MaintainRepository(ctx context.Context, params *git.MaintainRepositoryParams) error {
+	return g.maintain(ctx, params)
+}
+
+func (g *gitMock) PackRefs(ctx context.Context, params *git.PackRefsParams) error {
+	if g.packRefs == nil {
+		return nil
+	}
+	return g.packRefs(ctx, params)
+}
+
+type repoStoreMock struct {
+	store.RepoStore
+	repo *types.Repository
+}
+
+func (s repoStoreMock) Find(context.Context, int64) (*types.Repository, error) {
+	return s.repo, nil
+}
+
+type housekeepingStoreMock struct {
+	store.RepoHousekeepingStore
+	mx    sync.Mutex
+	state map[int64]types.RepoHousekeeping
+}
+
+func (s *housekeepingStoreMock) Find(_ context.Context, repoID int64) (*types.RepoHousekeeping, error) {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	housekeeping, ok := s.state[repoID]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	return &housekeeping, nil
+}
+
+func (s *housekeepingStoreMock) RecordPush(_ context.Context, repoID int64, pushed int64) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	housekeeping := s.state[repoID]
+	housekeeping.RepoID = repoID
+	housekeeping.Pushes++
+	housekeeping.LastPush = pushed
+	s.state[repoID] = housekeeping
+	return nil
+}
+
+func (s *housekeepingStoreMock) RecordMaintenance(
+	_ context.Context,
+	repoID int64,
+	pushes int64,
+	maintained int64,
+) error {
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	housekeeping := s.state[repoID]
+	housekeeping.RepoID = repoID
+	housekeeping.Pushes -= pushes
+	housekeeping.LastMaintenance = maintained
+	s.state[repoID] = housekeeping
+	return nil
+}
+
+func newTestService(maintain func(ctx context.Context, params *git.MaintainRepositoryParams) error) *Service {
+	return &Service{
+		config: Config{
+			RepoTimeout: time.Minute,
+			PruneExpiry: 24 * time.Hour,
+		},
+		git:                   &gitMock{maintain: maintain},
+		repoHousekeepingStore: &housekeepingStoreMock{state: map[int64]types.RepoHousekeeping{}},
+		mtxManager:            lock.NewInMemory(lock.Config{Tries: 1}),
+	}
+}
+
+func branchUpdated(repoID int64) *events.Event[*gitevents.BranchUpdatedPayload] {
+	return &events.Event[*gitevents.BranchUpdatedPayload]{
+		Timestamp: time.Now(),
+		Payload:   &gitevents.BranchUpdatedPayload{RepoID: repoID, Ref: "refs/heads/main"},
+	}
+}
+
+func TestService_Maintain(t *testing.T) {
+	ctx := context.Background()
+	repo := &types.Repository{ID: 1, GitUID: "repo-uid"}
+
+	var s *Service
+	s = newTestService(func(ctx context.Context, params *git.MaintainRepositoryParams) error {
+		require.Equal(t, "repo-uid", params.RepoUID)
+		require.Equal(t, 24*time.Hour, params.PruneExpiry)
+
+		// pushes aren't blocked by an ongoing maintenance.
+		return s.handleEventBranchUpdated(ctx, branchUpdated(repo.ID))
+	})
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, s.handleEventBranchUpdated(ctx, branchUpdated(repo.ID)))
+	}
+
+	housekeeping, err := s.Maintain(ctx, repo)
+	require.NoError(t, err)
+
+	// only the pushes that happened before the maintenance are deducted.
+	require.Equal(t, int64(1), housekeeping.Pushes)
+	require.NotZero(t, housekeeping.LastMaintenance)
+}
+
+func TestService_Maintain_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := &types.Repository{ID: 1, GitUID: "repo-uid"}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s := newTestService(func(context.Context, *git.MaintainRepositoryParams) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	errCh := make(chan error)
+	go func() {
+		_, err := s.Maintain(ctx, repo)
+		errCh <- err
+	}()
+	<-started
+
+	// a concurrent maintenance of the same repository isn't allowed.
+	_, err := s.Maintain(ctx, repo)
+	var lockErr *lock.Error
+	require.ErrorAs(t, err, &lockErr)
+
+	close(release)
+	require.NoError(t, <-errCh)
+
+	// the lock is released once the maintenance is completed.
+	go func() { <-started }()
+	_, err = s.Maintain(ctx, repo)
+	require.NoError(t, err)
+}
+
+func TestService_Maintain_Failure(t *testing.T) {
+	ctx := context.Background()
+	repo := &types.Repository{ID: 1, GitUID: "repo-uid"}
+
+	errMaintain := errors.New("maintenance failed")
+	s := newTestService(func(context.Context, *git.MaintainRepositoryParams) error {
+		return errMaintain
+	})
+
+	require.NoError(t, s.handleEventBranchUpdated(ctx, branchUpdated(repo.ID)))
+
+	_, err := s.Maintain(ctx, repo)
+	require.ErrorIs(t, err, errMaintain)
+
+	// the pushes are kept for the next maintenance.
+	housekeeping, err := s.repoHousekeepingStore.Find(ctx, repo.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), housekeeping.Pushes)
+	require.Zero(t, housekeeping.LastMaintenance)
+
+	// the lock is released after a failed maintenance.
+	_, err = s.Maintain(ctx, repo)
+	require.ErrorIs(t, err, errMaintain)
+}
+
+func TestService_Maintain_PackRefs(t *testing.T) {
+	ctx := context.Background()
+	repo := &types.Repository{ID: 1, GitUID: "repo-uid"}
+
+	var s *Service
+	s = newTestService(func(ctx context.Context, _ *git.MaintainRepositoryParams) error {
+		// pushes don't wait for the object maintenance.
+		return s.WaitForRefsMaintenance(ctx, repo)
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.git.(*gitMock).packRefs = func(_ context.Context, params *git.PackRefsParams) error {
+		require.Equal(t, "repo-uid", params.RepoUID)
+		close(started)
+		<-release
+		return nil
+	}
+
+	errCh := make(chan error)
+	go func() {
+		_, err := s.Maintain(ctx, repo)
+		errCh <- err
+	}()
+	<-started
+
+	// pushes wait for the references to be packed.
+	waitCh := make(chan error)
+	go func() {
+		waitCh <- s.WaitForRefsMaintenance(ctx, repo)
+	}()
+
+	select {
+	case err := <-waitCh:
+		t.Fatalf("push didn't wait for the references to be packed (err: %v)", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-errCh)
+	require.NoError(t, <-waitCh)
+}
+
+func TestRepoJobHandler_Handle(t *testing.T) {
+	ctx := context.Background()
+	repo := &types.Repository{ID: 1, GitUID: "repo-uid"}
+
+	var maintained string
+	s := newTestService(func(_ context.Context, params *git.MaintainRepositoryParams) error {
+		maintained = params.RepoUID
+		return nil
+	})
+	s.repoStore = repoStoreMock{repo: repo}
+
+	_, err := repoJobHandler{service: s}.Handle(ctx, `{"repo_id":1}`, nil)
+	require.NoError(t, err)
+	require.Equal(t, "repo-uid", maintained)
+
+	housekeeping, err := s.repoHousekeepingStore.Find(ctx, repo.ID)
+	require.NoError(t, err)
+	require.NotZero(t, housekeeping.LastMaintenance)
+
+	_, err = repoJobHandler{service: s}.Handle(ctx, `invalid`, nil)
+	require.Error(t, err)
+}
+
+func TestConfig_Prepare(t *testing.T) {
+	valid := func() *Config {
+		return &Config{
+			EventReaderName: "gitness",
+			Concurrency:     1,
+			NumWorkers:      1,
+			RepoLimit:       1,
+			MinPushes:       1,
+			RepoTimeout:     time.Minute,
+			PruneExpiry:     24 * time.Hour,
+		}
+	}
+
+	require.NoError(t, valid().Prepare())
+
+	config := valid()
+	config.PruneExpiry = time.Hour
+	require.NoError(t, config.Prepare())
+
+	// unreachable objects of in-flight pushes mustn't be pruned.
+	config.PruneExpiry = time.Minute
+	require.ErrorContains(t, config.Prepare(), "config.PruneExpiry")
+
+	config = valid()
+	config.RepoTimeout = 0
+	require.ErrorContains(t, config.Prepare(), "config.RepoTimeout")
+}
@@ -87,10 +87,11 @@ type Input struct {
 	Pipelines PipelineOption `json:"pipelines"`
 }
 
-const jobType = "repository_import"
+// JobType is the type of the repository import job. The job data is encrypted.
+const JobType = "repository_import"
 
 func (r *Repository) Register(executor *job.Executor) error {
-	return executor.Register(jobType, r)
+	return executor.Register(JobType, r)
 }
 
 // Run starts a background job that imports the provided repository from the provided clone URL.
@@ -172,7 +173,7 @@ func (r *Repository) getJobDef(jobUID string, input Input) (job.Definition, erro
 
 	return job.Definition{
 		UID:        jobUID,
-		Type:       jobType,
+		Type:       JobType,
 		MaxRetries: importJobMaxRetries,
 		Timeout:    importJobMaxDuration,
 		Data:       base64.StdEncoding.EncodeToString(encryptedData),
@@ -60,7 +60,7 @@ func ProvideRepoImporter(
 		indexer:       indexer,
 	}
 
-	err := executor.Register(jobType, importer)
+	err := executor.Register(JobType, importer)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,417 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package label
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/app/api/usererror"
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/check"
+	"github.com/harness/gitness/types/enum"
+)
+
+const maxLabelNameLength = 50
+
+// Service is responsible for management of pull request labels.
+// Labels are defined either in a space, in which case they are available to all repositories
+// in the space and its sub-spaces, or in a repository.
+type Service struct {
+	labelStore       store.LabelStore
+	labelAssignStore store.PullReqLabelAssignmentStore
+	spaceStore       store.SpaceStore
+}
+
+func NewService(
+	labelStore store.LabelStore,
+	labelAssignStore store.PullReqLabelAssignmentStore,
+	spaceStore store.SpaceStore,
+) *Service {
+	return &Service{
+		labelStore:       labelStore,
+		labelAssignStore: labelAssignStore,
+		spaceStore:       spaceStore,
+	}
+}
+
+// DefineInput holds the properties of a new label.
+type DefineInput struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Color       enum.LabelColor `json:"color"`
+}
+
+func (in *DefineInput) Sanitize() error {
+	in.Name = strings.TrimSpace(in.Name)
+	in.Description = strings.TrimSpace(in.Description)
+
+	if err := checkName(in.Name); err != nil {
+		return err
+	}
+
+	if err := check.Description(in.Description); err != nil {
+		return err
+	}
+
+	color, ok := in.Color.Sanitize()
+	if !ok {
+		return usererror.BadRequestf("Invalid label color '%s'.", in.Color)
+	}
+	in.Color = color
+
+	return nil
+}
+
+// UpdateInput holds the label properties that should be updated.
+type UpdateInput struct {
+	Name        *string          `json:"name"`
+	Description *string          `json:"description"`
+	Color       *enum.LabelColor `json:"color"`
+}
+
+func (in *UpdateInput) Sanitize() error {
+	if in.Name != nil {
+		*in.Name = strings.TrimSpace(*in.Name)
+		if err := checkName(*in.Name); err != nil {
+			return err
+		}
+	}
+
+	if in.Description != nil {
+		*in.Description = strings.TrimSpace(*in.Description)
+		if err := check.Description(*in.Description); err != nil {
+			return err
+		}
+	}
+
+	if in.Color != nil {
+		color, ok := in.Color.Sanitize()
+		if !ok {
+			return usererror.BadRequestf("Invalid label color '%s'.", *in.Color)
+		}
+		in.Color = &color
+	}
+
+	return nil
+}
+
+func checkName(name string) error {
+	if name == "" {
+		return usererror.BadRequest("Label name must be provided.")
+	}
+
+	if len(name) > maxLabelNameLength {
+		return usererror.BadRequestf("Label name can be at most %d in length.", maxLabelNameLength)
+	}
+
+	if strings.Contains(name, "/") {
+		return usererror.BadRequest("Label name can't contain the '/' character.")
+	}
+
+	return check.ForControlCharacters(name)
+}
+
+// Define creates a new label in a space or a repository.
+func (s *Service) Define(
+	ctx context.Context,
+	principalID int64,
+	spaceID, repoID *int64,
+	in *DefineInput,
+) (*types.Label, error) {
+	if err := in.Sanitize(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UnixMilli()
+	label := &types.Label{
+		SpaceID:     spaceID,
+		RepoID:      repoID,
+		Name:        in.Name,
+		Description: in.Description,
+		Color:       in.Color,
+		Created:     now,
+		Updated:     now,
+		CreatedBy:   principalID,
+		UpdatedBy:   principalID,
+	}
+
+	err := s.labelStore.Create(ctx, label)
+	if errors.Is(err, gitness_store.ErrDuplicate) {
+		return nil, usererror.Conflict(fmt.Sprintf("Label '%s' already exists.", in.Name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create label: %w", err)
+	}
+
+	return label, nil
+}
+
+// Find returns a label defined in a space or a repository by its name.
+func (s *Service) Find(
+	ctx context.Context,
+	spaceID, repoID *int64,
+	name string,
+) (*types.Label, error) {
+	label, err := s.labelStore.FindByName(ctx, spaceID, repoID, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find label by name: %w", err)
+	}
+
+	return label, nil
+}
+
+// Update updates a label defined in a space or a repository.
+func (s *Service) Update(
+	ctx context.Context,
+	principalID int64,
+	spaceID, repoID *int64,
+	name string,
+	in *UpdateInput,
+) (*types.Label, error) {
+	if err := in.Sanitize(); err != nil {
+		return nil, err
+	}
+
+	label, err := s.Find(ctx, spaceID, repoID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.Name != nil {
+		label.Name = *in.Name
+	}
+	if in.Description != nil {
+		label.Description = *in.Description
+	}
+	if in.Color != nil {
+		label.Color = *in.Color
+	}
+
+	label.Updated = time.Now().UnixMilli()
+	label.UpdatedBy = principalID
+
+	err = s.labelStore.Update(ctx, label)
+	if errors.Is(err, gitness_store.ErrDuplicate) {
+		return nil, usererror.Conflict(fmt.Sprintf("Label '%s' already exists.", label.Name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update label: %w", err)
+	}
+
+	return label, nil
+}
+
+// Delete deletes a label defined in a space or a repository.
+// The label is automatically removed from all pull requests it's assigned to.
+func (s *Service) Delete(
+	ctx context.Context,
+	spaceID, repoID *int64,
+	name string,
+) error {
+	if _, err := s.Find(ctx, spaceID, repoID, name); err != nil {
+		return err
+	}
+
+	if err := s.labelStore.Delete(ctx, spaceID, repoID, name); err != nil {
+		return fmt.Errorf("failed to delete label: %w", err)
+	}
+
+	return nil
+}
+
+// ListSpace returns labels defined in a space.
+// If the filter's Inherited flag is set, labels of all parent spaces are included.
+func (s *Service) ListSpace(
+	ctx context.Context,
+	spaceID int64,
+	filter *types.LabelFilter,
+) ([]*types.Label, error) {
+	if !filter.Inherited {
+		labels, err := s.labelStore.List(ctx, &spaceID, nil, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list space labels: %w", err)
+		}
+
+		return labels, nil
+	}
+
+	spaceIDs, err := s.spaceStore.GetAncestorIDs(ctx, spaceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent spaces: %w", err)
+	}
+
+	labels, err := s.labelStore.ListInScopes(ctx, 0, spaceIDs, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inherited space labels: %w", err)
+	}
+
+	return labels, nil
+}
+
+// ListRepo returns labels defined in a repository.
+// If the filter's Inherited flag is set, labels of all parent spaces of the repository are included.
+func (s *Service) ListRepo(
+	ctx context.Context,
+	repo *types.Repository,
+	filter *types.LabelFilter,
+) ([]*types.Label, error) {
+	if !filter.Inherited {
+		labels, err := s.labelStore.List(ctx, nil, &repo.ID, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repository labels: %w", err)
+		}
+
+		return labels, nil
+	}
+
+	spaceIDs, err := s.spaceStore.GetAncestorIDs(ctx, repo.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent spaces: %w", err)
+	}
+
+	labels, err := s.labelStore.ListInScopes(ctx, repo.ID, spaceIDs, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inherited repository labels: %w", err)
+	}
+
+	return labels, nil
+}
+
+// FindAvailable returns the label if it can be used in the repository,
+// that is if it's defined either in the repository itself or in one of its parent spaces.
+func (s *Service) FindAvailable(
+	ctx context.Context,
+	repo *types.Repository,
+	labelID int64,
+) (*types.Label, error) {
+	label, err := s.labelStore.Find(ctx, labelID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return nil, usererror.NotFound("Label not found.")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find label: %w", err)
+	}
+
+	if label.RepoID != nil {
+		if *label.RepoID != repo.ID {
+			return nil, usererror.NotFound("Label not found.")
+		}
+
+		return label, nil
+	}
+
+	spaceIDs, err := s.spaceStore.GetAncestorIDs(ctx, repo.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parent spaces: %w", err)
+	}
+
+	for _, spaceID := range spaceIDs {
+		if label.SpaceID != nil && *label.SpaceID == spaceID {
+			return label, nil
+		}
+	}
+
+	return nil, usererror.NotFound("Label not found.")
+}
+
+// Assign assigns the label to the pull request.
+// The returned flag is false if the label had already been assigned.
+func (s *Service) Assign(
+	ctx context.Context,
+	principalID int64,
+	pr *types.PullReq,
+	label *types.Label,
+) (bool, error) {
+	_, err := s.labelAssignStore.Find(ctx, pr.ID, label.ID)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return false, fmt.Errorf("failed to check existing label assignment: %w", err)
+	}
+
+	err = s.labelAssignStore.Assign(ctx, &types.PullReqLabel{
+		PullReqID: pr.ID,
+		LabelID:   label.ID,
+		Created:   time.Now().UnixMilli(),
+		CreatedBy: principalID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to assign label to pull request: %w", err)
+	}
+
+	return true, nil
+}
+
+// Unassign removes the label from the pull request.
+// The returned flag is false if the label wasn't assigned to the pull request.
+func (s *Service) Unassign(
+	ctx context.Context,
+	pr *types.PullReq,
+	labelID int64,
+) (bool, error) {
+	_, err := s.labelAssignStore.Find(ctx, pr.ID, labelID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check existing label assignment: %w", err)
+	}
+
+	if err = s.labelAssignStore.Unassign(ctx, pr.ID, labelID); err != nil {
+		return false, fmt.Errorf("failed to remove label from pull request: %w", err)
+	}
+
+	return true, nil
+}
+
+// ListAssigned returns labels assigned to the pull request.
+func (s *Service) ListAssigned(ctx context.Context, pr *types.PullReq) ([]*types.LabelInfo, error) {
+	labels, err := s.labelAssignStore.ListAssigned(ctx, pr.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pull request labels: %w", err)
+	}
+
+	return labels, nil
+}
+
+// Backfill sets the Labels field of the provided pull requests.
+func (s *Service) Backfill(ctx context.Context, pullReqs ...*types.PullReq) error {
+	if len(pullReqs) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(pullReqs))
+	for i, pr := range pullReqs {
+		ids[i] = pr.ID
+	}
+
+	labelMap, err := s.labelAssignStore.ListAssignedByPullReqIDs(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to list labels of pull requests: %w", err)
+	}
+
+	for _, pr := range pullReqs {
+		pr.Labels = labelMap[pr.ID]
+	}
+
+	return nil
+}
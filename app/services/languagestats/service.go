@@ -0,0 +1,232 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package languagestats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	gitevents "github.com/harness/gitness/app/events/git"
+	"github.com/harness/gitness/app/store"
+	gitnesserrors "github.com/harness/gitness/errors"
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/git"
+	gitenum "github.com/harness/gitness/git/enum"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/stream"
+	"github.com/harness/gitness/types"
+)
+
+const (
+	eventsReaderGroupName = "gitness:languagestats"
+)
+
+type Config struct {
+	EventReaderName string
+	Concurrency     int
+	MaxRetries      int
+}
+
+func (c *Config) Prepare() error {
+	if c == nil {
+		return errors.New("config is required")
+	}
+	if c.EventReaderName == "" {
+		return errors.New("config.EventReaderName is required")
+	}
+	if c.Concurrency < 1 {
+		return errors.New("config.Concurrency has to be a positive number")
+	}
+	if c.MaxRetries < 0 {
+		return errors.New("config.MaxRetries can't be negative")
+	}
+	return nil
+}
+
+// Service is responsible for calculating the language statistics of repositories.
+// The statistics are calculated for the default branch and cached against its head commit.
+type Service struct {
+	config                 Config
+	git                    git.Interface
+	repoStore              store.RepoStore
+	repoLanguageStatsStore store.RepoLanguageStatsStore
+}
+
+func NewService(
+	ctx context.Context,
+	config Config,
+	gitReaderFactory *events.ReaderFactory[*gitevents.Reader],
+	git git.Interface,
+	repoStore store.RepoStore,
+	repoLanguageStatsStore store.RepoLanguageStatsStore,
+) (*Service, error) {
+	if err := config.Prepare(); err != nil {
+		return nil, fmt.Errorf("provided language stats service config is invalid: %w", err)
+	}
+	service := &Service{
+		config:                 config,
+		git:                    git,
+		repoStore:              repoStore,
+		repoLanguageStatsStore: repoLanguageStatsStore,
+	}
+
+	_, err := gitReaderFactory.Launch(ctx, eventsReaderGroupName, config.EventReaderName,
+		func(r *gitevents.Reader) error {
+			const idleTimeout = 1 * time.Minute
+			r.Configure(
+				stream.WithConcurrency(config.Concurrency),
+				stream.WithHandlerOptions(
+					stream.WithIdleTimeout(idleTimeout),
+					stream.WithMaxRetries(config.MaxRetries),
+				))
+
+			// register events
+			_ = r.RegisterBranchCreated(service.handleEventBranchCreated)
+			_ = r.RegisterBranchUpdated(service.handleEventBranchUpdated)
+
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to launch git event reader for language stats: %w", err)
+	}
+
+	return service, nil
+}
+
+// Get returns the cached language statistics of the default branch of the repository.
+// The statistics are never calculated synchronously, they are updated whenever the default branch changes.
+// Until then, the statistics might be outdated (see CommitSHA) or empty.
+func (s *Service) Get(ctx context.Context, repo *types.Repository) (*types.RepoLanguageStats, error) {
+	stats, err := s.repoLanguageStatsStore.Find(ctx, repo.ID)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return &types.RepoLanguageStats{
+			RepoID:    repo.ID,
+			Languages: []types.LanguageStat{},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find cached language stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// update recalculates the language statistics of the repository,
+// unless they are calculated for the current head of the default branch already.
+func (s *Service) update(ctx context.Context, repo *types.Repository) error {
+	sha, err := s.getDefaultBranchSHA(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	// an empty repository doesn't have any code
+	if sha == "" {
+		return nil
+	}
+
+	stats, err := s.repoLanguageStatsStore.Find(ctx, repo.ID)
+	if err != nil && !errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return fmt.Errorf("failed to find cached language stats: %w", err)
+	}
+	if err == nil && stats.CommitSHA == sha {
+		return nil
+	}
+
+	return s.calculate(ctx, repo, sha)
+}
+
+func (s *Service) getDefaultBranchSHA(ctx context.Context, repo *types.Repository) (string, error) {
+	ref, err := s.git.GetRef(ctx, git.GetRefParams{
+		ReadParams: git.CreateReadParams(repo),
+		Name:       repo.DefaultBranch,
+		Type:       gitenum.RefTypeBranch,
+	})
+	if gitnesserrors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get default branch of repo %d: %w", repo.ID, err)
+	}
+
+	return ref.SHA, nil
+}
+
+func (s *Service) calculate(
+	ctx context.Context,
+	repo *types.Repository,
+	sha string,
+) error {
+	out, err := s.git.GetLanguageStats(ctx, &git.GetLanguageStatsParams{
+		ReadParams: git.CreateReadParams(repo),
+		SHA:        sha,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to calculate language stats of repo %d: %w", repo.ID, err)
+	}
+
+	stats := &types.RepoLanguageStats{
+		RepoID:    repo.ID,
+		CommitSHA: sha,
+		Languages: mapLanguageStats(out.Languages),
+		Updated:   time.Now().UnixMilli(),
+	}
+
+	if err = s.repoLanguageStatsStore.Upsert(ctx, stats); err != nil {
+		return fmt.Errorf("failed to store language stats of repo %d: %w", repo.ID, err)
+	}
+
+	language := ""
+	if len(stats.Languages) > 0 {
+		language = stats.Languages[0].Language
+	}
+
+	if language != repo.Language {
+		if err = s.repoStore.UpdateLanguage(ctx, repo.ID, language); err != nil {
+			return fmt.Errorf("failed to update primary language of repo %d: %w", repo.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// mapLanguageStats converts the language sizes into statistics ordered by size, the largest first.
+func mapLanguageStats(sizes map[string]int64) []types.LanguageStat {
+	var total int64
+	for _, size := range sizes {
+		total += size
+	}
+
+	languages := make([]types.LanguageStat, 0, len(sizes))
+	for language, size := range sizes {
+		languages = append(languages, types.LanguageStat{
+			Language:   language,
+			Bytes:      size,
+			Percentage: math.Round(float64(size)*10000/float64(total)) / 100,
+		})
+	}
+
+	sort.Slice(languages, func(i, j int) bool {
+		if languages[i].Bytes != languages[j].Bytes {
+			return languages[i].Bytes > languages[j].Bytes
+		}
+		return languages[i].Language < languages[j].Language
+	})
+
+	return languages
+}
@@ -0,0 +1,210 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package languagestats
+
+import (
+	"context"
+	"testing"
+
+	gitevents "github.com/harness/gitness/app/events/git"
+	"github.com/harness/gitness/app/store"
+	gitnesserrors "github.com/harness/gitness/errors"
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/git"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+type gitMock struct {
+	git.Interface
+	branches  map[string]string
+	languages map[string]int64
+	scans     int
+}
+
+func (g *gitMock) GetRef(_ context.Context, params git.GetRefParams) (git.GetRefResponse, error) {
+	sha, ok := g.branches[params.Name]
+	if !ok {
+		return git.GetRefResponse{}, gitnesserrors.NotFound("branch not found")
+	}
+	return git.GetRefResponse{SHA: sha}, nil
+}
+
+func (g *gitMock) GetLanguageStats(context.Context, *git.GetLanguageStatsParams) (*git.GetLanguageStatsOutput, error) {
+	g.scans++
+	return &git.GetLanguageStatsOutput{Languages: g.languages}, nil
+}
+
+type repoStoreMock struct {
+	store.RepoStore
+	repo *types.Repository
+}
+
+func (s *repoStoreMock) Find(context.Context, int64) (*types.Repository, error) {
+	repo := *s.repo
+	return &repo, nil
+}
+
+func (s *repoStoreMock) UpdateLanguage(_ context.Context, _ int64, language string) error {
+	s.repo.Language = language
+	return nil
+}
+
+type statsStoreMock struct {
+	store.RepoLanguageStatsStore
+	stats *types.RepoLanguageStats
+}
+
+func (s *statsStoreMock) Find(context.Context, int64) (*types.RepoLanguageStats, error) {
+	if s.stats == nil {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	return s.stats, nil
+}
+
+func (s *statsStoreMock) Upsert(_ context.Context, stats *types.RepoLanguageStats) error {
+	s.stats = stats
+	return nil
+}
+
+func branchUpdated(ref string) *events.Event[*gitevents.BranchUpdatedPayload] {
+	return &events.Event[*gitevents.BranchUpdatedPayload]{
+		Payload: &gitevents.BranchUpdatedPayload{RepoID: 1, Ref: ref},
+	}
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	repo := &types.Repository{ID: 1, DefaultBranch: "main"}
+	gitMock := &gitMock{
+		branches:  map[string]string{},
+		languages: map[string]int64{"Go": 300, "Shell": 100},
+	}
+	repoStore := &repoStoreMock{repo: repo}
+	statsStore := &statsStoreMock{}
+	s := &Service{
+		git:                    gitMock,
+		repoStore:              repoStore,
+		repoLanguageStatsStore: statsStore,
+	}
+
+	// a cache miss returns empty stats without scanning the repository.
+	stats, err := s.Get(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, &types.RepoLanguageStats{RepoID: 1, Languages: []types.LanguageStat{}}, stats)
+
+	// empty repositories aren't scanned.
+	require.NoError(t, s.handleEventBranchUpdated(ctx, branchUpdated("refs/heads/main")))
+	require.Equal(t, 0, gitMock.scans)
+	require.Nil(t, statsStore.stats)
+
+	// only updates of the default branch are scanned.
+	gitMock.branches["main"] = "sha1"
+	gitMock.branches["feature"] = "sha2"
+	require.NoError(t, s.handleEventBranchUpdated(ctx, branchUpdated("refs/heads/feature")))
+	require.Equal(t, 0, gitMock.scans)
+
+	require.NoError(t, s.handleEventBranchUpdated(ctx, branchUpdated("refs/heads/main")))
+	require.Equal(t, 1, gitMock.scans)
+	require.Equal(t, "Go", repo.Language)
+
+	stats, err = s.Get(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, "sha1", stats.CommitSHA)
+	require.Equal(t, []types.LanguageStat{
+		{Language: "Go", Bytes: 300, Percentage: 75},
+		{Language: "Shell", Bytes: 100, Percentage: 25},
+	}, stats.Languages)
+
+	// stats of the current head of the default branch aren't recalculated.
+	require.NoError(t, s.handleEventBranchUpdated(ctx, branchUpdated("refs/heads/main")))
+	require.Equal(t, 1, gitMock.scans)
+
+	// stale stats are returned until the default branch update is handled.
+	gitMock.branches["main"] = "sha3"
+	gitMock.languages = map[string]int64{"Go": 100, "Shell": 200}
+
+	stats, err = s.Get(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, "sha1", stats.CommitSHA)
+	require.Equal(t, 1, gitMock.scans)
+
+	require.NoError(t, s.handleEventBranchUpdated(ctx, branchUpdated("refs/heads/main")))
+	require.Equal(t, 2, gitMock.scans)
+	require.Equal(t, "Shell", repo.Language)
+
+	stats, err = s.Get(ctx, repo)
+	require.NoError(t, err)
+	require.Equal(t, "sha3", stats.CommitSHA)
+	require.Equal(t, "Shell", stats.Languages[0].Language)
+
+	// the primary language is reset once the repository doesn't contain any code.
+	gitMock.branches["main"] = "sha4"
+	gitMock.languages = map[string]int64{}
+
+	require.NoError(t, s.handleEventBranchUpdated(ctx, branchUpdated("refs/heads/main")))
+	require.Equal(t, "", repo.Language)
+
+	// events of other references are discarded.
+	err = s.handleEventBranchUpdated(ctx, branchUpdated("refs/tags/v1"))
+	require.ErrorContains(t, err, "discarding requested")
+}
+
+func TestMapLanguageStats(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    map[string]int64
+		expected []types.LanguageStat
+	}{
+		{
+			name:     "empty",
+			sizes:    map[string]int64{},
+			expected: []types.LanguageStat{},
+		},
+		{
+			name:  "single language",
+			sizes: map[string]int64{"Go": 42},
+			expected: []types.LanguageStat{
+				{Language: "Go", Bytes: 42, Percentage: 100},
+			},
+		},
+		{
+			name:  "ordered by size and name",
+			sizes: map[string]int64{"Shell": 1, "Go": 1, "Java": 1, "Python": 3},
+			expected: []types.LanguageStat{
+				{Language: "Python", Bytes: 3, Percentage: 50},
+				{Language: "Go", Bytes: 1, Percentage: 16.67},
+				{Language: "Java", Bytes: 1, Percentage: 16.67},
+				{Language: "Shell", Bytes: 1, Percentage: 16.67},
+			},
+		},
+		{
+			name:  "percentages are rounded to two decimals",
+			sizes: map[string]int64{"Go": 99999, "C": 1},
+			expected: []types.LanguageStat{
+				{Language: "Go", Bytes: 99999, Percentage: 100},
+				{Language: "C", Bytes: 1, Percentage: 0},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.expected, mapLanguageStats(test.sizes))
+		})
+	}
+}
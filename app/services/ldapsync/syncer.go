@@ -0,0 +1,262 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldapsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/job"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/rs/zerolog/log"
+)
+
+const jobType = "ldap-sync"
+
+// Syncer periodically syncs the users of the LDAP directory:
+// group memberships are kept in sync with the configured space memberships
+// and users that were removed from the directory are deactivated.
+// NOTE: Only memberships added by the ldap group mappings are updated or removed,
+// memberships that were added manually or by other identity providers are never touched.
+type Syncer struct {
+	enabled            bool
+	cron               string
+	maxDur             time.Duration
+	deactivateUsers    bool
+	systemPrincipalUID string
+	directory          ldap.Directory
+	memberships        []auth.GroupMembership
+	principalStore     store.PrincipalStore
+	identityStore      store.PrincipalIdentityStore
+	membershipStore    store.MembershipStore
+	spaceStore         store.SpaceStore
+	scheduler          *job.Scheduler
+}
+
+func (s *Syncer) Register(ctx context.Context) error {
+	if !s.enabled {
+		return nil
+	}
+
+	err := s.scheduler.AddRecurring(ctx, jobType, jobType, s.cron, s.maxDur)
+	if err != nil {
+		return fmt.Errorf("failed to register recurring job for ldap syncer: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Syncer) Handle(ctx context.Context, _ string, _ job.ProgressReporter) (string, error) {
+	if !s.enabled {
+		return "", nil
+	}
+
+	entries, err := s.directory.ListUsers(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list users of ldap directory: %w", err)
+	}
+
+	identities, err := s.identityStore.List(ctx, ldap.IdentityProvider)
+	if err != nil {
+		return "", fmt.Errorf("failed to list ldap identities: %w", err)
+	}
+
+	systemPrincipal, err := s.principalStore.FindByUID(ctx, s.systemPrincipalUID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find system principal: %w", err)
+	}
+
+	entriesByDN := make(map[string]*ldap.Entry, len(entries))
+	for _, entry := range entries {
+		entriesByDN[ldap.NormalizeDN(entry.DN)] = entry
+	}
+
+	// protect against deactivating all users in case of a misconfigured or empty directory.
+	deactivateUsers := s.deactivateUsers
+	if len(entries) == 0 && len(identities) > 0 {
+		log.Ctx(ctx).Warn().Msg("ldap directory returned no users, skipping deactivation of users")
+		deactivateUsers = false
+	}
+
+	spaceIDs := s.resolveSpaces(ctx)
+
+	log.Ctx(ctx).Info().Msgf("start ldap sync of %d users", len(identities))
+
+	for _, identity := range identities {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		user, err := s.principalStore.FindUser(ctx, identity.PrincipalID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).
+				Int64("principal_id", identity.PrincipalID).
+				Msg("failed to find user of ldap identity")
+			continue
+		}
+
+		log := log.Ctx(ctx).With().Str("user_uid", user.UID).Logger()
+
+		entry, ok := entriesByDN[identity.Subject]
+		if !ok {
+			if deactivateUsers && !user.Blocked {
+				if err = s.deactivateUser(ctx, user); err != nil {
+					log.Error().Err(err).Msg("failed to deactivate user removed from ldap directory")
+					continue
+				}
+
+				log.Info().Msg("deactivated user removed from ldap directory")
+			}
+
+			continue
+		}
+
+		if err = s.syncMemberships(ctx, user, entry.Groups, spaceIDs, systemPrincipal.ID); err != nil {
+			log.Error().Err(err).Msg("failed to sync memberships of user")
+		}
+	}
+
+	return "", nil
+}
+
+// resolveSpaces returns the IDs of the spaces of the group memberships.
+func (s *Syncer) resolveSpaces(ctx context.Context) map[string]int64 {
+	spaceIDs := make(map[string]int64, len(s.memberships))
+	for _, membership := range s.memberships {
+		if _, ok := spaceIDs[membership.SpaceRef]; ok {
+			continue
+		}
+
+		space, err := s.spaceStore.FindByRef(ctx, membership.SpaceRef)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).
+				Str("space_ref", membership.SpaceRef).
+				Msg("failed to find space of ldap group membership")
+			continue
+		}
+
+		spaceIDs[membership.SpaceRef] = space.ID
+	}
+
+	return spaceIDs
+}
+
+func (s *Syncer) deactivateUser(ctx context.Context, user *types.User) error {
+	user.Blocked = true
+	user.Updated = time.Now().UnixMilli()
+
+	return s.principalStore.UpdateUser(ctx, user)
+}
+
+// syncMemberships syncs the memberships of the user with its groups.
+// In case multiple groups map to the same space the first configured mapping wins.
+func (s *Syncer) syncMemberships(
+	ctx context.Context,
+	user *types.User,
+	groups []string,
+	spaceIDs map[string]int64,
+	systemPrincipalID int64,
+) error {
+	userGroups := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		userGroups[group] = struct{}{}
+	}
+
+	// managed contains all spaces of the group memberships, desired the roles the user should have.
+	managed := make([]int64, 0, len(spaceIDs))
+	managedSet := make(map[int64]struct{}, len(spaceIDs))
+	desired := make(map[int64]enum.MembershipRole)
+	for _, membership := range s.memberships {
+		spaceID, ok := spaceIDs[membership.SpaceRef]
+		if !ok {
+			continue
+		}
+
+		if _, ok = managedSet[spaceID]; !ok {
+			managedSet[spaceID] = struct{}{}
+			managed = append(managed, spaceID)
+		}
+
+		if _, ok = userGroups[membership.Group]; !ok {
+			continue
+		}
+
+		if _, ok = desired[spaceID]; !ok {
+			desired[spaceID] = membership.Role
+		}
+	}
+
+	now := time.Now().UnixMilli()
+
+	for _, spaceID := range managed {
+		role, isDesired := desired[spaceID]
+		key := types.MembershipKey{
+			SpaceID:     spaceID,
+			PrincipalID: user.ID,
+		}
+
+		existing, err := s.membershipStore.Find(ctx, key)
+		if errors.Is(err, gitness_store.ErrResourceNotFound) {
+			if !isDesired {
+				continue
+			}
+
+			err = s.membershipStore.Create(ctx, &types.Membership{
+				MembershipKey: key,
+				CreatedBy:     systemPrincipalID,
+				Created:       now,
+				Updated:       now,
+				Role:          role,
+				Source:        ldap.IdentityProvider,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create membership: %w", err)
+			}
+
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find membership: %w", err)
+		}
+
+		// memberships added manually or by other identity providers aren't managed by the ldap group mappings.
+		if existing.Source != ldap.IdentityProvider {
+			continue
+		}
+
+		switch {
+		case !isDesired:
+			if err = s.membershipStore.Delete(ctx, key); err != nil {
+				return fmt.Errorf("failed to delete membership: %w", err)
+			}
+		case existing.Role != role:
+			existing.Role = role
+			existing.Updated = now
+			if err = s.membershipStore.Update(ctx, existing); err != nil {
+				return fmt.Errorf("failed to update membership: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldapsync
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/app/store/database"
+	"github.com/harness/gitness/app/store/database/migrate"
+	gitness_store "github.com/harness/gitness/store"
+	gitness_database "github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// directory is an in-memory stand-in for an LDAP directory.
+type directory struct {
+	entries []*ldap.Entry
+}
+
+func (d *directory) Authenticate(context.Context, string, string) (*ldap.Entry, error) {
+	return nil, ldap.ErrNotFound
+}
+
+func (d *directory) ListUsers(context.Context) ([]*ldap.Entry, error) {
+	return d.entries, nil
+}
+
+func TestSyncer_Handle(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := gitness_database.ConnectAndMigrate(ctx, "sqlite3",
+		filepath.Join(t.TempDir(), "database.sqlite3"), migrate.Migrate)
+	require.NoError(t, err)
+
+	principalStore := database.NewPrincipalStore(db, store.ToLowerPrincipalUIDTransformation)
+	identityStore := database.NewPrincipalIdentityStore(db)
+	spacePathStore := database.NewSpacePathStore(db, store.ToLowerSpacePathTransformation)
+	membershipStore := database.NewMembershipStore(db, nil, spacePathStore)
+	spaceStore := database.NewSpaceStore(db, nil, spacePathStore)
+
+	now := time.Now().UnixMilli()
+
+	system := &types.Service{UID: "gitness", Email: "system@gitness.io", DisplayName: "Gitness", Salt: "salt"}
+	require.NoError(t, principalStore.CreateService(ctx, system))
+
+	space := &types.Space{UID: "space", CreatedBy: system.ID, Created: now, Updated: now}
+	require.NoError(t, spaceStore.Create(ctx, space))
+	require.NoError(t, spacePathStore.InsertSegment(ctx, &types.SpacePathSegment{
+		UID:       space.UID,
+		IsPrimary: true,
+		SpaceID:   space.ID,
+		CreatedBy: system.ID,
+		Created:   now,
+		Updated:   now,
+	}))
+	spaceRef := strconv.FormatInt(space.ID, 10)
+
+	users := map[string]*types.User{}
+	for _, uid := range []string{"alice", "bob", "carol", "dave", "erin", "frank"} {
+		user := &types.User{UID: uid, Email: uid + "@example.com", DisplayName: uid, Salt: "salt"}
+		require.NoError(t, principalStore.CreateUser(ctx, user))
+		require.NoError(t, identityStore.Create(ctx, &types.PrincipalIdentity{
+			Provider:    ldap.IdentityProvider,
+			Subject:     "uid=" + uid + ",ou=people,dc=example,dc=com",
+			PrincipalID: user.ID,
+		}))
+		users[uid] = user
+	}
+
+	addMembership := func(user string, createdBy int64, source string, role enum.MembershipRole) {
+		require.NoError(t, membershipStore.Create(ctx, &types.Membership{
+			MembershipKey: types.MembershipKey{SpaceID: space.ID, PrincipalID: users[user].ID},
+			CreatedBy:     createdBy,
+			Role:          role,
+			Source:        source,
+		}))
+	}
+
+	// carol was added by the group mapping but isn't a member of the group anymore.
+	addMembership("carol", system.ID, ldap.IdentityProvider, enum.MembershipRoleReader)
+	// dave was added manually, the membership must not be touched.
+	addMembership("dave", users["alice"].ID, "", enum.MembershipRoleSpaceOwner)
+	// erin was added by the group mapping with a different role.
+	addMembership("erin", system.ID, ldap.IdentityProvider, enum.MembershipRoleReader)
+	// frank was added by the group mapping of another identity provider, the membership must not be touched.
+	addMembership("frank", system.ID, "oidc", enum.MembershipRoleReader)
+
+	syncer := &Syncer{
+		enabled:            true,
+		deactivateUsers:    true,
+		systemPrincipalUID: system.UID,
+		directory: &directory{entries: []*ldap.Entry{
+			{DN: "uid=alice,ou=people,dc=example,dc=com", Groups: []string{"admins", "developers"}},
+			{DN: "UID=Carol,OU=People,DC=example,DC=com"},
+			{DN: "uid=dave,ou=people,dc=example,dc=com", Groups: []string{"developers"}},
+			{DN: "uid=erin,ou=people,dc=example,dc=com", Groups: []string{"developers"}},
+			{DN: "uid=frank,ou=people,dc=example,dc=com"},
+		}},
+		memberships: []auth.GroupMembership{
+			{Group: "admins", SpaceRef: spaceRef, Role: enum.MembershipRoleSpaceOwner},
+			{Group: "developers", SpaceRef: spaceRef, Role: enum.MembershipRoleContributor},
+		},
+		principalStore:  principalStore,
+		identityStore:   identityStore,
+		membershipStore: membershipStore,
+		spaceStore:      spaceStore,
+	}
+
+	_, err = syncer.Handle(ctx, "", nil)
+	require.NoError(t, err)
+
+	role := func(user string) enum.MembershipRole {
+		membership, err := membershipStore.Find(ctx,
+			types.MembershipKey{SpaceID: space.ID, PrincipalID: users[user].ID})
+		if err != nil {
+			require.ErrorIs(t, err, gitness_store.ErrResourceNotFound)
+			return ""
+		}
+		return membership.Role
+	}
+
+	assert.Equal(t, enum.MembershipRoleSpaceOwner, role("alice"), "first matching mapping wins")
+	assert.Equal(t, enum.MembershipRole(""), role("bob"), "removed users don't get memberships")
+	assert.Equal(t, enum.MembershipRole(""), role("carol"), "membership of removed group is deleted")
+	assert.Equal(t, enum.MembershipRoleSpaceOwner, role("dave"), "manual membership is unchanged")
+	assert.Equal(t, enum.MembershipRoleContributor, role("erin"), "membership role is updated")
+	assert.Equal(t, enum.MembershipRoleReader, role("frank"), "membership of other provider is unchanged")
+
+	for uid, user := range users {
+		user, err = principalStore.FindUser(ctx, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, uid == "bob", user.Blocked, "user %s", uid)
+	}
+}
+
+func TestSyncer_Handle_EmptyDirectory(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := gitness_database.ConnectAndMigrate(ctx, "sqlite3",
+		filepath.Join(t.TempDir(), "database.sqlite3"), migrate.Migrate)
+	require.NoError(t, err)
+
+	principalStore := database.NewPrincipalStore(db, store.ToLowerPrincipalUIDTransformation)
+	identityStore := database.NewPrincipalIdentityStore(db)
+
+	system := &types.Service{UID: "gitness", Email: "system@gitness.io", DisplayName: "Gitness", Salt: "salt"}
+	require.NoError(t, principalStore.CreateService(ctx, system))
+
+	user := &types.User{UID: "alice", Email: "alice@example.com", DisplayName: "alice", Salt: "salt"}
+	require.NoError(t, principalStore.CreateUser(ctx, user))
+	require.NoError(t, identityStore.Create(ctx, &types.PrincipalIdentity{
+		Provider:    ldap.IdentityProvider,
+		Subject:     "uid=alice,ou=people,dc=example,dc=com",
+		PrincipalID: user.ID,
+	}))
+
+	syncer := &Syncer{
+		enabled:            true,
+		deactivateUsers:    true,
+		systemPrincipalUID: system.UID,
+		directory:          &directory{},
+		principalStore:     principalStore,
+		identityStore:      identityStore,
+	}
+
+	_, err = syncer.Handle(ctx, "", nil)
+	require.NoError(t, err)
+
+	user, err = principalStore.FindUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.False(t, user.Blocked, "users aren't deactivated if the directory is empty")
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldapsync
+
+import (
+	"fmt"
+
+	"github.com/harness/gitness/app/auth"
+	"github.com/harness/gitness/app/auth/ldap"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/types"
+
+	"github.com/google/wire"
+)
+
+var WireSet = wire.NewSet(
+	ProvideSyncer,
+)
+
+func ProvideSyncer(
+	config *types.Config,
+	directory ldap.Directory,
+	principalStore store.PrincipalStore,
+	identityStore store.PrincipalIdentityStore,
+	membershipStore store.MembershipStore,
+	spaceStore store.SpaceStore,
+	scheduler *job.Scheduler,
+	executor *job.Executor,
+) (*Syncer, error) {
+	memberships, err := auth.ParseGroupMemberships(config.LDAP.GroupMemberships)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ldap group memberships: %w", err)
+	}
+
+	job := &Syncer{
+		enabled:            config.LDAP.Sync.Enabled && directory != nil,
+		cron:               config.LDAP.Sync.CRON,
+		maxDur:             config.LDAP.Sync.MaxDuration,
+		deactivateUsers:    config.LDAP.Sync.DeactivateUsers,
+		systemPrincipalUID: config.Principal.System.UID,
+		directory:          directory,
+		memberships:        memberships,
+		principalStore:     principalStore,
+		identityStore:      identityStore,
+		membershipStore:    membershipStore,
+		spaceStore:         spaceStore,
+		scheduler:          scheduler,
+	}
+
+	err = executor.Register(jobType, job)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
@@ -36,4 +36,5 @@ type Client interface {
 		recipients []*types.PrincipalInfo,
 		payload *PullReqStateChangedPayload,
 	) error
+	SendUserMentioned(ctx context.Context, recipients []*types.PrincipalInfo, payload *UserMentionedPayload) error
 }
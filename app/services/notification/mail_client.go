@@ -30,6 +30,7 @@ const (
 	TemplatePullReqBranchUpdated = "pullreq_branch_updated.html"
 	TemplateNameReviewSubmitted  = "review_submitted.html"
 	TemplatePullReqStateChanged  = "pullreq_state_changed.html"
+	TemplateUserMentioned        = "user_mentioned.html"
 )
 
 type MailClient struct {
@@ -131,6 +132,23 @@ func (m MailClient) SendPullReqStateChanged(
 	return m.Mailer.Send(ctx, *email)
 }
 
+func (m MailClient) SendUserMentioned(
+	ctx context.Context,
+	recipients []*types.PrincipalInfo,
+	payload *UserMentionedPayload,
+) error {
+	email, err := GenerateEmailFromPayload(TemplateUserMentioned, recipients, payload.Base, payload)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to generate mail requests after processing %s event: %w",
+			pullreqevents.UserMentionedEvent,
+			err,
+		)
+	}
+
+	return m.Mailer.Send(ctx, *email)
+}
+
 func GetSubjectPullRequest(
 	repoUID string,
 	prNum int64,
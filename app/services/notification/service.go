@@ -22,6 +22,7 @@ import (
 	"io/fs"
 	"path"
 
+	"github.com/harness/gitness/app/auth/authz"
 	pullreqevents "github.com/harness/gitness/app/events/pullreq"
 	"github.com/harness/gitness/app/store"
 	"github.com/harness/gitness/app/url"
@@ -90,12 +91,14 @@ type Service struct {
 	prReaderFactory       *events.ReaderFactory[*pullreqevents.Reader]
 	pullReqStore          store.PullReqStore
 	repoStore             store.RepoStore
+	principalStore        store.PrincipalStore
 	principalInfoView     store.PrincipalInfoView
 	principalInfoCache    store.PrincipalInfoCache
 	pullReqReviewersStore store.PullReqReviewerStore
 	pullReqActivityStore  store.PullReqActivityStore
 	spacePathStore        store.SpacePathStore
 	urlProvider           url.Provider
+	authorizer            authz.Authorizer
 }
 
 func NewService(
@@ -105,12 +108,14 @@ func NewService(
 	prReaderFactory *events.ReaderFactory[*pullreqevents.Reader],
 	pullReqStore store.PullReqStore,
 	repoStore store.RepoStore,
+	principalStore store.PrincipalStore,
 	principalInfoView store.PrincipalInfoView,
 	principalInfoCache store.PrincipalInfoCache,
 	pullReqReviewersStore store.PullReqReviewerStore,
 	pullReqActivityStore store.PullReqActivityStore,
 	spacePathStore store.SpacePathStore,
 	urlProvider url.Provider,
+	authorizer authz.Authorizer,
 ) (*Service, error) {
 	service := &Service{
 		config:                config,
@@ -118,12 +123,14 @@ func NewService(
 		prReaderFactory:       prReaderFactory,
 		pullReqStore:          pullReqStore,
 		repoStore:             repoStore,
+		principalStore:        principalStore,
 		principalInfoView:     principalInfoView,
 		principalInfoCache:    principalInfoCache,
 		pullReqReviewersStore: pullReqReviewersStore,
 		pullReqActivityStore:  pullReqActivityStore,
 		spacePathStore:        spacePathStore,
 		urlProvider:           urlProvider,
+		authorizer:            authorizer,
 	}
 
 	_, err := service.prReaderFactory.Launch(
@@ -142,6 +149,7 @@ func NewService(
 			_ = r.RegisterCommentCreated(service.notifyCommentCreated)
 			_ = r.RegisterBranchUpdated(service.notifyPullReqBranchUpdated)
 			_ = r.RegisterReviewSubmitted(service.notifyReviewSubmitted)
+			_ = r.RegisterUserMentioned(service.notifyUserMentioned)
 
 			// state changes
 			_ = r.RegisterMerged(service.notifyPullReqStateMerged)
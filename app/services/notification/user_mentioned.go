@@ -0,0 +1,121 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apiauth "github.com/harness/gitness/app/api/auth"
+	"github.com/harness/gitness/app/auth"
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/events"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+)
+
+type UserMentionedPayload struct {
+	Base      *BasePullReqPayload
+	Mentioner *types.PrincipalInfo
+	Text      string
+	InComment bool
+}
+
+func (s *Service) notifyUserMentioned(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.UserMentionedPayload],
+) error {
+	payload, recipients, err := s.processUserMentionedEvent(ctx, event)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to process %s event for pullReqID %d: %w",
+			pullreqevents.UserMentionedEvent,
+			event.Payload.PullReqID,
+			err,
+		)
+	}
+
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	err = s.notificationClient.SendUserMentioned(ctx, recipients, payload)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to send email for event %s for pullReqID %d: %w",
+			pullreqevents.UserMentionedEvent,
+			event.Payload.PullReqID,
+			err,
+		)
+	}
+	return nil
+}
+
+func (s *Service) processUserMentionedEvent(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.UserMentionedPayload],
+) (*UserMentionedPayload, []*types.PrincipalInfo, error) {
+	base, err := s.getBasePayload(ctx, event.Payload.Base)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get base payload: %w", err)
+	}
+
+	mentioner, err := s.principalInfoCache.Get(ctx, event.Payload.PrincipalID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch mentioner from principalInfoCache: %w", err)
+	}
+
+	text := base.PullReq.Description
+	if event.Payload.ActivityID != 0 {
+		activity, err := s.pullReqActivityStore.Find(ctx, event.Payload.ActivityID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch activity from pullReqActivityStore: %w", err)
+		}
+
+		text = activity.Text
+	}
+
+	recipients := make([]*types.PrincipalInfo, 0, len(event.Payload.MentionedIDs))
+	for _, id := range event.Payload.MentionedIDs {
+		recipient, err := s.principalStore.Find(ctx, id)
+		if errors.Is(err, gitness_store.ErrResourceNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to fetch mentioned principal from principalStore: %w", err)
+		}
+
+		// the access could have been revoked since the principal was mentioned.
+		session := &auth.Session{Principal: *recipient}
+		err = apiauth.CheckRepo(ctx, s.authorizer, session, base.Repo, enum.PermissionRepoView, true)
+		if errors.Is(err, apiauth.ErrNotAuthorized) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to check repo access of mentioned principal: %w", err)
+		}
+
+		recipients = append(recipients, recipient.ToPrincipalInfo())
+	}
+
+	return &UserMentionedPayload{
+		Base:      base,
+		Mentioner: mentioner,
+		Text:      text,
+		InComment: event.Payload.ActivityID != 0,
+	}, recipients, nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/app/auth"
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/events"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+type repoStoreMock struct {
+	store.RepoStore
+	repo *types.Repository
+}
+
+func (s repoStoreMock) Find(context.Context, int64) (*types.Repository, error) {
+	return s.repo, nil
+}
+
+type pullReqStoreMock struct {
+	store.PullReqStore
+	pr *types.PullReq
+}
+
+func (s pullReqStoreMock) Find(context.Context, int64) (*types.PullReq, error) {
+	return s.pr, nil
+}
+
+type principalStoreMock struct {
+	store.PrincipalStore
+	principals map[int64]*types.Principal
+}
+
+func (s principalStoreMock) Find(_ context.Context, id int64) (*types.Principal, error) {
+	p, ok := s.principals[id]
+	if !ok {
+		return nil, gitness_store.ErrResourceNotFound
+	}
+	return p, nil
+}
+
+type principalInfoCacheMock struct {
+	store.PrincipalInfoCache
+	principals map[int64]*types.Principal
+}
+
+func (c principalInfoCacheMock) Get(_ context.Context, id int64) (*types.PrincipalInfo, error) {
+	return c.principals[id].ToPrincipalInfo(), nil
+}
+
+type urlProviderMock struct {
+	url.Provider
+}
+
+func (urlProviderMock) GenerateUIPRURL(repoPath string, _ int64) string {
+	return "http://localhost/" + repoPath
+}
+
+// authorizerMock grants access only to the listed principals.
+type authorizerMock struct {
+	allowed map[int64]bool
+}
+
+func (a authorizerMock) Check(_ context.Context, session *auth.Session, _ *types.Scope, _ *types.Resource,
+	_ enum.Permission) (bool, error) {
+	return a.allowed[session.Principal.ID], nil
+}
+
+func (a authorizerMock) CheckAll(context.Context, *auth.Session, ...types.PermissionCheck) (bool, error) {
+	return false, nil
+}
+
+func TestService_ProcessUserMentionedEvent(t *testing.T) {
+	principals := map[int64]*types.Principal{
+		1: {ID: 1, UID: "author", Type: enum.PrincipalTypeUser},
+		2: {ID: 2, UID: "member", Type: enum.PrincipalTypeUser},
+		3: {ID: 3, UID: "outsider", Type: enum.PrincipalTypeUser},
+	}
+
+	s := &Service{
+		repoStore:          repoStoreMock{repo: &types.Repository{ID: 1, Path: "space/repo"}},
+		pullReqStore:       pullReqStoreMock{pr: &types.PullReq{ID: 1, Number: 1, CreatedBy: 1, Description: "hi"}},
+		principalStore:     principalStoreMock{principals: principals},
+		principalInfoCache: principalInfoCacheMock{principals: principals},
+		urlProvider:        urlProviderMock{},
+		authorizer:         authorizerMock{allowed: map[int64]bool{1: true, 2: true}},
+	}
+
+	event := &events.Event[*pullreqevents.UserMentionedPayload]{
+		Payload: &pullreqevents.UserMentionedPayload{
+			Base: pullreqevents.Base{
+				PullReqID:    1,
+				TargetRepoID: 1,
+				PrincipalID:  1,
+			},
+			MentionedIDs: []int64{2, 3, 4},
+		},
+	}
+
+	payload, recipients, err := s.processUserMentionedEvent(context.Background(), event)
+	require.NoError(t, err)
+	require.Equal(t, "hi", payload.Text)
+	require.False(t, payload.InComment)
+	require.Len(t, recipients, 1)
+	require.Equal(t, int64(2), recipients[0].ID)
+}
@@ -17,6 +17,7 @@ package notification
 import (
 	"context"
 
+	"github.com/harness/gitness/app/auth/authz"
 	pullreqevents "github.com/harness/gitness/app/events/pullreq"
 	"github.com/harness/gitness/app/services/notification/mailer"
 	"github.com/harness/gitness/app/store"
@@ -38,12 +39,14 @@ func ProvideNotificationService(
 	prReaderFactory *events.ReaderFactory[*pullreqevents.Reader],
 	pullReqStore store.PullReqStore,
 	repoStore store.RepoStore,
+	principalStore store.PrincipalStore,
 	principalInfoView store.PrincipalInfoView,
 	principalInfoCache store.PrincipalInfoCache,
 	pullReqReviewersStore store.PullReqReviewerStore,
 	pullReqActivityStore store.PullReqActivityStore,
 	spacePathStore store.SpacePathStore,
 	urlProvider url.Provider,
+	authorizer authz.Authorizer,
 ) (*Service, error) {
 	return NewService(
 		ctx,
@@ -52,12 +55,14 @@ func ProvideNotificationService(
 		prReaderFactory,
 		pullReqStore,
 		repoStore,
+		principalStore,
 		principalInfoView,
 		principalInfoCache,
 		pullReqReviewersStore,
 		pullReqActivityStore,
 		spacePathStore,
 		urlProvider,
+		authorizer,
 	)
 }
 
@@ -16,6 +16,7 @@ package protection
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/harness/gitness/types"
 )
@@ -33,6 +34,14 @@ type (
 		RefAction   RefAction
 		RefType     RefType
 		RefNames    []string
+
+		// Internal marks ref changes made by the server itself (merges, web commits, ...).
+		// Their lifecycle was verified by the operation already, so only the new commits are checked.
+		Internal bool
+
+		// UnverifiedCommits returns the SHAs of the new commits of the branch without a verified signature.
+		// It's optional and only called by rules requiring signed commits, as it's expensive to compute.
+		UnverifiedCommits func(ctx context.Context, branch string) ([]string, error)
 	}
 
 	RefType int
@@ -43,6 +52,8 @@ type (
 		CreateForbidden bool `json:"create_forbidden,omitempty"`
 		DeleteForbidden bool `json:"delete_forbidden,omitempty"`
 		UpdateForbidden bool `json:"update_forbidden,omitempty"`
+
+		RequireSignedCommits bool `json:"require_signed_commits,omitempty"`
 	}
 )
 
@@ -68,29 +79,48 @@ const (
 	codeLifecycleCreate = "lifecycle.create"
 	codeLifecycleDelete = "lifecycle.delete"
 	codeLifecycleUpdate = "lifecycle.update"
+
+	codeLifecycleSignedCommits = "lifecycle.signed_commits"
 )
 
-func (v *DefLifecycle) RefChangeVerify(_ context.Context, in RefChangeVerifyInput) ([]types.RuleViolations, error) {
+func (v *DefLifecycle) RefChangeVerify(ctx context.Context, in RefChangeVerifyInput) ([]types.RuleViolations, error) {
 	var violations types.RuleViolations
 
-	switch in.RefAction {
-	case RefActionCreate:
+	switch {
+	case in.Internal:
+		// the operation creating the commits verified the ref change already.
+	case in.RefAction == RefActionCreate:
 		if v.CreateForbidden {
 			violations.Addf(codeLifecycleCreate,
 				"Creation of branch %q is not allowed.", in.RefNames[0])
 		}
-	case RefActionDelete:
+	case in.RefAction == RefActionDelete:
 		if v.DeleteForbidden {
 			violations.Addf(codeLifecycleDelete,
 				"Delete of branch %q is not allowed.", in.RefNames[0])
 		}
-	case RefActionUpdate:
+	case in.RefAction == RefActionUpdate:
 		if v.UpdateForbidden {
 			violations.Addf(codeLifecycleUpdate,
 				"Push to branch %q is not allowed. Please use pull requests.", in.RefNames[0])
 		}
 	}
 
+	if v.RequireSignedCommits && in.UnverifiedCommits != nil && in.RefAction != RefActionDelete {
+		for _, branch := range in.RefNames {
+			shas, err := in.UnverifiedCommits(ctx, branch)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get unverified commits of branch %q: %w", branch, err)
+			}
+
+			if len(shas) > 0 {
+				violations.Addf(codeLifecycleSignedCommits,
+					"Branch %q requires signed commits, but %d commit(s) don't have a verified signature (e.g. %s).",
+					branch, len(shas), shas[0])
+			}
+		}
+	}
+
 	if len(violations.Violations) > 0 {
 		return []types.RuleViolations{violations}, nil
 	}
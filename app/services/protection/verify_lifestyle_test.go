@@ -25,12 +25,15 @@ import (
 // nolint:gocognit // it's a unit test
 func TestDefLifecycle_RefChangeVerify(t *testing.T) {
 	const refName = "a"
+	unverified := func(context.Context, string) ([]string, error) { return []string{"abc"}, nil }
 	tests := []struct {
-		name      string
-		def       DefLifecycle
-		action    RefAction
-		expCodes  []string
-		expParams [][]any
+		name       string
+		def        DefLifecycle
+		action     RefAction
+		internal   bool
+		unverified func(context.Context, string) ([]string, error)
+		expCodes   []string
+		expParams  [][]any
 	}{
 		{
 			name: "empty",
@@ -56,6 +59,41 @@ func TestDefLifecycle_RefChangeVerify(t *testing.T) {
 			expCodes:  []string{"lifecycle.update"},
 			expParams: [][]any{{refName}},
 		},
+		{
+			name:       "lifecycle.signed_commits-fail",
+			def:        DefLifecycle{RequireSignedCommits: true},
+			action:     RefActionUpdate,
+			unverified: unverified,
+			expCodes:   []string{"lifecycle.signed_commits"},
+			expParams:  [][]any{{refName, 1, "abc"}},
+		},
+		{
+			name:       "lifecycle.signed_commits-success",
+			def:        DefLifecycle{RequireSignedCommits: true},
+			action:     RefActionCreate,
+			unverified: func(context.Context, string) ([]string, error) { return nil, nil },
+		},
+		{
+			name:       "lifecycle.signed_commits-delete",
+			def:        DefLifecycle{RequireSignedCommits: true},
+			action:     RefActionDelete,
+			unverified: unverified,
+		},
+		{
+			name:     "lifecycle.update-internal",
+			def:      DefLifecycle{UpdateForbidden: true},
+			action:   RefActionUpdate,
+			internal: true,
+		},
+		{
+			name:       "lifecycle.signed_commits-internal-fail",
+			def:        DefLifecycle{UpdateForbidden: true, RequireSignedCommits: true},
+			action:     RefActionUpdate,
+			internal:   true,
+			unverified: unverified,
+			expCodes:   []string{"lifecycle.signed_commits"},
+			expParams:  [][]any{{refName, 1, "abc"}},
+		},
 	}
 
 	for _, test := range tests {
@@ -64,6 +102,9 @@ func TestDefLifecycle_RefChangeVerify(t *testing.T) {
 				RefNames:  []string{refName},
 				RefAction: test.action,
 				RefType:   RefTypeBranch,
+				Internal:  test.internal,
+
+				UnverifiedCommits: test.unverified,
 			}
 
 			if err := test.def.Sanitize(); err != nil {
@@ -60,6 +60,7 @@ func (s *Service) handleFileViewedOnBranchUpdate(ctx context.Context,
 		// DELETED: mark as obsolete - handles open pr file deletions
 		// CREATED: mark as obsolete - handles cases in which file deleted while PR was closed
 		// RENAMED: mark old + new path as obsolete - similar to deleting old file and creating new one
+		// COPIED: mark new path as obsolete - similar to creating a new file
 		// UPDATED: mark as obsolete - in case pr is closed file SHA is handling it
 		// This strategy leads to a behavior very similar to what github is doing
 		switch fileDiff.Status {
@@ -69,6 +70,8 @@ func (s *Service) handleFileViewedOnBranchUpdate(ctx context.Context,
 			obsoletePaths = append(obsoletePaths, fileDiff.OldPath)
 		case git.FileDiffStatusRenamed:
 			obsoletePaths = append(obsoletePaths, fileDiff.OldPath, fileDiff.Path)
+		case git.FileDiffStatusCopied:
+			obsoletePaths = append(obsoletePaths, fileDiff.Path)
 		case git.FileDiffStatusModified:
 			obsoletePaths = append(obsoletePaths, fileDiff.Path)
 		case git.FileDiffStatusUndefined:
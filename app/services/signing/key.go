@@ -0,0 +1,59 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"golang.org/x/crypto/ssh"
+)
+
+var ErrInvalidKey = errors.New("invalid signing key")
+
+// GetKeyFingerprint validates the public key and returns its fingerprint.
+// GPG keys are identified by the fingerprint of their primary key, ssh keys by their SHA256 fingerprint.
+func GetKeyFingerprint(keyType enum.SigningKeyType, content string) (string, error) {
+	switch keyType {
+	case enum.SigningKeyTypeGPG:
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(content))
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidKey, err)
+		}
+		if len(entities) != 1 {
+			return "", fmt.Errorf("%w: expected exactly one key but got %d", ErrInvalidKey, len(entities))
+		}
+		if entities[0].PrivateKey != nil {
+			return "", fmt.Errorf("%w: private keys are not allowed", ErrInvalidKey)
+		}
+
+		return fmt.Sprintf("%X", entities[0].PrimaryKey.Fingerprint), nil
+
+	case enum.SigningKeyTypeSSH:
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(content))
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", ErrInvalidKey, err)
+		}
+
+		return ssh.FingerprintSHA256(publicKey), nil
+
+	default:
+		return "", fmt.Errorf("%w: unknown key type '%s'", ErrInvalidKey, keyType)
+	}
+}
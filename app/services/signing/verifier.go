@@ -0,0 +1,214 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/git"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/types"
+)
+
+// Verifier verifies signatures of commits and tags against the signing keys registered by the principals.
+type Verifier struct {
+	git            git.Interface
+	principalStore store.PrincipalStore
+	signingKeys    store.SigningKeyStore
+	systemUID      string
+}
+
+func NewVerifier(
+	git git.Interface,
+	principalStore store.PrincipalStore,
+	signingKeys store.SigningKeyStore,
+	systemUID string,
+) *Verifier {
+	return &Verifier{
+		git:            git,
+		principalStore: principalStore,
+		signingKeys:    signingKeys,
+		systemUID:      systemUID,
+	}
+}
+
+// signer contains the principal with the provided email and its signing keys.
+type signer struct {
+	principal *types.Principal
+	keys      []git.SigningKey
+}
+
+// Session verifies signatures of multiple objects, the keys of a principal are only loaded once per session.
+type Session struct {
+	verifier *Verifier
+	signers  map[string]*signer
+}
+
+// NewSession returns a new verification session.
+// Sessions are short-lived, so that removed keys don't verify any signatures after removal.
+func (v *Verifier) NewSession() *Session {
+	return &Session{
+		verifier: v,
+		signers:  map[string]*signer{},
+	}
+}
+
+// VerifyCommit verifies the signature of the commit against the keys of the committer.
+func (s *Session) VerifyCommit(ctx context.Context, commit *git.Commit) (*types.CommitVerification, error) {
+	return s.Verify(ctx, commit.Signature, commit.Committer.Identity.Email)
+}
+
+// VerifyCommits verifies the signatures of all commits, the results have the same order as the commits.
+func (s *Session) VerifyCommits(ctx context.Context, commits []git.Commit) ([]*types.CommitVerification, error) {
+	verifications := make([]*types.CommitVerification, len(commits))
+	for i := range commits {
+		var err error
+		verifications[i], err = s.VerifyCommit(ctx, &commits[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return verifications, nil
+}
+
+// VerifyTag verifies the signature of the annotated tag against the keys of the tagger.
+func (s *Session) VerifyTag(ctx context.Context, tag *git.CommitTag) (*types.CommitVerification, error) {
+	email := ""
+	if tag.Tagger != nil {
+		email = tag.Tagger.Identity.Email
+	}
+
+	return s.Verify(ctx, tag.Signature, email)
+}
+
+// Verify verifies the signature against the keys of the principal with the provided email.
+func (s *Session) Verify(
+	ctx context.Context,
+	signature *git.ObjectSignature,
+	email string,
+) (*types.CommitVerification, error) {
+	var keys []git.SigningKey
+	var principal *types.Principal
+
+	// no need to look up keys of the signer for unsigned objects.
+	if signature != nil {
+		owner, err := s.getSigner(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = owner.keys
+		principal = owner.principal
+	}
+
+	result, err := s.verifier.git.VerifySignature(&git.VerifySignatureParams{
+		Signature: signature,
+		Keys:      keys,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify signature: %w", err)
+	}
+
+	// objects created by the server (merges, web edits) are signed with the instance key.
+	if signature != nil && !result.Verified {
+		serverResult, ok, err := s.verifier.verifyServerSignature(signature)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			principal, err = s.verifier.principalStore.FindByUID(ctx, s.verifier.systemUID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find system principal: %w", err)
+			}
+			result = serverResult
+		}
+	}
+
+	verification := &types.CommitVerification{
+		Verified: result.Verified,
+		Reason:   string(result.Reason),
+		KeyID:    result.KeyID,
+	}
+	if result.Verified {
+		verification.Signer = principal.ToPrincipalInfo()
+	}
+
+	return verification, nil
+}
+
+// verifyServerSignature verifies the signature against the key used by the server to sign commits.
+// The returned bool indicates whether the signature was created by the server.
+func (v *Verifier) verifyServerSignature(
+	signature *git.ObjectSignature,
+) (git.VerifySignatureOutput, bool, error) {
+	key := v.git.GetSigningKey()
+	if key == nil {
+		return git.VerifySignatureOutput{}, false, nil
+	}
+
+	result, err := v.git.VerifySignature(&git.VerifySignatureParams{
+		Signature: signature,
+		Keys:      []git.SigningKey{*key},
+	})
+	if err != nil {
+		return git.VerifySignatureOutput{}, false, fmt.Errorf("failed to verify signature with server key: %w", err)
+	}
+
+	return result, result.Verified, nil
+}
+
+func (s *Session) getSigner(ctx context.Context, email string) (*signer, error) {
+	email = strings.ToLower(email)
+	if known, ok := s.signers[email]; ok {
+		return known, nil
+	}
+
+	res := &signer{}
+	s.signers[email] = res
+
+	if email == "" {
+		return res, nil
+	}
+
+	principal, err := s.verifier.principalStore.FindByEmail(ctx, email)
+	if errors.Is(err, gitness_store.ErrResourceNotFound) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find principal by email: %w", err)
+	}
+
+	keys, err := s.verifier.signingKeys.List(ctx, principal.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys of principal: %w", err)
+	}
+
+	res.principal = principal
+	res.keys = make([]git.SigningKey, len(keys))
+	for i, key := range keys {
+		res.keys[i] = git.SigningKey{
+			ID:      key.ID,
+			Type:    git.SigningKeyType(key.Type),
+			Content: key.Content,
+		}
+	}
+
+	return res, nil
+}
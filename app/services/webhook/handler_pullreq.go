@@ -348,3 +348,92 @@ func (s *Service) handleEventPullReqComment(
 			}, nil
 		})
 }
+
+// PullReqLabelPayload describes the body of the pullreq label added and removed triggers.
+type PullReqLabelPayload struct {
+	BaseSegment
+	PullReqSegment
+	PullReqTargetReferenceSegment
+	ReferenceSegment
+	ReferenceDetailsSegment
+	PullReqLabelSegment
+}
+
+// handleEventPullReqLabelAdded handles label added events
+// and triggers label added webhooks for the target repo.
+func (s *Service) handleEventPullReqLabelAdded(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.LabelAddedPayload],
+) error {
+	return s.handleEventPullReqLabel(ctx, enum.WebhookTriggerPullReqLabelAdded,
+		event.ID, &event.Payload.Base, LabelInfo{
+			ID:          event.Payload.LabelID,
+			Name:        event.Payload.LabelName,
+			Description: event.Payload.LabelDescription,
+			Color:       event.Payload.LabelColor,
+		})
+}
+
+// handleEventPullReqLabelRemoved handles label removed events
+// and triggers label removed webhooks for the target repo.
+func (s *Service) handleEventPullReqLabelRemoved(
+	ctx context.Context,
+	event *events.Event[*pullreqevents.LabelRemovedPayload],
+) error {
+	return s.handleEventPullReqLabel(ctx, enum.WebhookTriggerPullReqLabelRemoved,
+		event.ID, &event.Payload.Base, LabelInfo{
+			ID:          event.Payload.LabelID,
+			Name:        event.Payload.LabelName,
+			Description: event.Payload.LabelDescription,
+			Color:       event.Payload.LabelColor,
+		})
+}
+
+func (s *Service) handleEventPullReqLabel(
+	ctx context.Context,
+	trigger enum.WebhookTrigger,
+	eventID string,
+	base *pullreqevents.Base,
+	label LabelInfo,
+) error {
+	return s.triggerForEventWithPullReq(ctx, trigger,
+		eventID, base.PrincipalID, base.PullReqID,
+		func(principal *types.Principal, pr *types.PullReq, targetRepo, sourceRepo *types.Repository) (any, error) {
+			commitInfo, err := s.fetchCommitInfoForEvent(ctx, sourceRepo.GitUID, pr.SourceSHA)
+			if err != nil {
+				return nil, err
+			}
+			targetRepoInfo := repositoryInfoFrom(targetRepo, s.urlProvider)
+			sourceRepoInfo := repositoryInfoFrom(sourceRepo, s.urlProvider)
+
+			return &PullReqLabelPayload{
+				BaseSegment: BaseSegment{
+					Trigger:   trigger,
+					Repo:      targetRepoInfo,
+					Principal: principalInfoFrom(principal.ToPrincipalInfo()),
+				},
+				PullReqSegment: PullReqSegment{
+					PullReq: pullReqInfoFrom(pr, targetRepo, s.urlProvider),
+				},
+				PullReqTargetReferenceSegment: PullReqTargetReferenceSegment{
+					TargetRef: ReferenceInfo{
+						Name: gitReferenceNamePrefixBranch + pr.TargetBranch,
+						Repo: targetRepoInfo,
+					},
+				},
+				ReferenceSegment: ReferenceSegment{
+					Ref: ReferenceInfo{
+						Name: gitReferenceNamePrefixBranch + pr.SourceBranch,
+						Repo: sourceRepoInfo,
+					},
+				},
+				ReferenceDetailsSegment: ReferenceDetailsSegment{
+					SHA:    pr.SourceSHA,
+					Commit: &commitInfo,
+				},
+				PullReqLabelSegment: PullReqLabelSegment{
+					LabelInfo: label,
+				},
+			}, nil
+		})
+}
@@ -0,0 +1,182 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pullreqevents "github.com/harness/gitness/app/events/pullreq"
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/app/url"
+	"github.com/harness/gitness/events"
+	"github.com/harness/gitness/git"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/stretchr/testify/require"
+)
+
+type principalStoreMock struct {
+	store.PrincipalStore
+}
+
+func (principalStoreMock) Find(_ context.Context, id int64) (*types.Principal, error) {
+	return &types.Principal{ID: id, UID: "user", Type: enum.PrincipalTypeUser}, nil
+}
+
+type pullreqStoreMock struct {
+	store.PullReqStore
+	pr *types.PullReq
+}
+
+func (s pullreqStoreMock) Find(context.Context, int64) (*types.PullReq, error) {
+	return s.pr, nil
+}
+
+type repoStoreMock struct {
+	store.RepoStore
+	repo *types.Repository
+}
+
+func (s repoStoreMock) Find(context.Context, int64) (*types.Repository, error) {
+	return s.repo, nil
+}
+
+type gitMock struct {
+	git.Interface
+}
+
+func (gitMock) GetCommit(_ context.Context, params *git.GetCommitParams) (*git.GetCommitOutput, error) {
+	return &git.GetCommitOutput{Commit: git.Commit{SHA: params.SHA}}, nil
+}
+
+type urlProviderMock struct {
+	url.Provider
+}
+
+func (urlProviderMock) GenerateGITCloneURL(repoPath string) string {
+	return "http://localhost/git/" + repoPath + ".git"
+}
+
+func (urlProviderMock) GenerateUIPRURL(repoPath string, _ int64) string {
+	return "http://localhost/" + repoPath + "/pulls/1"
+}
+
+type webhookStoreMock struct {
+	store.WebhookStore
+	webhooks []*types.Webhook
+}
+
+func (s webhookStoreMock) List(context.Context, enum.WebhookParent, int64,
+	*types.WebhookFilter) ([]*types.Webhook, error) {
+	return s.webhooks, nil
+}
+
+func (s webhookStoreMock) UpdateOptLock(_ context.Context, hook *types.Webhook,
+	mutateFn func(hook *types.Webhook) error) (*types.Webhook, error) {
+	return hook, mutateFn(hook)
+}
+
+type webhookExecutionStoreMock struct {
+	store.WebhookExecutionStore
+	executions []*types.WebhookExecution
+}
+
+func (s *webhookExecutionStoreMock) ListForTrigger(context.Context, string) ([]*types.WebhookExecution, error) {
+	return nil, nil
+}
+
+func (s *webhookExecutionStoreMock) Create(_ context.Context, execution *types.WebhookExecution) error {
+	s.executions = append(s.executions, execution)
+	return nil
+}
+
+func TestService_HandleEventPullReqLabel(t *testing.T) {
+	ctx := context.Background()
+
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := &types.Repository{ID: 1, Path: "space/repo", UID: "repo"}
+	pr := &types.PullReq{
+		ID:           1,
+		Number:       1,
+		SourceRepoID: repo.ID,
+		SourceBranch: "feature",
+		SourceSHA:    "abc",
+		TargetRepoID: repo.ID,
+		TargetBranch: "main",
+	}
+	executionStore := &webhookExecutionStoreMock{}
+
+	// there is no label store, the label has to be taken from the event as it might not exist anymore.
+	s := &Service{
+		webhookStore: webhookStoreMock{webhooks: []*types.Webhook{
+			{ID: 1, URL: server.URL, Enabled: true, Triggers: []enum.WebhookTrigger{
+				enum.WebhookTriggerPullReqLabelRemoved,
+			}},
+		}},
+		webhookExecutionStore: executionStore,
+		repoStore:             repoStoreMock{repo: repo},
+		pullreqStore:          pullreqStoreMock{pr: pr},
+		principalStore:        principalStoreMock{},
+		git:                   gitMock{},
+		urlProvider:           urlProviderMock{},
+		secureHTTPClient:      http.DefaultClient,
+	}
+
+	err := s.handleEventPullReqLabelRemoved(ctx, &events.Event[*pullreqevents.LabelRemovedPayload]{
+		ID: "event",
+		Payload: &pullreqevents.LabelRemovedPayload{
+			Base: pullreqevents.Base{
+				PullReqID:    pr.ID,
+				SourceRepoID: repo.ID,
+				TargetRepoID: repo.ID,
+				PrincipalID:  1,
+				Number:       pr.Number,
+			},
+			LabelID:          7,
+			LabelName:        "bug",
+			LabelDescription: "Something isn't working",
+			LabelColor:       enum.LabelColorRed,
+		},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, executionStore.executions, 1)
+	require.Equal(t, enum.WebhookExecutionResultSuccess, executionStore.executions[0].Result)
+
+	payload := &PullReqLabelPayload{}
+	require.NoError(t, json.Unmarshal(<-bodies, payload))
+	require.Equal(t, enum.WebhookTriggerPullReqLabelRemoved, payload.Trigger)
+	require.Equal(t, LabelInfo{
+		ID:          7,
+		Name:        "bug",
+		Description: "Something isn't working",
+		Color:       enum.LabelColorRed,
+	}, payload.LabelInfo)
+	require.Equal(t, int64(1), payload.PullReq.Number)
+	require.Equal(t, "refs/heads/feature", payload.Ref.Name)
+}
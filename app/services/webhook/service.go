@@ -175,6 +175,8 @@ func NewService(
 			_ = r.RegisterClosed(service.handleEventPullReqClosed)
 			_ = r.RegisterCommentCreated(service.handleEventPullReqComment)
 			_ = r.RegisterMerged(service.handleEventPullReqMerged)
+			_ = r.RegisterLabelAdded(service.handleEventPullReqLabelAdded)
+			_ = r.RegisterLabelRemoved(service.handleEventPullReqLabelRemoved)
 
 			return nil
 		})
@@ -195,6 +195,9 @@ func (s *Service) executeWebhook(ctx context.Context, webhook *types.Webhook, tr
 		execution.Duration = int64(time.Since(start))
 		execution.Created = time.Now().UnixMilli()
 
+		executionsTotal.WithLabelValues(string(triggerType), string(execution.Result)).Inc()
+		executionDuration.WithLabelValues(string(triggerType)).Observe(time.Since(start).Seconds())
+
 		// TODO: what if saving execution failed? For now we will rerun it in case of error or not show it in history
 		err := s.webhookExecutionStore.Create(oCtx, &execution)
 		if err != nil {
@@ -68,6 +68,11 @@ type PullReqCommentSegment struct {
 	CommentInfo CommentInfo `json:"comment"`
 }
 
+// PullReqLabelSegment contains details for all pull req label related payloads for webhooks.
+type PullReqLabelSegment struct {
+	LabelInfo LabelInfo `json:"label"`
+}
+
 // RepositoryInfo describes the repo related info for a webhook payload.
 // NOTE: don't use types package as we want webhook payload to be independent from API calls.
 type RepositoryInfo struct {
@@ -207,3 +212,11 @@ type CommentInfo struct {
 	ID   int64  `json:"id"`
 	Text string `json:"text"`
 }
+
+// LabelInfo describes the label related info for a webhook payload.
+type LabelInfo struct {
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Color       enum.LabelColor `json:"color"`
+}
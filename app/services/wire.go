@@ -16,7 +16,10 @@ package services
 
 import (
 	"github.com/harness/gitness/app/services/cleanup"
+	"github.com/harness/gitness/app/services/housekeeping"
 	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/languagestats"
+	"github.com/harness/gitness/app/services/ldapsync"
 	"github.com/harness/gitness/app/services/metric"
 	"github.com/harness/gitness/app/services/notification"
 	"github.com/harness/gitness/app/services/pullreq"
@@ -42,6 +45,9 @@ type Services struct {
 	Cleanup            *cleanup.Service
 	Notification       *notification.Service
 	Keywordsearch      *keywordsearch.Service
+	LanguageStats      *languagestats.Service
+	Housekeeping       *housekeeping.Service
+	LDAPSyncer         *ldapsync.Syncer
 }
 
 func ProvideServices(
@@ -54,6 +60,9 @@ func ProvideServices(
 	cleanupSvc *cleanup.Service,
 	notificationSvc *notification.Service,
 	keywordsearchSvc *keywordsearch.Service,
+	languageStatsSvc *languagestats.Service,
+	housekeepingSvc *housekeeping.Service,
+	ldapSyncer *ldapsync.Syncer,
 ) Services {
 	return Services{
 		Webhook:            webhooksSvc,
@@ -65,5 +74,8 @@ func ProvideServices(
 		Cleanup:            cleanupSvc,
 		Notification:       notificationSvc,
 		Keywordsearch:      keywordsearchSvc,
+		LanguageStats:      languageStatsSvc,
+		Housekeeping:       housekeepingSvc,
+		LDAPSyncer:         ldapSyncer,
 	}
 }
@@ -159,6 +159,9 @@ type (
 		// GetRootSpace returns a space where space_parent_id is NULL.
 		GetRootSpace(ctx context.Context, spaceID int64) (*types.Space, error)
 
+		// GetAncestorIDs returns the IDs of the space and all its ancestors, starting with the space itself.
+		GetAncestorIDs(ctx context.Context, spaceID int64) ([]int64, error)
+
 		// Create creates a new space
 		Create(ctx context.Context, space *types.Space) error
 
@@ -196,6 +199,9 @@ type (
 		// Update the repo size.
 		UpdateSize(ctx context.Context, repoID int64, repoSize int64) error
 
+		// Update the primary language of the repo.
+		UpdateLanguage(ctx context.Context, repoID int64, language string) error
+
 		// Get the repo size.
 		GetSize(ctx context.Context, repoID int64) (int64, error)
 
@@ -420,6 +426,134 @@ type (
 		ListAllRepoRules(ctx context.Context, repoID int64) ([]types.RuleInfoInternal, error)
 	}
 
+	// LabelStore defines the label data storage.
+	LabelStore interface {
+		// Find finds a label by its ID.
+		Find(ctx context.Context, id int64) (*types.Label, error)
+
+		// FindByName finds a label defined in a space or a repository by its name.
+		FindByName(ctx context.Context, spaceID, repoID *int64, name string) (*types.Label, error)
+
+		// Create creates a new label.
+		Create(ctx context.Context, label *types.Label) error
+
+		// Update updates an existing label.
+		Update(ctx context.Context, label *types.Label) error
+
+		// Delete deletes a label defined in a space or a repository by its name.
+		Delete(ctx context.Context, spaceID, repoID *int64, name string) error
+
+		// List returns labels defined in a space or a repository.
+		List(ctx context.Context, spaceID, repoID *int64, filter *types.LabelFilter) ([]*types.Label, error)
+
+		// ListInScopes returns labels defined in the repository (optional) and in the provided spaces.
+		ListInScopes(ctx context.Context,
+			repoID int64, spaceIDs []int64, filter *types.LabelFilter) ([]*types.Label, error)
+	}
+
+	// PullReqLabelAssignmentStore defines the storage of labels assigned to pull requests.
+	PullReqLabelAssignmentStore interface {
+		// Assign assigns a label to a pull request. Assigning an already assigned label is a no-op.
+		Assign(ctx context.Context, label *types.PullReqLabel) error
+
+		// Unassign removes a label from a pull request.
+		Unassign(ctx context.Context, pullreqID, labelID int64) error
+
+		// Find returns the label assignment or an error if the label isn't assigned to the pull request.
+		Find(ctx context.Context, pullreqID, labelID int64) (*types.PullReqLabel, error)
+
+		// ListAssigned returns labels assigned to a pull request.
+		ListAssigned(ctx context.Context, pullreqID int64) ([]*types.LabelInfo, error)
+
+		// ListAssignedByPullReqIDs returns labels assigned to each of the provided pull requests.
+		ListAssignedByPullReqIDs(ctx context.Context, pullreqIDs []int64) (map[int64][]*types.LabelInfo, error)
+	}
+
+	// PrincipalIdentityStore defines the storage of identities of principals at external identity providers.
+	PrincipalIdentityStore interface {
+		// Find returns the identity of the provider with the given subject.
+		Find(ctx context.Context, provider, subject string) (*types.PrincipalIdentity, error)
+
+		// Create creates a new identity.
+		Create(ctx context.Context, identity *types.PrincipalIdentity) error
+
+		// UpdateLastLogin updates the time of the last login using the identity.
+		UpdateLastLogin(ctx context.Context, provider, subject string, lastLogin int64) error
+
+		// List returns all identities of the provider.
+		List(ctx context.Context, provider string) ([]*types.PrincipalIdentity, error)
+	}
+
+	// SigningKeyStore defines the signing key data storage.
+	SigningKeyStore interface {
+		// Create creates a new signing key.
+		Create(ctx context.Context, key *types.SigningKey) error
+
+		// Delete deletes the signing key of the principal.
+		Delete(ctx context.Context, principalID, id int64) error
+
+		// List returns the signing keys of the principal.
+		List(ctx context.Context, principalID int64) ([]*types.SigningKey, error)
+	}
+
+	// SpaceEnvVarStore defines the space environment variable data storage.
+	SpaceEnvVarStore interface {
+		// List returns the environment variables of the provided spaces.
+		List(ctx context.Context, spaceIDs ...int64) ([]*types.SpaceEnvVar, error)
+
+		// Upsert creates the environment variable or updates it if the space already has a variable with the key.
+		Upsert(ctx context.Context, envVar *types.SpaceEnvVar) error
+
+		// Delete deletes the environment variable of the space with the provided key.
+		Delete(ctx context.Context, spaceID int64, key string) error
+	}
+
+	// RepoLanguageStatsStore defines the repository language statistics data storage.
+	RepoLanguageStatsStore interface {
+		// Find returns the cached language statistics of the repository.
+		Find(ctx context.Context, repoID int64) (*types.RepoLanguageStats, error)
+
+		// Upsert creates or replaces the cached language statistics of the repository.
+		Upsert(ctx context.Context, stats *types.RepoLanguageStats) error
+	}
+
+	// RepoHousekeepingStore defines the repository housekeeping data storage.
+	RepoHousekeepingStore interface {
+		// Find returns the housekeeping state of the repository.
+		Find(ctx context.Context, repoID int64) (*types.RepoHousekeeping, error)
+
+		// ListPending returns the housekeeping state of repositories with at least minPushes
+		// pushes since their last maintenance, ordered by the number of pushes (descending).
+		ListPending(ctx context.Context, minPushes int64, limit int) ([]*types.RepoHousekeeping, error)
+
+		// RecordPush increments the number of pushes since the last maintenance of the repository.
+		RecordPush(ctx context.Context, repoID int64, pushed int64) error
+
+		// RecordMaintenance records the maintenance of the repository.
+		// The provided number of pushes which were covered by the maintenance is deducted.
+		RecordMaintenance(ctx context.Context, repoID int64, pushes int64, maintained int64) error
+	}
+
+	// PullReqReactionStore defines the pull request reaction data storage.
+	PullReqReactionStore interface {
+		// Add adds the reaction. It returns false if the principal already reacted with the same emoji.
+		Add(ctx context.Context, reaction *types.PullReqReaction) (bool, error)
+
+		// Remove removes the reaction. It returns false if the reaction didn't exist.
+		Remove(ctx context.Context, pullreqID int64, activityID *int64,
+			principalID int64, emoji enum.PullReqReaction) (bool, error)
+
+		// Count returns aggregated reactions of the pull request description and all the pull request comments.
+		// The result is keyed by the activity ID; reactions to the pull request description are under the key 0.
+		// The principal ID is used to mark the reactions of the principal.
+		Count(ctx context.Context, pullreqID, principalID int64) (map[int64][]*types.PullReqReactionCount, error)
+
+		// CountForTarget returns aggregated reactions of the pull request description (nil activity ID)
+		// or of a single pull request comment.
+		CountForTarget(ctx context.Context, pullreqID int64, activityID *int64,
+			principalID int64) ([]*types.PullReqReactionCount, error)
+	}
+
 	// WebhookStore defines the webhook data storage.
 	WebhookStore interface {
 		// Find finds the webhook by id.
@@ -661,6 +795,9 @@ type (
 
 		// List lists the connectors in a given space.
 		List(ctx context.Context, spaceID int64, filter types.ListQueryFilter) ([]*types.Connector, error)
+
+		// ListAll lists all the connectors in the provided spaces.
+		ListAll(ctx context.Context, spaceIDs ...int64) ([]*types.Connector, error)
 	}
 
 	TemplateStore interface {
@@ -26,6 +26,7 @@ import (
 	"github.com/harness/gitness/store/database/dbtx"
 	"github.com/harness/gitness/types"
 
+	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 )
@@ -41,7 +42,9 @@ const (
 	connectorColumns = `
 	connector_id,
 	connector_description,
+	connector_type,
 	connector_space_id,
+	connector_repo_id,
 	connector_uid,
 	connector_data,
 	connector_created,
@@ -94,6 +97,7 @@ func (s *connectorStore) Create(ctx context.Context, connector *types.Connector)
 		connector_description
 		,connector_type
 		,connector_space_id
+		,connector_repo_id
 		,connector_uid
 		,connector_data
 		,connector_created
@@ -103,6 +107,7 @@ func (s *connectorStore) Create(ctx context.Context, connector *types.Connector)
 		:connector_description
 		,:connector_type
 		,:connector_space_id
+		,:connector_repo_id
 		,:connector_uid
 		,:connector_data
 		,:connector_created
@@ -226,6 +231,33 @@ func (s *connectorStore) List(
 	return dst, nil
 }
 
+// ListAll lists all the connectors present in the provided spaces.
+func (s *connectorStore) ListAll(ctx context.Context, spaceIDs ...int64) ([]*types.Connector, error) {
+	if len(spaceIDs) == 0 {
+		return []*types.Connector{}, nil
+	}
+
+	stmt := database.Builder.
+		Select(connectorColumns).
+		From("connectors").
+		Where(squirrel.Eq{"connector_space_id": spaceIDs}).
+		OrderBy("connector_id")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to convert query to sql")
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*types.Connector{}
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed executing custom list query")
+	}
+
+	return dst, nil
+}
+
 // Delete deletes a connector given a connector ID.
 func (s *connectorStore) Delete(ctx context.Context, id int64) error {
 	const connectorDeleteStmt = `
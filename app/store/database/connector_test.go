@@ -0,0 +1,70 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/store/database"
+	"github.com/harness/gitness/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectorStore(t *testing.T) {
+	data := setupLabelTestData(t)
+	ctx := context.Background()
+	connectorStore := database.NewConnectorStore(data.db)
+
+	now := time.Now().UnixMilli()
+
+	spaceConnector := &types.Connector{
+		SpaceID: data.spaceID,
+		UID:     "space-registry",
+		Type:    types.ConnectorTypeDockerRegistry,
+		Data:    "{}",
+		Created: now,
+		Updated: now,
+	}
+	require.NoError(t, connectorStore.Create(ctx, spaceConnector))
+
+	repoConnector := &types.Connector{
+		SpaceID: data.spaceID,
+		RepoID:  &data.repoID,
+		UID:     "repo-registry",
+		Type:    types.ConnectorTypeDockerRegistry,
+		Data:    "{}",
+		Created: now,
+		Updated: now,
+	}
+	require.NoError(t, connectorStore.Create(ctx, repoConnector))
+
+	found, err := connectorStore.FindByUID(ctx, data.spaceID, "repo-registry")
+	require.NoError(t, err)
+	require.Equal(t, types.ConnectorTypeDockerRegistry, found.Type)
+	require.NotNil(t, found.RepoID)
+	require.Equal(t, data.repoID, *found.RepoID)
+
+	list, err := connectorStore.ListAll(ctx, data.spaceID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	require.Equal(t, "space-registry", list[0].UID)
+	require.Nil(t, list[0].RepoID)
+	require.Equal(t, types.ConnectorTypeDockerRegistry, list[0].Type)
+	require.Equal(t, "repo-registry", list[1].UID)
+	require.Equal(t, data.repoID, *list[1].RepoID)
+}
@@ -0,0 +1,333 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.LabelStore = (*LabelStore)(nil)
+
+// NewLabelStore returns a new LabelStore.
+func NewLabelStore(db *sqlx.DB) *LabelStore {
+	return &LabelStore{
+		db: db,
+	}
+}
+
+// LabelStore implements a store.LabelStore backed by a relational database.
+type LabelStore struct {
+	db *sqlx.DB
+}
+
+type label struct {
+	ID          int64           `db:"label_id"`
+	SpaceID     null.Int        `db:"label_space_id"`
+	RepoID      null.Int        `db:"label_repo_id"`
+	Name        string          `db:"label_name"`
+	Description string          `db:"label_description"`
+	Color       enum.LabelColor `db:"label_color"`
+	Created     int64           `db:"label_created"`
+	Updated     int64           `db:"label_updated"`
+	CreatedBy   int64           `db:"label_created_by"`
+	UpdatedBy   int64           `db:"label_updated_by"`
+}
+
+const (
+	labelColumns = `
+		 label_id
+		,label_space_id
+		,label_repo_id
+		,label_name
+		,label_description
+		,label_color
+		,label_created
+		,label_updated
+		,label_created_by
+		,label_updated_by`
+
+	labelSelectBase = `
+		SELECT` + labelColumns + `
+		FROM labels`
+)
+
+// Find finds a label by its ID.
+func (s *LabelStore) Find(ctx context.Context, id int64) (*types.Label, error) {
+	const sqlQuery = labelSelectBase + `
+		WHERE label_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &label{}
+	if err := db.GetContext(ctx, dst, sqlQuery, id); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed to find label")
+	}
+
+	return mapLabel(dst), nil
+}
+
+// FindByName finds a label defined in a space or a repository by its name.
+func (s *LabelStore) FindByName(ctx context.Context, spaceID, repoID *int64, name string) (*types.Label, error) {
+	stmt := database.Builder.
+		Select(labelColumns).
+		From("labels").
+		Where("LOWER(label_name) = ?", strings.ToLower(name))
+	stmt = applyLabelParentID(stmt, spaceID, repoID)
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert find label by name query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &label{}
+	if err = db.GetContext(ctx, dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed executing find label by name query")
+	}
+
+	return mapLabel(dst), nil
+}
+
+// Create creates a new label.
+func (s *LabelStore) Create(ctx context.Context, lbl *types.Label) error {
+	const sqlQuery = `
+		INSERT INTO labels (
+			 label_space_id
+			,label_repo_id
+			,label_name
+			,label_description
+			,label_color
+			,label_created
+			,label_updated
+			,label_created_by
+			,label_updated_by
+		) values (
+			 :label_space_id
+			,:label_repo_id
+			,:label_name
+			,:label_description
+			,:label_color
+			,:label_created
+			,:label_updated
+			,:label_created_by
+			,:label_updated_by
+		) RETURNING label_id`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapInternalLabel(lbl))
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to bind label object")
+	}
+
+	if err = db.QueryRowContext(ctx, query, arg...).Scan(&lbl.ID); err != nil {
+		return database.ProcessSQLErrorf(err, "Insert label query failed")
+	}
+
+	return nil
+}
+
+// Update updates an existing label.
+func (s *LabelStore) Update(ctx context.Context, lbl *types.Label) error {
+	const sqlQuery = `
+		UPDATE labels
+		SET
+			 label_name = :label_name
+			,label_description = :label_description
+			,label_color = :label_color
+			,label_updated = :label_updated
+			,label_updated_by = :label_updated_by
+		WHERE label_id = :label_id`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapInternalLabel(lbl))
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to bind label object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to update label")
+	}
+
+	return nil
+}
+
+// Delete deletes a label defined in a space or a repository by its name.
+func (s *LabelStore) Delete(ctx context.Context, spaceID, repoID *int64, name string) error {
+	stmt := database.Builder.
+		Delete("labels").
+		Where("LOWER(label_name) = ?", strings.ToLower(name))
+
+	if spaceID != nil {
+		stmt = stmt.Where("label_space_id = ?", *spaceID)
+	}
+
+	if repoID != nil {
+		stmt = stmt.Where("label_repo_id = ?", *repoID)
+	}
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to convert delete label query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err = db.ExecContext(ctx, sql, args...); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed executing delete label query")
+	}
+
+	return nil
+}
+
+// List returns labels defined in a space or a repository.
+func (s *LabelStore) List(
+	ctx context.Context,
+	spaceID, repoID *int64,
+	filter *types.LabelFilter,
+) ([]*types.Label, error) {
+	stmt := database.Builder.
+		Select(labelColumns).
+		From("labels")
+
+	stmt = applyLabelParentID(stmt, spaceID, repoID)
+	stmt = applyLabelFilter(stmt, filter)
+
+	return s.list(ctx, stmt)
+}
+
+// ListInScopes returns labels defined in the repository (optional) and in the provided spaces.
+func (s *LabelStore) ListInScopes(
+	ctx context.Context,
+	repoID int64,
+	spaceIDs []int64,
+	filter *types.LabelFilter,
+) ([]*types.Label, error) {
+	stmt := database.Builder.
+		Select(labelColumns).
+		From("labels")
+
+	if repoID > 0 {
+		stmt = stmt.Where(squirrel.Or{
+			squirrel.Eq{"label_space_id": spaceIDs},
+			squirrel.Eq{"label_repo_id": repoID},
+		})
+	} else {
+		stmt = stmt.Where(squirrel.Eq{"label_space_id": spaceIDs})
+	}
+
+	stmt = applyLabelFilter(stmt, filter)
+
+	return s.list(ctx, stmt)
+}
+
+func (s *LabelStore) list(ctx context.Context, stmt squirrel.SelectBuilder) ([]*types.Label, error) {
+	stmt = stmt.OrderBy("LOWER(label_name) ASC", "label_id ASC")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert list labels query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := make([]*label, 0)
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed executing list labels query")
+	}
+
+	return mapSliceLabel(dst), nil
+}
+
+func applyLabelParentID(
+	stmt squirrel.SelectBuilder,
+	spaceID, repoID *int64,
+) squirrel.SelectBuilder {
+	if spaceID != nil {
+		stmt = stmt.Where("label_space_id = ?", *spaceID)
+	}
+
+	if repoID != nil {
+		stmt = stmt.Where("label_repo_id = ?", *repoID)
+	}
+
+	return stmt
+}
+
+func applyLabelFilter(
+	stmt squirrel.SelectBuilder,
+	filter *types.LabelFilter,
+) squirrel.SelectBuilder {
+	if filter.Query != "" {
+		stmt = stmt.Where("LOWER(label_name) LIKE ?", fmt.Sprintf("%%%s%%", strings.ToLower(filter.Query)))
+	}
+
+	stmt = stmt.Limit(database.Limit(filter.Size))
+	stmt = stmt.Offset(database.Offset(filter.Page, filter.Size))
+
+	return stmt
+}
+
+func mapLabel(lbl *label) *types.Label {
+	return &types.Label{
+		ID:          lbl.ID,
+		SpaceID:     lbl.SpaceID.Ptr(),
+		RepoID:      lbl.RepoID.Ptr(),
+		Name:        lbl.Name,
+		Description: lbl.Description,
+		Color:       lbl.Color,
+		Created:     lbl.Created,
+		Updated:     lbl.Updated,
+		CreatedBy:   lbl.CreatedBy,
+		UpdatedBy:   lbl.UpdatedBy,
+	}
+}
+
+func mapSliceLabel(dbLabels []*label) []*types.Label {
+	result := make([]*types.Label, len(dbLabels))
+	for i, lbl := range dbLabels {
+		result[i] = mapLabel(lbl)
+	}
+	return result
+}
+
+func mapInternalLabel(lbl *types.Label) *label {
+	return &label{
+		ID:          lbl.ID,
+		SpaceID:     null.IntFromPtr(lbl.SpaceID),
+		RepoID:      null.IntFromPtr(lbl.RepoID),
+		Name:        lbl.Name,
+		Description: lbl.Description,
+		Color:       lbl.Color,
+		Created:     lbl.Created,
+		Updated:     lbl.Updated,
+		CreatedBy:   lbl.CreatedBy,
+		UpdatedBy:   lbl.UpdatedBy,
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/app/store/database"
+	"github.com/harness/gitness/app/store/database/migrate"
+	gitness_store "github.com/harness/gitness/store"
+	gitness_database "github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+type labelTestData struct {
+	db      *sqlx.DB
+	userID  int64
+	spaceID int64
+	repoID  int64
+	prIDs   []int64
+}
+
+// setupLabelTestData creates a user, a space with a repository and two pull requests in the repository.
+func setupLabelTestData(t *testing.T) labelTestData {
+	t.Helper()
+
+	ctx := context.Background()
+
+	db, err := gitness_database.ConnectAndMigrate(ctx, "sqlite3",
+		filepath.Join(t.TempDir(), "database.sqlite3"), migrate.Migrate)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	principalStore := database.NewPrincipalStore(db, store.ToLowerPrincipalUIDTransformation)
+	spacePathStore := database.NewSpacePathStore(db, store.ToLowerSpacePathTransformation)
+	spaceStore := database.NewSpaceStore(db, nil, spacePathStore)
+	repoStore := database.NewRepoStore(db, nil, spacePathStore)
+	pullreqStore := database.NewPullReqStore(db, nil)
+
+	now := time.Now().UnixMilli()
+
+	user := &types.User{UID: "user", Email: "user@example.com", DisplayName: "User", Salt: "salt"}
+	require.NoError(t, principalStore.CreateUser(ctx, user))
+
+	space := &types.Space{UID: "space", CreatedBy: user.ID, Created: now, Updated: now}
+	require.NoError(t, spaceStore.Create(ctx, space))
+	require.NoError(t, spacePathStore.InsertSegment(ctx, &types.SpacePathSegment{
+		UID:       space.UID,
+		IsPrimary: true,
+		SpaceID:   space.ID,
+		CreatedBy: user.ID,
+		Created:   now,
+		Updated:   now,
+	}))
+
+	repo := &types.Repository{
+		ParentID:      space.ID,
+		UID:           "repo",
+		GitUID:        "gitrepo",
+		DefaultBranch: "main",
+		CreatedBy:     user.ID,
+		Created:       now,
+		Updated:       now,
+	}
+	require.NoError(t, repoStore.Create(ctx, repo))
+
+	data := labelTestData{db: db, userID: user.ID, spaceID: space.ID, repoID: repo.ID}
+	for i := int64(1); i <= 2; i++ {
+		pr := &types.PullReq{
+			Number:       i,
+			CreatedBy:    user.ID,
+			Created:      now,
+			Updated:      now,
+			State:        enum.PullReqStateOpen,
+			Title:        "pull request",
+			SourceRepoID: repo.ID,
+			SourceBranch: fmt.Sprintf("feature-%d", i),
+			SourceSHA:    "0000000000000000000000000000000000000001",
+			TargetRepoID: repo.ID,
+			TargetBranch: "main",
+		}
+		require.NoError(t, pullreqStore.Create(ctx, pr))
+		data.prIDs = append(data.prIDs, pr.ID)
+	}
+
+	return data
+}
+
+func TestLabelStore(t *testing.T) {
+	ctx := context.Background()
+	data := setupLabelTestData(t)
+	labelStore := database.NewLabelStore(data.db)
+
+	newLabel := func(spaceID, repoID *int64, name string, color enum.LabelColor) *types.Label {
+		now := time.Now().UnixMilli()
+		return &types.Label{
+			SpaceID:   spaceID,
+			RepoID:    repoID,
+			Name:      name,
+			Color:     color,
+			Created:   now,
+			Updated:   now,
+			CreatedBy: data.userID,
+			UpdatedBy: data.userID,
+		}
+	}
+
+	bug := newLabel(&data.spaceID, nil, "bug", enum.LabelColorRed)
+	require.NoError(t, labelStore.Create(ctx, bug))
+	require.NotZero(t, bug.ID)
+
+	feature := newLabel(&data.spaceID, nil, "Feature", enum.LabelColorGreen)
+	require.NoError(t, labelStore.Create(ctx, feature))
+
+	backend := newLabel(nil, &data.repoID, "backend", enum.LabelColorBlue)
+	require.NoError(t, labelStore.Create(ctx, backend))
+
+	// names are unique per space and repository, independent of the case.
+	err := labelStore.Create(ctx, newLabel(&data.spaceID, nil, "BUG", enum.LabelColorGray))
+	require.ErrorIs(t, err, gitness_store.ErrDuplicate)
+	require.NoError(t, labelStore.Create(ctx, newLabel(nil, &data.repoID, "bug", enum.LabelColorGray)))
+
+	found, err := labelStore.Find(ctx, bug.ID)
+	require.NoError(t, err)
+	require.Equal(t, bug, found)
+
+	found, err = labelStore.FindByName(ctx, &data.spaceID, nil, "FEATURE")
+	require.NoError(t, err)
+	require.Equal(t, feature.ID, found.ID)
+
+	_, err = labelStore.FindByName(ctx, nil, &data.repoID, "feature")
+	require.ErrorIs(t, err, gitness_store.ErrResourceNotFound)
+
+	bug.Name = "defect"
+	bug.Description = "Something isn't working"
+	bug.Color = enum.LabelColorOrange
+	require.NoError(t, labelStore.Update(ctx, bug))
+
+	found, err = labelStore.Find(ctx, bug.ID)
+	require.NoError(t, err)
+	require.Equal(t, bug, found)
+
+	labelNames := func(labels []*types.Label) []string {
+		names := make([]string, len(labels))
+		for i, label := range labels {
+			names[i] = label.Name
+		}
+		return names
+	}
+
+	labels, err := labelStore.List(ctx, &data.spaceID, nil, &types.LabelFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"defect", "Feature"}, labelNames(labels))
+
+	labels, err = labelStore.List(ctx, &data.spaceID, nil, &types.LabelFilter{
+		ListQueryFilter: types.ListQueryFilter{Query: "FEAT"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Feature"}, labelNames(labels))
+
+	labels, err = labelStore.ListInScopes(ctx, data.repoID, []int64{data.spaceID}, &types.LabelFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"backend", "bug", "defect", "Feature"}, labelNames(labels))
+
+	labels, err = labelStore.ListInScopes(ctx, 0, []int64{data.spaceID}, &types.LabelFilter{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"defect", "Feature"}, labelNames(labels))
+
+	require.NoError(t, labelStore.Delete(ctx, &data.spaceID, nil, "FEATURE"))
+
+	_, err = labelStore.Find(ctx, feature.ID)
+	require.ErrorIs(t, err, gitness_store.ErrResourceNotFound)
+}
+
+func TestPullReqLabelAssignmentStore(t *testing.T) {
+	ctx := context.Background()
+	data := setupLabelTestData(t)
+	labelStore := database.NewLabelStore(data.db)
+	assignStore := database.NewPullReqLabelAssignmentStore(data.db)
+
+	labels := map[string]*types.Label{}
+	for _, name := range []string{"bug", "Feature"} {
+		label := &types.Label{
+			SpaceID:   &data.spaceID,
+			Name:      name,
+			Color:     enum.LabelColorRed,
+			CreatedBy: data.userID,
+			UpdatedBy: data.userID,
+		}
+		require.NoError(t, labelStore.Create(ctx, label))
+		labels[name] = label
+	}
+
+	assign := func(prID int64, label *types.Label) {
+		require.NoError(t, assignStore.Assign(ctx, &types.PullReqLabel{
+			PullReqID: prID,
+			LabelID:   label.ID,
+			Created:   time.Now().UnixMilli(),
+			CreatedBy: data.userID,
+		}))
+	}
+
+	pr1, pr2 := data.prIDs[0], data.prIDs[1]
+
+	assign(pr1, labels["Feature"])
+	assign(pr1, labels["bug"])
+	assign(pr2, labels["bug"])
+
+	// assigning an already assigned label is a no-op.
+	assign(pr1, labels["bug"])
+
+	assignment, err := assignStore.Find(ctx, pr1, labels["bug"].ID)
+	require.NoError(t, err)
+	require.Equal(t, data.userID, assignment.CreatedBy)
+
+	assigned, err := assignStore.ListAssigned(ctx, pr1)
+	require.NoError(t, err)
+	require.Equal(t, []*types.LabelInfo{
+		labels["bug"].ToInfo(),
+		labels["Feature"].ToInfo(),
+	}, assigned)
+
+	assignedByPR, err := assignStore.ListAssignedByPullReqIDs(ctx, []int64{pr1, pr2})
+	require.NoError(t, err)
+	require.Len(t, assignedByPR[pr1], 2)
+	require.Equal(t, []*types.LabelInfo{labels["bug"].ToInfo()}, assignedByPR[pr2])
+
+	require.NoError(t, assignStore.Unassign(ctx, pr1, labels["Feature"].ID))
+
+	_, err = assignStore.Find(ctx, pr1, labels["Feature"].ID)
+	require.ErrorIs(t, err, gitness_store.ErrResourceNotFound)
+
+	// deleting a label removes its assignments.
+	require.NoError(t, labelStore.Delete(ctx, &data.spaceID, nil, "bug"))
+
+	assigned, err = assignStore.ListAssigned(ctx, pr1)
+	require.NoError(t, err)
+	require.Empty(t, assigned)
+
+	assignedByPR, err = assignStore.ListAssignedByPullReqIDs(ctx, []int64{pr1, pr2})
+	require.NoError(t, err)
+	require.Empty(t, assignedByPR)
+}
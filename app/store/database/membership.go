@@ -60,7 +60,8 @@ type membership struct {
 	Created   int64 `db:"membership_created"`
 	Updated   int64 `db:"membership_updated"`
 
-	Role enum.MembershipRole `db:"membership_role"`
+	Role   enum.MembershipRole `db:"membership_role"`
+	Source string              `db:"membership_source"`
 }
 
 type membershipPrincipal struct {
@@ -80,7 +81,8 @@ const (
 		,membership_created_by
 		,membership_created
 		,membership_updated
-		,membership_role`
+		,membership_role
+		,membership_source`
 
 	membershipSelectBase = `
 	SELECT` + membershipColumns + `
@@ -128,6 +130,7 @@ func (s *MembershipStore) Create(ctx context.Context, membership *types.Membersh
 		,membership_created
 		,membership_updated
 		,membership_role
+		,membership_source
 	) values (
 		 :membership_space_id
 		,:membership_principal_id
@@ -135,6 +138,7 @@ func (s *MembershipStore) Create(ctx context.Context, membership *types.Membersh
 		,:membership_created
 		,:membership_updated
 		,:membership_role
+		,:membership_source
 	)`
 
 	db := dbtx.GetAccessor(ctx, s.db)
@@ -384,6 +388,7 @@ func mapToMembership(m *membership) types.Membership {
 		Created:   m.Created,
 		Updated:   m.Updated,
 		Role:      m.Role,
+		Source:    m.Source,
 	}
 }
 
@@ -395,6 +400,7 @@ func mapToInternalMembership(m *types.Membership) membership {
 		Created:     m.Created,
 		Updated:     m.Updated,
 		Role:        m.Role,
+		Source:      m.Source,
 	}
 }
 
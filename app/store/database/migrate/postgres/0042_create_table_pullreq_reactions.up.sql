@@ -0,0 +1,29 @@
+CREATE TABLE pullreq_reactions (
+ pullreq_reaction_id SERIAL PRIMARY KEY
+,pullreq_reaction_pullreq_id INTEGER NOT NULL
+,pullreq_reaction_activity_id INTEGER
+,pullreq_reaction_principal_id INTEGER NOT NULL
+,pullreq_reaction_emoji TEXT NOT NULL
+,pullreq_reaction_created BIGINT NOT NULL
+,CONSTRAINT fk_pullreq_reaction_pullreq_id FOREIGN KEY (pullreq_reaction_pullreq_id)
+    REFERENCES pullreqs (pullreq_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+,CONSTRAINT fk_pullreq_reaction_activity_id FOREIGN KEY (pullreq_reaction_activity_id)
+    REFERENCES pullreq_activities (pullreq_activity_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+,CONSTRAINT fk_pullreq_reaction_principal_id FOREIGN KEY (pullreq_reaction_principal_id)
+    REFERENCES principals (principal_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+);
+
+-- a principal can react with the same emoji only once on the pull request description (NULL activity) or a comment
+CREATE UNIQUE INDEX pullreq_reactions_target_principal_emoji
+    ON pullreq_reactions(
+        pullreq_reaction_pullreq_id
+        ,COALESCE(pullreq_reaction_activity_id, 0)
+        ,pullreq_reaction_principal_id
+        ,pullreq_reaction_emoji
+    );
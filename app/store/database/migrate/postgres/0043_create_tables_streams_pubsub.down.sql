@@ -0,0 +1,4 @@
+DROP TABLE pubsub_payloads;
+DROP TABLE stream_pending;
+DROP TABLE stream_groups;
+DROP TABLE stream_messages;
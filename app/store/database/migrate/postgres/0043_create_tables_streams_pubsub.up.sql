@@ -0,0 +1,48 @@
+-- Message ids are assigned on insert but become visible on commit, so concurrent producers can commit
+-- them out of order. The id of the inserting transaction is stored with the message to allow consumers
+-- to only read messages of transactions that are guaranteed to be completed.
+CREATE TABLE stream_messages (
+ message_id BIGSERIAL PRIMARY KEY
+,message_txid BIGINT NOT NULL DEFAULT txid_current()
+,message_stream TEXT NOT NULL
+,message_values TEXT NOT NULL
+,message_created BIGINT NOT NULL
+);
+
+CREATE INDEX stream_messages_stream_txid_id
+    ON stream_messages(message_stream, message_txid, message_id);
+
+CREATE TABLE stream_groups (
+ group_stream TEXT NOT NULL
+,group_name TEXT NOT NULL
+,group_last_message_txid BIGINT NOT NULL DEFAULT txid_current()
+,group_last_message_id BIGINT NOT NULL
+,group_created BIGINT NOT NULL
+,PRIMARY KEY (group_stream, group_name)
+);
+
+CREATE TABLE stream_pending (
+ pending_group TEXT NOT NULL
+,pending_message_id BIGINT NOT NULL
+,pending_stream TEXT NOT NULL
+,pending_consumer TEXT NOT NULL
+,pending_delivered BIGINT NOT NULL
+,pending_deliveries INTEGER NOT NULL
+,PRIMARY KEY (pending_group, pending_message_id)
+,CONSTRAINT fk_pending_message_id FOREIGN KEY (pending_message_id)
+    REFERENCES stream_messages (message_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+);
+
+CREATE INDEX stream_pending_stream_group_delivered
+    ON stream_pending(pending_stream, pending_group, pending_delivered);
+
+CREATE TABLE pubsub_payloads (
+ payload_id BIGSERIAL PRIMARY KEY
+,payload_data BYTEA NOT NULL
+,payload_created BIGINT NOT NULL
+);
+
+CREATE INDEX pubsub_payloads_created
+    ON pubsub_payloads(payload_created);
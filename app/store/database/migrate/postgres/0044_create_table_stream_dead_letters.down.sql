@@ -0,0 +1,4 @@
+DROP TABLE stream_dead_letters;
+
+ALTER TABLE stream_pending
+    DROP COLUMN pending_error;
@@ -0,0 +1,17 @@
+ALTER TABLE stream_pending
+    ADD COLUMN pending_error TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE stream_dead_letters (
+ dead_letter_id BIGSERIAL PRIMARY KEY
+,dead_letter_stream TEXT NOT NULL
+,dead_letter_group TEXT NOT NULL
+,dead_letter_message_id BIGINT NOT NULL
+,dead_letter_values TEXT NOT NULL
+,dead_letter_message_created BIGINT NOT NULL
+,dead_letter_deliveries INTEGER NOT NULL
+,dead_letter_error TEXT NOT NULL
+,dead_letter_created BIGINT NOT NULL
+);
+
+CREATE INDEX stream_dead_letters_group_created
+    ON stream_dead_letters(dead_letter_group, dead_letter_created);
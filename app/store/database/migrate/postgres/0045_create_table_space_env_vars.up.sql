@@ -0,0 +1,21 @@
+CREATE TABLE space_env_vars (
+ space_env_var_id SERIAL PRIMARY KEY
+,space_env_var_space_id INTEGER NOT NULL
+,space_env_var_key TEXT NOT NULL
+,space_env_var_value TEXT NOT NULL
+,space_env_var_secret_uid TEXT NOT NULL
+,space_env_var_created_by INTEGER NOT NULL
+,space_env_var_created BIGINT NOT NULL
+,space_env_var_updated BIGINT NOT NULL
+,CONSTRAINT fk_space_env_var_space_id FOREIGN KEY (space_env_var_space_id)
+    REFERENCES spaces (space_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+,CONSTRAINT fk_space_env_var_created_by FOREIGN KEY (space_env_var_created_by)
+    REFERENCES principals (principal_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION
+);
+
+CREATE UNIQUE INDEX space_env_vars_space_id_key
+    ON space_env_vars(space_env_var_space_id, space_env_var_key);
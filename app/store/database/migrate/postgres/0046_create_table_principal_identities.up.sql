@@ -0,0 +1,15 @@
+CREATE TABLE principal_identities (
+ principal_identity_provider TEXT NOT NULL
+,principal_identity_subject TEXT NOT NULL
+,principal_identity_principal_id INTEGER NOT NULL
+,principal_identity_created BIGINT NOT NULL
+,principal_identity_last_login BIGINT NOT NULL
+,PRIMARY KEY (principal_identity_provider, principal_identity_subject)
+,CONSTRAINT fk_principal_identity_principal_id FOREIGN KEY (principal_identity_principal_id)
+    REFERENCES principals (principal_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+);
+
+CREATE INDEX principal_identities_principal_id
+    ON principal_identities(principal_identity_principal_id);
@@ -0,0 +1 @@
+ALTER TABLE repositories ADD COLUMN repo_language TEXT NOT NULL DEFAULT '';
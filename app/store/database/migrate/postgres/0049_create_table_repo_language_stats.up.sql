@@ -0,0 +1,10 @@
+CREATE TABLE repo_language_stats (
+ repo_language_stats_repo_id INTEGER PRIMARY KEY
+,repo_language_stats_commit_sha TEXT NOT NULL
+,repo_language_stats_languages TEXT NOT NULL
+,repo_language_stats_updated BIGINT NOT NULL
+,CONSTRAINT fk_repo_language_stats_repo_id FOREIGN KEY (repo_language_stats_repo_id)
+    REFERENCES repositories (repo_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+CREATE TABLE repo_housekeeping (
+ repo_housekeeping_repo_id INTEGER PRIMARY KEY
+,repo_housekeeping_pushes INTEGER NOT NULL
+,repo_housekeeping_last_push BIGINT NOT NULL
+,repo_housekeeping_last_maintenance BIGINT NOT NULL
+,CONSTRAINT fk_repo_housekeeping_repo_id FOREIGN KEY (repo_housekeeping_repo_id)
+    REFERENCES repositories (repo_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+);
+
+CREATE INDEX repo_housekeeping_pushes
+    ON repo_housekeeping(repo_housekeeping_pushes);
@@ -0,0 +1,3 @@
+DROP INDEX connectors_repo_id;
+
+ALTER TABLE connectors DROP COLUMN connector_repo_id;
@@ -0,0 +1,12 @@
+ALTER TABLE memberships ADD COLUMN membership_source TEXT NOT NULL DEFAULT '';
+
+-- memberships added by a service principal to users that only sign in via ldap were added by the ldap group sync.
+UPDATE memberships
+SET membership_source = 'ldap'
+WHERE membership_created_by IN (
+    SELECT principal_id FROM principals WHERE principal_type = 'service'
+) AND membership_principal_id IN (
+    SELECT principal_identity_principal_id FROM principal_identities WHERE principal_identity_provider = 'ldap'
+) AND membership_principal_id NOT IN (
+    SELECT principal_identity_principal_id FROM principal_identities WHERE principal_identity_provider <> 'ldap'
+);
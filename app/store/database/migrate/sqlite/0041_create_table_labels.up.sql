@@ -0,0 +1,60 @@
+CREATE TABLE labels (
+ label_id INTEGER PRIMARY KEY AUTOINCREMENT
+,label_space_id INTEGER
+,label_repo_id INTEGER
+,label_name TEXT NOT NULL
+,label_description TEXT NOT NULL
+,label_color TEXT NOT NULL
+,label_created BIGINT NOT NULL
+,label_updated BIGINT NOT NULL
+,label_created_by INTEGER NOT NULL
+,label_updated_by INTEGER NOT NULL
+,CONSTRAINT fk_label_space_id FOREIGN KEY (label_space_id)
+    REFERENCES spaces (space_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+,CONSTRAINT fk_label_repo_id FOREIGN KEY (label_repo_id)
+    REFERENCES repositories (repo_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+,CONSTRAINT fk_label_created_by FOREIGN KEY (label_created_by)
+    REFERENCES principals (principal_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION
+,CONSTRAINT fk_label_updated_by FOREIGN KEY (label_updated_by)
+    REFERENCES principals (principal_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION
+);
+
+CREATE UNIQUE INDEX labels_space_id_name
+    ON labels(label_space_id, LOWER(label_name))
+    WHERE label_space_id IS NOT NULL;
+
+CREATE UNIQUE INDEX labels_repo_id_name
+    ON labels(label_repo_id, LOWER(label_name))
+    WHERE label_repo_id IS NOT NULL;
+
+CREATE TABLE pullreq_labels (
+ pullreq_label_pullreq_id INTEGER NOT NULL
+,pullreq_label_label_id INTEGER NOT NULL
+,pullreq_label_created BIGINT NOT NULL
+,pullreq_label_created_by INTEGER NOT NULL
+,CONSTRAINT pk_pullreq_labels PRIMARY KEY (pullreq_label_pullreq_id, pullreq_label_label_id)
+,CONSTRAINT fk_pullreq_label_pullreq_id FOREIGN KEY (pullreq_label_pullreq_id)
+    REFERENCES pullreqs (pullreq_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+,CONSTRAINT fk_pullreq_label_label_id FOREIGN KEY (pullreq_label_label_id)
+    REFERENCES labels (label_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+,CONSTRAINT fk_pullreq_label_created_by FOREIGN KEY (pullreq_label_created_by)
+    REFERENCES principals (principal_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION
+);
+
+-- used to filter pull requests by label
+CREATE INDEX pullreq_labels_label_id
+    ON pullreq_labels(pullreq_label_label_id);
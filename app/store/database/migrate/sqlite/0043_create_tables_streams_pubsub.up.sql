@@ -0,0 +1,47 @@
+-- sqlite serializes all writes, so message ids always become visible in order and the transaction id
+-- is only kept for compatibility with the PostgreSQL schema.
+CREATE TABLE stream_messages (
+ message_id INTEGER PRIMARY KEY AUTOINCREMENT
+,message_txid BIGINT NOT NULL DEFAULT 0
+,message_stream TEXT NOT NULL
+,message_values TEXT NOT NULL
+,message_created BIGINT NOT NULL
+);
+
+CREATE INDEX stream_messages_stream_txid_id
+    ON stream_messages(message_stream, message_txid, message_id);
+
+CREATE TABLE stream_groups (
+ group_stream TEXT NOT NULL
+,group_name TEXT NOT NULL
+,group_last_message_txid BIGINT NOT NULL DEFAULT 0
+,group_last_message_id BIGINT NOT NULL
+,group_created BIGINT NOT NULL
+,PRIMARY KEY (group_stream, group_name)
+);
+
+CREATE TABLE stream_pending (
+ pending_group TEXT NOT NULL
+,pending_message_id BIGINT NOT NULL
+,pending_stream TEXT NOT NULL
+,pending_consumer TEXT NOT NULL
+,pending_delivered BIGINT NOT NULL
+,pending_deliveries INTEGER NOT NULL
+,PRIMARY KEY (pending_group, pending_message_id)
+,CONSTRAINT fk_pending_message_id FOREIGN KEY (pending_message_id)
+    REFERENCES stream_messages (message_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+);
+
+CREATE INDEX stream_pending_stream_group_delivered
+    ON stream_pending(pending_stream, pending_group, pending_delivered);
+
+CREATE TABLE pubsub_payloads (
+ payload_id INTEGER PRIMARY KEY AUTOINCREMENT
+,payload_data BLOB NOT NULL
+,payload_created BIGINT NOT NULL
+);
+
+CREATE INDEX pubsub_payloads_created
+    ON pubsub_payloads(payload_created);
@@ -0,0 +1,19 @@
+CREATE TABLE signing_keys (
+ signing_key_id INTEGER PRIMARY KEY AUTOINCREMENT
+,signing_key_principal_id INTEGER NOT NULL
+,signing_key_type TEXT NOT NULL
+,signing_key_identifier TEXT NOT NULL
+,signing_key_fingerprint TEXT NOT NULL
+,signing_key_content TEXT NOT NULL
+,signing_key_created BIGINT NOT NULL
+,CONSTRAINT fk_signing_key_principal_id FOREIGN KEY (signing_key_principal_id)
+    REFERENCES principals (principal_id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX signing_keys_fingerprint
+    ON signing_keys(signing_key_fingerprint);
+
+CREATE INDEX signing_keys_principal_id
+    ON signing_keys(signing_key_principal_id);
@@ -0,0 +1,4 @@
+ALTER TABLE connectors ADD COLUMN connector_repo_id INTEGER
+    REFERENCES repositories (repo_id) ON DELETE CASCADE;
+
+CREATE INDEX connectors_repo_id ON connectors(connector_repo_id);
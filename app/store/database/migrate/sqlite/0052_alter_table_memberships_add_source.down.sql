@@ -0,0 +1 @@
+ALTER TABLE memberships DROP COLUMN membership_source;
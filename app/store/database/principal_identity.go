@@ -0,0 +1,167 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.PrincipalIdentityStore = (*PrincipalIdentityStore)(nil)
+
+const principalIdentityColumns = `
+	 principal_identity_provider
+	,principal_identity_subject
+	,principal_identity_principal_id
+	,principal_identity_created
+	,principal_identity_last_login`
+
+// NewPrincipalIdentityStore returns a new PrincipalIdentityStore.
+func NewPrincipalIdentityStore(db *sqlx.DB) *PrincipalIdentityStore {
+	return &PrincipalIdentityStore{
+		db: db,
+	}
+}
+
+// PrincipalIdentityStore implements store.PrincipalIdentityStore backed by a relational database.
+type PrincipalIdentityStore struct {
+	db *sqlx.DB
+}
+
+// Find returns the identity of the provider with the given subject.
+func (s *PrincipalIdentityStore) Find(
+	ctx context.Context,
+	provider string,
+	subject string,
+) (*types.PrincipalIdentity, error) {
+	stmt := database.Builder.
+		Select(principalIdentityColumns).
+		From("principal_identities").
+		Where("principal_identity_provider = ?", provider).
+		Where("principal_identity_subject = ?", subject)
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert find principal identity query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &types.PrincipalIdentity{}
+	if err = db.GetContext(ctx, dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed to find principal identity")
+	}
+
+	return dst, nil
+}
+
+// Create creates a new identity.
+func (s *PrincipalIdentityStore) Create(ctx context.Context, identity *types.PrincipalIdentity) error {
+	const sqlQuery = `
+		INSERT INTO principal_identities (
+			 principal_identity_provider
+			,principal_identity_subject
+			,principal_identity_principal_id
+			,principal_identity_created
+			,principal_identity_last_login
+		) values (
+			 :principal_identity_provider
+			,:principal_identity_subject
+			,:principal_identity_principal_id
+			,:principal_identity_created
+			,:principal_identity_last_login
+		)`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, identity)
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to bind principal identity object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to insert principal identity")
+	}
+
+	return nil
+}
+
+// UpdateLastLogin updates the time of the last login using the identity.
+func (s *PrincipalIdentityStore) UpdateLastLogin(
+	ctx context.Context,
+	provider string,
+	subject string,
+	lastLogin int64,
+) error {
+	stmt := database.Builder.
+		Update("principal_identities").
+		Set("principal_identity_last_login", lastLogin).
+		Where("principal_identity_provider = ?", provider).
+		Where("principal_identity_subject = ?", subject)
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to convert update principal identity query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	result, err := db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to update principal identity")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to get number of updated rows")
+	}
+
+	if count == 0 {
+		return gitness_store.ErrResourceNotFound
+	}
+
+	return nil
+}
+
+// List returns all identities of the provider.
+func (s *PrincipalIdentityStore) List(ctx context.Context, provider string) ([]*types.PrincipalIdentity, error) {
+	stmt := database.Builder.
+		Select(principalIdentityColumns).
+		From("principal_identities").
+		Where("principal_identity_provider = ?", provider).
+		OrderBy("principal_identity_subject")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert list principal identities query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := make([]*types.PrincipalIdentity, 0)
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed executing list principal identities query")
+	}
+
+	return dst, nil
+}
@@ -482,7 +482,7 @@ func mapPullReqActivity(act *pullReqActivity) *types.PullReqActivity {
 		Kind:       act.Kind,
 		Text:       act.Text,
 		PayloadRaw: act.Payload,
-		Metadata:   make(map[string]interface{}),
+		Metadata:   nil,
 		ResolvedBy: act.ResolvedBy.Ptr(),
 		Resolved:   act.Resolved.Ptr(),
 		Author:     types.PrincipalInfo{},
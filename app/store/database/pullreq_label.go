@@ -0,0 +1,203 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.PullReqLabelAssignmentStore = (*PullReqLabelAssignmentStore)(nil)
+
+// NewPullReqLabelAssignmentStore returns a new PullReqLabelAssignmentStore.
+func NewPullReqLabelAssignmentStore(db *sqlx.DB) *PullReqLabelAssignmentStore {
+	return &PullReqLabelAssignmentStore{
+		db: db,
+	}
+}
+
+// PullReqLabelAssignmentStore implements store.PullReqLabelAssignmentStore backed by a relational database.
+type PullReqLabelAssignmentStore struct {
+	db *sqlx.DB
+}
+
+type pullReqLabel struct {
+	PullReqID int64 `db:"pullreq_label_pullreq_id"`
+	LabelID   int64 `db:"pullreq_label_label_id"`
+	Created   int64 `db:"pullreq_label_created"`
+	CreatedBy int64 `db:"pullreq_label_created_by"`
+}
+
+type pullReqLabelInfo struct {
+	PullReqID int64           `db:"pullreq_label_pullreq_id"`
+	ID        int64           `db:"label_id"`
+	SpaceID   null.Int        `db:"label_space_id"`
+	RepoID    null.Int        `db:"label_repo_id"`
+	Name      string          `db:"label_name"`
+	Color     enum.LabelColor `db:"label_color"`
+}
+
+const (
+	pullReqLabelColumns = `
+		 pullreq_label_pullreq_id
+		,pullreq_label_label_id
+		,pullreq_label_created
+		,pullreq_label_created_by`
+
+	pullReqLabelInfoColumns = `
+		 pullreq_label_pullreq_id
+		,label_id
+		,label_space_id
+		,label_repo_id
+		,label_name
+		,label_color`
+)
+
+// Assign assigns a label to a pull request. Assigning an already assigned label is a no-op.
+func (s *PullReqLabelAssignmentStore) Assign(ctx context.Context, prLabel *types.PullReqLabel) error {
+	const sqlQuery = `
+		INSERT INTO pullreq_labels (` + pullReqLabelColumns + `
+		) values (
+			 :pullreq_label_pullreq_id
+			,:pullreq_label_label_id
+			,:pullreq_label_created
+			,:pullreq_label_created_by
+		)
+		ON CONFLICT (pullreq_label_pullreq_id, pullreq_label_label_id) DO NOTHING`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, &pullReqLabel{
+		PullReqID: prLabel.PullReqID,
+		LabelID:   prLabel.LabelID,
+		Created:   prLabel.Created,
+		CreatedBy: prLabel.CreatedBy,
+	})
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to bind pull request label object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to assign label to pull request")
+	}
+
+	return nil
+}
+
+// Unassign removes a label from a pull request.
+func (s *PullReqLabelAssignmentStore) Unassign(ctx context.Context, pullreqID, labelID int64) error {
+	const sqlQuery = `
+		DELETE FROM pullreq_labels
+		WHERE pullreq_label_pullreq_id = $1 AND pullreq_label_label_id = $2`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, pullreqID, labelID); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to unassign label from pull request")
+	}
+
+	return nil
+}
+
+// Find returns the label assignment or an error if the label isn't assigned to the pull request.
+func (s *PullReqLabelAssignmentStore) Find(
+	ctx context.Context,
+	pullreqID, labelID int64,
+) (*types.PullReqLabel, error) {
+	const sqlQuery = `
+		SELECT` + pullReqLabelColumns + `
+		FROM pullreq_labels
+		WHERE pullreq_label_pullreq_id = $1 AND pullreq_label_label_id = $2`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &pullReqLabel{}
+	if err := db.GetContext(ctx, dst, sqlQuery, pullreqID, labelID); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed to find pull request label")
+	}
+
+	return &types.PullReqLabel{
+		PullReqID: dst.PullReqID,
+		LabelID:   dst.LabelID,
+		Created:   dst.Created,
+		CreatedBy: dst.CreatedBy,
+	}, nil
+}
+
+// ListAssigned returns labels assigned to a pull request.
+func (s *PullReqLabelAssignmentStore) ListAssigned(
+	ctx context.Context,
+	pullreqID int64,
+) ([]*types.LabelInfo, error) {
+	labelMap, err := s.ListAssignedByPullReqIDs(ctx, []int64{pullreqID})
+	if err != nil {
+		return nil, err
+	}
+
+	labels := labelMap[pullreqID]
+	if labels == nil {
+		labels = []*types.LabelInfo{}
+	}
+
+	return labels, nil
+}
+
+// ListAssignedByPullReqIDs returns labels assigned to each of the provided pull requests.
+func (s *PullReqLabelAssignmentStore) ListAssignedByPullReqIDs(
+	ctx context.Context,
+	pullreqIDs []int64,
+) (map[int64][]*types.LabelInfo, error) {
+	stmt := database.Builder.
+		Select(pullReqLabelInfoColumns).
+		From("pullreq_labels").
+		InnerJoin("labels ON label_id = pullreq_label_label_id").
+		Where(squirrel.Eq{"pullreq_label_pullreq_id": pullreqIDs}).
+		OrderBy("LOWER(label_name) ASC", "label_id ASC")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert list assigned labels query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := make([]*pullReqLabelInfo, 0)
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed executing list assigned labels query")
+	}
+
+	result := make(map[int64][]*types.LabelInfo)
+	for _, l := range dst {
+		result[l.PullReqID] = append(result[l.PullReqID], &types.LabelInfo{
+			ID:      l.ID,
+			SpaceID: l.SpaceID.Ptr(),
+			RepoID:  l.RepoID.Ptr(),
+			Name:    l.Name,
+			Color:   l.Color,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,230 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/guregu/null"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.PullReqReactionStore = (*PullReqReactionStore)(nil)
+
+// NewPullReqReactionStore returns a new PullReqReactionStore.
+func NewPullReqReactionStore(db *sqlx.DB) *PullReqReactionStore {
+	return &PullReqReactionStore{
+		db: db,
+	}
+}
+
+// PullReqReactionStore implements store.PullReqReactionStore backed by a relational database.
+type PullReqReactionStore struct {
+	db *sqlx.DB
+}
+
+type pullReqReaction struct {
+	PullReqID   int64                `db:"pullreq_reaction_pullreq_id"`
+	ActivityID  null.Int             `db:"pullreq_reaction_activity_id"`
+	PrincipalID int64                `db:"pullreq_reaction_principal_id"`
+	Emoji       enum.PullReqReaction `db:"pullreq_reaction_emoji"`
+	Created     int64                `db:"pullreq_reaction_created"`
+}
+
+type pullReqReactionCount struct {
+	ActivityID int64                `db:"activity_id"`
+	Emoji      enum.PullReqReaction `db:"emoji"`
+	Count      int                  `db:"reaction_count"`
+	Reacted    int                  `db:"reacted"`
+}
+
+// Add adds the reaction. It returns false if the principal already reacted with the same emoji.
+func (s *PullReqReactionStore) Add(ctx context.Context, reaction *types.PullReqReaction) (bool, error) {
+	const sqlQuery = `
+		INSERT INTO pullreq_reactions (
+			 pullreq_reaction_pullreq_id
+			,pullreq_reaction_activity_id
+			,pullreq_reaction_principal_id
+			,pullreq_reaction_emoji
+			,pullreq_reaction_created
+		) values (
+			 :pullreq_reaction_pullreq_id
+			,:pullreq_reaction_activity_id
+			,:pullreq_reaction_principal_id
+			,:pullreq_reaction_emoji
+			,:pullreq_reaction_created
+		)
+		ON CONFLICT DO NOTHING`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, &pullReqReaction{
+		PullReqID:   reaction.PullReqID,
+		ActivityID:  null.IntFromPtr(reaction.ActivityID),
+		PrincipalID: reaction.PrincipalID,
+		Emoji:       reaction.Emoji,
+		Created:     reaction.Created,
+	})
+	if err != nil {
+		return false, database.ProcessSQLErrorf(err, "Failed to bind pull request reaction object")
+	}
+
+	result, err := db.ExecContext(ctx, query, arg...)
+	if err != nil {
+		return false, database.ProcessSQLErrorf(err, "Failed to insert pull request reaction")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, database.ProcessSQLErrorf(err, "Failed to get number of inserted rows")
+	}
+
+	return count > 0, nil
+}
+
+// Remove removes the reaction. It returns false if the reaction didn't exist.
+func (s *PullReqReactionStore) Remove(
+	ctx context.Context,
+	pullreqID int64,
+	activityID *int64,
+	principalID int64,
+	emoji enum.PullReqReaction,
+) (bool, error) {
+	stmt := database.Builder.
+		Delete("pullreq_reactions").
+		Where("pullreq_reaction_pullreq_id = ?", pullreqID).
+		Where("pullreq_reaction_principal_id = ?", principalID).
+		Where("pullreq_reaction_emoji = ?", emoji)
+
+	if activityID != nil {
+		stmt = stmt.Where("pullreq_reaction_activity_id = ?", *activityID)
+	} else {
+		stmt = stmt.Where("pullreq_reaction_activity_id IS NULL")
+	}
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to convert delete pull request reaction query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	result, err := db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return false, database.ProcessSQLErrorf(err, "Failed to delete pull request reaction")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return false, database.ProcessSQLErrorf(err, "Failed to get number of deleted rows")
+	}
+
+	return count > 0, nil
+}
+
+// Count returns aggregated reactions of the pull request description and all the pull request comments.
+// The result is keyed by the activity ID; reactions to the pull request description are under the key 0.
+func (s *PullReqReactionStore) Count(
+	ctx context.Context,
+	pullreqID int64,
+	principalID int64,
+) (map[int64][]*types.PullReqReactionCount, error) {
+	dst, err := s.count(ctx, s.countQuery(pullreqID, principalID))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64][]*types.PullReqReactionCount)
+	for _, c := range dst {
+		result[c.ActivityID] = append(result[c.ActivityID], mapPullReqReactionCount(c))
+	}
+
+	return result, nil
+}
+
+// CountForTarget returns aggregated reactions of the pull request description (nil activity ID)
+// or of a single pull request comment.
+func (s *PullReqReactionStore) CountForTarget(
+	ctx context.Context,
+	pullreqID int64,
+	activityID *int64,
+	principalID int64,
+) ([]*types.PullReqReactionCount, error) {
+	stmt := s.countQuery(pullreqID, principalID)
+	if activityID != nil {
+		stmt = stmt.Where("pullreq_reaction_activity_id = ?", *activityID)
+	} else {
+		stmt = stmt.Where("pullreq_reaction_activity_id IS NULL")
+	}
+
+	dst, err := s.count(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*types.PullReqReactionCount, len(dst))
+	for i, c := range dst {
+		result[i] = mapPullReqReactionCount(c)
+	}
+
+	return result, nil
+}
+
+func (s *PullReqReactionStore) countQuery(pullreqID, principalID int64) squirrel.SelectBuilder {
+	return database.Builder.
+		Select("COALESCE(pullreq_reaction_activity_id, 0) AS activity_id").
+		Column("pullreq_reaction_emoji AS emoji").
+		Column("COUNT(*) AS reaction_count").
+		Column("SUM(CASE WHEN pullreq_reaction_principal_id = ? THEN 1 ELSE 0 END) AS reacted", principalID).
+		From("pullreq_reactions").
+		Where("pullreq_reaction_pullreq_id = ?", pullreqID).
+		GroupBy("COALESCE(pullreq_reaction_activity_id, 0)", "pullreq_reaction_emoji").
+		OrderBy("MIN(pullreq_reaction_created) ASC")
+}
+
+func (s *PullReqReactionStore) count(
+	ctx context.Context,
+	stmt squirrel.SelectBuilder,
+) ([]*pullReqReactionCount, error) {
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert count pull request reactions query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := make([]*pullReqReactionCount, 0)
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed executing count pull request reactions query")
+	}
+
+	return dst, nil
+}
+
+func mapPullReqReactionCount(c *pullReqReactionCount) *types.PullReqReactionCount {
+	return &types.PullReqReactionCount{
+		Emoji:   c.Emoji,
+		Count:   c.Count,
+		Reacted: c.Reacted > 0,
+	}
+}
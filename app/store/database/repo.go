@@ -82,6 +82,8 @@ type repository struct {
 	NumMergedPulls int `db:"repo_num_merged_pulls"`
 
 	Importing bool `db:"repo_importing"`
+
+	Language string `db:"repo_language"`
 }
 
 const (
@@ -106,7 +108,8 @@ const (
 		,repo_num_closed_pulls
 		,repo_num_open_pulls
 		,repo_num_merged_pulls
-		,repo_importing`
+		,repo_importing
+		,repo_language`
 
 	repoSelectBase = `
 		SELECT` + repoColumnsForJoin + `
@@ -323,6 +326,37 @@ func (s *RepoStore) UpdateSize(ctx context.Context, repoID int64, size int64) er
 	return nil
 }
 
+// UpdateLanguage updates the primary language of a specific repository in the database.
+func (s *RepoStore) UpdateLanguage(ctx context.Context, repoID int64, language string) error {
+	stmt := database.Builder.
+		Update("repositories").
+		Set("repo_language", language).
+		Where("repo_id = ?", repoID)
+
+	sqlQuery, args, err := stmt.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "Failed to create sql query")
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	result, err := db.ExecContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to update repo language")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to get number of updated rows")
+	}
+
+	if count == 0 {
+		return fmt.Errorf("repo %d language not updated: %w", repoID, gitness_store.ErrResourceNotFound)
+	}
+
+	return nil
+}
+
 // GetSize returns the repo size.
 func (s *RepoStore) GetSize(ctx context.Context, repoID int64) (int64, error) {
 	query := "SELECT repo_size FROM repositories WHERE repo_id = $1;"
@@ -538,6 +572,7 @@ func (s *RepoStore) mapToRepo(
 		NumOpenPulls:   in.NumOpenPulls,
 		NumMergedPulls: in.NumMergedPulls,
 		Importing:      in.Importing,
+		Language:       in.Language,
 		// Path: is set below
 	}
 
@@ -616,6 +651,7 @@ func mapToInternalRepo(in *types.Repository) *repository {
 		NumOpenPulls:   in.NumOpenPulls,
 		NumMergedPulls: in.NumMergedPulls,
 		Importing:      in.Importing,
+		Language:       in.Language,
 	}
 }
 
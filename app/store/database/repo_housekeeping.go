@@ -0,0 +1,153 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.RepoHousekeepingStore = (*RepoHousekeepingStore)(nil)
+
+const repoHousekeepingColumns = `
+	 repo_housekeeping_repo_id
+	,repo_housekeeping_pushes
+	,repo_housekeeping_last_push
+	,repo_housekeeping_last_maintenance`
+
+// NewRepoHousekeepingStore returns a new RepoHousekeepingStore.
+func NewRepoHousekeepingStore(db *sqlx.DB) *RepoHousekeepingStore {
+	return &RepoHousekeepingStore{
+		db: db,
+	}
+}
+
+// RepoHousekeepingStore implements store.RepoHousekeepingStore backed by a relational database.
+type RepoHousekeepingStore struct {
+	db *sqlx.DB
+}
+
+type repoHousekeeping struct {
+	RepoID          int64 `db:"repo_housekeeping_repo_id"`
+	Pushes          int64 `db:"repo_housekeeping_pushes"`
+	LastPush        int64 `db:"repo_housekeeping_last_push"`
+	LastMaintenance int64 `db:"repo_housekeeping_last_maintenance"`
+}
+
+// Find returns the housekeeping state of the repository.
+func (s *RepoHousekeepingStore) Find(ctx context.Context, repoID int64) (*types.RepoHousekeeping, error) {
+	const sqlQuery = `
+		SELECT` + repoHousekeepingColumns + `
+		FROM repo_housekeeping
+		WHERE repo_housekeeping_repo_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &repoHousekeeping{}
+	if err := db.GetContext(ctx, dst, sqlQuery, repoID); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed to find repo housekeeping")
+	}
+
+	return mapToRepoHousekeeping(dst), nil
+}
+
+// ListPending returns the housekeeping state of repositories with at least minPushes
+// pushes since their last maintenance, ordered by the number of pushes (descending).
+func (s *RepoHousekeepingStore) ListPending(
+	ctx context.Context,
+	minPushes int64,
+	limit int,
+) ([]*types.RepoHousekeeping, error) {
+	const sqlQuery = `
+		SELECT` + repoHousekeepingColumns + `
+		FROM repo_housekeeping
+		WHERE repo_housekeeping_pushes >= $1
+		ORDER BY repo_housekeeping_pushes DESC, repo_housekeeping_last_maintenance ASC
+		LIMIT $2`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := []*repoHousekeeping{}
+	if err := db.SelectContext(ctx, &dst, sqlQuery, minPushes, limit); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed to list pending repo housekeeping")
+	}
+
+	res := make([]*types.RepoHousekeeping, len(dst))
+	for i := range dst {
+		res[i] = mapToRepoHousekeeping(dst[i])
+	}
+
+	return res, nil
+}
+
+// RecordPush increments the number of pushes since the last maintenance of the repository.
+func (s *RepoHousekeepingStore) RecordPush(ctx context.Context, repoID int64, pushed int64) error {
+	const sqlQuery = `
+		INSERT INTO repo_housekeeping (` + repoHousekeepingColumns + `
+		) values ($1, 1, $2, 0)
+		ON CONFLICT (repo_housekeeping_repo_id) DO UPDATE SET
+			 repo_housekeeping_pushes = repo_housekeeping.repo_housekeeping_pushes + 1
+			,repo_housekeeping_last_push = EXCLUDED.repo_housekeeping_last_push`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, repoID, pushed); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to record push for repo housekeeping")
+	}
+
+	return nil
+}
+
+// RecordMaintenance records the maintenance of the repository.
+// The provided number of pushes which were covered by the maintenance is deducted.
+func (s *RepoHousekeepingStore) RecordMaintenance(
+	ctx context.Context,
+	repoID int64,
+	pushes int64,
+	maintained int64,
+) error {
+	const sqlQuery = `
+		INSERT INTO repo_housekeeping (` + repoHousekeepingColumns + `
+		) values ($1, 0, 0, $2)
+		ON CONFLICT (repo_housekeeping_repo_id) DO UPDATE SET
+			 repo_housekeeping_pushes = CASE
+				WHEN repo_housekeeping.repo_housekeeping_pushes > $3
+				THEN repo_housekeeping.repo_housekeeping_pushes - $3
+				ELSE 0 END
+			,repo_housekeeping_last_maintenance = EXCLUDED.repo_housekeeping_last_maintenance`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	if _, err := db.ExecContext(ctx, sqlQuery, repoID, maintained, pushes); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to record repo maintenance")
+	}
+
+	return nil
+}
+
+func mapToRepoHousekeeping(in *repoHousekeeping) *types.RepoHousekeeping {
+	return &types.RepoHousekeeping{
+		RepoID:          in.RepoID,
+		Pushes:          in.Pushes,
+		LastPush:        in.LastPush,
+		LastMaintenance: in.LastMaintenance,
+	}
+}
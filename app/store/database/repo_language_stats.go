@@ -0,0 +1,129 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/harness/gitness/app/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/jmoiron/sqlx"
+	sqlxtypes "github.com/jmoiron/sqlx/types"
+)
+
+var _ store.RepoLanguageStatsStore = (*RepoLanguageStatsStore)(nil)
+
+const repoLanguageStatsColumns = `
+	 repo_language_stats_repo_id
+	,repo_language_stats_commit_sha
+	,repo_language_stats_languages
+	,repo_language_stats_updated`
+
+// NewRepoLanguageStatsStore returns a new RepoLanguageStatsStore.
+func NewRepoLanguageStatsStore(db *sqlx.DB) *RepoLanguageStatsStore {
+	return &RepoLanguageStatsStore{
+		db: db,
+	}
+}
+
+// RepoLanguageStatsStore implements store.RepoLanguageStatsStore backed by a relational database.
+type RepoLanguageStatsStore struct {
+	db *sqlx.DB
+}
+
+type repoLanguageStats struct {
+	RepoID    int64              `db:"repo_language_stats_repo_id"`
+	CommitSHA string             `db:"repo_language_stats_commit_sha"`
+	Languages sqlxtypes.JSONText `db:"repo_language_stats_languages"`
+	Updated   int64              `db:"repo_language_stats_updated"`
+}
+
+// Find returns the cached language statistics of the repository.
+func (s *RepoLanguageStatsStore) Find(ctx context.Context, repoID int64) (*types.RepoLanguageStats, error) {
+	const sqlQuery = `
+		SELECT` + repoLanguageStatsColumns + `
+		FROM repo_language_stats
+		WHERE repo_language_stats_repo_id = $1`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := &repoLanguageStats{}
+	if err := db.GetContext(ctx, dst, sqlQuery, repoID); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed to find repo language stats")
+	}
+
+	return mapToRepoLanguageStats(dst)
+}
+
+// Upsert creates or replaces the cached language statistics of the repository.
+func (s *RepoLanguageStatsStore) Upsert(ctx context.Context, stats *types.RepoLanguageStats) error {
+	const sqlQuery = `
+		INSERT INTO repo_language_stats (
+			 repo_language_stats_repo_id
+			,repo_language_stats_commit_sha
+			,repo_language_stats_languages
+			,repo_language_stats_updated
+		) values (
+			 :repo_language_stats_repo_id
+			,:repo_language_stats_commit_sha
+			,:repo_language_stats_languages
+			,:repo_language_stats_updated
+		)
+		ON CONFLICT (repo_language_stats_repo_id) DO UPDATE SET
+			 repo_language_stats_commit_sha = EXCLUDED.repo_language_stats_commit_sha
+			,repo_language_stats_languages = EXCLUDED.repo_language_stats_languages
+			,repo_language_stats_updated = EXCLUDED.repo_language_stats_updated`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, mapToInternalRepoLanguageStats(stats))
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to bind repo language stats object")
+	}
+
+	if _, err = db.ExecContext(ctx, query, arg...); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to upsert repo language stats")
+	}
+
+	return nil
+}
+
+func mapToRepoLanguageStats(in *repoLanguageStats) (*types.RepoLanguageStats, error) {
+	var languages []types.LanguageStat
+	if err := json.Unmarshal(in.Languages, &languages); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal repo language stats: %w", err)
+	}
+
+	return &types.RepoLanguageStats{
+		RepoID:    in.RepoID,
+		CommitSHA: in.CommitSHA,
+		Languages: languages,
+		Updated:   in.Updated,
+	}, nil
+}
+
+func mapToInternalRepoLanguageStats(in *types.RepoLanguageStats) *repoLanguageStats {
+	return &repoLanguageStats{
+		RepoID:    in.RepoID,
+		CommitSHA: in.CommitSHA,
+		Languages: EncodeToSQLXJSON(in.Languages),
+		Updated:   in.Updated,
+	}
+}
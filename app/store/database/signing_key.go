@@ -0,0 +1,138 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.SigningKeyStore = (*SigningKeyStore)(nil)
+
+const signingKeyColumns = `
+	 signing_key_id
+	,signing_key_principal_id
+	,signing_key_type
+	,signing_key_identifier
+	,signing_key_fingerprint
+	,signing_key_content
+	,signing_key_created`
+
+// NewSigningKeyStore returns a new SigningKeyStore.
+func NewSigningKeyStore(db *sqlx.DB) *SigningKeyStore {
+	return &SigningKeyStore{
+		db: db,
+	}
+}
+
+// SigningKeyStore implements store.SigningKeyStore backed by a relational database.
+type SigningKeyStore struct {
+	db *sqlx.DB
+}
+
+// Create creates a new signing key.
+func (s *SigningKeyStore) Create(ctx context.Context, key *types.SigningKey) error {
+	const sqlQuery = `
+		INSERT INTO signing_keys (
+			 signing_key_principal_id
+			,signing_key_type
+			,signing_key_identifier
+			,signing_key_fingerprint
+			,signing_key_content
+			,signing_key_created
+		) values (
+			 :signing_key_principal_id
+			,:signing_key_type
+			,:signing_key_identifier
+			,:signing_key_fingerprint
+			,:signing_key_content
+			,:signing_key_created
+		) RETURNING signing_key_id`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, key)
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to bind signing key object")
+	}
+
+	if err = db.QueryRowContext(ctx, query, arg...).Scan(&key.ID); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to insert signing key")
+	}
+
+	return nil
+}
+
+// Delete deletes the signing key of the principal.
+func (s *SigningKeyStore) Delete(ctx context.Context, principalID, id int64) error {
+	stmt := database.Builder.
+		Delete("signing_keys").
+		Where("signing_key_principal_id = ?", principalID).
+		Where("signing_key_id = ?", id)
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to convert delete signing key query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	result, err := db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to delete signing key")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to get number of deleted rows")
+	}
+
+	if count == 0 {
+		return gitness_store.ErrResourceNotFound
+	}
+
+	return nil
+}
+
+// List returns the signing keys of the principal ordered by creation time.
+func (s *SigningKeyStore) List(ctx context.Context, principalID int64) ([]*types.SigningKey, error) {
+	stmt := database.Builder.
+		Select(signingKeyColumns).
+		From("signing_keys").
+		Where("signing_key_principal_id = ?", principalID).
+		OrderBy("signing_key_created", "signing_key_id")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert list signing keys query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := make([]*types.SigningKey, 0)
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed executing list signing keys query")
+	}
+
+	return dst, nil
+}
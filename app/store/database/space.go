@@ -145,6 +145,34 @@ WHERE space_parent_id IS NULL;`
 	return s.Find(ctx, rootID)
 }
 
+// GetAncestorIDs returns the IDs of the space and all its ancestors, starting with the space itself.
+func (s *SpaceStore) GetAncestorIDs(ctx context.Context, spaceID int64) ([]int64, error) {
+	const query = `
+		WITH RECURSIVE space_ancestors(space_ancestor_id, space_ancestor_parent_id, space_ancestor_level) AS (
+			SELECT space_id, space_parent_id, 0
+			FROM spaces
+			WHERE space_id = $1
+
+			UNION
+
+			SELECT spaces.space_id, spaces.space_parent_id, space_ancestors.space_ancestor_level + 1
+			FROM spaces
+			JOIN space_ancestors ON spaces.space_id = space_ancestors.space_ancestor_parent_id
+		)
+		SELECT space_ancestor_id
+		FROM space_ancestors
+		ORDER BY space_ancestor_level`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	var ids []int64
+	if err := db.SelectContext(ctx, &ids, query, spaceID); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "failed to get space ancestors")
+	}
+
+	return ids, nil
+}
+
 // Create a new space.
 func (s *SpaceStore) Create(ctx context.Context, space *types.Space) error {
 	if space == nil {
@@ -0,0 +1,151 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/app/store"
+	gitness_store "github.com/harness/gitness/store"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/store/database/dbtx"
+	"github.com/harness/gitness/types"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+var _ store.SpaceEnvVarStore = (*SpaceEnvVarStore)(nil)
+
+const spaceEnvVarColumns = `
+	 space_env_var_id
+	,space_env_var_space_id
+	,space_env_var_key
+	,space_env_var_value
+	,space_env_var_secret_uid
+	,space_env_var_created_by
+	,space_env_var_created
+	,space_env_var_updated`
+
+// NewSpaceEnvVarStore returns a new SpaceEnvVarStore.
+func NewSpaceEnvVarStore(db *sqlx.DB) *SpaceEnvVarStore {
+	return &SpaceEnvVarStore{
+		db: db,
+	}
+}
+
+// SpaceEnvVarStore implements store.SpaceEnvVarStore backed by a relational database.
+type SpaceEnvVarStore struct {
+	db *sqlx.DB
+}
+
+// List returns the environment variables of the provided spaces ordered by key.
+func (s *SpaceEnvVarStore) List(ctx context.Context, spaceIDs ...int64) ([]*types.SpaceEnvVar, error) {
+	if len(spaceIDs) == 0 {
+		return []*types.SpaceEnvVar{}, nil
+	}
+
+	stmt := database.Builder.
+		Select(spaceEnvVarColumns).
+		From("space_env_vars").
+		Where(squirrel.Eq{"space_env_var_space_id": spaceIDs}).
+		OrderBy("space_env_var_key")
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert list space env vars query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	dst := make([]*types.SpaceEnvVar, 0)
+	if err = db.SelectContext(ctx, &dst, sql, args...); err != nil {
+		return nil, database.ProcessSQLErrorf(err, "Failed executing list space env vars query")
+	}
+
+	return dst, nil
+}
+
+// Upsert creates the environment variable or updates it if the space already has a variable with the key.
+func (s *SpaceEnvVarStore) Upsert(ctx context.Context, envVar *types.SpaceEnvVar) error {
+	const sqlQuery = `
+		INSERT INTO space_env_vars (
+			 space_env_var_space_id
+			,space_env_var_key
+			,space_env_var_value
+			,space_env_var_secret_uid
+			,space_env_var_created_by
+			,space_env_var_created
+			,space_env_var_updated
+		) values (
+			 :space_env_var_space_id
+			,:space_env_var_key
+			,:space_env_var_value
+			,:space_env_var_secret_uid
+			,:space_env_var_created_by
+			,:space_env_var_created
+			,:space_env_var_updated
+		)
+		ON CONFLICT (space_env_var_space_id, space_env_var_key) DO UPDATE SET
+			 space_env_var_value = EXCLUDED.space_env_var_value
+			,space_env_var_secret_uid = EXCLUDED.space_env_var_secret_uid
+			,space_env_var_updated = EXCLUDED.space_env_var_updated
+		RETURNING space_env_var_id, space_env_var_created_by, space_env_var_created`
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	query, arg, err := db.BindNamed(sqlQuery, envVar)
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to bind space env var object")
+	}
+
+	if err = db.QueryRowContext(ctx, query, arg...).Scan(&envVar.ID, &envVar.CreatedBy, &envVar.Created); err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to upsert space env var")
+	}
+
+	return nil
+}
+
+// Delete deletes the environment variable of the space with the provided key.
+func (s *SpaceEnvVarStore) Delete(ctx context.Context, spaceID int64, key string) error {
+	stmt := database.Builder.
+		Delete("space_env_vars").
+		Where("space_env_var_space_id = ?", spaceID).
+		Where("space_env_var_key = ?", key)
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to convert delete space env var query to sql: %w", err)
+	}
+
+	db := dbtx.GetAccessor(ctx, s.db)
+
+	result, err := db.ExecContext(ctx, sql, args...)
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to delete space env var")
+	}
+
+	count, err := result.RowsAffected()
+	if err != nil {
+		return database.ProcessSQLErrorf(err, "Failed to get number of deleted rows")
+	}
+
+	if count == 0 {
+		return gitness_store.ErrResourceNotFound
+	}
+
+	return nil
+}
@@ -35,6 +35,14 @@ var WireSet = wire.NewSet(
 	ProvideSpaceStore,
 	ProvideRepoStore,
 	ProvideRuleStore,
+	ProvideLabelStore,
+	ProvidePullReqLabelAssignmentStore,
+	ProvidePullReqReactionStore,
+	ProvideSpaceEnvVarStore,
+	ProvidePrincipalIdentityStore,
+	ProvideSigningKeyStore,
+	ProvideRepoLanguageStatsStore,
+	ProvideRepoHousekeepingStore,
 	ProvideJobStore,
 	ProvideExecutionStore,
 	ProvidePipelineStore,
@@ -120,6 +128,46 @@ func ProvideRuleStore(
 	return NewRuleStore(db, principalInfoCache)
 }
 
+// ProvideLabelStore provides a label store.
+func ProvideLabelStore(db *sqlx.DB) store.LabelStore {
+	return NewLabelStore(db)
+}
+
+// ProvidePullReqLabelAssignmentStore provides a pull request label assignment store.
+func ProvidePullReqLabelAssignmentStore(db *sqlx.DB) store.PullReqLabelAssignmentStore {
+	return NewPullReqLabelAssignmentStore(db)
+}
+
+// ProvidePrincipalIdentityStore provides a principal identity store.
+func ProvidePrincipalIdentityStore(db *sqlx.DB) store.PrincipalIdentityStore {
+	return NewPrincipalIdentityStore(db)
+}
+
+// ProvideSigningKeyStore provides a signing key store.
+func ProvideSigningKeyStore(db *sqlx.DB) store.SigningKeyStore {
+	return NewSigningKeyStore(db)
+}
+
+// ProvideRepoLanguageStatsStore provides a repository language statistics store.
+func ProvideRepoLanguageStatsStore(db *sqlx.DB) store.RepoLanguageStatsStore {
+	return NewRepoLanguageStatsStore(db)
+}
+
+// ProvideRepoHousekeepingStore provides a repository housekeeping store.
+func ProvideRepoHousekeepingStore(db *sqlx.DB) store.RepoHousekeepingStore {
+	return NewRepoHousekeepingStore(db)
+}
+
+// ProvideSpaceEnvVarStore provides a space environment variable store.
+func ProvideSpaceEnvVarStore(db *sqlx.DB) store.SpaceEnvVarStore {
+	return NewSpaceEnvVarStore(db)
+}
+
+// ProvidePullReqReactionStore provides a pull request reaction store.
+func ProvidePullReqReactionStore(db *sqlx.DB) store.PullReqReactionStore {
+	return NewPullReqReactionStore(db)
+}
+
 // ProvideJobStore provides a job store.
 func ProvideJobStore(db *sqlx.DB) job.Store {
 	return NewJobStore(db)
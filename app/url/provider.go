@@ -68,6 +68,9 @@ type Provider interface {
 
 	// GetAPIProto returns the proto for the API hostname
 	GetAPIProto() string
+
+	// GenerateOIDCCallbackURL returns the public url the OpenID Connect provider redirects to after login.
+	GenerateOIDCCallbackURL() string
 }
 
 // Provider provides the URLs of the gitness system.
@@ -189,3 +192,7 @@ func (p *provider) GetGITHostname() string {
 func (p *provider) GetAPIProto() string {
 	return p.apiURL.Scheme
 }
+
+func (p *provider) GenerateOIDCCallbackURL() string {
+	return p.apiURL.JoinPath("v1", "oidc", "callback").String()
+}
@@ -21,6 +21,7 @@ type Provider string
 const (
 	ProviderGCS        Provider = "gcs"
 	ProviderFileSystem Provider = "filesystem"
+	ProviderS3         Provider = "s3"
 )
 
 type Config struct {
@@ -29,4 +30,11 @@ type Config struct {
 	KeyPath               string
 	TargetPrincipal       string
 	ImpersonationLifetime time.Duration
+
+	// S3 specific configuration (the credentials fall back to the default AWS credential chain).
+	Endpoint        string
+	PathStyle       bool
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
 }
@@ -18,8 +18,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 )
@@ -92,3 +95,61 @@ func (c *FileSystemStore) Download(_ context.Context, filePath string) (io.ReadC
 	}
 	return io.ReadCloser(file), nil
 }
+
+func (c *FileSystemStore) Delete(_ context.Context, filePath string) error {
+	fileDiskPath := fmt.Sprintf(fileDiskPathFmt, c.basePath, filePath)
+
+	err := os.Remove(fileDiskPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove file: %w", err)
+	}
+
+	return nil
+}
+
+func (c *FileSystemStore) List(_ context.Context, prefix string) ([]ObjectInfo, error) {
+	// only walk the directory that contains all files with the prefix.
+	dir, _ := path.Split(prefix)
+	root := filepath.Join(c.basePath, filepath.FromSlash(dir))
+
+	objects := make([]ObjectInfo, 0)
+	err := filepath.WalkDir(root, func(fileDiskPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(c.basePath, fileDiskPath)
+		if err != nil {
+			return err
+		}
+
+		filePath := filepath.ToSlash(rel)
+		if !strings.HasPrefix(filePath, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		objects = append(objects, ObjectInfo{
+			Path:         filePath,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return objects, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"time"
 )
 
 var (
@@ -34,4 +35,17 @@ type Store interface {
 
 	// Download returns a reader for a file in the blob store.
 	Download(ctx context.Context, filePath string) (io.ReadCloser, error)
+
+	// Delete removes a file from the blob store. Deleting a file that doesn't exist isn't an error.
+	Delete(ctx context.Context, filePath string) error
+
+	// List returns all files in the blob store with a path that starts with the provided prefix.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// ObjectInfo holds the details of a file in the blob store.
+type ObjectInfo struct {
+	Path         string
+	Size         int64
+	LastModified time.Time
 }
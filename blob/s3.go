@@ -0,0 +1,164 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const (
+	defaultS3Region = "us-east-1"
+
+	s3SignedURLExpiry = 1 * time.Hour
+)
+
+// S3Store is a blob store for AWS S3 and S3 compatible storage services (e.g. MinIO).
+type S3Store struct {
+	config Config
+	client *s3.S3
+}
+
+func NewS3Store(cfg Config) (Store, error) {
+	region := cfg.Region
+	if region == "" {
+		region = defaultS3Region
+	}
+
+	awsConfig := &aws.Config{
+		Region:           aws.String(region),
+		S3ForcePathStyle: aws.Bool(cfg.PathStyle),
+	}
+
+	if cfg.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(cfg.Endpoint)
+		awsConfig.DisableSSL = aws.Bool(strings.HasPrefix(cfg.Endpoint, "http://"))
+	}
+
+	if cfg.AccessKeyID != "" {
+		awsConfig.Credentials = credentials.NewStaticCredentials(cfg.AccessKeyID, cfg.SecretAccessKey, "")
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 session: %w", err)
+	}
+
+	return &S3Store{
+		config: cfg,
+		client: s3.New(sess),
+	}, nil
+}
+
+func (c *S3Store) Upload(ctx context.Context, file io.Reader, filePath string) error {
+	uploader := s3manager.NewUploaderWithClient(c.client)
+	_, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		ACL:    aws.String(s3.ObjectCannedACLPrivate),
+		Bucket: aws.String(c.config.Bucket),
+		Key:    aws.String(filePath),
+		Body:   file,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write file to S3: %w", err)
+	}
+
+	return nil
+}
+
+func (c *S3Store) GetSignedURL(_ context.Context, filePath string) (string, error) {
+	req, _ := c.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(c.config.Bucket),
+		Key:    aws.String(filePath),
+	})
+
+	signedURL, err := req.Presign(s3SignedURLExpiry)
+	if err != nil {
+		return "", fmt.Errorf("failed to create signed URL for file: %s %w", filePath, err)
+	}
+
+	return signedURL, nil
+}
+
+func (c *S3Store) Download(ctx context.Context, filePath string) (io.ReadCloser, error) {
+	out, err := c.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(c.config.Bucket),
+		Key:    aws.String(filePath),
+	})
+	if isS3NotFound(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reader for file: %s in bucket: %s %w", filePath, c.config.Bucket, err)
+	}
+
+	return out.Body, nil
+}
+
+func (c *S3Store) Delete(ctx context.Context, filePath string) error {
+	_, err := c.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(c.config.Bucket),
+		Key:    aws.String(filePath),
+	})
+	if err != nil && !isS3NotFound(err) {
+		return fmt.Errorf("failed to delete file: %s from bucket: %s %w", filePath, c.config.Bucket, err)
+	}
+
+	return nil
+}
+
+func (c *S3Store) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := make([]ObjectInfo, 0)
+	err := c.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(c.config.Bucket),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Path:         aws.StringValue(obj.Key),
+				Size:         aws.Int64Value(obj.Size),
+				LastModified: aws.TimeValue(obj.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files with prefix: %s in bucket: %s %w", prefix, c.config.Bucket, err)
+	}
+
+	return objects, nil
+}
+
+// isS3NotFound returns true in case the error indicates that the object doesn't exist.
+func isS3NotFound(err error) bool {
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusNotFound {
+		return true
+	}
+
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey
+}
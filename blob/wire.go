@@ -31,6 +31,8 @@ func ProvideStore(ctx context.Context, config Config) (Store, error) {
 		return NewFileSystemStore(config)
 	case ProviderGCS:
 		return NewGCSStore(ctx, config)
+	case ProviderS3:
+		return NewS3Store(config)
 	default:
 		return nil, fmt.Errorf("invalid blob store provider: %s", config.Provider)
 	}
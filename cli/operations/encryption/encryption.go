@@ -0,0 +1,61 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/cli/server"
+	"github.com/harness/gitness/store/database"
+	"github.com/harness/gitness/types"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+func Register(app *kingpin.Application) {
+	cmd := app.Command("encryption", "manage encryption of stored secrets")
+	registerRotate(cmd)
+}
+
+func loadConfig(envfile string) (*types.Config, error) {
+	_ = godotenv.Load(envfile)
+
+	config, err := server.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	return config, nil
+}
+
+func getDB(ctx context.Context, config *types.Config) (*sqlx.DB, error) {
+	db, err := database.Connect(ctx, config.Database.Driver, config.Database.Datasource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database handle: %w", err)
+	}
+
+	return db, nil
+}
+
+func setupLoggingContext(ctx context.Context) context.Context {
+	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	log := log.Logger.With().Logger()
+	return log.WithContext(ctx)
+}
@@ -0,0 +1,296 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/harness/gitness/app/services/exporter"
+	"github.com/harness/gitness/app/services/importer"
+	"github.com/harness/gitness/encrypt"
+	"github.com/harness/gitness/store/database"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+// encryptedColumn describes a database column that holds encrypted data.
+type encryptedColumn struct {
+	table    string
+	idColumn string
+	column   string
+	filter   squirrel.Sqlizer
+	// decode converts the stored value to ciphertext.
+	decode func(stored []byte) ([]byte, error)
+	// encode converts ciphertext to the value that's stored.
+	encode func(ciphertext []byte) any
+}
+
+// encryptedColumns contains all columns that hold encrypted data.
+var encryptedColumns = []encryptedColumn{
+	{
+		table:    "secrets",
+		idColumn: "secret_id",
+		column:   "secret_data",
+		decode:   decodeRaw,
+		encode:   func(ciphertext []byte) any { return ciphertext },
+	},
+	{
+		table:    "webhooks",
+		idColumn: "webhook_id",
+		column:   "webhook_secret",
+		filter:   squirrel.NotEq{"webhook_secret": ""},
+		decode:   decodeRaw,
+		encode:   func(ciphertext []byte) any { return string(ciphertext) },
+	},
+	{
+		table:    "jobs",
+		idColumn: "job_uid",
+		column:   "job_data",
+		filter:   squirrel.Eq{"job_type": []string{importer.JobType, exporter.JobType}},
+		decode: func(stored []byte) ([]byte, error) {
+			return base64.StdEncoding.DecodeString(string(stored))
+		},
+		encode: func(ciphertext []byte) any { return base64.StdEncoding.EncodeToString(ciphertext) },
+	},
+}
+
+func decodeRaw(stored []byte) ([]byte, error) {
+	return stored, nil
+}
+
+type commandRotate struct {
+	envfile   string
+	batchSize int
+	dryRun    bool
+}
+
+func (c *commandRotate) run(*kingpin.ParseContext) error {
+	ctx := setupLoggingContext(context.Background())
+
+	config, err := loadConfig(c.envfile)
+	if err != nil {
+		return err
+	}
+
+	if config.Encrypter.Secret == "" && len(config.Encrypter.Keys) == 0 {
+		return errors.New("no encryption key is configured")
+	}
+
+	encrypter, err := encrypt.ProvideEncrypter(config)
+	if err != nil {
+		return fmt.Errorf("failed to create encrypter: %w", err)
+	}
+
+	// with a keyring, data that is already encrypted with the primary key doesn't have to be re-encrypted.
+	primaryKeyID := ""
+	if keyring, ok := encrypter.(*encrypt.Keyring); ok {
+		primaryKeyID = keyring.PrimaryKeyID()
+	}
+
+	db, err := getDB(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, col := range encryptedColumns {
+		r := rotator{
+			db:           db,
+			encrypter:    encrypter,
+			primaryKeyID: primaryKeyID,
+			col:          col,
+			batchSize:    c.batchSize,
+			dryRun:       c.dryRun,
+		}
+
+		stats, err := r.rotate(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to re-encrypt %s.%s: %w", col.table, col.column, err)
+		}
+
+		log.Ctx(ctx).Info().
+			Int("rotated", stats.rotated).
+			Int("skipped", stats.skipped).
+			Int("failed", stats.failed).
+			Bool("dry_run", c.dryRun).
+			Msgf("re-encrypted %s.%s", col.table, col.column)
+
+		failed += stats.failed
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to re-encrypt %d values", failed)
+	}
+
+	return nil
+}
+
+type rotateStats struct {
+	rotated int
+	skipped int
+	failed  int
+}
+
+// rotator re-encrypts all values of an encrypted column in batches.
+type rotator struct {
+	db           *sqlx.DB
+	encrypter    encrypt.Encrypter
+	primaryKeyID string
+	col          encryptedColumn
+	batchSize    int
+	dryRun       bool
+}
+
+type encryptedRow struct {
+	ID   any    `db:"id"`
+	Data []byte `db:"data"`
+}
+
+func (r *rotator) rotate(ctx context.Context) (rotateStats, error) {
+	var (
+		stats  rotateStats
+		lastID any
+	)
+
+	for {
+		rows, err := r.listBatch(ctx, lastID)
+		if err != nil {
+			return stats, err
+		}
+
+		if len(rows) == 0 {
+			return stats, nil
+		}
+
+		if err = r.rotateBatch(ctx, rows, &stats); err != nil {
+			return stats, err
+		}
+
+		lastID = rows[len(rows)-1].ID
+	}
+}
+
+func (r *rotator) listBatch(ctx context.Context, lastID any) ([]encryptedRow, error) {
+	stmt := database.Builder.
+		Select(r.col.idColumn+" AS id", r.col.column+" AS data").
+		From(r.col.table).
+		OrderBy(r.col.idColumn).
+		Limit(uint64(r.batchSize))
+
+	if r.col.filter != nil {
+		stmt = stmt.Where(r.col.filter)
+	}
+
+	if lastID != nil {
+		stmt = stmt.Where(squirrel.Gt{r.col.idColumn: lastID})
+	}
+
+	sql, args, err := stmt.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert query to sql: %w", err)
+	}
+
+	rows := make([]encryptedRow, 0, r.batchSize)
+	if err = r.db.SelectContext(ctx, &rows, sql, args...); err != nil {
+		return nil, fmt.Errorf("failed to list encrypted values: %w", err)
+	}
+
+	return rows, nil
+}
+
+func (r *rotator) rotateBatch(ctx context.Context, rows []encryptedRow, stats *rotateStats) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, row := range rows {
+		ciphertext, err := r.col.decode(row.Data)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("failed to decode %s.%s of %v", r.col.table, r.col.column, row.ID)
+			stats.failed++
+			continue
+		}
+
+		if r.primaryKeyID != "" && encrypt.KeyID(ciphertext) == r.primaryKeyID {
+			stats.skipped++
+			continue
+		}
+
+		plaintext, err := r.encrypter.Decrypt(ciphertext)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msgf("failed to decrypt %s.%s of %v", r.col.table, r.col.column, row.ID)
+			stats.failed++
+			continue
+		}
+
+		ciphertext, err = r.encrypter.Encrypt(plaintext)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt value: %w", err)
+		}
+
+		stats.rotated++
+
+		if r.dryRun {
+			continue
+		}
+
+		sql, args, err := database.Builder.
+			Update(r.col.table).
+			Set(r.col.column, r.col.encode(ciphertext)).
+			Where(squirrel.Eq{r.col.idColumn: row.ID}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to convert update query to sql: %w", err)
+		}
+
+		if _, err = tx.ExecContext(ctx, sql, args...); err != nil {
+			return fmt.Errorf("failed to update encrypted value of %v: %w", row.ID, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func registerRotate(app *kingpin.CmdClause) {
+	c := &commandRotate{}
+
+	cmd := app.Command("rotate", "re-encrypt all stored secrets with the primary encryption key").
+		Action(c.run)
+
+	cmd.Arg("envfile", "load the environment variable file").
+		Default("").
+		StringVar(&c.envfile)
+
+	cmd.Flag("batch-size", "number of values re-encrypted per transaction").
+		Default("100").
+		IntVar(&c.batchSize)
+
+	cmd.Flag("dry-run", "verify that all values can be re-encrypted without updating them").
+		BoolVar(&c.dryRun)
+}
@@ -0,0 +1,256 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/harness/gitness/app/services/importer"
+	"github.com/harness/gitness/app/store/database"
+	"github.com/harness/gitness/app/store/database/migrate"
+	"github.com/harness/gitness/encrypt"
+	"github.com/harness/gitness/job"
+	"github.com/harness/gitness/types"
+	"github.com/harness/gitness/types/enum"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPrimaryKey = "11111111111111111111111111111111"
+	testLegacyKey  = "00000000000000000000000000000000"
+)
+
+func TestRotator_Rotate(t *testing.T) {
+	ctx := context.Background()
+
+	// foreign keys are left disabled, so the encrypted rows can be created without their parents.
+	db, err := sqlx.Open("sqlite3", filepath.Join(t.TempDir(), "database.sqlite3"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, migrate.Migrate(ctx, db))
+
+	legacy, err := encrypt.New(testLegacyKey, false)
+	require.NoError(t, err)
+
+	keyring, err := encrypt.NewKeyring(map[string]string{"k1": testPrimaryKey}, "k1", testLegacyKey, false)
+	require.NoError(t, err)
+
+	encryptWith := func(e encrypt.Encrypter, plaintext string) []byte {
+		ciphertext, err := e.Encrypt(plaintext)
+		require.NoError(t, err)
+		return ciphertext
+	}
+
+	// 5 values per column with a batch size of 2 - one of the secrets is encrypted with the primary key already.
+	secretStore := database.NewSecretStore(db)
+	for i := 0; i < 5; i++ {
+		e := legacy
+		if i == 2 {
+			e = keyring
+		}
+		err = secretStore.Create(ctx, &types.Secret{
+			SpaceID: 1,
+			UID:     fmt.Sprintf("secret%d", i),
+			Data:    string(encryptWith(e, fmt.Sprintf("secret%d", i))),
+		})
+		require.NoError(t, err)
+	}
+
+	webhookStore := database.NewWebhookStore(db)
+	for i := 0; i < 5; i++ {
+		err = webhookStore.Create(ctx, &types.Webhook{
+			ParentID:   1,
+			ParentType: enum.WebhookParentRepo,
+			UID:        fmt.Sprintf("webhook%d", i),
+			URL:        "https://example.com",
+			Secret:     string(encryptWith(legacy, fmt.Sprintf("webhook%d", i))),
+		})
+		require.NoError(t, err)
+	}
+
+	// webhooks without secret are ignored
+	err = webhookStore.Create(ctx, &types.Webhook{
+		ParentID:   1,
+		ParentType: enum.WebhookParentRepo,
+		UID:        "webhook-no-secret",
+		URL:        "https://example.com",
+	})
+	require.NoError(t, err)
+
+	jobStore := database.NewJobStore(db)
+	for i := 0; i < 5; i++ {
+		err = jobStore.Create(ctx, &job.Job{
+			UID:  fmt.Sprintf("job%d", i),
+			Type: importer.JobType,
+			Data: base64.StdEncoding.EncodeToString(encryptWith(legacy, fmt.Sprintf("job%d", i))),
+		})
+		require.NoError(t, err)
+	}
+
+	// jobs of other types don't have encrypted data
+	err = jobStore.Create(ctx, &job.Job{UID: "job-plain", Type: "other", Data: "plain"})
+	require.NoError(t, err)
+
+	rotateAll := func(dryRun bool) []rotateStats {
+		stats := make([]rotateStats, len(encryptedColumns))
+		for i, col := range encryptedColumns {
+			r := rotator{
+				db:           db,
+				encrypter:    keyring,
+				primaryKeyID: keyring.PrimaryKeyID(),
+				col:          col,
+				batchSize:    2,
+				dryRun:       dryRun,
+			}
+
+			stats[i], err = r.rotate(ctx)
+			require.NoError(t, err)
+		}
+		return stats
+	}
+
+	// dry run doesn't update any values.
+	require.Equal(t, []rotateStats{{rotated: 4, skipped: 1}, {rotated: 5}, {rotated: 5}}, rotateAll(true))
+
+	secret, err := secretStore.FindByUID(ctx, 1, "secret0")
+	require.NoError(t, err)
+	require.Equal(t, "", encrypt.KeyID([]byte(secret.Data)))
+
+	require.Equal(t, []rotateStats{{rotated: 4, skipped: 1}, {rotated: 5}, {rotated: 5}}, rotateAll(false))
+
+	for i := 0; i < 5; i++ {
+		secret, err = secretStore.FindByUID(ctx, 1, fmt.Sprintf("secret%d", i))
+		require.NoError(t, err)
+		requireEncryptedWithPrimary(t, keyring, []byte(secret.Data), fmt.Sprintf("secret%d", i))
+
+		var webhook *types.Webhook
+		webhook, err = webhookStore.FindByUID(ctx, enum.WebhookParentRepo, 1, fmt.Sprintf("webhook%d", i))
+		require.NoError(t, err)
+		requireEncryptedWithPrimary(t, keyring, []byte(webhook.Secret), fmt.Sprintf("webhook%d", i))
+
+		var j *job.Job
+		j, err = jobStore.Find(ctx, fmt.Sprintf("job%d", i))
+		require.NoError(t, err)
+		data, err := base64.StdEncoding.DecodeString(j.Data)
+		require.NoError(t, err)
+		requireEncryptedWithPrimary(t, keyring, data, fmt.Sprintf("job%d", i))
+	}
+
+	webhook, err := webhookStore.FindByUID(ctx, enum.WebhookParentRepo, 1, "webhook-no-secret")
+	require.NoError(t, err)
+	require.Equal(t, "", webhook.Secret)
+
+	j, err := jobStore.Find(ctx, "job-plain")
+	require.NoError(t, err)
+	require.Equal(t, "plain", j.Data)
+
+	// all values are encrypted with the primary key already.
+	require.Equal(t, []rotateStats{{skipped: 5}, {skipped: 5}, {skipped: 5}}, rotateAll(false))
+}
+
+func TestRotator_Rotate_UndecryptableValue(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlx.Open("sqlite3", filepath.Join(t.TempDir(), "database.sqlite3"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, migrate.Migrate(ctx, db))
+
+	keyring, err := encrypt.NewKeyring(map[string]string{"k1": testPrimaryKey}, "k1", testLegacyKey, false)
+	require.NoError(t, err)
+
+	secretStore := database.NewSecretStore(db)
+	err = secretStore.Create(ctx, &types.Secret{SpaceID: 1, UID: "broken", Data: "not encrypted"})
+	require.NoError(t, err)
+
+	r := rotator{
+		db:           db,
+		encrypter:    keyring,
+		primaryKeyID: keyring.PrimaryKeyID(),
+		col:          encryptedColumns[0],
+		batchSize:    2,
+	}
+
+	stats, err := r.rotate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rotateStats{failed: 1}, stats)
+
+	secret, err := secretStore.FindByUID(ctx, 1, "broken")
+	require.NoError(t, err)
+	require.Equal(t, "not encrypted", secret.Data)
+}
+
+func TestRotator_Rotate_Compat(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlx.Open("sqlite3", filepath.Join(t.TempDir(), "database.sqlite3"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, migrate.Migrate(ctx, db))
+
+	keyring, err := encrypt.NewKeyring(map[string]string{"k1": testPrimaryKey}, "k1", testLegacyKey, true)
+	require.NoError(t, err)
+
+	// the value is encrypted with a key that was removed from the keyring.
+	other, err := encrypt.NewKeyring(map[string]string{"old": testLegacyKey}, "old", "", false)
+	require.NoError(t, err)
+	unknown, err := other.Encrypt("unknown")
+	require.NoError(t, err)
+
+	secretStore := database.NewSecretStore(db)
+	err = secretStore.Create(ctx, &types.Secret{SpaceID: 1, UID: "plain", Data: "plain"})
+	require.NoError(t, err)
+	err = secretStore.Create(ctx, &types.Secret{SpaceID: 1, UID: "unknown", Data: string(unknown)})
+	require.NoError(t, err)
+
+	r := rotator{
+		db:           db,
+		encrypter:    keyring,
+		primaryKeyID: keyring.PrimaryKeyID(),
+		col:          encryptedColumns[0],
+		batchSize:    2,
+	}
+
+	// in compatibility mode plain text values get encrypted,
+	// but values with a key ID prefix must never be treated as plain text.
+	stats, err := r.rotate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rotateStats{rotated: 1, failed: 1}, stats)
+
+	secret, err := secretStore.FindByUID(ctx, 1, "plain")
+	require.NoError(t, err)
+	requireEncryptedWithPrimary(t, keyring, []byte(secret.Data), "plain")
+
+	secret, err = secretStore.FindByUID(ctx, 1, "unknown")
+	require.NoError(t, err)
+	require.Equal(t, string(unknown), secret.Data)
+}
+
+func requireEncryptedWithPrimary(t *testing.T, keyring *encrypt.Keyring, ciphertext []byte, expected string) {
+	t.Helper()
+
+	require.Equal(t, keyring.PrimaryKeyID(), encrypt.KeyID(ciphertext))
+
+	plaintext, err := keyring.Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, expected, plaintext)
+}
@@ -24,7 +24,9 @@ import (
 
 	"github.com/harness/gitness/app/services/cleanup"
 	"github.com/harness/gitness/app/services/codeowners"
+	"github.com/harness/gitness/app/services/housekeeping"
 	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/languagestats"
 	"github.com/harness/gitness/app/services/notification"
 	"github.com/harness/gitness/app/services/trigger"
 	"github.com/harness/gitness/app/services/webhook"
@@ -257,6 +259,11 @@ func ProvideBlobStoreConfig(config *types.Config) (blob.Config, error) {
 		KeyPath:               config.BlobStore.KeyPath,
 		TargetPrincipal:       config.BlobStore.TargetPrincipal,
 		ImpersonationLifetime: config.BlobStore.ImpersonationLifetime,
+		Endpoint:              config.BlobStore.Endpoint,
+		PathStyle:             config.BlobStore.PathStyle,
+		Region:                config.BlobStore.Region,
+		AccessKeyID:           config.BlobStore.AccessKeyID,
+		SecretAccessKey:       config.BlobStore.SecretAccessKey,
 	}, nil
 }
 
@@ -271,6 +278,10 @@ func ProvideGitConfig(config *types.Config) gittypes.Config {
 			Mode:     config.Git.LastCommitCache.Mode,
 			Duration: config.Git.LastCommitCache.Duration,
 		},
+		SigningKey: gittypes.SigningKeyConfig{
+			Path:       config.Git.SigningKey.Path,
+			Passphrase: config.Git.SigningKey.Passphrase,
+		},
 	}
 }
 
@@ -334,6 +345,7 @@ func ProvidePubsubConfig(config *types.Config) pubsub.Config {
 		App:            config.PubSub.AppNamespace,
 		Namespace:      config.PubSub.DefaultNamespace,
 		Provider:       config.PubSub.Provider,
+		Datasource:     config.Database.Datasource,
 		HealthInterval: config.PubSub.HealthInterval,
 		SendTimeout:    config.PubSub.SendTimeout,
 		ChannelSize:    config.PubSub.ChannelSize,
@@ -363,10 +375,37 @@ func ProvideKeywordSearchConfig(config *types.Config) keywordsearch.Config {
 	}
 }
 
+// ProvideLanguageStatsConfig loads the language stats service config from the main config.
+func ProvideLanguageStatsConfig(config *types.Config) languagestats.Config {
+	return languagestats.Config{
+		EventReaderName: config.InstanceID,
+		Concurrency:     config.LanguageStats.Concurrency,
+		MaxRetries:      config.LanguageStats.MaxRetries,
+	}
+}
+
+// ProvideHousekeepingConfig loads the housekeeping service config from the main config.
+func ProvideHousekeepingConfig(config *types.Config) housekeeping.Config {
+	return housekeeping.Config{
+		EventReaderName: config.InstanceID,
+		Concurrency:     config.Housekeeping.Concurrency,
+		MaxRetries:      config.Housekeeping.MaxRetries,
+		Enabled:         config.Housekeeping.Enabled,
+		CRON:            config.Housekeeping.CRON,
+		MaxDuration:     config.Housekeeping.MaxDuration,
+		NumWorkers:      config.Housekeeping.NumWorkers,
+		RepoLimit:       config.Housekeeping.RepoLimit,
+		MinPushes:       config.Housekeeping.MinPushes,
+		RepoTimeout:     config.Housekeeping.RepoTimeout,
+		PruneExpiry:     config.Housekeeping.PruneExpiry,
+	}
+}
+
 func ProvideJobsConfig(config *types.Config) job.Config {
 	return job.Config{
 		InstanceID:                  config.InstanceID,
 		BackgroundJobsMaxRunning:    config.BackgroundJobs.MaxRunning,
 		BackgroundJobsRetentionTime: config.BackgroundJobs.RetentionTime,
+		MetricsEnabled:              config.Prometheus.Enabled,
 	}
 }
@@ -100,6 +100,20 @@ func (c *command) run(*kingpin.ParseContext) error {
 			}
 		}
 
+		if system.services.Housekeeping != nil {
+			if err := system.services.Housekeeping.Register(gCtx); err != nil {
+				log.Error().Err(err).Msg("failed to register housekeeping service")
+				return err
+			}
+		}
+
+		if system.services.LDAPSyncer != nil {
+			if err := system.services.LDAPSyncer.Register(gCtx); err != nil {
+				log.Error().Err(err).Msg("failed to register ldap syncer")
+				return err
+			}
+		}
+
 		if err := system.services.Cleanup.Register(gCtx); err != nil {
 			log.Error().Err(err).Msg("failed to register cleanup service")
 			return err
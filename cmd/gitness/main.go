@@ -17,6 +17,7 @@ package main
 import (
 	"github.com/harness/gitness/cli"
 	"github.com/harness/gitness/cli/operations/account"
+	"github.com/harness/gitness/cli/operations/encryption"
 	"github.com/harness/gitness/cli/operations/hooks"
 	"github.com/harness/gitness/cli/operations/migrate"
 	"github.com/harness/gitness/cli/operations/user"
@@ -38,6 +39,7 @@ func main() {
 	app := kingpin.New(application, description)
 
 	migrate.Register(app)
+	encryption.Register(app)
 	server.Register(app, initSystem)
 
 	user.Register(app)
@@ -33,6 +33,7 @@ import (
 	controllerwebhook "github.com/harness/gitness/app/api/controller/webhook"
 	"github.com/harness/gitness/app/auth/authn"
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/auth/ldap"
 	"github.com/harness/gitness/app/bootstrap"
 	gitevents "github.com/harness/gitness/app/events/git"
 	pullreqevents "github.com/harness/gitness/app/events/pullreq"
@@ -41,11 +42,13 @@ import (
 	"github.com/harness/gitness/app/pipeline/canceler"
 	"github.com/harness/gitness/app/pipeline/commit"
 	"github.com/harness/gitness/app/pipeline/converter"
+	"github.com/harness/gitness/app/pipeline/environ"
 	"github.com/harness/gitness/app/pipeline/file"
 	"github.com/harness/gitness/app/pipeline/manager"
 	"github.com/harness/gitness/app/pipeline/resolver"
 	"github.com/harness/gitness/app/pipeline/runner"
 	"github.com/harness/gitness/app/pipeline/scheduler"
+	pipelinesecret "github.com/harness/gitness/app/pipeline/secret"
 	"github.com/harness/gitness/app/pipeline/triggerer"
 	"github.com/harness/gitness/app/router"
 	"github.com/harness/gitness/app/server"
@@ -54,14 +57,19 @@ import (
 	"github.com/harness/gitness/app/services/codecomments"
 	"github.com/harness/gitness/app/services/codeowners"
 	"github.com/harness/gitness/app/services/exporter"
+	"github.com/harness/gitness/app/services/housekeeping"
 	"github.com/harness/gitness/app/services/importer"
 	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/app/services/languagestats"
+	"github.com/harness/gitness/app/services/ldapsync"
 	"github.com/harness/gitness/app/services/metric"
 	"github.com/harness/gitness/app/services/notification"
 	"github.com/harness/gitness/app/services/notification/mailer"
 	"github.com/harness/gitness/app/services/protection"
 	pullreqservice "github.com/harness/gitness/app/services/pullreq"
 	"github.com/harness/gitness/app/services/reposize"
+	"github.com/harness/gitness/app/services/signing"
 	"github.com/harness/gitness/app/services/trigger"
 	"github.com/harness/gitness/app/services/usergroup"
 	"github.com/harness/gitness/app/services/webhook"
@@ -158,6 +166,8 @@ func initSystem(ctx context.Context, config *types.Config) (*cliserver.System, e
 		connector.WireSet,
 		template.WireSet,
 		manager.WireSet,
+		pipelinesecret.WireSet,
+		environ.WireSet,
 		triggerer.WireSet,
 		file.WireSet,
 		converter.WireSet,
@@ -173,11 +183,19 @@ func initSystem(ctx context.Context, config *types.Config) (*cliserver.System, e
 		exporter.WireSet,
 		metric.WireSet,
 		reposize.WireSet,
+		ldapsync.WireSet,
+		signing.WireSet,
+		ldap.WireSet,
 		cliserver.ProvideCodeOwnerConfig,
 		codeowners.WireSet,
+		label.WireSet,
 		cliserver.ProvideKeywordSearchConfig,
 		keywordsearch.WireSet,
 		controllerkeywordsearch.WireSet,
+		cliserver.ProvideLanguageStatsConfig,
+		languagestats.WireSet,
+		cliserver.ProvideHousekeepingConfig,
+		housekeeping.WireSet,
 		usergroup.WireSet,
 	)
 	return &cliserver.System{}, nil
@@ -32,6 +32,7 @@ import (
 	webhook2 "github.com/harness/gitness/app/api/controller/webhook"
 	"github.com/harness/gitness/app/auth/authn"
 	"github.com/harness/gitness/app/auth/authz"
+	"github.com/harness/gitness/app/auth/ldap"
 	"github.com/harness/gitness/app/bootstrap"
 	events4 "github.com/harness/gitness/app/events/git"
 	events3 "github.com/harness/gitness/app/events/pullreq"
@@ -40,11 +41,13 @@ import (
 	"github.com/harness/gitness/app/pipeline/canceler"
 	"github.com/harness/gitness/app/pipeline/commit"
 	"github.com/harness/gitness/app/pipeline/converter"
+	"github.com/harness/gitness/app/pipeline/environ"
 	"github.com/harness/gitness/app/pipeline/file"
 	"github.com/harness/gitness/app/pipeline/manager"
 	"github.com/harness/gitness/app/pipeline/resolver"
 	"github.com/harness/gitness/app/pipeline/runner"
 	"github.com/harness/gitness/app/pipeline/scheduler"
+	secret2 "github.com/harness/gitness/app/pipeline/secret"
 	"github.com/harness/gitness/app/pipeline/triggerer"
 	"github.com/harness/gitness/app/router"
 	server2 "github.com/harness/gitness/app/server"
@@ -53,14 +56,19 @@ import (
 	"github.com/harness/gitness/app/services/codecomments"
 	"github.com/harness/gitness/app/services/codeowners"
 	"github.com/harness/gitness/app/services/exporter"
+	"github.com/harness/gitness/app/services/housekeeping"
 	"github.com/harness/gitness/app/services/importer"
 	"github.com/harness/gitness/app/services/keywordsearch"
+	"github.com/harness/gitness/app/services/label"
+	"github.com/harness/gitness/app/services/languagestats"
+	"github.com/harness/gitness/app/services/ldapsync"
 	"github.com/harness/gitness/app/services/metric"
 	"github.com/harness/gitness/app/services/notification"
 	"github.com/harness/gitness/app/services/notification/mailer"
 	"github.com/harness/gitness/app/services/protection"
 	"github.com/harness/gitness/app/services/pullreq"
 	"github.com/harness/gitness/app/services/reposize"
+	"github.com/harness/gitness/app/services/signing"
 	trigger2 "github.com/harness/gitness/app/services/trigger"
 	"github.com/harness/gitness/app/services/usergroup"
 	"github.com/harness/gitness/app/services/webhook"
@@ -113,14 +121,20 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	principalUIDTransformation := store.ProvidePrincipalUIDTransformation()
 	principalStore := database.ProvidePrincipalStore(db, principalUIDTransformation)
 	tokenStore := database.ProvideTokenStore(db)
-	controller := user.ProvideController(transactor, principalUID, authorizer, principalStore, tokenStore, membershipStore)
-	serviceController := service.NewController(principalUID, authorizer, principalStore)
-	bootstrapBootstrap := bootstrap.ProvideBootstrap(config, controller, serviceController)
-	authenticator := authn.ProvideAuthenticator(config, principalStore, tokenStore)
+	principalIdentityStore := database.ProvidePrincipalIdentityStore(db)
+	signingKeyStore := database.ProvideSigningKeyStore(db)
 	provider, err := url.ProvideURLProvider(config)
 	if err != nil {
 		return nil, err
 	}
+	directory := ldap.ProvideDirectory(config)
+	controller, err := user.ProvideController(transactor, principalUID, authorizer, principalStore, tokenStore, membershipStore, spaceStore, principalIdentityStore, signingKeyStore, config, provider, directory)
+	if err != nil {
+		return nil, err
+	}
+	serviceController := service.NewController(principalUID, authorizer, principalStore)
+	bootstrapBootstrap := bootstrap.ProvideBootstrap(config, controller, serviceController)
+	authenticator := authn.ProvideAuthenticator(config, principalStore, tokenStore)
 	pathUID := check.ProvidePathUIDCheck()
 	repoStore := database.ProvideRepoStore(db, spacePathCache, spacePathStore)
 	pipelineStore := database.ProvidePipelineStore(db)
@@ -155,10 +169,16 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	}
 	jobStore := database.ProvideJobStore(db)
 	pubsubConfig := server.ProvidePubsubConfig(config)
-	pubSub := pubsub.ProvidePubSub(pubsubConfig, universalClient)
+	pubSub, err := pubsub.ProvidePubSub(pubsubConfig, universalClient, db)
+	if err != nil {
+		return nil, err
+	}
 	executor := job.ProvideExecutor(jobStore, pubSub)
 	lockConfig := server.ProvideLockConfig(config)
-	mutexManager := lock.ProvideMutexManager(lockConfig, universalClient)
+	mutexManager, err := lock.ProvideMutexManager(lockConfig, universalClient, db)
+	if err != nil {
+		return nil, err
+	}
 	jobConfig := server.ProvideJobsConfig(config)
 	jobScheduler, err := job.ProvideScheduler(jobStore, executor, mutexManager, pubSub, jobConfig)
 	if err != nil {
@@ -175,7 +195,7 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	usergroupResolver := usergroup.ProvideUserGroupResolver()
 	codeownersService := codeowners.ProvideCodeOwners(gitInterface, repoStore, codeownersConfig, principalStore, usergroupResolver)
 	eventsConfig := server.ProvideEventsConfig(config)
-	eventsSystem, err := events.ProvideSystem(eventsConfig, universalClient)
+	eventsSystem, err := events.ProvideSystem(eventsConfig, universalClient, db)
 	if err != nil {
 		return nil, err
 	}
@@ -187,45 +207,26 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	repoController := repo.ProvideController(config, transactor, provider, pathUID, authorizer, repoStore, spaceStore, pipelineStore, principalStore, ruleStore, principalInfoCache, protectionManager, gitInterface, repository, codeownersService, reporter, indexer, resourceLimiter)
-	executionStore := database.ProvideExecutionStore(db)
-	checkStore := database.ProvideCheckStore(db, principalInfoCache)
-	stageStore := database.ProvideStageStore(db)
-	schedulerScheduler, err := scheduler.ProvideScheduler(stageStore, mutexManager)
+	labelStore := database.ProvideLabelStore(db)
+	pullReqLabelAssignmentStore := database.ProvidePullReqLabelAssignmentStore(db)
+	labelService := label.ProvideLabelService(labelStore, pullReqLabelAssignmentStore, spaceStore)
+	blobConfig, err := server.ProvideBlobStoreConfig(config)
 	if err != nil {
 		return nil, err
 	}
-	stepStore := database.ProvideStepStore(db)
-	cancelerCanceler := canceler.ProvideCanceler(executionStore, streamer, repoStore, schedulerScheduler, stageStore, stepStore)
-	commitService := commit.ProvideService(gitInterface)
-	fileService := file.ProvideService(gitInterface)
-	converterService := converter.ProvideService(fileService)
-	templateStore := database.ProvideTemplateStore(db)
-	pluginStore := database.ProvidePluginStore(db)
-	triggererTriggerer := triggerer.ProvideTriggerer(executionStore, checkStore, stageStore, transactor, pipelineStore, fileService, converterService, schedulerScheduler, repoStore, provider, templateStore, pluginStore)
-	executionController := execution.ProvideController(transactor, authorizer, executionStore, checkStore, cancelerCanceler, commitService, triggererTriggerer, repoStore, stageStore, pipelineStore)
-	logStore := logs.ProvideLogStore(db, config)
-	logStream := livelog.ProvideLogStream()
-	logsController := logs2.ProvideController(authorizer, executionStore, repoStore, pipelineStore, stageStore, stepStore, logStore, logStream)
-	secretStore := database.ProvideSecretStore(db)
-	connectorStore := database.ProvideConnectorStore(db)
-	exporterRepository, err := exporter.ProvideSpaceExporter(provider, gitInterface, repoStore, jobScheduler, executor, encrypter, streamer)
+	blobStore, err := blob.ProvideStore(ctx, blobConfig)
 	if err != nil {
 		return nil, err
 	}
-	spaceController := space.ProvideController(config, transactor, provider, streamer, pathUID, authorizer, spacePathStore, pipelineStore, secretStore, connectorStore, templateStore, spaceStore, repoStore, principalStore, repoController, membershipStore, repository, exporterRepository, resourceLimiter)
-	pipelineController := pipeline.ProvideController(pathUID, repoStore, triggerStore, authorizer, pipelineStore)
-	secretController := secret.ProvideController(pathUID, encrypter, secretStore, authorizer, spaceStore)
-	triggerController := trigger.ProvideController(authorizer, triggerStore, pathUID, pipelineStore, repoStore)
-	connectorController := connector.ProvideController(pathUID, connectorStore, authorizer, spaceStore)
-	templateController := template.ProvideController(pathUID, templateStore, authorizer, spaceStore)
-	pluginController := plugin.ProvideController(pluginStore)
+	verifier := signing.ProvideVerifier(gitInterface, principalStore, signingKeyStore, config)
 	pullReqStore := database.ProvidePullReqStore(db, principalInfoCache)
 	pullReqActivityStore := database.ProvidePullReqActivityStore(db, principalInfoCache)
 	codeCommentView := database.ProvideCodeCommentView(db)
 	pullReqReviewStore := database.ProvidePullReqReviewStore(db)
 	pullReqReviewerStore := database.ProvidePullReqReviewerStore(db, principalInfoCache)
 	pullReqFileViewStore := database.ProvidePullReqFileViewStore(db)
+	checkStore := database.ProvideCheckStore(db, principalInfoCache)
+	pullReqReactionStore := database.ProvidePullReqReactionStore(db)
 	eventsReporter, err := events3.ProvideReporter(eventsSystem)
 	if err != nil {
 		return nil, err
@@ -245,7 +246,53 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	pullreqController := pullreq2.ProvideController(transactor, provider, authorizer, pullReqStore, pullReqActivityStore, codeCommentView, pullReqReviewStore, pullReqReviewerStore, repoStore, principalStore, pullReqFileViewStore, membershipStore, checkStore, gitInterface, eventsReporter, mutexManager, migrator, pullreqService, protectionManager, streamer, codeownersService)
+	pullreqController := pullreq2.ProvideController(transactor, provider, authorizer, pullReqStore, pullReqActivityStore, codeCommentView, pullReqReviewStore, pullReqReviewerStore, repoStore, principalStore, principalInfoCache, pullReqFileViewStore, membershipStore, checkStore, pullReqReactionStore, gitInterface, eventsReporter, mutexManager, migrator, pullreqService, protectionManager, streamer, codeownersService, labelService, verifier)
+	languagestatsConfig := server.ProvideLanguageStatsConfig(config)
+	repoLanguageStatsStore := database.ProvideRepoLanguageStatsStore(db)
+	languagestatsService, err := languagestats.ProvideService(ctx, languagestatsConfig, readerFactory, gitInterface, repoStore, repoLanguageStatsStore)
+	if err != nil {
+		return nil, err
+	}
+	housekeepingConfig := server.ProvideHousekeepingConfig(config)
+	repoHousekeepingStore := database.ProvideRepoHousekeepingStore(db)
+	housekeepingService, err := housekeeping.ProvideService(ctx, housekeepingConfig, readerFactory, gitInterface, repoStore, repoHousekeepingStore, mutexManager, jobScheduler, executor)
+	if err != nil {
+		return nil, err
+	}
+	repoController := repo.ProvideController(config, transactor, provider, pathUID, authorizer, repoStore, spaceStore, pipelineStore, principalStore, ruleStore, principalInfoCache, protectionManager, gitInterface, repository, codeownersService, reporter, indexer, resourceLimiter, labelService, blobStore, verifier, pullreqController, languagestatsService, housekeepingService)
+	executionStore := database.ProvideExecutionStore(db)
+	stageStore := database.ProvideStageStore(db)
+	schedulerScheduler, err := scheduler.ProvideScheduler(stageStore, mutexManager)
+	if err != nil {
+		return nil, err
+	}
+	stepStore := database.ProvideStepStore(db)
+	cancelerCanceler := canceler.ProvideCanceler(executionStore, streamer, repoStore, schedulerScheduler, stageStore, stepStore)
+	commitService := commit.ProvideService(gitInterface)
+	fileService := file.ProvideService(gitInterface)
+	converterService := converter.ProvideService(fileService)
+	templateStore := database.ProvideTemplateStore(db)
+	pluginStore := database.ProvidePluginStore(db)
+	triggererTriggerer := triggerer.ProvideTriggerer(executionStore, checkStore, stageStore, transactor, pipelineStore, fileService, converterService, schedulerScheduler, repoStore, provider, templateStore, pluginStore)
+	executionController := execution.ProvideController(transactor, authorizer, executionStore, checkStore, cancelerCanceler, commitService, triggererTriggerer, repoStore, stageStore, pipelineStore)
+	logStore := logs.ProvideLogStore(db, config)
+	logStream := livelog.ProvideLogStream()
+	logsController := logs2.ProvideController(authorizer, executionStore, repoStore, pipelineStore, stageStore, stepStore, logStore, logStream)
+	secretStore := database.ProvideSecretStore(db)
+	connectorStore := database.ProvideConnectorStore(db)
+	exporterRepository, err := exporter.ProvideSpaceExporter(provider, gitInterface, repoStore, jobScheduler, executor, encrypter, streamer)
+	if err != nil {
+		return nil, err
+	}
+	spaceEnvVarStore := database.ProvideSpaceEnvVarStore(db)
+	environService := environ.ProvideService(spaceStore, spaceEnvVarStore)
+	spaceController := space.ProvideController(config, transactor, provider, streamer, pathUID, authorizer, spacePathStore, pipelineStore, secretStore, connectorStore, templateStore, spaceStore, repoStore, principalStore, repoController, membershipStore, repository, exporterRepository, resourceLimiter, labelService, spaceEnvVarStore, environService)
+	pipelineController := pipeline.ProvideController(pathUID, repoStore, triggerStore, authorizer, pipelineStore)
+	secretController := secret.ProvideController(pathUID, encrypter, secretStore, authorizer, spaceStore)
+	triggerController := trigger.ProvideController(authorizer, triggerStore, pathUID, pipelineStore, repoStore)
+	connectorController := connector.ProvideController(pathUID, connectorStore, authorizer, spaceStore, repoStore)
+	templateController := template.ProvideController(pathUID, templateStore, authorizer, spaceStore)
+	pluginController := plugin.ProvideController(pluginStore)
 	webhookConfig := server.ProvideWebhookConfig(config)
 	webhookStore := database.ProvideWebhookStore(db)
 	webhookExecutionStore := database.ProvideWebhookExecutionStore(db)
@@ -258,29 +305,25 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	githookController := githook.ProvideController(authorizer, principalStore, repoStore, reporter2, gitInterface, pullReqStore, provider, protectionManager, clientFactory, resourceLimiter)
+	githookController := githook.ProvideController(authorizer, principalStore, repoStore, reporter2, gitInterface, pullReqStore, provider, protectionManager, clientFactory, resourceLimiter, verifier, housekeepingService)
 	serviceaccountController := serviceaccount.NewController(principalUID, authorizer, principalStore, spaceStore, repoStore, tokenStore)
 	principalController := principal.ProvideController(principalStore)
 	v := check2.ProvideCheckSanitizers()
 	checkController := check2.ProvideController(transactor, authorizer, repoStore, checkStore, gitInterface, v)
-	systemController := system.NewController(principalStore, config)
-	blobConfig, err := server.ProvideBlobStoreConfig(config)
-	if err != nil {
-		return nil, err
-	}
-	blobStore, err := blob.ProvideStore(ctx, blobConfig)
-	if err != nil {
-		return nil, err
-	}
+	systemController := system.NewController(principalStore, config, gitInterface)
 	uploadController := upload.ProvideController(authorizer, repoStore, blobStore)
 	searcher := keywordsearch.ProvideSearcher(localIndexSearcher)
 	keywordsearchController := keywordsearch2.ProvideController(authorizer, searcher, repoController, spaceController)
 	apiHandler := router.ProvideAPIHandler(ctx, config, authenticator, repoController, executionController, logsController, spaceController, pipelineController, secretController, triggerController, connectorController, templateController, pluginController, pullreqController, webhookController, githookController, serviceaccountController, controller, principalController, checkController, systemController, uploadController, keywordsearchController)
 	gitHandler := router.ProvideGitHandler(provider, authenticator, repoController)
 	webHandler := router.ProvideWebHandler(config)
-	routerRouter := router.ProvideRouter(apiHandler, gitHandler, webHandler, provider)
+	routerRouter := router.ProvideRouter(config, apiHandler, gitHandler, webHandler, provider)
 	serverServer := server2.ProvideServer(config, routerRouter)
-	executionManager := manager.ProvideExecutionManager(config, executionStore, pipelineStore, provider, streamer, fileService, converterService, logStore, logStream, checkStore, repoStore, schedulerScheduler, secretStore, stageStore, stepStore, principalStore)
+	secretService, err := secret2.ProvideService(config, spacePathStore)
+	if err != nil {
+		return nil, err
+	}
+	executionManager := manager.ProvideExecutionManager(config, executionStore, pipelineStore, provider, streamer, fileService, converterService, logStore, logStream, checkStore, connectorStore, repoStore, schedulerScheduler, secretStore, spaceStore, secretService, encrypter, environService, stageStore, stepStore, principalStore)
 	client := manager.ProvideExecutionClient(executionManager, provider, config)
 	resolverManager := resolver.ProvideResolver(config, pluginStore, templateStore, executionStore, repoStore)
 	runtimeRunner, err := runner.ProvideExecutionRunner(config, client, resolverManager)
@@ -309,7 +352,7 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	mailerMailer := mailer.ProvideMailClient(config)
 	notificationClient := notification.ProvideMailClient(mailerMailer)
 	notificationConfig := server.ProvideNotificationConfig(config)
-	notificationService, err := notification.ProvideNotificationService(ctx, notificationClient, notificationConfig, eventsReaderFactory, pullReqStore, repoStore, principalInfoView, principalInfoCache, pullReqReviewerStore, pullReqActivityStore, spacePathStore, provider)
+	notificationService, err := notification.ProvideNotificationService(ctx, notificationClient, notificationConfig, eventsReaderFactory, pullReqStore, repoStore, principalStore, principalInfoView, principalInfoCache, pullReqReviewerStore, pullReqActivityStore, spacePathStore, provider, authorizer)
 	if err != nil {
 		return nil, err
 	}
@@ -318,7 +361,11 @@ func initSystem(ctx context.Context, config *types.Config) (*server.System, erro
 	if err != nil {
 		return nil, err
 	}
-	servicesServices := services.ProvideServices(webhookService, pullreqService, triggerService, jobScheduler, collector, calculator, cleanupService, notificationService, keywordsearchService)
+	syncer, err := ldapsync.ProvideSyncer(config, directory, principalStore, principalIdentityStore, membershipStore, spaceStore, jobScheduler, executor)
+	if err != nil {
+		return nil, err
+	}
+	servicesServices := services.ProvideServices(webhookService, pullreqService, triggerService, jobScheduler, collector, calculator, cleanupService, notificationService, keywordsearchService, languagestatsService, housekeepingService, syncer)
 	serverSystem := server.NewSystem(bootstrapBootstrap, serverServer, poller, resolverManager, servicesServices)
 	return serverSystem, nil
 }
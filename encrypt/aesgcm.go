@@ -82,6 +82,15 @@ func (e *Aesgcm) Decrypt(ciphertext []byte) (string, error) {
 
 // New provides a new aesgcm encrypter.
 func New(key string, compat bool) (Encrypter, error) {
+	e, err := newAesgcm(key)
+	if err != nil {
+		return nil, err
+	}
+	e.Compat = compat
+	return e, nil
+}
+
+func newAesgcm(key string) (*Aesgcm, error) {
 	if len(key) != 32 {
 		return nil, errKeySize
 	}
@@ -90,5 +99,5 @@ func New(key string, compat bool) (Encrypter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Aesgcm{block: block, Compat: compat}, nil
+	return &Aesgcm{block: block}, nil
 }
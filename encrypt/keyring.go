@@ -0,0 +1,195 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// keyIDPrefix is the prefix of ciphertext encrypted by the keyring,
+// the full prefix has the format "$k1$<keyID>$".
+const keyIDPrefix = "$k1$"
+
+var keyIDRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]{1,32}$`)
+
+// Keyring is an Encrypter that supports multiple keys to allow for key rotation.
+// Data is always encrypted with the primary key and the ciphertext is prefixed with the ID of the key.
+// Data can be decrypted with any key of the keyring, including the legacy key,
+// which is used for ciphertext without a key ID prefix.
+type Keyring struct {
+	primaryID string
+	primary   *Aesgcm
+	keys      map[string]*Aesgcm
+	legacy    *Aesgcm
+	Compat    bool
+}
+
+// NewKeyring returns a new keyring encrypter. The keys are a map of key ID to key,
+// and the optional legacy key is used for decryption of ciphertext that has no key ID prefix.
+func NewKeyring(keys map[string]string, primaryID string, legacyKey string, compat bool) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keyring requires at least one key")
+	}
+
+	if primaryID == "" && len(keys) == 1 {
+		for id := range keys {
+			primaryID = id
+		}
+	}
+
+	k := &Keyring{
+		primaryID: primaryID,
+		keys:      make(map[string]*Aesgcm, len(keys)),
+		Compat:    compat,
+	}
+
+	for id, key := range keys {
+		if !keyIDRegex.MatchString(id) {
+			return nil, fmt.Errorf("invalid key id %q: only alphanumeric characters, '-' and '_' are allowed", id)
+		}
+
+		e, err := newAesgcm(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", id, err)
+		}
+
+		k.keys[id] = e
+	}
+
+	primary, ok := k.keys[primaryID]
+	if !ok {
+		return nil, fmt.Errorf("primary key %q isn't part of the keyring", primaryID)
+	}
+
+	k.primary = primary
+
+	if legacyKey != "" {
+		legacy, err := newAesgcm(legacyKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid legacy key: %w", err)
+		}
+
+		k.legacy = legacy
+	}
+
+	return k, nil
+}
+
+// ParseKeys parses a list of keys in the format "<keyID>:<key>".
+func ParseKeys(keys []string) (map[string]string, error) {
+	result := make(map[string]string, len(keys))
+	for i, entry := range keys {
+		id, key, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("key entry %d is not of the format '<keyID>:<key>'", i)
+		}
+
+		if _, exists := result[id]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", id)
+		}
+
+		result[id] = key
+	}
+
+	return result, nil
+}
+
+// PrimaryKeyID returns the ID of the key used for encryption.
+func (k *Keyring) PrimaryKeyID() string {
+	return k.primaryID
+}
+
+func (k *Keyring) Encrypt(plaintext string) ([]byte, error) {
+	ciphertext, err := k.primary.Encrypt(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := keyIDPrefix + k.primaryID + "$"
+
+	return append([]byte(prefix), ciphertext...), nil
+}
+
+func (k *Keyring) Decrypt(ciphertext []byte) (string, error) {
+	// ciphertext with a key ID prefix is never treated as plain text, not even in compatibility mode.
+	if keyID, data, ok := splitKeyID(ciphertext); ok {
+		e, ok := k.keys[keyID]
+		if !ok {
+			return "", fmt.Errorf("ciphertext is encrypted with unknown key %q", keyID)
+		}
+
+		plaintext, err := e.Decrypt(data)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt ciphertext with key %q: %w", keyID, err)
+		}
+
+		return plaintext, nil
+	}
+
+	// ciphertext without key ID prefix was encrypted before the keyring was introduced - try all known keys.
+	if k.legacy != nil {
+		if plaintext, err := k.legacy.Decrypt(ciphertext); err == nil {
+			return plaintext, nil
+		}
+	}
+
+	for _, e := range k.keys {
+		if plaintext, err := e.Decrypt(ciphertext); err == nil {
+			return plaintext, nil
+		}
+	}
+
+	// if the decryption utility is running in compatibility
+	// mode, it will return the ciphertext as plain text if
+	// decryption fails. This should be used when running the
+	// database in mixed-mode, where there is a mix of encrypted
+	// and unencrypted content.
+	if k.Compat {
+		return string(ciphertext), nil
+	}
+
+	return "", errors.New("failed to decrypt ciphertext with any known key")
+}
+
+// KeyID returns the ID of the key the ciphertext was encrypted with,
+// or an empty string in case the ciphertext doesn't have a key ID prefix.
+func KeyID(ciphertext []byte) string {
+	keyID, _, _ := splitKeyID(ciphertext)
+	return keyID
+}
+
+func splitKeyID(ciphertext []byte) (string, []byte, bool) {
+	if !bytes.HasPrefix(ciphertext, []byte(keyIDPrefix)) {
+		return "", nil, false
+	}
+
+	rest := ciphertext[len(keyIDPrefix):]
+
+	idx := bytes.IndexByte(rest, '$')
+	if idx < 0 {
+		return "", nil, false
+	}
+
+	keyID := string(rest[:idx])
+	if !keyIDRegex.MatchString(keyID) {
+		return "", nil, false
+	}
+
+	return keyID, rest[idx+1:], true
+}
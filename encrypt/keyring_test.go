@@ -0,0 +1,179 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encrypt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKey1      = "11111111111111111111111111111111"
+	testKey2      = "22222222222222222222222222222222"
+	testLegacyKey = "00000000000000000000000000000000"
+)
+
+func TestKeyring_RoundTrip(t *testing.T) {
+	k, err := NewKeyring(map[string]string{"k1": testKey1, "k2": testKey2}, "k2", "", false)
+	require.NoError(t, err)
+
+	ciphertext, err := k.Encrypt("secret")
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(ciphertext, []byte("$k1$k2$")))
+	require.Equal(t, "k2", KeyID(ciphertext))
+
+	plaintext, err := k.Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "secret", plaintext)
+
+	// data encrypted with a previous primary key can still be decrypted.
+	rotated, err := NewKeyring(map[string]string{"k1": testKey1, "k2": testKey2}, "k1", "", false)
+	require.NoError(t, err)
+
+	plaintext, err = rotated.Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "secret", plaintext)
+}
+
+func TestKeyring_SingleKeyIsPrimary(t *testing.T) {
+	k, err := NewKeyring(map[string]string{"k1": testKey1}, "", "", false)
+	require.NoError(t, err)
+	require.Equal(t, "k1", k.PrimaryKeyID())
+}
+
+func TestKeyring_Legacy(t *testing.T) {
+	legacy, err := New(testLegacyKey, false)
+	require.NoError(t, err)
+
+	ciphertext, err := legacy.Encrypt("secret")
+	require.NoError(t, err)
+	require.Equal(t, "", KeyID(ciphertext))
+
+	k, err := NewKeyring(map[string]string{"k1": testKey1}, "k1", testLegacyKey, false)
+	require.NoError(t, err)
+
+	plaintext, err := k.Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "secret", plaintext)
+
+	// unprefixed data can also be decrypted with a key of the keyring.
+	k, err = NewKeyring(map[string]string{"k1": testKey1, "legacy": testLegacyKey}, "k1", "", false)
+	require.NoError(t, err)
+
+	plaintext, err = k.Decrypt(ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "secret", plaintext)
+}
+
+func TestKeyring_UnknownKeyID(t *testing.T) {
+	other, err := NewKeyring(map[string]string{"other": testKey2}, "other", "", false)
+	require.NoError(t, err)
+
+	ciphertext, err := other.Encrypt("secret")
+	require.NoError(t, err)
+
+	k, err := NewKeyring(map[string]string{"k1": testKey1}, "k1", testLegacyKey, false)
+	require.NoError(t, err)
+
+	_, err = k.Decrypt(ciphertext)
+	require.Error(t, err)
+
+	// data with a key ID prefix is never returned as plain text, not even in compatibility mode.
+	k.Compat = true
+
+	_, err = k.Decrypt(ciphertext)
+	require.Error(t, err)
+}
+
+func TestKeyring_Compat(t *testing.T) {
+	k, err := NewKeyring(map[string]string{"k1": testKey1}, "k1", testLegacyKey, true)
+	require.NoError(t, err)
+
+	// in compatibility mode, unprefixed data that can't be decrypted is returned as is.
+	plaintext, err := k.Decrypt([]byte("plain value"))
+	require.NoError(t, err)
+	require.Equal(t, "plain value", plaintext)
+
+	ciphertext, err := k.Encrypt("secret")
+	require.NoError(t, err)
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 0xff
+
+	_, err = k.Decrypt(tampered)
+	require.Error(t, err)
+}
+
+func TestKeyring_TamperedCiphertext(t *testing.T) {
+	k, err := NewKeyring(map[string]string{"k1": testKey1}, "k1", testLegacyKey, false)
+	require.NoError(t, err)
+
+	ciphertext, err := k.Encrypt("secret")
+	require.NoError(t, err)
+
+	tampered := bytes.Clone(ciphertext)
+	tampered[len(tampered)-1] ^= 0xff
+
+	_, err = k.Decrypt(tampered)
+	require.Error(t, err)
+
+	// a modified key id doesn't allow decryption with a different key.
+	k2, err := NewKeyring(map[string]string{"k1": testKey1, "k2": testKey2}, "k1", "", false)
+	require.NoError(t, err)
+
+	_, err = k2.Decrypt(bytes.Replace(ciphertext, []byte("$k1$k1$"), []byte("$k1$k2$"), 1))
+	require.Error(t, err)
+
+	// truncated data
+	_, err = k.Decrypt([]byte("$k1$k1$abc"))
+	require.Error(t, err)
+}
+
+func TestNewKeyring_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		keys      map[string]string
+		primaryID string
+		legacyKey string
+	}{
+		{name: "no keys", keys: nil},
+		{name: "invalid key id", keys: map[string]string{"k$1": testKey1}, primaryID: "k$1"},
+		{name: "invalid key size", keys: map[string]string{"k1": "short"}, primaryID: "k1"},
+		{name: "unknown primary key", keys: map[string]string{"k1": testKey1}, primaryID: "k2"},
+		{name: "no primary key", keys: map[string]string{"k1": testKey1, "k2": testKey2}},
+		{name: "invalid legacy key", keys: map[string]string{"k1": testKey1}, primaryID: "k1", legacyKey: "short"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewKeyring(test.keys, test.primaryID, test.legacyKey, false)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys([]string{"k1:" + testKey1, "k2:" + testKey2})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"k1": testKey1, "k2": testKey2}, keys)
+
+	_, err = ParseKeys([]string{testKey1})
+	require.Error(t, err)
+
+	_, err = ParseKeys([]string{"k1:" + testKey1, "k1:" + testKey2})
+	require.Error(t, err)
+}
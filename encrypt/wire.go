@@ -15,6 +15,8 @@
 package encrypt
 
 import (
+	"fmt"
+
 	"github.com/harness/gitness/types"
 
 	"github.com/google/wire"
@@ -26,6 +28,16 @@ var WireSet = wire.NewSet(
 )
 
 func ProvideEncrypter(config *types.Config) (Encrypter, error) {
+	if len(config.Encrypter.Keys) > 0 {
+		keys, err := ParseKeys(config.Encrypter.Keys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse encryption keys: %w", err)
+		}
+
+		return NewKeyring(keys, config.Encrypter.PrimaryKeyID,
+			config.Encrypter.Secret, config.Encrypter.MixedContent)
+	}
+
 	if config.Encrypter.Secret == "" {
 		return &none{}, nil
 	}
@@ -45,6 +45,10 @@ type Mode string
 const (
 	ModeRedis    Mode = "redis"
 	ModeInMemory Mode = "inmemory"
+	ModePostgres Mode = "postgres"
+	// ModeDatabase stores the event streams in the primary database (sqlite or postgres).
+	// It's a durable alternative to ModeInMemory for single node installations.
+	ModeDatabase Mode = "database"
 )
 
 // Config defines the config of the events system.
@@ -59,7 +63,7 @@ func (c *Config) Validate() error {
 	if c == nil {
 		return errors.New("config is required")
 	}
-	if c.Mode != ModeRedis && c.Mode != ModeInMemory {
+	if c.Mode != ModeRedis && c.Mode != ModeInMemory && c.Mode != ModePostgres && c.Mode != ModeDatabase {
 		return fmt.Errorf("config.Mode '%s' is not supported", c.Mode)
 	}
 	if c.MaxStreamLength < 1 {
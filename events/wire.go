@@ -22,6 +22,7 @@ import (
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/wire"
+	"github.com/jmoiron/sqlx"
 )
 
 // WireSet provides a wire set for this package.
@@ -29,7 +30,7 @@ var WireSet = wire.NewSet(
 	ProvideSystem,
 )
 
-func ProvideSystem(config Config, redisClient redis.UniversalClient) (*System, error) {
+func ProvideSystem(config Config, redisClient redis.UniversalClient, db *sqlx.DB) (*System, error) {
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("provided config is invalid: %w", err)
 	}
@@ -41,6 +42,10 @@ func ProvideSystem(config Config, redisClient redis.UniversalClient) (*System, e
 		system, err = provideSystemInMemory(config)
 	case ModeRedis:
 		system, err = provideSystemRedis(config, redisClient)
+	case ModePostgres:
+		system, err = provideSystemPostgres(config, db)
+	case ModeDatabase:
+		system, err = provideSystemDatabase(config, db)
 	default:
 		return nil, fmt.Errorf("events system mode '%s' is not supported", config.Mode)
 	}
@@ -76,6 +81,26 @@ func provideSystemRedis(config Config, redisClient redis.UniversalClient) (*Syst
 	)
 }
 
+func provideSystemPostgres(config Config, db *sqlx.DB) (*System, error) {
+	if db != nil && db.DriverName() != "postgres" {
+		return nil, fmt.Errorf("database driver '%s' isn't supported, postgres is required", db.DriverName())
+	}
+
+	return provideSystemDatabase(config, db)
+}
+
+func provideSystemDatabase(config Config, db *sqlx.DB) (*System, error) {
+	if db == nil {
+		return nil, errors.New("database connection required")
+	}
+
+	return NewSystem(
+		newDatabaseStreamConsumerFactoryMethod(db, config.Namespace),
+		newDatabaseStreamProducer(db, config.Namespace,
+			config.MaxStreamLength, config.ApproxMaxStreamLength),
+	)
+}
+
 func newMemoryStreamConsumerFactoryMethod(broker *stream.MemoryBroker, namespace string) StreamConsumerFactoryFunc {
 	return func(groupName string, consumerName string) (StreamConsumer, error) {
 		return stream.NewMemoryConsumer(broker, namespace, groupName)
@@ -99,3 +124,14 @@ func newRedisStreamProducer(redisClient redis.UniversalClient, namespace string,
 	maxStreamLength int64, approxMaxStreamLength bool) StreamProducer {
 	return stream.NewRedisProducer(redisClient, namespace, maxStreamLength, approxMaxStreamLength)
 }
+
+func newDatabaseStreamConsumerFactoryMethod(db *sqlx.DB, namespace string) StreamConsumerFactoryFunc {
+	return func(groupName string, consumerName string) (StreamConsumer, error) {
+		return stream.NewDatabaseConsumer(db, namespace, groupName, consumerName)
+	}
+}
+
+func newDatabaseStreamProducer(db *sqlx.DB, namespace string,
+	maxStreamLength int64, approxMaxStreamLength bool) StreamProducer {
+	return stream.NewDatabaseProducer(db, namespace, maxStreamLength, approxMaxStreamLength)
+}
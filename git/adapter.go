@@ -17,6 +17,7 @@ package git
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/harness/gitness/git/adapter"
 	"github.com/harness/gitness/git/enum"
@@ -34,6 +35,10 @@ type Adapter interface {
 	SharedRepository(tmp string, repoUID string, remotePath string) (*adapter.SharedRepo, error)
 	Config(ctx context.Context, repoPath, key, value string) error
 	CountObjects(ctx context.Context, repoPath string) (types.ObjectCount, error)
+	RepackObjects(ctx context.Context, repoPath string) error
+	PruneObjects(ctx context.Context, repoPath string, expiry time.Duration) error
+	PackRefs(ctx context.Context, repoPath string) error
+	WriteCommitGraph(ctx context.Context, repoPath string) error
 
 	SetDefaultBranch(ctx context.Context, repoPath string,
 		defaultBranch string, allowEmpty bool) error
@@ -47,19 +52,26 @@ type Adapter interface {
 	Commit(ctx context.Context, repoPath string, opts types.CommitChangesOptions) error
 	Push(ctx context.Context, repoPath string, opts types.PushOptions) error
 	ReadTree(ctx context.Context, repoPath, ref string, w io.Writer, args ...string) error
+	Archive(ctx context.Context, repoPath string, treeish string, format enum.ArchiveFormat,
+		prefix string, paths []string, w io.Writer) error
 	GetTreeNode(ctx context.Context, repoPath string, ref string, treePath string) (*types.TreeNode, error)
 	ListTreeNodes(ctx context.Context, repoPath string, ref string, treePath string) ([]types.TreeNode, error)
 	PathsDetails(ctx context.Context, repoPath string, ref string, paths []string) ([]types.PathDetails, error)
 	GetSubmodule(ctx context.Context, repoPath string, ref string, treePath string) (*types.Submodule, error)
 	GetBlob(ctx context.Context, repoPath string, sha string, sizeLimit int64) (*types.BlobReader, error)
+	GetLanguageStats(ctx context.Context, repoPath string, sha string) (map[string]int64, error)
 	WalkReferences(ctx context.Context, repoPath string, handler types.WalkReferencesHandler,
 		opts *types.WalkReferencesOptions) error
 	GetCommit(ctx context.Context, repoPath string, ref string) (*types.Commit, error)
 	GetCommits(ctx context.Context, repoPath string, refs []string) ([]types.Commit, error)
 	ListCommits(ctx context.Context, repoPath string,
 		ref string, page int, limit int, filter types.CommitFilter) ([]types.Commit, []types.PathRenameDetails, error)
+	ListCommitsFollowRenames(ctx context.Context, repoPath string,
+		ref string, page int, limit int, filter types.CommitFilter) ([]types.Commit, []string, error)
 	ListCommitSHAs(ctx context.Context, repoPath string,
 		ref string, page int, limit int, filter types.CommitFilter) ([]string, error)
+	ListCommitActivity(ctx context.Context, repoPath string,
+		ref string, filter types.CommitFilter) ([]types.CommitActivity, error)
 	GetLatestCommit(ctx context.Context, repoPath string, ref string, treePath string) (*types.Commit, error)
 	GetFullCommitID(ctx context.Context, repoPath, shortID string) (string, error)
 	GetAnnotatedTag(ctx context.Context, repoPath string, sha string) (*types.Tag, error)
@@ -78,6 +90,10 @@ type Adapter interface {
 	IsAncestor(ctx context.Context, repoPath, ancestorCommitSHA, descendantCommitSHA string) (bool, error)
 	Blame(ctx context.Context, repoPath, rev, file string, lineFrom, lineTo int) types.BlameReader
 	Sync(ctx context.Context, repoPath string, source string, refSpecs []string) error
+	ListPushedCommits(ctx context.Context, repoPath string, oldSHA, newSHA string,
+		alternateObjectDirs []string) ([]types.Commit, error)
+	VerifySignature(signature *types.ObjectSignature, keys []types.SigningKey) types.SignatureVerification
+	SigningKey() *types.SigningKey
 
 	//
 	// Diff operations
@@ -93,11 +109,13 @@ type Adapter interface {
 		base,
 		head string,
 		mergeBase bool,
+		opts types.DiffOptions,
 		w io.Writer) error
 
 	CommitDiff(ctx context.Context,
 		repoPath,
 		sha string,
+		opts types.DiffOptions,
 		w io.Writer) error
 
 	DiffShortStat(ctx context.Context,
@@ -16,6 +16,7 @@ package adapter
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/harness/gitness/cache"
 	"github.com/harness/gitness/git/hook"
@@ -29,6 +30,7 @@ type Adapter struct {
 	traceGit        bool
 	lastCommitCache cache.Cache[CommitEntryKey, *types.Commit]
 	githookFactory  hook.ClientFactory
+	signer          *objectSigner
 }
 
 func New(
@@ -44,9 +46,18 @@ func New(
 		return Adapter{}, err
 	}
 
+	var signer *objectSigner
+	if config.SigningKey.Path != "" {
+		signer, err = newObjectSigner(config.SigningKey.Path, config.SigningKey.Passphrase)
+		if err != nil {
+			return Adapter{}, fmt.Errorf("failed to load commit signing key: %w", err)
+		}
+	}
+
 	return Adapter{
 		traceGit:        config.Trace,
 		lastCommitCache: lastCommitCache,
 		githookFactory:  githookFactory,
+		signer:          signer,
 	}, nil
 }
@@ -79,6 +79,26 @@ func TestParseTagDataFromCatFile(t *testing.T) {
 		types.Signature{Identity: types.Identity{Name: "max", Email: "max@mail.com"}, When: when},
 		"gpgsig -----BEGIN PGP SIGNATURE-----\n\nw...B\n-----END PGP SIGNATURE-----\n\nsome message",
 		"some message")
+
+	// test with trailing ssh signature
+	testParseTagDataFromCatFileFor(t, "sha012", types.GitObjectTypeCommit, "name3",
+		types.Signature{Identity: types.Identity{Name: "max", Email: "max@mail.com"}, When: when},
+		"\nsome message\n-----BEGIN SSH SIGNATURE-----\nU1NI...\n-----END SSH SIGNATURE-----\n",
+		"some message")
+}
+
+func TestParseTagDataFromCatFile_Signature(t *testing.T) {
+	data := "object sha012\ntype commit\ntag name\ntagger max <max@mail.com> 1666401234 -0700\n\n" +
+		"some message\n"
+	signature := "-----BEGIN PGP SIGNATURE-----\n\nw...B\n-----END PGP SIGNATURE-----\n"
+
+	res, err := parseTagDataFromCatFile([]byte(data + signature))
+	require.NoError(t, err)
+
+	require.Equal(t, "some message", res.Message)
+	require.NotNil(t, res.Signature)
+	require.Equal(t, signature, res.Signature.Signature)
+	require.Equal(t, data, res.Signature.Payload)
 }
 
 func testParseTagDataFromCatFileFor(t *testing.T, object string, typ types.GitObjectType, name string,
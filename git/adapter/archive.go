@@ -0,0 +1,79 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git/enum"
+
+	"code.gitea.io/gitea/modules/git"
+)
+
+// Archive streams the archive of the provided tree-ish in the requested format to the writer.
+// If paths are provided, only files under those paths are included in the archive.
+// A non-empty prefix is prepended to every file path in the archive.
+func (a Adapter) Archive(
+	ctx context.Context,
+	repoPath string,
+	treeish string,
+	format enum.ArchiveFormat,
+	prefix string,
+	paths []string,
+	w io.Writer,
+) error {
+	defer observeOperation("archive")()
+
+	if repoPath == "" {
+		return ErrRepositoryPathEmpty
+	}
+	if treeish == "" {
+		return errors.InvalidArgument("tree-ish cannot be empty")
+	}
+	if _, ok := format.Sanitize(); !ok {
+		return errors.InvalidArgument("unsupported archive format '%s'", format)
+	}
+
+	args := make([]string, 0, 6+len(paths))
+	args = append(args, "archive", "--format="+string(format))
+	if prefix != "" {
+		args = append(args, "--prefix="+prefix)
+	}
+	args = append(args, treeish)
+	if len(paths) > 0 {
+		args = append(args, "--")
+		args = append(args, paths...)
+	}
+
+	cmd := git.NewCommand(ctx, args...)
+	cmd.SetDescription(fmt.Sprintf("Archive [repo_path: %s]", repoPath))
+	errbuf := bytes.Buffer{}
+	if err := cmd.Run(&git.RunOpts{
+		Dir:    repoPath,
+		Stdout: w,
+		Stderr: &errbuf,
+	}); err != nil {
+		if errbuf.Len() > 0 {
+			err = &runStdError{err: err, stderr: errbuf.String()}
+		}
+		return processGiteaErrorf(err, "git archive of '%s' failed", treeish)
+	}
+
+	return nil
+}
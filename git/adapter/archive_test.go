@@ -0,0 +1,123 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/harness/gitness/git/enum"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestAdapter_Archive(t *testing.T) {
+	git := setupGit(t)
+	repo, teardown := setupRepo(t, git, "testarchive")
+	defer teardown()
+
+	sha := writeFile(t, repo, "file.txt", "some content", nil)
+	sha = writeFile(t, repo, "docs/readme.md", "readme", []string{sha.String()})
+
+	tests := []struct {
+		name   string
+		format enum.ArchiveFormat
+		prefix string
+		paths  []string
+		want   []string
+	}{
+		{
+			name:   "tar.gz",
+			format: enum.ArchiveFormatTarGz,
+			want:   []string{"docs/", "docs/readme.md", "file.txt"},
+		},
+		{
+			name:   "zip with prefix",
+			format: enum.ArchiveFormatZip,
+			prefix: "repo/",
+			want:   []string{"repo/", "repo/docs/", "repo/docs/readme.md", "repo/file.txt"},
+		},
+		{
+			name:   "tar with path",
+			format: enum.ArchiveFormatTar,
+			paths:  []string{"docs"},
+			want:   []string{"docs/", "docs/readme.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			err := git.Archive(context.Background(), repo.Path, sha.String(), tt.format, tt.prefix, tt.paths, buf)
+			if err != nil {
+				t.Fatalf("Archive() error = %v", err)
+			}
+
+			got := archiveFileNames(t, tt.format, buf.Bytes())
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Archive() files = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func archiveFileNames(t *testing.T, format enum.ArchiveFormat, data []byte) []string {
+	t.Helper()
+
+	var names []string
+
+	if format == enum.ArchiveFormatZip {
+		r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatalf("failed to open zip archive: %v", err)
+		}
+		for _, f := range r.File {
+			names = append(names, f.Name)
+		}
+		return names
+	}
+
+	var r io.Reader = bytes.NewReader(data)
+	if format == enum.ArchiveFormatTarGz {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatalf("failed to open gzip archive: %v", err)
+		}
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to read tar archive: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeXGlobalHeader {
+			continue
+		}
+		names = append(names, hdr.Name)
+	}
+
+	return names
+}
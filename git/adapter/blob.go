@@ -32,6 +32,8 @@ func (a Adapter) GetBlob(
 	sha string,
 	sizeLimit int64,
 ) (*types.BlobReader, error) {
+	defer observeOperation("get_blob")()
+
 	stdIn, stdOut, cancel := git.CatFileBatch(ctx, repoPath)
 
 	_, err := stdIn.Write([]byte(sha + "\n"))
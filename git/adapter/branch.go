@@ -30,6 +30,8 @@ func (a Adapter) GetBranch(
 	repoPath string,
 	branchName string,
 ) (*types.Branch, error) {
+	defer observeOperation("get_branch")()
+
 	if repoPath == "" {
 		return nil, ErrRepositoryPathEmpty
 	}
@@ -15,9 +15,11 @@
 package adapter
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 	"time"
@@ -35,6 +37,8 @@ func (a Adapter) GetLatestCommit(
 	rev string,
 	treePath string,
 ) (*types.Commit, error) {
+	defer observeOperation("get_latest_commit")()
+
 	if repoPath == "" {
 		return nil, ErrRepositoryPathEmpty
 	}
@@ -82,10 +86,6 @@ func (a Adapter) listCommitSHAs(
 	// add refCommitSHA as starting point
 	args = append(args, ref)
 
-	if len(filter.Path) != 0 {
-		args = append(args, "--", filter.Path)
-	}
-
 	// add pagination if requested
 	// TODO: we should add absolut limits to protect git (return error)
 	if limit > 0 {
@@ -109,6 +109,11 @@ func (a Adapter) listCommitSHAs(
 		args = append(args, "--committer", filter.Committer)
 	}
 
+	// the path has to be the last argument, everything after "--" is treated as a path
+	if len(filter.Path) != 0 {
+		args = append(args, "--", filter.Path)
+	}
+
 	stdout, _, runErr := gitea.NewCommand(ctx, args...).RunStdBytes(&gitea.RunOpts{Dir: repoPath})
 	if runErr != nil {
 		// TODO: handle error in case they don't have a common merge base!
@@ -129,6 +134,8 @@ func (a Adapter) ListCommitSHAs(
 	limit int,
 	filter types.CommitFilter,
 ) ([]string, error) {
+	defer observeOperation("list_commit_shas")()
+
 	return a.listCommitSHAs(ctx, repoPath, ref, page, limit, filter)
 }
 
@@ -142,6 +149,8 @@ func (a Adapter) ListCommits(ctx context.Context,
 	limit int,
 	filter types.CommitFilter,
 ) ([]types.Commit, []types.PathRenameDetails, error) {
+	defer observeOperation("list_commits")()
+
 	if repoPath == "" {
 		return nil, nil, ErrRepositoryPathEmpty
 	}
@@ -183,6 +192,176 @@ func (a Adapter) ListCommits(ctx context.Context,
 	return commits, nil, nil
 }
 
+// ListCommitsFollowRenames lists the commits reachable from ref that changed the file at filter.Path,
+// following the history of the file beyond renames.
+// Besides the commits, it returns the path the file had at each of the returned commits.
+// Note: ref & afterRef can be Branch / Tag / CommitSHA.
+// Note: git log --skip doesn't take --follow into account, hence the pagination is done while parsing the output.
+func (a Adapter) ListCommitsFollowRenames(ctx context.Context,
+	repoPath string,
+	ref string,
+	page int,
+	limit int,
+	filter types.CommitFilter,
+) ([]types.Commit, []string, error) {
+	defer observeOperation("list_commits_follow_renames")()
+
+	if repoPath == "" {
+		return nil, nil, ErrRepositoryPathEmpty
+	}
+	if filter.Path == "" {
+		return nil, nil, errors.InvalidArgument("path is required to follow renames")
+	}
+
+	giteaRepo, err := gitea.OpenRepository(ctx, repoPath)
+	if err != nil {
+		return nil, nil, processGiteaErrorf(err, "failed to open repository")
+	}
+	defer giteaRepo.Close()
+
+	args := make([]string, 0, 16)
+	args = append(args, "log", "--follow", "--name-status", "-z", "--format="+logCommitMarker+"%H")
+
+	if filter.Since > 0 || filter.Until > 0 {
+		args = append(args, "--date", "unix")
+	}
+	if filter.Since > 0 {
+		args = append(args, "--since", strconv.FormatInt(filter.Since, 10))
+	}
+	if filter.Until > 0 {
+		args = append(args, "--until", strconv.FormatInt(filter.Until, 10))
+	}
+	if filter.Committer != "" {
+		args = append(args, "--committer", filter.Committer)
+	}
+
+	if filter.AfterRef != "" {
+		args = append(args, "^"+filter.AfterRef)
+	}
+	args = append(args, ref, "--", filter.Path)
+
+	skip, count := 0, -1
+	if limit > 0 {
+		count = limit
+		if page > 1 {
+			skip = (page - 1) * limit
+		}
+	}
+
+	// the git command is canceled as soon as the requested page is read.
+	cmdCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	pipeOut, pipeIn := io.Pipe()
+	defer pipeOut.Close()
+
+	stderr := strings.Builder{}
+	go func() {
+		err := gitea.NewCommand(cmdCtx, args...).Run(&gitea.RunOpts{
+			Dir:    repoPath,
+			Stdout: pipeIn,
+			Stderr: &stderr,
+		})
+		if err != nil && cmdCtx.Err() == nil {
+			_ = pipeIn.CloseWithError(gitea.ConcatenateError(err, stderr.String()))
+		} else {
+			_ = pipeIn.Close()
+		}
+	}()
+
+	commitSHAs, paths, err := parseFollowRenamesLog(pipeOut, filter.Path, skip, count)
+	if err != nil {
+		return nil, nil, processGiteaErrorf(err, "failed to list commits following renames")
+	}
+
+	cancel()
+
+	giteaCommits, err := getGiteaCommits(giteaRepo, commitSHAs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	commits := make([]types.Commit, len(giteaCommits))
+	for i := range giteaCommits {
+		var commit *types.Commit
+		commit, err = mapGiteaCommit(giteaCommits[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		commits[i] = *commit
+	}
+
+	return commits, paths, nil
+}
+
+// parseFollowRenamesLog parses the output of git log --follow --name-status -z
+// and returns the commit SHAs and the path the file had at each of the commits.
+// The first skip commits are skipped and at most count commits are returned (all if count is negative).
+func parseFollowRenamesLog(r io.Reader, path string, skip, count int) ([]string, []string, error) {
+	shas := make([]string, 0)
+	paths := make([]string, 0)
+
+	// the path of the file at the commit that is currently being parsed.
+	current := path
+	index := -1
+
+	// the output is a sequence of NUL terminated fields: the commit header followed by the status of the file,
+	// which is <status>\0<path>\0 or <status>\0<old path>\0<new path>\0 for renames and copies.
+	// Paths are taken as they are, as they can contain any character (except NUL).
+	scanner := bufio.NewScanner(r)
+	scanner.Split(scanNullTerminatedStrings)
+	for scanner.Scan() {
+		// the diff output is separated from the commit header by a new line.
+		field := strings.TrimPrefix(scanner.Text(), "\n")
+		if field == "" {
+			continue
+		}
+
+		if sha, ok := strings.CutPrefix(field, logCommitMarker); ok {
+			index++
+			if count >= 0 && index >= skip+count {
+				break
+			}
+			if index >= skip {
+				shas = append(shas, sha)
+				paths = append(paths, current)
+			}
+			continue
+		}
+
+		fieldPaths := make([]string, 1, 2)
+		if field[0] == 'R' || field[0] == 'C' {
+			fieldPaths = fieldPaths[:2]
+		}
+		for i := range fieldPaths {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return nil, nil, err
+				}
+				return nil, nil, fmt.Errorf("missing path of file status '%s'", field)
+			}
+			fieldPaths[i] = scanner.Text()
+		}
+
+		if index < 0 {
+			continue
+		}
+
+		if index >= skip {
+			paths[len(paths)-1] = fieldPaths[len(fieldPaths)-1]
+		}
+
+		// the older commits have the file under the old path
+		current = fieldPaths[0]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return shas, paths, nil
+}
+
 // In case of rename of a file, same commit will be listed twice - Once in old file and second time in new file.
 // Hence, we are making it a pattern to only list it as part of new file and not as part of old file.
 func cleanupCommitsForRename(
@@ -288,11 +467,23 @@ func (a Adapter) GetCommit(
 	repoPath string,
 	rev string,
 ) (*types.Commit, error) {
+	defer observeOperation("get_commit")()
+
 	if repoPath == "" {
 		return nil, ErrRepositoryPathEmpty
 	}
 
-	return getCommit(ctx, repoPath, rev, "")
+	commit, err := getCommit(ctx, repoPath, rev, "")
+	if err != nil {
+		return nil, err
+	}
+
+	commit.Signature, err = getCommitSignature(ctx, repoPath, commit.SHA)
+	if err != nil {
+		return nil, err
+	}
+
+	return commit, nil
 }
 
 func (a Adapter) GetFullCommitID(
@@ -313,6 +504,8 @@ func (a Adapter) GetCommits(
 	repoPath string,
 	refs []string,
 ) ([]types.Commit, error) {
+	defer observeOperation("get_commits")()
+
 	if repoPath == "" {
 		return nil, ErrRepositoryPathEmpty
 	}
@@ -350,6 +543,8 @@ func (a Adapter) GetCommitDivergences(
 	requests []types.CommitDivergenceRequest,
 	max int32,
 ) ([]types.CommitDivergence, error) {
+	defer observeOperation("get_commit_divergences")()
+
 	if repoPath == "" {
 		return nil, ErrRepositoryPathEmpty
 	}
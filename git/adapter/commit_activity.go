@@ -0,0 +1,167 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/git/types"
+
+	gitea "code.gitea.io/gitea/modules/git"
+)
+
+const (
+	// logCommitMarker marks the header line of every commit in the output of git log.
+	logCommitMarker      = "\x1e"
+	commitActivityFormat = "--format=%x1e%H%x00%aN%x00%aE%x00%at"
+)
+
+// ListCommitActivity returns the author and the number of added and deleted lines
+// of every commit reachable from ref, newest commit first.
+// Note: ref & afterRef can be Branch / Tag / CommitSHA.
+// Note: merge commits are reported without any changed lines.
+func (a Adapter) ListCommitActivity(
+	ctx context.Context,
+	repoPath string,
+	ref string,
+	filter types.CommitFilter,
+) ([]types.CommitActivity, error) {
+	defer observeOperation("list_commit_activity")()
+
+	if repoPath == "" {
+		return nil, ErrRepositoryPathEmpty
+	}
+
+	args := []string{"log", "--numstat", commitActivityFormat}
+	if filter.Since > 0 || filter.Until > 0 {
+		args = append(args, "--date", "unix")
+	}
+	if filter.Since > 0 {
+		args = append(args, "--since", strconv.FormatInt(filter.Since, 10))
+	}
+	if filter.Until > 0 {
+		args = append(args, "--until", strconv.FormatInt(filter.Until, 10))
+	}
+	if filter.Committer != "" {
+		args = append(args, "--committer", filter.Committer)
+	}
+	if filter.AfterRef != "" {
+		args = append(args, "^"+filter.AfterRef)
+	}
+	args = append(args, ref)
+	if filter.Path != "" {
+		args = append(args, "--", filter.Path)
+	}
+
+	pipeOut, pipeIn := io.Pipe()
+	defer pipeOut.Close()
+
+	stderr := strings.Builder{}
+	go func() {
+		err := gitea.NewCommand(ctx, args...).Run(&gitea.RunOpts{
+			Dir:    repoPath,
+			Stdout: pipeIn,
+			Stderr: &stderr,
+		})
+		if err != nil {
+			_ = pipeIn.CloseWithError(gitea.ConcatenateError(err, stderr.String()))
+		} else {
+			_ = pipeIn.Close()
+		}
+	}()
+
+	activities, err := parseCommitActivity(pipeOut)
+	if err != nil {
+		return nil, processGiteaErrorf(err, "failed to list commit activity")
+	}
+
+	return activities, nil
+}
+
+// parseCommitActivity parses the output of git log with the commitActivityFormat and --numstat.
+func parseCommitActivity(r io.Reader) ([]types.CommitActivity, error) {
+	activities := make([]types.CommitActivity, 0)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		if header, ok := strings.CutPrefix(line, logCommitMarker); ok {
+			activity, err := parseCommitActivityHeader(header)
+			if err != nil {
+				return nil, err
+			}
+			activities = append(activities, activity)
+			continue
+		}
+
+		if len(activities) == 0 {
+			return nil, fmt.Errorf("unexpected line before first commit: %q", line)
+		}
+
+		// numstat line: <added>\t<deleted>\t<path> - binary files have "-" instead of line counts.
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected numstat line: %q", line)
+		}
+
+		current := &activities[len(activities)-1]
+		if added, err := strconv.ParseInt(parts[0], 10, 64); err == nil {
+			current.Additions += added
+		}
+		if deleted, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+			current.Deletions += deleted
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return activities, nil
+}
+
+func parseCommitActivityHeader(header string) (types.CommitActivity, error) {
+	const fieldCount = 4
+	fields := strings.Split(header, "\x00")
+	if len(fields) != fieldCount {
+		return types.CommitActivity{}, fmt.Errorf("unexpected commit header: %q", header)
+	}
+
+	timestamp, err := strconv.ParseInt(fields[3], 10, 64)
+	if err != nil {
+		return types.CommitActivity{}, fmt.Errorf("failed to parse author time %q: %w", fields[3], err)
+	}
+
+	return types.CommitActivity{
+		SHA: fields[0],
+		Author: types.Signature{
+			Identity: types.Identity{
+				Name:  fields[1],
+				Email: fields[2],
+			},
+			When: time.Unix(timestamp, 0).UTC(),
+		},
+	}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/git/types"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCommitActivity(t *testing.T) {
+	// a sample of git log output with the commit activity format and --numstat
+	const logOut = "\x1e2d4b0e5c1b4f1b9a9a3e2b1c8d7e6f5a4b3c2d1e\x00Jane\x00jane@example.com\x001700000000\n" +
+		"\n" +
+		"10\t2\tmain.go\n" +
+		"-\t-\tlogo.png\n" +
+		"3\t0\t{old => new}/file.go\n" +
+		"\x1e8f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7d6\x00Merge\x00merge@example.com\x001699990000\n" +
+		"\x1e1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d\x00John\x00john@example.com\x001699900000\n" +
+		"\n" +
+		"0\t5\tREADME.md\n"
+
+	got, err := parseCommitActivity(strings.NewReader(logOut))
+	if err != nil {
+		t.Fatalf("failed to parse commit activity: %s", err.Error())
+	}
+
+	want := []types.CommitActivity{
+		{
+			SHA: "2d4b0e5c1b4f1b9a9a3e2b1c8d7e6f5a4b3c2d1e",
+			Author: types.Signature{
+				Identity: types.Identity{Name: "Jane", Email: "jane@example.com"},
+				When:     time.Unix(1700000000, 0).UTC(),
+			},
+			Additions: 13,
+			Deletions: 2,
+		},
+		{
+			SHA: "8f1e2d3c4b5a69788796a5b4c3d2e1f0a9b8c7d6",
+			Author: types.Signature{
+				Identity: types.Identity{Name: "Merge", Email: "merge@example.com"},
+				When:     time.Unix(1699990000, 0).UTC(),
+			},
+		},
+		{
+			SHA: "1a2b3c4d5e6f708192a3b4c5d6e7f8091a2b3c4d",
+			Author: types.Signature{
+				Identity: types.Identity{Name: "John", Email: "john@example.com"},
+				When:     time.Unix(1699900000, 0).UTC(),
+			},
+			Deletions: 5,
+		},
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("commit activity mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseCommitActivity_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		logOut string
+	}{
+		{
+			name:   "numstat before header",
+			logOut: "1\t2\tfile.go\n",
+		},
+		{
+			name:   "invalid header",
+			logOut: "\x1e2d4b0e5c\x00Jane\n",
+		},
+		{
+			name:   "invalid timestamp",
+			logOut: "\x1e2d4b0e5c\x00Jane\x00jane@example.com\x00now\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := parseCommitActivity(strings.NewReader(test.logOut)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/harness/gitness/git/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdapter_ListCommitsFollowRenames_NonASCIIPath(t *testing.T) {
+	git := setupGit(t)
+	ctx := context.Background()
+	repoPath := t.TempDir()
+
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(cmd.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+
+	// git quotes such paths in its default output.
+	const (
+		oldPath = "dökumente/résumé\t1.md"
+		newPath = "docs/日本語 résumé.md"
+	)
+
+	run("init", "-b", "main")
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, "dökumente"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, oldPath), []byte("line1\nline2\nline3\n"), 0o600))
+	run("add", "-A")
+	run("commit", "-m", "add")
+	c1 := run("rev-parse", "HEAD")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(repoPath, "docs"), 0o700))
+	run("mv", oldPath, newPath)
+	run("commit", "-m", "rename")
+	c2 := run("rev-parse", "HEAD")
+
+	require.NoError(t, os.WriteFile(filepath.Join(repoPath, newPath), []byte("line1\nline2\nline3\nline4\n"), 0o600))
+	run("commit", "-am", "update")
+	c3 := run("rev-parse", "HEAD")
+
+	commits, paths, err := git.ListCommitsFollowRenames(ctx, repoPath, "main", 0, 0, types.CommitFilter{Path: newPath})
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+	require.Equal(t, []string{c3, c2, c1}, []string{commits[0].SHA, commits[1].SHA, commits[2].SHA})
+	require.Equal(t, []string{newPath, newPath, oldPath}, paths)
+
+	commits, paths, err = git.ListCommitsFollowRenames(ctx, repoPath, "main", 2, 2, types.CommitFilter{Path: newPath})
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	require.Equal(t, c1, commits[0].SHA)
+	require.Equal(t, []string{oldPath}, paths)
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseFollowRenamesLog(t *testing.T) {
+	// a sample of git log --follow --name-status -z output
+	const logOut = "\x1ec5\x00\nM\x00docs/rëadme.md\x00" +
+		"\x1ec4\x00\nR100\x00rëadme\t.md\x00docs/rëadme.md\x00" +
+		"\x1ec3\x00\nM\x00rëadme\t.md\x00" +
+		"\x1ec2\x00\nR087\x00\nREADME\x00rëadme\t.md\x00" +
+		"\x1ec1\x00\nA\x00\nREADME\x00"
+
+	tests := []struct {
+		name      string
+		skip      int
+		count     int
+		wantSHAs  []string
+		wantPaths []string
+	}{
+		{
+			name:      "all",
+			skip:      0,
+			count:     -1,
+			wantSHAs:  []string{"c5", "c4", "c3", "c2", "c1"},
+			wantPaths: []string{"docs/rëadme.md", "docs/rëadme.md", "rëadme\t.md", "rëadme\t.md", "\nREADME"},
+		},
+		{
+			name:      "first page",
+			skip:      0,
+			count:     2,
+			wantSHAs:  []string{"c5", "c4"},
+			wantPaths: []string{"docs/rëadme.md", "docs/rëadme.md"},
+		},
+		{
+			name:      "second page",
+			skip:      2,
+			count:     2,
+			wantSHAs:  []string{"c3", "c2"},
+			wantPaths: []string{"rëadme\t.md", "rëadme\t.md"},
+		},
+		{
+			name:      "last page",
+			skip:      4,
+			count:     2,
+			wantSHAs:  []string{"c1"},
+			wantPaths: []string{"\nREADME"},
+		},
+		{
+			name:      "beyond last page",
+			skip:      6,
+			count:     2,
+			wantSHAs:  []string{},
+			wantPaths: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shas, paths, err := parseFollowRenamesLog(strings.NewReader(logOut), "docs/rëadme.md", test.skip, test.count)
+			if err != nil {
+				t.Fatalf("failed to parse log: %s", err.Error())
+			}
+
+			if diff := cmp.Diff(test.wantSHAs, shas); diff != "" {
+				t.Errorf("commit SHAs mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(test.wantPaths, paths); diff != "" {
+				t.Errorf("paths mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/harness/gitness/errors"
@@ -34,12 +35,20 @@ func (a Adapter) RawDiff(
 	baseRef string,
 	headRef string,
 	mergeBase bool,
+	opts types.DiffOptions,
 	w io.Writer,
 ) error {
+	defer observeOperation("raw_diff")()
+
 	if repoPath == "" {
 		return ErrRepositoryPathEmpty
 	}
 
+	diffArgs, err := diffOptionsArgs(opts)
+	if err != nil {
+		return err
+	}
+
 	baseTag, err := a.GetAnnotatedTag(ctx, repoPath, baseRef)
 	if err == nil {
 		baseRef = baseTag.TargetSha
@@ -50,8 +59,9 @@ func (a Adapter) RawDiff(
 		headRef = headTag.TargetSha
 	}
 
-	args := make([]string, 0, 8)
-	args = append(args, "diff", "-M", "--full-index")
+	args := make([]string, 0, 8+len(diffArgs))
+	args = append(args, "diff", "--full-index")
+	args = append(args, diffArgs...)
 	if mergeBase {
 		args = append(args, "--merge-base")
 	}
@@ -78,16 +88,27 @@ func (a Adapter) CommitDiff(
 	ctx context.Context,
 	repoPath string,
 	sha string,
+	opts types.DiffOptions,
 	w io.Writer,
 ) error {
+	defer observeOperation("commit_diff")()
+
 	if repoPath == "" {
 		return ErrRepositoryPathEmpty
 	}
 	if sha == "" {
 		return errors.InvalidArgument("commit sha cannot be empty")
 	}
-	args := make([]string, 0, 8)
-	args = append(args, "show", "--full-index", "--pretty=format:%b", sha)
+
+	diffArgs, err := diffOptionsArgs(opts)
+	if err != nil {
+		return err
+	}
+
+	args := make([]string, 0, 8+len(diffArgs))
+	args = append(args, "show", "--full-index", "--pretty=format:%b")
+	args = append(args, diffArgs...)
+	args = append(args, sha)
 
 	stderr := new(bytes.Buffer)
 	cmd := git.NewCommand(ctx, args...)
@@ -101,6 +122,43 @@ func (a Adapter) CommitDiff(
 	return nil
 }
 
+// diffOptionsArgs converts the diff options into git diff arguments.
+// Rename detection is always enabled, copy detection only if requested.
+func diffOptionsArgs(opts types.DiffOptions) ([]string, error) {
+	if opts.RenameThreshold < 0 || opts.RenameThreshold > 100 {
+		return nil, errors.InvalidArgument("rename threshold must be between 0 and 100")
+	}
+	if opts.ContextLines != nil && *opts.ContextLines < 0 {
+		return nil, errors.InvalidArgument("context lines cannot be negative")
+	}
+
+	ignoreWhitespace, ok := opts.IgnoreWhitespace.Sanitize()
+	if !ok {
+		return nil, errors.InvalidArgument("unknown ignore whitespace mode '%s'", opts.IgnoreWhitespace)
+	}
+
+	args := make([]string, 0, 4)
+	if arg := ignoreWhitespace.Arg(); arg != "" {
+		args = append(args, arg)
+	}
+
+	if opts.ContextLines != nil {
+		args = append(args, "--unified="+strconv.Itoa(*opts.ContextLines))
+	}
+
+	threshold := ""
+	if opts.RenameThreshold > 0 {
+		threshold = strconv.Itoa(opts.RenameThreshold) + "%"
+	}
+
+	args = append(args, "-M"+threshold)
+	if opts.DetectCopies {
+		args = append(args, "-C"+threshold)
+	}
+
+	return args, nil
+}
+
 func (a Adapter) DiffShortStat(
 	ctx context.Context,
 	repoPath string,
@@ -108,6 +166,8 @@ func (a Adapter) DiffShortStat(
 	headRef string,
 	useMergeBase bool,
 ) (types.DiffShortStat, error) {
+	defer observeOperation("diff_short_stat")()
+
 	if repoPath == "" {
 		return types.DiffShortStat{}, ErrRepositoryPathEmpty
 	}
@@ -142,6 +202,8 @@ func (a Adapter) GetDiffHunkHeaders(
 	targetRef string,
 	sourceRef string,
 ) ([]*types.DiffFileHunkHeaders, error) {
+	defer observeOperation("get_diff_hunk_headers")()
+
 	if repoPath == "" {
 		return nil, ErrRepositoryPathEmpty
 	}
@@ -189,6 +251,8 @@ func (a Adapter) DiffCut(
 	path string,
 	params types.DiffCutParams,
 ) (types.HunkHeader, types.Hunk, error) {
+	defer observeOperation("diff_cut")()
+
 	if repoPath == "" {
 		return types.HunkHeader{}, types.Hunk{}, ErrRepositoryPathEmpty
 	}
@@ -20,6 +20,10 @@ import (
 	"testing"
 
 	"github.com/harness/gitness/git/adapter"
+	"github.com/harness/gitness/git/enum"
+	"github.com/harness/gitness/git/types"
+
+	"github.com/gotidy/ptr"
 )
 
 func TestAdapter_RawDiff(t *testing.T) {
@@ -81,6 +85,7 @@ index f0eec86f614944a81f87d879ebdc9a79aea0d7ea..47d2739ba2c34690248c8f91b84bb54e
 		baseRef   string
 		headRef   string
 		mergeBase bool
+		opts      types.DiffOptions
 	}
 	tests := []struct {
 		name    string
@@ -141,11 +146,35 @@ index f0eec86f614944a81f87d879ebdc9a79aea0d7ea..47d2739ba2c34690248c8f91b84bb54e
 			wantW:   want,
 			wantErr: false,
 		},
+		{
+			name:    "test invalid rename threshold",
+			adapter: git,
+			args: args{
+				ctx:       context.Background(),
+				repoPath:  repo.Path,
+				baseRef:   baseBranch,
+				headRef:   headBranch,
+				mergeBase: false,
+				opts: types.DiffOptions{
+					RenameThreshold: 101,
+				},
+			},
+			wantW:   "",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			w := &bytes.Buffer{}
-			err := tt.adapter.RawDiff(tt.args.ctx, tt.args.repoPath, tt.args.baseRef, tt.args.headRef, tt.args.mergeBase, w)
+			err := tt.adapter.RawDiff(
+				tt.args.ctx,
+				tt.args.repoPath,
+				tt.args.baseRef,
+				tt.args.headRef,
+				tt.args.mergeBase,
+				tt.args.opts,
+				w,
+			)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("RawDiff() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -156,3 +185,83 @@ index f0eec86f614944a81f87d879ebdc9a79aea0d7ea..47d2739ba2c34690248c8f91b84bb54e
 		})
 	}
 }
+
+func TestAdapter_RawDiff_Options(t *testing.T) {
+	git := setupGit(t)
+	repo, teardown := setupRepo(t, git, "testrawdiffoptions")
+	defer teardown()
+
+	baseSHA := writeFile(t, repo, "file.txt",
+		"line 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nline 10\nline 11\nline 12\n", nil)
+	// line 2 is changed, line 11 only differs in whitespace.
+	headSHA := writeFile(t, repo, "file.txt",
+		"line 1\nline two\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\nline 9\nline 10\nline  11\nline 12\n",
+		[]string{baseSHA.String()})
+
+	const header = `diff --git a/file.txt b/file.txt
+index 624b469cb073d1245cfe818f6622ed2fa7b74606..47b07bc5725c15776907ac5f8ecdff7d799a72b0 100644
+--- a/file.txt
++++ b/file.txt
+`
+
+	tests := []struct {
+		name  string
+		opts  types.DiffOptions
+		wantW string
+	}{
+		{
+			name: "default",
+			wantW: header + `@@ -1,5 +1,5 @@
+ line 1
+-line 2
++line two
+ line 3
+ line 4
+ line 5
+@@ -8,5 +8,5 @@ line 7
+ line 8
+ line 9
+ line 10
+-line 11
++line  11
+ line 12
+`,
+		},
+		{
+			name: "without context lines",
+			opts: types.DiffOptions{ContextLines: ptr.Int(0)},
+			wantW: header + `@@ -2 +2 @@ line 1
+-line 2
++line two
+@@ -11 +11 @@ line 10
+-line 11
++line  11
+`,
+		},
+		{
+			name: "ignore whitespace",
+			opts: types.DiffOptions{
+				IgnoreWhitespace: enum.DiffIgnoreWhitespaceAll,
+				ContextLines:     ptr.Int(0),
+				RenameThreshold:  75,
+				DetectCopies:     true,
+			},
+			wantW: header + `@@ -2 +2 @@ line 1
+-line 2
++line two
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bytes.Buffer{}
+			err := git.RawDiff(context.Background(), repo.Path, baseSHA.String(), headSHA.String(), false, tt.opts, w)
+			if err != nil {
+				t.Fatalf("RawDiff() error = %v", err)
+			}
+			if gotW := w.String(); gotW != tt.wantW {
+				t.Errorf("RawDiff() gotW = %v, want %v", gotW, tt.wantW)
+			}
+		})
+	}
+}
@@ -19,4 +19,6 @@ const (
 	gitCommitterEmail = "GIT_COMMITTER_EMAIL"
 	gitCommitterName  = "GIT_COMMITTER_NAME"
 	gitCommitterDate  = "GIT_COMMITTER_DATE"
+
+	gitAlternateObjectDirectories = "GIT_ALTERNATE_OBJECT_DIRECTORIES"
 )
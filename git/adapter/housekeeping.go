@@ -0,0 +1,94 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	gitea "code.gitea.io/gitea/modules/git"
+)
+
+// RepackObjects packs the loose objects of the repository and consolidates its packs incrementally.
+// Packs are combined such that they form a geometric progression by object count, which avoids
+// rewriting the biggest packs on every run. A multi-pack-index (incl. bitmap) is written for the resulting packs.
+func (a Adapter) RepackObjects(ctx context.Context, repoPath string) error {
+	defer observeOperation("repack_objects")()
+
+	if repoPath == "" {
+		return ErrRepositoryPathEmpty
+	}
+
+	cmd := gitea.NewCommand(ctx,
+		"repack", "-d", "-l", "--geometric=2", "--write-midx", "--write-bitmap-index",
+	)
+	if _, _, err := cmd.RunStdString(&gitea.RunOpts{Dir: repoPath}); err != nil {
+		return processGiteaErrorf(err, "failed to repack objects")
+	}
+
+	return nil
+}
+
+// PruneObjects removes unreachable loose objects that are older than the provided expiry.
+// The expiry protects objects of in-flight operations (e.g. a push that didn't update its refs yet).
+func (a Adapter) PruneObjects(ctx context.Context, repoPath string, expiry time.Duration) error {
+	defer observeOperation("prune_objects")()
+
+	if repoPath == "" {
+		return ErrRepositoryPathEmpty
+	}
+
+	expire := strconv.FormatInt(int64(expiry.Seconds()), 10) + ".seconds.ago"
+	cmd := gitea.NewCommand(ctx, "prune", "--expire="+expire)
+	if _, _, err := cmd.RunStdString(&gitea.RunOpts{Dir: repoPath}); err != nil {
+		return processGiteaErrorf(err, "failed to prune objects older than %s", expiry)
+	}
+
+	return nil
+}
+
+// PackRefs packs all loose references of the repository into the packed-refs file.
+func (a Adapter) PackRefs(ctx context.Context, repoPath string) error {
+	defer observeOperation("pack_refs")()
+
+	if repoPath == "" {
+		return ErrRepositoryPathEmpty
+	}
+
+	cmd := gitea.NewCommand(ctx, "pack-refs", "--all", "--prune")
+	if _, _, err := cmd.RunStdString(&gitea.RunOpts{Dir: repoPath}); err != nil {
+		return processGiteaErrorf(err, "failed to pack refs")
+	}
+
+	return nil
+}
+
+// WriteCommitGraph writes an incremental commit-graph (incl. changed-path bloom filters)
+// for all commits reachable from any reference of the repository.
+func (a Adapter) WriteCommitGraph(ctx context.Context, repoPath string) error {
+	defer observeOperation("write_commit_graph")()
+
+	if repoPath == "" {
+		return ErrRepositoryPathEmpty
+	}
+
+	cmd := gitea.NewCommand(ctx, "commit-graph", "write", "--reachable", "--split", "--changed-paths")
+	if _, _, err := cmd.RunStdString(&gitea.RunOpts{Dir: repoPath}); err != nil {
+		return processGiteaErrorf(err, "failed to write commit graph")
+	}
+
+	return nil
+}
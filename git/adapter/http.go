@@ -35,6 +35,8 @@ func (a Adapter) InfoRefs(
 	w io.Writer,
 	env ...string,
 ) error {
+	defer observeOperation("info_refs")()
+
 	cmd := &bytes.Buffer{}
 	if err := git.NewCommand(ctx, service, "--stateless-rpc", "--advertise-refs", ".").
 		Run(&git.RunOpts{
@@ -66,6 +68,8 @@ func (a Adapter) ServicePack(
 	stdout io.Writer,
 	env ...string,
 ) error {
+	defer observeOperation("service_pack")()
+
 	// set this for allow pre-receive and post-receive execute
 	env = append(env, "SSH_ORIGINAL_COMMAND="+service)
 
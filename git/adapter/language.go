@@ -0,0 +1,49 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"context"
+
+	gitea "code.gitea.io/gitea/modules/git"
+)
+
+// GetLanguageStats returns the number of bytes per language for the tree of the given commit.
+// Languages are detected using file extension, filename and content heuristics,
+// and the linguist-* attributes of the .gitattributes file in the tree are honored.
+func (a Adapter) GetLanguageStats(
+	ctx context.Context,
+	repoPath string,
+	sha string,
+) (map[string]int64, error) {
+	defer observeOperation("get_language_stats")()
+
+	if repoPath == "" {
+		return nil, ErrRepositoryPathEmpty
+	}
+
+	giteaRepo, err := gitea.OpenRepository(ctx, repoPath)
+	if err != nil {
+		return nil, processGiteaErrorf(err, "failed to open repository")
+	}
+	defer giteaRepo.Close()
+
+	stats, err := giteaRepo.GetLanguageStats(sha)
+	if err != nil {
+		return nil, processGiteaErrorf(err, "failed to get language stats for commit '%s'", sha)
+	}
+
+	return stats, nil
+}
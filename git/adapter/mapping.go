@@ -72,6 +72,14 @@ func mapGiteaCommit(giteaCommit *gitea.Commit) (*types.Commit, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to map gitea commiter: %w", err)
 	}
+	var signature *types.ObjectSignature
+	if giteaCommit.Signature != nil {
+		signature = &types.ObjectSignature{
+			Signature: giteaCommit.Signature.Signature,
+			Payload:   giteaCommit.Signature.Payload,
+		}
+	}
+
 	return &types.Commit{
 		SHA:   giteaCommit.ID.String(),
 		Title: giteaCommit.Summary(),
@@ -79,6 +87,7 @@ func mapGiteaCommit(giteaCommit *gitea.Commit) (*types.Commit, error) {
 		Message:   strings.TrimRight(giteaCommit.Message(), "\n"),
 		Author:    author,
 		Committer: committer,
+		Signature: signature,
 	}, nil
 }
 
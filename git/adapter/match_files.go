@@ -34,6 +34,8 @@ func (a Adapter) MatchFiles(
 	pattern string,
 	maxSize int,
 ) ([]types.FileContent, error) {
+	defer observeOperation("match_files")()
+
 	nodes, err := lsDirectory(ctx, repoPath, rev, treePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list files in match files: %w", err)
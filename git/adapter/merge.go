@@ -293,6 +293,8 @@ func (a Adapter) Merge(
 	identity *types.Identity,
 	env ...string,
 ) (types.MergeResult, error) {
+	defer observeOperation("merge")()
+
 	var (
 		outbuf, errbuf strings.Builder
 	)
@@ -302,9 +304,14 @@ func (a Adapter) Merge(
 	}
 
 	stagingBranch := "staging"
-	// TODO: sign merge commit
+	// commits are signed by the adapter itself (if configured) once the merge is completed.
 	signArg := "--no-gpg-sign"
 
+	baseSHA, err := a.GetFullCommitID(ctx, tmpBasePath, "HEAD")
+	if err != nil {
+		return types.MergeResult{}, fmt.Errorf("failed to get base commit before merge: %w", err)
+	}
+
 	switch mergeMethod {
 	case enum.MergeMethodMerge:
 		cmd := git.NewCommand(ctx, "merge", "--no-ff", "--no-commit", trackingBranch)
@@ -469,9 +476,46 @@ func (a Adapter) Merge(
 		return types.MergeResult{}, fmt.Errorf("wrong merge method provided: %s", mergeMethod)
 	}
 
+	if err = a.signMergeCommits(ctx, tmpBasePath, baseSHA, trackingBranch); err != nil {
+		return types.MergeResult{}, err
+	}
+
 	return types.MergeResult{}, nil
 }
 
+// signMergeCommits signs all commits created by the merge and moves HEAD to the signed commits.
+func (a Adapter) signMergeCommits(
+	ctx context.Context,
+	tmpBasePath string,
+	baseSHA string,
+	trackingBranch string,
+) error {
+	if a.signer == nil {
+		return nil
+	}
+
+	headSHA, err := a.GetFullCommitID(ctx, tmpBasePath, "HEAD")
+	if err != nil {
+		return fmt.Errorf("failed to get head commit after merge: %w", err)
+	}
+
+	signedSHA, err := a.signCommits(ctx, tmpBasePath, headSHA, baseSHA, trackingBranch)
+	if err != nil {
+		return fmt.Errorf("failed to sign merge commits: %w", err)
+	}
+
+	if signedSHA == headSHA {
+		return nil
+	}
+
+	if err = git.NewCommand(ctx, "update-ref", "HEAD", signedSHA, headSHA).
+		Run(&git.RunOpts{Dir: tmpBasePath}); err != nil {
+		return processGiteaErrorf(err, "failed to update HEAD to signed merge commit")
+	}
+
+	return nil
+}
+
 func conflictFiles(
 	ctx context.Context,
 	pr *types.PullRequest,
@@ -519,19 +563,6 @@ func (a Adapter) GetDiffTree(
 		return outbuf.String(), nil
 	}
 
-	scanNullTerminatedStrings := func(data []byte, atEOF bool) (advance int, token []byte, err error) {
-		if atEOF && len(data) == 0 {
-			return 0, nil, nil
-		}
-		if i := bytes.IndexByte(data, '\x00'); i >= 0 {
-			return i + 1, data[0:i], nil
-		}
-		if atEOF {
-			return len(data), data, nil
-		}
-		return 0, nil, nil
-	}
-
 	list, err := getDiffTreeFromBranch(repoPath, baseBranch, headBranch)
 	if err != nil {
 		return "", err
@@ -552,6 +583,20 @@ func (a Adapter) GetDiffTree(
 	return out.String(), nil
 }
 
+// scanNullTerminatedStrings is a bufio.SplitFunc that splits NUL terminated output (e.g. of git commands with -z).
+func scanNullTerminatedStrings(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\x00'); i >= 0 {
+		return i + 1, data[0:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
 // GetMergeBase checks and returns merge base of two branches and the reference used as base.
 func (a Adapter) GetMergeBase(
 	ctx context.Context,
@@ -560,6 +605,8 @@ func (a Adapter) GetMergeBase(
 	base string,
 	head string,
 ) (string, string, error) {
+	defer observeOperation("get_merge_base")()
+
 	if repoPath == "" {
 		return "", "", ErrRepositoryPathEmpty
 	}
@@ -591,6 +638,8 @@ func (a Adapter) IsAncestor(
 	repoPath string,
 	ancestorCommitSHA, descendantCommitSHA string,
 ) (bool, error) {
+	defer observeOperation("is_ancestor")()
+
 	if repoPath == "" {
 		return false, ErrRepositoryPathEmpty
 	}
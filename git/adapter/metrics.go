@@ -0,0 +1,39 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "gitness",
+	Subsystem: "git",
+	Name:      "operation_duration_seconds",
+	Help:      "Duration of git operations executed by the git adapter.",
+	Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300},
+}, []string{"operation"})
+
+// observeOperation starts the timer of a git operation. The returned function
+// records the duration of the operation and should be deferred by the caller.
+func observeOperation(operation string) func() {
+	start := time.Now()
+	return func() {
+		operationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+	}
+}
@@ -27,6 +27,8 @@ func (a Adapter) PathsDetails(ctx context.Context,
 	rev string,
 	paths []string,
 ) ([]types.PathDetails, error) {
+	defer observeOperation("paths_details")()
+
 	// resolve the git revision to the commit SHA - we need the commit SHA for the last commit hash entry key.
 	commitSHA, err := a.ResolveRev(ctx, repoPath, rev)
 	if err != nil {
@@ -45,6 +45,8 @@ func (a Adapter) WalkReferences(
 	handler types.WalkReferencesHandler,
 	opts *types.WalkReferencesOptions,
 ) error {
+	defer observeOperation("walk_references")()
+
 	if repoPath == "" {
 		return ErrRepositoryPathEmpty
 	}
@@ -160,6 +162,8 @@ func (a Adapter) GetRef(
 	repoPath string,
 	ref string,
 ) (string, error) {
+	defer observeOperation("get_ref")()
+
 	if repoPath == "" {
 		return "", ErrRepositoryPathEmpty
 	}
@@ -188,6 +192,8 @@ func (a Adapter) UpdateRef(
 	oldValue string,
 	newValue string,
 ) error {
+	defer observeOperation("update_ref")()
+
 	if repoPath == "" {
 		return ErrRepositoryPathEmpty
 	}
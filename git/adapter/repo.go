@@ -150,6 +150,8 @@ func (a Adapter) Clone(
 	to string,
 	opts types.CloneRepoOptions,
 ) error {
+	defer observeOperation("clone")()
+
 	err := gitea.Clone(ctx, from, to, gitea.CloneRepoOptions{
 		Timeout:       opts.Timeout,
 		Mirror:        opts.Mirror,
@@ -177,6 +179,8 @@ func (a Adapter) Sync(
 	source string,
 	refSpecs []string,
 ) error {
+	defer observeOperation("sync")()
+
 	if repoPath == "" {
 		return ErrRepositoryPathEmpty
 	}
@@ -268,6 +272,8 @@ func (a Adapter) Push(
 	repoPath string,
 	opts types.PushOptions,
 ) error {
+	defer observeOperation("push")()
+
 	if repoPath == "" {
 		return ErrRepositoryPathEmpty
 	}
@@ -371,6 +377,8 @@ func (a Adapter) Push(
 }
 
 func (a Adapter) CountObjects(ctx context.Context, repoPath string) (types.ObjectCount, error) {
+	defer observeOperation("count_objects")()
+
 	cmd := gitea.NewCommand(ctx,
 		"count-objects", "-v",
 	)
@@ -19,6 +19,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -114,6 +117,34 @@ func (r *SharedRepo) Init(ctx context.Context) error {
 	return nil
 }
 
+// AddAlternates makes the objects of the provided repositories available in the shared repository.
+func (r *SharedRepo) AddAlternates(ctx context.Context, repoPaths ...string) error {
+	stdout, _, runErr := gitea.NewCommand(ctx, "rev-parse", "--git-path", "objects/info/alternates").
+		RunStdString(&gitea.RunOpts{Dir: r.tmpPath})
+	if runErr != nil {
+		return processGiteaErrorf(runErr, "failed to get path of the alternates file")
+	}
+
+	alternates := strings.TrimSpace(stdout)
+	if !filepath.IsAbs(alternates) {
+		alternates = filepath.Join(r.tmpPath, alternates)
+	}
+
+	f, err := os.OpenFile(alternates, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open alternates file '%s': %w", alternates, err)
+	}
+	defer f.Close()
+
+	for _, repoPath := range repoPaths {
+		if _, err := fmt.Fprintln(f, filepath.Join(repoPath, "objects")); err != nil {
+			return fmt.Errorf("failed to write alternates file '%s': %w", alternates, err)
+		}
+	}
+
+	return nil
+}
+
 // SetDefaultIndex sets the git index to our HEAD.
 func (r *SharedRepo) SetDefaultIndex(ctx context.Context) error {
 	if _, _, err := gitea.NewCommand(ctx, "read-tree", "HEAD").RunStdString(&gitea.RunOpts{Dir: r.tmpPath}); err != nil {
@@ -255,6 +286,179 @@ func (r *SharedRepo) WriteTree(ctx context.Context) (string, error) {
 	return strings.TrimSpace(stdout), nil
 }
 
+// MergeTree performs a three-way merge of the provided tree-ishes into the index.
+// Files that were changed on both sides are merged on content level,
+// the paths of all files that couldn't be merged cleanly are returned.
+func (r *SharedRepo) MergeTree(
+	ctx context.Context,
+	base string,
+	ours string,
+	theirs string,
+) ([]string, error) {
+	if _, _, err := gitea.NewCommand(ctx, "read-tree", "-m", "-i", "--aggressive", base, ours, theirs).
+		RunStdString(&gitea.RunOpts{Dir: r.tmpPath}); err != nil {
+		return nil, processGiteaErrorf(err, "unable to merge trees in temporary repo for: %s", r.repoUID)
+	}
+
+	entries, err := r.ListUnmergedEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := make([]string, 0)
+	for i := range entries {
+		resolved, err := r.resolveUnmergedEntry(ctx, &entries[i])
+		if err != nil {
+			return nil, err
+		}
+		if !resolved {
+			conflicts = append(conflicts, entries[i].Path)
+		}
+	}
+
+	return conflicts, nil
+}
+
+// ListUnmergedEntries returns all paths of the index that have unmerged stages.
+func (r *SharedRepo) ListUnmergedEntries(ctx context.Context) ([]types.UnmergedEntry, error) {
+	stdout, _, err := gitea.NewCommand(ctx, "ls-files", "-u", "-z").RunStdString(&gitea.RunOpts{Dir: r.tmpPath})
+	if err != nil {
+		return nil, processGiteaErrorf(err, "unable to list unmerged files in temporary repo for: %s", r.repoUID)
+	}
+
+	// output has the format "<mode> <sha> <stage>\t<path>\x00" and is sorted by path.
+	var entries []types.UnmergedEntry
+	for _, line := range strings.Split(stdout, "\x00") {
+		if line == "" {
+			continue
+		}
+
+		info, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(info)
+		if !ok || len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected output of ls-files: %q", line)
+		}
+
+		if len(entries) == 0 || entries[len(entries)-1].Path != path {
+			entries = append(entries, types.UnmergedEntry{Path: path})
+		}
+
+		current := &entries[len(entries)-1]
+		entry := &types.IndexEntry{Mode: fields[0], SHA: fields[1]}
+		switch fields[2] {
+		case "1":
+			current.Base = entry
+		case "2":
+			current.Ours = entry
+		case "3":
+			current.Theirs = entry
+		default:
+			return nil, fmt.Errorf("unexpected stage in output of ls-files: %q", line)
+		}
+	}
+
+	return entries, nil
+}
+
+// resolveUnmergedEntry tries to merge a file that was modified on both sides and adds the result to the index.
+// Returns false in case the entry can't be resolved without conflicts.
+func (r *SharedRepo) resolveUnmergedEntry(ctx context.Context, entry *types.UnmergedEntry) (bool, error) {
+	// added / deleted on one side and modified on the other side are conflicts.
+	if entry.Base == nil || entry.Ours == nil || entry.Theirs == nil {
+		return false, nil
+	}
+
+	var mode string
+	switch {
+	case entry.Ours.Mode == entry.Theirs.Mode:
+		mode = entry.Ours.Mode
+	case entry.Base.Mode == entry.Ours.Mode:
+		mode = entry.Theirs.Mode
+	case entry.Base.Mode == entry.Theirs.Mode:
+		mode = entry.Ours.Mode
+	default:
+		return false, nil
+	}
+
+	// only regular files can be merged on content level.
+	if mode != "100644" && mode != "100755" {
+		return false, nil
+	}
+
+	content, clean, err := r.MergeFile(ctx, entry.Base.SHA, entry.Ours.SHA, entry.Theirs.SHA, "ours", "base", "theirs")
+	if err != nil {
+		return false, err
+	}
+	if !clean {
+		return false, nil
+	}
+
+	sha, err := r.WriteGitObject(ctx, bytes.NewReader(content))
+	if err != nil {
+		return false, fmt.Errorf("unable to write merged file '%s': %w", entry.Path, err)
+	}
+
+	// adding the merged file with stage 0 removes the unmerged entries of the path.
+	if err = r.AddObjectToIndex(ctx, mode, sha, entry.Path); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// MergeFile performs a three-way merge of the provided blobs and returns the merged content.
+// In case of conflicts the returned content contains conflict markers using the provided labels
+// and the returned bool is false.
+func (r *SharedRepo) MergeFile(
+	ctx context.Context,
+	baseSHA, oursSHA, theirsSHA string,
+	oursLabel, baseLabel, theirsLabel string,
+) ([]byte, bool, error) {
+	dir, err := os.MkdirTemp(r.tmpPath, "merge-file")
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to create directory for merging files: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	blobs := []struct {
+		name string
+		sha  string
+	}{{"ours", oursSHA}, {"base", baseSHA}, {"theirs", theirsSHA}}
+	for _, blob := range blobs {
+		content, _, err := gitea.NewCommand(ctx, "cat-file", "blob", blob.sha).
+			RunStdBytes(&gitea.RunOpts{Dir: r.tmpPath})
+		if err != nil {
+			return nil, false, processGiteaErrorf(err, "unable to read blob '%s'", blob.sha)
+		}
+		if err := os.WriteFile(filepath.Join(dir, blob.name), content, 0o600); err != nil {
+			return nil, false, fmt.Errorf("unable to write blob '%s' for merging: %w", blob.sha, err)
+		}
+	}
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	err = gitea.NewCommand(ctx, "merge-file", "-p", "-L", oursLabel, "-L", baseLabel, "-L", theirsLabel,
+		"ours", "base", "theirs").
+		Run(&gitea.RunOpts{
+			Dir:    dir,
+			Stdout: stdout,
+			Stderr: stderr,
+		})
+
+	// merge-file exits with the number of conflicts (or a negative value, e.g. for binary files).
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return stdout.Bytes(), false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to merge files: %w\nStderr: %s", err, stderr)
+	}
+
+	return stdout.Bytes(), true, nil
+}
+
 // GetLastCommit gets the last commit ID SHA of the repo.
 func (r *SharedRepo) GetLastCommit(ctx context.Context) (string, error) {
 	return r.GetLastCommitByRef(ctx, "HEAD")
@@ -284,6 +488,33 @@ func (r *SharedRepo) CommitTreeWithDate(
 	treeHash, message string,
 	signoff bool,
 	authorDate, committerDate time.Time,
+) (string, error) {
+	var parents []string
+	if parent != "" {
+		parents = []string{parent}
+	}
+
+	return r.commitTree(ctx, parents, author, committer, treeHash, message, signoff, authorDate, committerDate)
+}
+
+// CommitTreeWithParents creates a commit of the provided tree with all provided parents (e.g. a merge commit).
+func (r *SharedRepo) CommitTreeWithParents(
+	ctx context.Context,
+	parents []string,
+	author, committer *types.Identity,
+	treeHash, message string,
+	authorDate, committerDate time.Time,
+) (string, error) {
+	return r.commitTree(ctx, parents, author, committer, treeHash, message, false, authorDate, committerDate)
+}
+
+func (r *SharedRepo) commitTree(
+	ctx context.Context,
+	parents []string,
+	author, committer *types.Identity,
+	treeHash, message string,
+	signoff bool,
+	authorDate, committerDate time.Time,
 ) (string, error) {
 	// setup environment variables used by git-commit-tree
 	// See https://git-scm.com/book/en/v2/Git-Internals-Environment-Variables
@@ -299,14 +530,13 @@ func (r *SharedRepo) CommitTreeWithDate(
 	_, _ = messageBytes.WriteString(message)
 	_, _ = messageBytes.WriteString("\n")
 
-	var args []string
-	if parent != "" {
-		args = []string{"commit-tree", treeHash, "-p", parent}
-	} else {
-		args = []string{"commit-tree", treeHash}
+	args := make([]string, 0, 3+2*len(parents))
+	args = append(args, "commit-tree", treeHash)
+	for _, parent := range parents {
+		args = append(args, "-p", parent)
 	}
 
-	// temporary no signing
+	// commits are signed by the adapter itself (if configured), independent of the local git setup.
 	args = append(args, "--no-gpg-sign")
 
 	if signoff {
@@ -334,7 +564,18 @@ func (r *SharedRepo) CommitTreeWithDate(
 		return "", processGiteaErrorf(err, "unable to commit-tree in temporary repo: %s Error: %v\nStdout: %s\nStderr: %s",
 			r.repoUID, err, stdout, stderr)
 	}
-	return strings.TrimSpace(stdout.String()), nil
+
+	commitSHA := strings.TrimSpace(stdout.String())
+	if r.adapter.signer == nil {
+		return commitSHA, nil
+	}
+
+	signedSHA, err := r.adapter.signCommit(ctx, r.tmpPath, commitSHA, nil)
+	if err != nil {
+		return "", fmt.Errorf("unable to sign commit in temporary repo %s: %w", r.repoUID, err)
+	}
+
+	return signedSHA, nil
 }
 
 func (r *SharedRepo) PushDeleteBranch(
@@ -0,0 +1,71 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedRepo_MergeTree(t *testing.T) {
+	git := setupGit(t)
+	repo, teardown := setupRepo(t, git, "testmergetree")
+	defer teardown()
+
+	ctx := context.Background()
+
+	writeFile(t, repo, "a.txt", "line1\nline2\nline3\n", nil)
+	base := writeFile(t, repo, "b.txt", "x\n", nil).String()
+	ours := writeFile(t, repo, "a.txt", "LINE1\nline2\nline3\n", []string{base}).String()
+	theirs := writeFile(t, repo, "a.txt", "line1\nline2\nLINE3\n", []string{base}).String()
+	theirs = writeFile(t, repo, "b.txt", "y\n", []string{theirs}).String()
+	conflicting := writeFile(t, repo, "a.txt", "other\nline2\nline3\n", []string{base}).String()
+
+	require.NoError(t, repo.SetReference("refs/heads/main", ours))
+
+	// changes of both sides are merged
+	shared, err := git.SharedRepository(t.TempDir(), "testmergetree", repo.Path)
+	require.NoError(t, err)
+	defer shared.Close(ctx)
+	require.NoError(t, shared.Clone(ctx, "main"))
+
+	conflicts, err := shared.MergeTree(ctx, base, ours, theirs)
+	require.NoError(t, err)
+	require.Empty(t, conflicts)
+
+	tree, err := shared.WriteTree(ctx)
+	require.NoError(t, err)
+
+	content := &bytes.Buffer{}
+	require.NoError(t, shared.ShowFile(ctx, "a.txt", tree, content))
+	require.Equal(t, "LINE1\nline2\nLINE3\n", content.String())
+
+	content.Reset()
+	require.NoError(t, shared.ShowFile(ctx, "b.txt", tree, content))
+	require.Equal(t, "y\n", content.String())
+
+	// changes of the same lines are reported as conflicts
+	conflictShared, err := git.SharedRepository(t.TempDir(), "testmergetree", repo.Path)
+	require.NoError(t, err)
+	defer conflictShared.Close(ctx)
+	require.NoError(t, conflictShared.Clone(ctx, "main"))
+
+	conflicts, err = conflictShared.MergeTree(ctx, base, ours, conflicting)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a.txt"}, conflicts)
+}
@@ -0,0 +1,340 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/harness/gitness/git/types"
+
+	"code.gitea.io/gitea/modules/git"
+	"github.com/42wim/sshsig"
+	sshsigpem "github.com/42wim/sshsig/pem"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// sshSignatureNamespace is the namespace git uses for ssh signatures.
+	sshSignatureNamespace = "git"
+
+	pgpSignaturePrefix = "-----BEGIN PGP SIGNATURE-----"
+	sshSignaturePrefix = "-----BEGIN SSH SIGNATURE-----"
+)
+
+// VerifySignature verifies the signature of a git object against the provided signing keys.
+func (a Adapter) VerifySignature(
+	signature *types.ObjectSignature,
+	keys []types.SigningKey,
+) types.SignatureVerification {
+	defer observeOperation("verify_signature")()
+
+	if signature == nil || signature.Signature == "" {
+		return types.SignatureVerification{Reason: types.SignatureVerificationReasonUnsigned}
+	}
+
+	switch {
+	case strings.HasPrefix(signature.Signature, pgpSignaturePrefix):
+		return verifyGPGSignature(signature, keys)
+	case strings.HasPrefix(signature.Signature, sshSignaturePrefix):
+		return verifySSHSignature(signature, keys)
+	default:
+		return types.SignatureVerification{Reason: types.SignatureVerificationReasonUnknownSignature}
+	}
+}
+
+func verifyGPGSignature(
+	signature *types.ObjectSignature,
+	keys []types.SigningKey,
+) types.SignatureVerification {
+	keyID, err := getGPGSignatureKeyID(signature.Signature)
+	if err != nil {
+		return types.SignatureVerification{Reason: types.SignatureVerificationReasonMalformedSignature}
+	}
+
+	result := types.SignatureVerification{
+		Reason: types.SignatureVerificationReasonUnknownKey,
+		KeyID:  keyID,
+	}
+
+	for _, key := range keys {
+		if key.Type != types.SigningKeyTypeGPG {
+			continue
+		}
+
+		keyring, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key.Content))
+		if err != nil {
+			continue
+		}
+
+		_, err = openpgp.CheckArmoredDetachedSignature(
+			keyring,
+			strings.NewReader(signature.Payload),
+			strings.NewReader(signature.Signature),
+			nil,
+		)
+		if errors.Is(err, pgperrors.ErrUnknownIssuer) {
+			continue
+		}
+		if err != nil {
+			// the key matches the issuer of the signature, but the signature isn't valid.
+			result.Reason = types.SignatureVerificationReasonBadSignature
+			return result
+		}
+
+		result.Verified = true
+		result.Reason = types.SignatureVerificationReasonValid
+		result.SigningKeyID = key.ID
+		return result
+	}
+
+	return result
+}
+
+// getGPGSignatureKeyID returns the id of the key that created the armored gpg signature.
+func getGPGSignatureKeyID(signature string) (string, error) {
+	block, err := armor.Decode(strings.NewReader(signature))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode armored signature: %w", err)
+	}
+
+	p, err := packet.Read(block.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read signature packet: %w", err)
+	}
+
+	sig, ok := p.(*packet.Signature)
+	if !ok {
+		return "", fmt.Errorf("unexpected packet of type %T", p)
+	}
+
+	switch {
+	case sig.IssuerKeyId != nil:
+		return fmt.Sprintf("%016X", *sig.IssuerKeyId), nil
+	case len(sig.IssuerFingerprint) > 0:
+		return fmt.Sprintf("%X", sig.IssuerFingerprint), nil
+	default:
+		return "", nil
+	}
+}
+
+func verifySSHSignature(
+	signature *types.ObjectSignature,
+	keys []types.SigningKey,
+) types.SignatureVerification {
+	signingKey, err := getSSHSignaturePublicKey(signature.Signature)
+	if err != nil {
+		return types.SignatureVerification{Reason: types.SignatureVerificationReasonMalformedSignature}
+	}
+
+	result := types.SignatureVerification{
+		Reason: types.SignatureVerificationReasonUnknownKey,
+		KeyID:  ssh.FingerprintSHA256(signingKey),
+	}
+
+	for _, key := range keys {
+		if key.Type != types.SigningKeyTypeSSH {
+			continue
+		}
+
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.Content))
+		if err != nil || ssh.FingerprintSHA256(publicKey) != result.KeyID {
+			continue
+		}
+
+		err = sshsig.Verify(
+			strings.NewReader(signature.Payload),
+			[]byte(signature.Signature),
+			[]byte(key.Content),
+			sshSignatureNamespace,
+		)
+		if err != nil {
+			result.Reason = types.SignatureVerificationReasonBadSignature
+			return result
+		}
+
+		result.Verified = true
+		result.Reason = types.SignatureVerificationReasonValid
+		result.SigningKeyID = key.ID
+		return result
+	}
+
+	return result
+}
+
+// getSSHSignaturePublicKey returns the public key embedded in the armored ssh signature.
+func getSSHSignaturePublicKey(signature string) (ssh.PublicKey, error) {
+	block, _ := sshsigpem.Decode([]byte(signature))
+	if block == nil {
+		return nil, errors.New("failed to decode armored signature")
+	}
+
+	wrapped := sshsig.WrappedSig{}
+	if err := ssh.Unmarshal(block.Bytes, &wrapped); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal signature: %w", err)
+	}
+
+	publicKey, err := ssh.ParsePublicKey([]byte(wrapped.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key of signature: %w", err)
+	}
+
+	return publicKey, nil
+}
+
+// getCommitSignature returns the signature of a commit (nil if the commit isn't signed).
+func getCommitSignature(
+	ctx context.Context,
+	repoPath string,
+	sha string,
+) (*types.ObjectSignature, error) {
+	id, err := git.NewIDFromString(sha)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit sha '%s': %w", sha, err)
+	}
+
+	stdout, _, err := git.NewCommand(ctx, "cat-file", "commit", sha).
+		RunStdBytes(&git.RunOpts{Dir: repoPath})
+	if err != nil {
+		return nil, processGiteaErrorf(err, "failed to read commit '%s'", sha)
+	}
+
+	commit, err := git.CommitFromReader(nil, id, bytes.NewReader(stdout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse commit '%s': %w", sha, err)
+	}
+
+	if commit.Signature == nil {
+		return nil, nil
+	}
+
+	return &types.ObjectSignature{
+		Signature: commit.Signature.Signature,
+		Payload:   commit.Signature.Payload,
+	}, nil
+}
+
+// ListPushedCommits lists the commits of a push to a reference.
+// For updates (oldSHA is set) all commits reachable from newSHA that aren't reachable from oldSHA are listed,
+// independent of whether other references point to them already.
+// For new references all commits reachable from newSHA that aren't reachable by any existing reference are listed.
+// As it's used during a push, the alternate object directories allow reading not yet accepted objects.
+func (a Adapter) ListPushedCommits(
+	ctx context.Context,
+	repoPath string,
+	oldSHA string,
+	newSHA string,
+	alternateObjectDirs []string,
+) ([]types.Commit, error) {
+	defer observeOperation("list_pushed_commits")()
+
+	if repoPath == "" {
+		return nil, ErrRepositoryPathEmpty
+	}
+
+	var env []string
+	if len(alternateObjectDirs) > 0 {
+		env = append(env,
+			gitAlternateObjectDirectories+"="+strings.Join(alternateObjectDirs, string(os.PathListSeparator)))
+	}
+
+	args := []string{"rev-list"}
+	if oldSHA == "" || oldSHA == types.NilSHA {
+		args = append(args, newSHA, "--not", "--all")
+	} else {
+		args = append(args, oldSHA+".."+newSHA)
+	}
+
+	stdout, _, err := git.NewCommand(ctx, args...).RunStdBytes(&git.RunOpts{Dir: repoPath, Env: env})
+	if err != nil {
+		return nil, processGiteaErrorf(err, "failed to list pushed commits")
+	}
+
+	shas := parseLinesToSlice(stdout)
+	if len(shas) == 0 {
+		return []types.Commit{}, nil
+	}
+
+	// read all commits with a single cat-file call, as gitea's batch reader doesn't support a custom env.
+	pipeOut, pipeIn := io.Pipe()
+	defer pipeOut.Close()
+	stderr := strings.Builder{}
+
+	go func() {
+		stdin := strings.NewReader(strings.Join(shas, "\n") + "\n")
+		err := git.NewCommand(ctx, "cat-file", "--batch").
+			Run(&git.RunOpts{Dir: repoPath, Env: env, Stdin: stdin, Stdout: pipeIn, Stderr: &stderr})
+		if err != nil {
+			_ = pipeIn.CloseWithError(git.ConcatenateError(err, stderr.String()))
+		} else {
+			_ = pipeIn.Close()
+		}
+	}()
+
+	reader := bufio.NewReader(pipeOut)
+	commits := make([]types.Commit, len(shas))
+	for i := range shas {
+		commit, err := readBatchCommit(reader)
+		if err != nil {
+			return nil, processGiteaErrorf(err, "failed to read pushed commits")
+		}
+
+		commits[i] = *commit
+	}
+
+	return commits, nil
+}
+
+// readBatchCommit reads the next commit from the output of cat-file --batch.
+func readBatchCommit(reader *bufio.Reader) (*types.Commit, error) {
+	sha, typ, size, err := git.ReadBatchLine(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cat-file batch line: %w", err)
+	}
+	if typ != string(types.GitObjectTypeCommit) {
+		return nil, fmt.Errorf("git object '%s' is of type '%s', expected commit", sha, typ)
+	}
+
+	id, err := git.NewIDFromString(string(sha))
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit sha '%s': %w", sha, err)
+	}
+
+	content := io.LimitReader(reader, size)
+	giteaCommit, err := git.CommitFromReader(nil, id, content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse commit '%s': %w", sha, err)
+	}
+
+	// discard any unread content and the new line following it.
+	if _, err = io.Copy(io.Discard, content); err != nil {
+		return nil, fmt.Errorf("failed to read cat-file batch output: %w", err)
+	}
+	if _, err = reader.Discard(1); err != nil {
+		return nil, fmt.Errorf("failed to read cat-file batch output: %w", err)
+	}
+
+	return mapGiteaCommit(giteaCommit)
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/harness/gitness/git/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdapter_ListPushedCommits(t *testing.T) {
+	git := setupGit(t)
+	repo, teardown := setupRepo(t, git, "testlistpushedcommits")
+	defer teardown()
+
+	ctx := context.Background()
+
+	base := writeFile(t, repo, "a.txt", "a\n", nil).String()
+	require.NoError(t, repo.SetReference("refs/heads/main", base))
+
+	// the commit was pushed to another branch already.
+	feature := writeFile(t, repo, "b.txt", "b\n", []string{base}).String()
+	require.NoError(t, repo.SetReference("refs/heads/feature", feature))
+
+	// not yet referenced commits, like the objects of an ongoing push.
+	pushed := []string{feature}
+	for i := 0; i < 50; i++ {
+		pushed = append(pushed, writeFile(t, repo, "c.txt", string(rune('a'+i%26)), pushed[len(pushed)-1:]).String())
+	}
+	head := pushed[len(pushed)-1]
+
+	shas := func(commits []types.Commit) []string {
+		res := make([]string, len(commits))
+		for i := range commits {
+			res[i] = commits[i].SHA
+		}
+		return res
+	}
+
+	// updates list all commits added to the reference, even if they are referenced by another branch.
+	commits, err := git.ListPushedCommits(ctx, repo.Path, base, feature, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{feature}, shas(commits))
+	require.Equal(t, "write file operation", commits[0].Title)
+
+	commits, err = git.ListPushedCommits(ctx, repo.Path, base, head, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, pushed, shas(commits))
+
+	commits, err = git.ListPushedCommits(ctx, repo.Path, head, head, nil)
+	require.NoError(t, err)
+	require.Empty(t, commits)
+
+	// new references only list commits that aren't referenced yet.
+	commits, err = git.ListPushedCommits(ctx, repo.Path, types.NilSHA, feature, nil)
+	require.NoError(t, err)
+	require.Empty(t, commits)
+
+	commits, err = git.ListPushedCommits(ctx, repo.Path, "", head, nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, pushed[1:], shas(commits))
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	"github.com/harness/gitness/git/types"
+
+	"github.com/42wim/sshsig"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const testSignaturePayload = "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n" +
+	"author max <max@mail.com> 1666401234 -0700\ncommitter max <max@mail.com> 1666401234 -0700\n\nsome message\n"
+
+func TestVerifySignature_GPG(t *testing.T) {
+	entity, err := openpgp.NewEntity("max", "", "max@mail.com", nil)
+	require.NoError(t, err)
+
+	publicKey := &bytes.Buffer{}
+	w, err := armor.Encode(publicKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	signature := &bytes.Buffer{}
+	err = openpgp.ArmoredDetachSign(signature, entity, strings.NewReader(testSignaturePayload), nil)
+	require.NoError(t, err)
+
+	otherEntity, err := openpgp.NewEntity("other", "", "other@mail.com", nil)
+	require.NoError(t, err)
+	otherKey := &bytes.Buffer{}
+	w, err = armor.Encode(otherKey, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, otherEntity.Serialize(w))
+	require.NoError(t, w.Close())
+
+	testVerifySignature(t, types.SigningKeyTypeGPG, signature.String(), publicKey.String(), otherKey.String())
+}
+
+func TestVerifySignature_SSH(t *testing.T) {
+	privateKeyPEM, publicKey := generateTestSSHKey(t)
+	_, otherKey := generateTestSSHKey(t)
+
+	signature, err := sshsig.Sign(privateKeyPEM, strings.NewReader(testSignaturePayload), sshSignatureNamespace)
+	require.NoError(t, err)
+
+	testVerifySignature(t, types.SigningKeyTypeSSH, string(signature), publicKey, otherKey)
+}
+
+func testVerifySignature(t *testing.T, keyType types.SigningKeyType, signature, key, otherKey string) {
+	a := Adapter{}
+	validKey := types.SigningKey{ID: 1, Type: keyType, Content: key}
+	unrelatedKey := types.SigningKey{ID: 2, Type: keyType, Content: otherKey}
+
+	res := a.VerifySignature(&types.ObjectSignature{Signature: signature, Payload: testSignaturePayload},
+		[]types.SigningKey{unrelatedKey, validKey})
+	require.True(t, res.Verified)
+	require.Equal(t, types.SignatureVerificationReasonValid, res.Reason)
+	require.Equal(t, int64(1), res.SigningKeyID)
+	require.NotEmpty(t, res.KeyID)
+
+	res = a.VerifySignature(&types.ObjectSignature{Signature: signature, Payload: testSignaturePayload + "x"},
+		[]types.SigningKey{validKey})
+	require.False(t, res.Verified)
+	require.Equal(t, types.SignatureVerificationReasonBadSignature, res.Reason)
+
+	res = a.VerifySignature(&types.ObjectSignature{Signature: signature, Payload: testSignaturePayload},
+		[]types.SigningKey{unrelatedKey})
+	require.False(t, res.Verified)
+	require.Equal(t, types.SignatureVerificationReasonUnknownKey, res.Reason)
+
+	res = a.VerifySignature(nil, []types.SigningKey{validKey})
+	require.False(t, res.Verified)
+	require.Equal(t, types.SignatureVerificationReasonUnsigned, res.Reason)
+}
+
+func generateTestSSHKey(t *testing.T) ([]byte, string) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), string(ssh.MarshalAuthorizedKey(sshPub))
+}
@@ -0,0 +1,131 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/git/types"
+
+	"code.gitea.io/gitea/modules/git"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+const testSigningKeyPassphrase = "secret"
+
+func TestSignCommits_GPG(t *testing.T) {
+	entity, err := openpgp.NewEntity("gitness", "", "system@gitness.io", nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.EncryptPrivateKeys([]byte(testSigningKeyPassphrase), nil))
+
+	privateKey := &bytes.Buffer{}
+	w, err := armor.Encode(privateKey, openpgp.PrivateKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.SerializePrivateWithoutSigning(w, nil))
+	require.NoError(t, w.Close())
+
+	testSignCommits(t, types.SigningKeyTypeGPG, privateKey.Bytes())
+}
+
+func TestSignCommits_SSH(t *testing.T) {
+	privateKeyPEM, _ := generateTestSSHKey(t)
+
+	rawKey, err := ssh.ParseRawPrivateKey(privateKeyPEM)
+	require.NoError(t, err)
+
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(rawKey, "", []byte(testSigningKeyPassphrase))
+	require.NoError(t, err)
+
+	testSignCommits(t, types.SigningKeyTypeSSH, pem.EncodeToMemory(block))
+}
+
+func testSignCommits(t *testing.T, keyType types.SigningKeyType, privateKey []byte) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	keyPath := filepath.Join(dir, "signing.key")
+	require.NoError(t, os.WriteFile(keyPath, privateKey, 0o600))
+
+	_, err := newObjectSigner(keyPath, "wrong")
+	require.Error(t, err)
+
+	a, err := New(types.Config{
+		SigningKey: types.SigningKeyConfig{Path: keyPath, Passphrase: testSigningKeyPassphrase},
+	}, NewInMemoryLastCommitCache(time.Minute), nil)
+	require.NoError(t, err)
+	require.NotNil(t, a.SigningKey())
+	require.Equal(t, keyType, a.SigningKey().Type)
+
+	repoPath := filepath.Join(dir, "repo.git")
+	require.NoError(t, git.InitRepository(ctx, repoPath, true))
+
+	commitTree := func(args ...string) string {
+		stdout, _, err := git.NewCommand(ctx, append([]string{"commit-tree", git.EmptyTreeSHA}, args...)...).
+			RunStdString(&git.RunOpts{
+				Dir: repoPath,
+				Env: []string{
+					"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+					"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com",
+				},
+				Stdin: strings.NewReader("some message\n"),
+			})
+		require.NoError(t, err)
+		return strings.TrimSpace(stdout)
+	}
+
+	first := commitTree()
+	second := commitTree("-p", first)
+
+	// commits reachable from the excluded revision remain untouched.
+	head, err := a.signCommits(ctx, repoPath, second, first)
+	require.NoError(t, err)
+	require.NotEqual(t, second, head)
+	requireSignedBy(t, a, repoPath, head)
+
+	parent, _, err := git.NewCommand(ctx, "rev-parse", head+"^").RunStdString(&git.RunOpts{Dir: repoPath})
+	require.NoError(t, err)
+	require.Equal(t, first, strings.TrimSpace(parent))
+
+	// parents of rewritten commits point to the signed commits.
+	head, err = a.signCommits(ctx, repoPath, second)
+	require.NoError(t, err)
+	requireSignedBy(t, a, repoPath, head)
+
+	parent, _, err = git.NewCommand(ctx, "rev-parse", head+"^").RunStdString(&git.RunOpts{Dir: repoPath})
+	require.NoError(t, err)
+	require.NotEqual(t, first, strings.TrimSpace(parent))
+	requireSignedBy(t, a, repoPath, strings.TrimSpace(parent))
+}
+
+func requireSignedBy(t *testing.T, a Adapter, repoPath string, sha string) {
+	t.Helper()
+
+	signature, err := getCommitSignature(context.Background(), repoPath, sha)
+	require.NoError(t, err)
+	require.NotNil(t, signature)
+
+	res := a.VerifySignature(signature, []types.SigningKey{*a.SigningKey()})
+	require.True(t, res.Verified, "reason: %s", res.Reason)
+}
@@ -32,6 +32,7 @@ import (
 const (
 	pgpSignatureBeginToken = "\n-----BEGIN PGP SIGNATURE-----\n" //#nosec G101
 	pgpSignatureEndToken   = "\n-----END PGP SIGNATURE-----"     //#nosec G101
+	sshSignatureBeginToken = "\n-----BEGIN SSH SIGNATURE-----\n" //#nosec G101
 )
 
 // GetAnnotatedTag returns the tag for a specific tag sha.
@@ -57,6 +58,8 @@ func (a Adapter) GetAnnotatedTags(
 	repoPath string,
 	shas []string,
 ) ([]types.Tag, error) {
+	defer observeOperation("get_annotated_tags")()
+
 	if repoPath == "" {
 		return nil, ErrRepositoryPathEmpty
 	}
@@ -71,6 +74,8 @@ func (a Adapter) CreateTag(
 	targetSHA string,
 	opts *types.CreateTagOptions,
 ) error {
+	defer observeOperation("create_tag")()
+
 	if repoPath == "" {
 		return ErrRepositoryPathEmpty
 	}
@@ -200,6 +205,16 @@ func parseTagDataFromCatFile(data []byte) (tag types.Tag, err error) {
 		return tag, err
 	}
 
+	// a signed tag has the signature appended to the message - the signed payload is everything before it.
+	if sigStart := findTrailingSignature(data[p:]); sigStart > -1 {
+		sigStart += p
+		tag.Signature = &types.ObjectSignature{
+			Signature: string(data[sigStart:]),
+			Payload:   string(data[:sigStart]),
+		}
+		data = data[:sigStart]
+	}
+
 	// remainder is message and gpg (remove leading and tailing new lines)
 	message := string(bytes.Trim(data[p:], "\n"))
 
@@ -224,6 +239,19 @@ func parseTagDataFromCatFile(data []byte) (tag types.Tag, err error) {
 	return tag, nil
 }
 
+// findTrailingSignature returns the start index of the (PGP or SSH) signature appended to the data, or -1.
+func findTrailingSignature(data []byte) int {
+	for _, token := range []string{pgpSignatureBeginToken, sshSignatureBeginToken} {
+		idx := bytes.LastIndex(data, []byte(token))
+		if idx > -1 {
+			// skip the leading new line, it's part of the payload.
+			return idx + 1
+		}
+	}
+
+	return -1
+}
+
 func giteaParseCatFileLine(data []byte, start int, header string) (string, int, error) {
 	// for simplicity only look at data from start onwards
 	data = data[start:]
@@ -161,6 +161,8 @@ func lsFile(
 
 // GetTreeNode returns the tree node at the given path as found for the provided reference.
 func (a Adapter) GetTreeNode(ctx context.Context, repoPath, rev, treePath string) (*types.TreeNode, error) {
+	defer observeOperation("get_tree_node")()
+
 	// root path (empty path) is a special case
 	if treePath == "" {
 		if repoPath == "" {
@@ -195,6 +197,8 @@ func (a Adapter) GetTreeNode(ctx context.Context, repoPath, rev, treePath string
 
 // ListTreeNodes lists the child nodes of a tree reachable from ref via the specified path.
 func (a Adapter) ListTreeNodes(ctx context.Context, repoPath, rev, treePath string) ([]types.TreeNode, error) {
+	defer observeOperation("list_tree_nodes")()
+
 	list, err := lsDirectory(ctx, repoPath, rev, treePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list tree nodes: %w", err)
@@ -0,0 +1,78 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git/enum"
+)
+
+type ArchiveParams struct {
+	ReadParams
+	// Treeish is the commit, tag or tree the archive is created from.
+	Treeish string
+	Format  enum.ArchiveFormat
+	// Prefix is prepended to every file path in the archive (typically ends with a slash).
+	Prefix string
+	// Paths optionally limits the archive to the provided paths.
+	Paths []string
+}
+
+func (p *ArchiveParams) Validate() error {
+	if p == nil {
+		return ErrNoParamsProvided
+	}
+
+	if err := p.ReadParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.Treeish == "" {
+		return errors.InvalidArgument("tree-ish cannot be empty")
+	}
+
+	if _, ok := p.Format.Sanitize(); !ok {
+		return errors.InvalidArgument("unsupported archive format '%s'", p.Format)
+	}
+
+	if strings.HasPrefix(p.Prefix, "/") || strings.Contains(p.Prefix, "..") {
+		return errors.InvalidArgument("archive prefix must be a relative path")
+	}
+
+	for i := range p.Paths {
+		p.Paths[i] = strings.Trim(path.Clean("/"+p.Paths[i]), "/")
+		if p.Paths[i] == "" {
+			return errors.InvalidArgument("archive path cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// Archive writes the archive of the repository content at the provided tree-ish to the writer.
+func (s *Service) Archive(ctx context.Context, params *ArchiveParams, w io.Writer) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	return s.adapter.Archive(ctx, repoPath, params.Treeish, params.Format, params.Prefix, params.Paths, w)
+}
@@ -0,0 +1,318 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git/types"
+
+	"code.gitea.io/gitea/modules/git"
+	"github.com/rs/zerolog/log"
+)
+
+// ApplyCommitParams holds the parameters for cherry-picking or reverting a commit.
+type ApplyCommitParams struct {
+	WriteParams
+	// SHA is the sha of the commit that is cherry-picked or reverted.
+	SHA string
+	// Branch is the branch the changes are applied to (optional, default: default branch).
+	Branch string
+	// NewBranch (optional) is created from Branch and receives the new commit instead of Branch.
+	NewBranch string
+	// Mainline is the parent number (starting from 1) of a merge commit that is used as the base.
+	// It's mandatory for merge commits and has to be 0 otherwise.
+	Mainline int
+
+	// Title overwrites the generated commit title (optional).
+	Title string
+	// Message overwrites the generated commit message (optional).
+	Message string
+
+	// Committer overwrites the git committer used for the new commit
+	// (optional, default: actor)
+	Committer *Identity
+	// CommitterDate overwrites the git committer date used for the new commit
+	// (optional, default: current time on server)
+	CommitterDate *time.Time
+	// Author overwrites the git author used for the new commit
+	// (optional, default: author of the original commit for cherry-picks, committer for reverts)
+	Author *Identity
+	// AuthorDate overwrites the git author date used for the new commit
+	// (optional, default: author date of the original commit for cherry-picks, committer date for reverts)
+	AuthorDate *time.Time
+}
+
+func (p *ApplyCommitParams) Validate() error {
+	if err := p.WriteParams.Validate(); err != nil {
+		return err
+	}
+
+	if !isValidGitSHA(p.SHA) {
+		return errors.InvalidArgument("the provided commit sha '%s' is of invalid format.", p.SHA)
+	}
+
+	if p.Mainline < 0 {
+		return errors.InvalidArgument("mainline has to be a positive number")
+	}
+
+	return nil
+}
+
+type ApplyCommitOutput struct {
+	// CommitSHA is the sha of the newly created commit (empty in case of conflicts).
+	CommitSHA string
+	// Branch is the branch the new commit was pushed to.
+	Branch        string
+	ConflictFiles []string
+}
+
+// CherryPick applies the changes introduced by a commit on top of a branch.
+func (s *Service) CherryPick(ctx context.Context, params *ApplyCommitParams) (ApplyCommitOutput, error) {
+	return s.applyCommit(ctx, params, false)
+}
+
+// Revert applies the inverse of the changes introduced by a commit on top of a branch.
+func (s *Service) Revert(ctx context.Context, params *ApplyCommitParams) (ApplyCommitOutput, error) {
+	return s.applyCommit(ctx, params, true)
+}
+
+//nolint:gocognit,funlen
+func (s *Service) applyCommit(
+	ctx context.Context,
+	params *ApplyCommitParams,
+	revert bool,
+) (ApplyCommitOutput, error) {
+	if err := params.Validate(); err != nil {
+		return ApplyCommitOutput{}, err
+	}
+
+	log := log.Ctx(ctx).With().Str("repo_uid", params.RepoUID).Logger()
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	repo, err := s.adapter.OpenRepository(ctx, repoPath)
+	if err != nil {
+		return ApplyCommitOutput{}, fmt.Errorf("failed to open repo: %w", err)
+	}
+	defer repo.Close()
+
+	if err = s.validateApplyCommitBranches(repo, params); err != nil {
+		return ApplyCommitOutput{}, err
+	}
+
+	log.Debug().Msg("create shared repo")
+
+	shared, err := s.adapter.SharedRepository(s.tmpDir, params.RepoUID, repo.Path)
+	if err != nil {
+		return ApplyCommitOutput{}, fmt.Errorf("failed to create shared repository: %w", err)
+	}
+	defer shared.Close(ctx)
+
+	if err = shared.Clone(ctx, params.Branch); err != nil {
+		return ApplyCommitOutput{}, fmt.Errorf("failed to clone branch '%s': %w", params.Branch, err)
+	}
+
+	head, err := shared.GetBranchCommit(params.Branch)
+	if err != nil {
+		return ApplyCommitOutput{}, fmt.Errorf("failed to get latest commit of branch '%s': %w", params.Branch, err)
+	}
+
+	commit, err := shared.GetCommit(params.SHA)
+	if git.IsErrNotExist(err) {
+		return ApplyCommitOutput{}, errors.NotFound("commit '%s' doesn't exist", params.SHA)
+	}
+	if err != nil {
+		return ApplyCommitOutput{}, fmt.Errorf("failed to get commit '%s': %w", params.SHA, err)
+	}
+
+	parentSHA, err := getApplyCommitParent(commit, params.Mainline)
+	if err != nil {
+		return ApplyCommitOutput{}, err
+	}
+
+	// cherry-pick applies parent..commit, revert applies the inverse commit..parent.
+	base, theirs := parentSHA, commit.ID.String()
+	if revert {
+		base, theirs = theirs, base
+	}
+
+	log.Debug().Msg("merge trees")
+
+	conflicts, err := shared.MergeTree(ctx, base, head.ID.String(), theirs)
+	if err != nil {
+		return ApplyCommitOutput{}, fmt.Errorf("failed to merge changes of commit '%s': %w", params.SHA, err)
+	}
+	if len(conflicts) > 0 {
+		return ApplyCommitOutput{ConflictFiles: conflicts}, nil
+	}
+
+	treeHash, err := shared.WriteTree(ctx)
+	if err != nil {
+		return ApplyCommitOutput{}, fmt.Errorf("failed to write tree object: %w", err)
+	}
+
+	if treeHash == head.Tree.ID.String() {
+		return ApplyCommitOutput{}, errors.PreconditionFailed("commit '%s' doesn't introduce any changes on branch '%s'",
+			params.SHA, params.Branch)
+	}
+
+	committer := params.Actor
+	if params.Committer != nil {
+		committer = *params.Committer
+	}
+	committerDate := time.Now().UTC()
+	if params.CommitterDate != nil {
+		committerDate = *params.CommitterDate
+	}
+
+	// cherry-picked commits keep the original author, reverts are authored by the committer.
+	author := &types.Identity{Name: committer.Name, Email: committer.Email}
+	authorDate := committerDate
+	if !revert {
+		author = &types.Identity{Name: commit.Author.Name, Email: commit.Author.Email}
+		authorDate = commit.Author.When
+	}
+	if params.Author != nil {
+		author = &types.Identity{Name: params.Author.Name, Email: params.Author.Email}
+	}
+	if params.AuthorDate != nil {
+		authorDate = *params.AuthorDate
+	}
+
+	message := getApplyCommitMessage(commit, params, revert)
+
+	log.Debug().Msg("commit tree")
+
+	commitSHA, err := shared.CommitTreeWithDate(
+		ctx,
+		head.ID.String(),
+		author,
+		&types.Identity{
+			Name:  committer.Name,
+			Email: committer.Email,
+		},
+		treeHash,
+		message,
+		false,
+		authorDate,
+		committerDate,
+	)
+	if err != nil {
+		return ApplyCommitOutput{}, fmt.Errorf("failed to commit the tree: %w", err)
+	}
+
+	log.Debug().Msg("push branch to original repo")
+
+	env := CreateEnvironmentForPush(ctx, params.WriteParams)
+	if err = shared.PushCommitToBranch(ctx, commitSHA, params.NewBranch, false, env...); err != nil {
+		return ApplyCommitOutput{}, fmt.Errorf("failed to push commit to branch '%s': %w", params.NewBranch, err)
+	}
+
+	log.Debug().Msg("done")
+
+	return ApplyCommitOutput{
+		CommitSHA: commitSHA,
+		Branch:    params.NewBranch,
+	}, nil
+}
+
+func (s *Service) validateApplyCommitBranches(repo *git.Repository, params *ApplyCommitParams) error {
+	if params.Branch == "" {
+		defaultBranch, err := repo.GetDefaultBranch()
+		if err != nil {
+			return fmt.Errorf("failed to get default branch: %w", err)
+		}
+		params.Branch = defaultBranch
+	}
+
+	params.Branch = strings.TrimPrefix(strings.TrimSpace(params.Branch), gitReferenceNamePrefixBranch)
+	params.NewBranch = strings.TrimPrefix(strings.TrimSpace(params.NewBranch), gitReferenceNamePrefixBranch)
+	if params.NewBranch == "" {
+		params.NewBranch = params.Branch
+	}
+
+	if _, err := repo.GetBranch(params.Branch); err != nil {
+		if git.IsErrBranchNotExist(err) {
+			return errors.NotFound("branch '%s' doesn't exist", params.Branch)
+		}
+		return fmt.Errorf("failed to get branch '%s': %w", params.Branch, err)
+	}
+
+	if params.NewBranch != params.Branch {
+		existingBranch, err := repo.GetBranch(params.NewBranch)
+		if existingBranch != nil {
+			return errors.Conflict("branch '%s' already exists", existingBranch.Name)
+		}
+		if err != nil && !git.IsErrBranchNotExist(err) {
+			return fmt.Errorf("failed to get branch '%s': %w", params.NewBranch, err)
+		}
+	}
+
+	return nil
+}
+
+// getApplyCommitParent returns the parent of the commit that's used as base for applying the commit.
+func getApplyCommitParent(commit *git.Commit, mainline int) (string, error) {
+	parentCount := commit.ParentCount()
+
+	switch {
+	case parentCount == 0 && mainline == 0:
+		return git.EmptyTreeSHA, nil
+	case parentCount == 1 && mainline == 0:
+		return commit.Parents[0].String(), nil
+	case parentCount > 1 && mainline == 0:
+		return "", errors.InvalidArgument("commit '%s' is a merge commit but no mainline was provided",
+			commit.ID.String())
+	case parentCount <= 1:
+		return "", errors.InvalidArgument("mainline was provided but commit '%s' isn't a merge commit",
+			commit.ID.String())
+	case mainline > parentCount:
+		return "", errors.InvalidArgument("commit '%s' has no parent %d", commit.ID.String(), mainline)
+	default:
+		return commit.Parents[mainline-1].String(), nil
+	}
+}
+
+// getApplyCommitMessage returns the message of the new commit, following the conventions of the git cli.
+func getApplyCommitMessage(commit *git.Commit, params *ApplyCommitParams, revert bool) string {
+	title := strings.TrimSpace(params.Title)
+	body := strings.TrimSpace(params.Message)
+
+	if title == "" && revert {
+		title = fmt.Sprintf("Revert %q", commit.Summary())
+	}
+	if title == "" {
+		title = commit.Summary()
+	}
+
+	if body == "" && revert {
+		body = fmt.Sprintf("This reverts commit %s.", commit.ID.String())
+	}
+	if body == "" && !revert {
+		_, originalBody, _ := strings.Cut(strings.TrimSpace(commit.CommitMessage), "\n")
+		body = strings.TrimSpace(originalBody)
+		if body != "" {
+			body += "\n\n"
+		}
+		body += fmt.Sprintf("(cherry picked from commit %s)", commit.ID.String())
+	}
+
+	return title + "\n\n" + body
+}
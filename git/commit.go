@@ -30,11 +30,12 @@ type GetCommitParams struct {
 }
 
 type Commit struct {
-	SHA       string    `json:"sha"`
-	Title     string    `json:"title"`
-	Message   string    `json:"message,omitempty"`
-	Author    Signature `json:"author"`
-	Committer Signature `json:"committer"`
+	SHA       string           `json:"sha"`
+	Title     string           `json:"title"`
+	Message   string           `json:"message,omitempty"`
+	Author    Signature        `json:"author"`
+	Committer Signature        `json:"committer"`
+	Signature *ObjectSignature `json:"-"`
 }
 
 type GetCommitOutput struct {
@@ -90,6 +91,9 @@ type ListCommitsParams struct {
 	ReadParams
 	// GitREF is a git reference (branch / tag / commit SHA)
 	GitREF string
+	// GitREFRepoUID specifies the UID of the repo that contains GitREF (optional, default: RepoUID).
+	// It allows to list the commits of a fork that aren't part of After in the repo with RepoUID.
+	GitREFRepoUID string
 	// After is a git reference (branch / tag / commit SHA)
 	// If provided, commits only up to that reference will be returned (exlusive)
 	After string
@@ -105,6 +109,10 @@ type ListCommitsParams struct {
 
 	// Committer allows to filter for commits based on the committer - Optional, ignored if string is empty.
 	Committer string
+
+	// FollowRenames allows to continue listing the history of the file at Path beyond renames - Optional.
+	// It requires Path to be set.
+	FollowRenames bool
 }
 
 type RenameDetails struct {
@@ -117,7 +125,9 @@ type RenameDetails struct {
 type ListCommitsOutput struct {
 	Commits       []Commit
 	RenameDetails []*RenameDetails
-	TotalCommits  int
+	// Paths contains the path the file had at each of the commits, it's only set if FollowRenames is requested.
+	Paths        []string
+	TotalCommits int
 }
 
 func (s *Service) ListCommits(ctx context.Context, params *ListCommitsParams) (*ListCommitsOutput, error) {
@@ -125,22 +135,65 @@ func (s *Service) ListCommits(ctx context.Context, params *ListCommitsParams) (*
 		return nil, ErrNoParamsProvided
 	}
 
-	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	if params.FollowRenames && params.Path == "" {
+		return nil, errors.InvalidArgument("a path is required to follow renames")
+	}
 
-	gitCommits, renameDetails, err := s.adapter.ListCommits(
-		ctx,
-		repoPath,
-		params.GitREF,
-		int(params.Page),
-		int(params.Limit),
-		types.CommitFilter{
-			AfterRef:  params.After,
-			Path:      params.Path,
-			Since:     params.Since,
-			Until:     params.Until,
-			Committer: params.Committer,
-		},
+	var output *ListCommitsOutput
+	err := s.withCompareRepo(ctx, params.RepoUID, params.After, params.GitREFRepoUID, params.GitREF,
+		func(repoPath string, after string, gitRef string) error {
+			var err error
+			output, err = s.listCommits(ctx, repoPath, gitRef, after, params)
+			return err
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// listCommits lists the commits of gitRef (up to after) in the repo at repoPath.
+func (s *Service) listCommits(
+	ctx context.Context,
+	repoPath string,
+	gitRef string,
+	after string,
+	params *ListCommitsParams,
+) (*ListCommitsOutput, error) {
+	filter := types.CommitFilter{
+		AfterRef:  after,
+		Path:      params.Path,
+		Since:     params.Since,
+		Until:     params.Until,
+		Committer: params.Committer,
+	}
+
+	var (
+		gitCommits    []types.Commit
+		renameDetails []types.PathRenameDetails
+		paths         []string
+		err           error
 	)
+	if params.FollowRenames {
+		gitCommits, paths, err = s.adapter.ListCommitsFollowRenames(
+			ctx,
+			repoPath,
+			gitRef,
+			int(params.Page),
+			int(params.Limit),
+			filter,
+		)
+	} else {
+		gitCommits, renameDetails, err = s.adapter.ListCommits(
+			ctx,
+			repoPath,
+			gitRef,
+			int(params.Page),
+			int(params.Limit),
+			filter,
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -149,9 +202,9 @@ func (s *Service) ListCommits(ctx context.Context, params *ListCommitsParams) (*
 	totalCommits := 0
 	if params.Page == 1 && len(gitCommits) < int(params.Limit) {
 		totalCommits = len(gitCommits)
-	} else if params.After != "" && params.GitREF != params.After {
+	} else if after != "" && gitRef != after {
 		div, err := s.adapter.GetCommitDivergences(ctx, repoPath, []types.CommitDivergenceRequest{
-			{From: params.GitREF, To: params.After},
+			{From: gitRef, To: after},
 		}, 0)
 		if err != nil {
 			return nil, err
@@ -173,6 +226,7 @@ func (s *Service) ListCommits(ctx context.Context, params *ListCommitsParams) (*
 	return &ListCommitsOutput{
 		Commits:       commits,
 		RenameDetails: mapRenameDetails(renameDetails),
+		Paths:         paths,
 		TotalCommits:  totalCommits,
 	}, nil
 }
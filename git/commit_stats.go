@@ -0,0 +1,220 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/git/types"
+)
+
+// commitStatsCacheDuration is the time the statistics are kept in memory.
+// The statistics are cached per commit SHA, so cached entries never become outdated.
+const commitStatsCacheDuration = 15 * time.Minute
+
+type CommitStatsParams struct {
+	ReadParams
+	// GitREF is a git reference (branch / tag / commit SHA)
+	GitREF string
+
+	// Since allows to filter for commits since the provided UNIX timestamp - Optional, ignored if value is 0.
+	Since int64
+
+	// Until allows to filter for commits until the provided UNIX timestamp - Optional, ignored if value is 0.
+	Until int64
+
+	// Committer allows to filter for commits based on the committer - Optional, ignored if string is empty.
+	Committer string
+}
+
+type CommitStatsOutput struct {
+	// SHA is the commit the statistics were calculated for.
+	SHA string
+	// Contributors contains the statistics per commit author, the most active contributor first.
+	Contributors []ContributorStats
+	// Weeks contains the statistics of all commits per week, oldest week first.
+	Weeks []WeeklyCommitStats
+}
+
+type ContributorStats struct {
+	Author    Identity
+	Commits   int64
+	Additions int64
+	Deletions int64
+	// Weeks contains the statistics of the author's commits per week, oldest week first.
+	Weeks []WeeklyCommitStats
+}
+
+type WeeklyCommitStats struct {
+	// Week is the start of the week (Sunday 00:00 UTC).
+	Week      time.Time
+	Commits   int64
+	Additions int64
+	Deletions int64
+}
+
+// GetCommitStats returns the commit activity statistics of the commits reachable from the provided reference.
+// Only weeks with at least one commit are part of the output.
+func (s *Service) GetCommitStats(ctx context.Context, params *CommitStatsParams) (*CommitStatsOutput, error) {
+	if params == nil {
+		return nil, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	commit, err := s.adapter.GetCommit(ctx, repoPath, params.GitREF)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commitStatsCache.Get(ctx, commitStatsKey{
+		repoPath:  repoPath,
+		sha:       commit.SHA,
+		since:     params.Since,
+		until:     params.Until,
+		committer: params.Committer,
+	})
+}
+
+type commitStatsKey struct {
+	repoPath  string
+	sha       string
+	since     int64
+	until     int64
+	committer string
+}
+
+type commitStatsGetter struct {
+	adapter Adapter
+}
+
+// Find implements the cache.Getter interface.
+func (g commitStatsGetter) Find(ctx context.Context, key commitStatsKey) (*CommitStatsOutput, error) {
+	activities, err := g.adapter.ListCommitActivity(ctx, key.repoPath, key.sha, types.CommitFilter{
+		Since:     key.since,
+		Until:     key.until,
+		Committer: key.committer,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commit activity: %w", err)
+	}
+
+	stats := aggregateCommitStats(activities)
+	stats.SHA = key.sha
+
+	return stats, nil
+}
+
+// aggregateCommitStats groups the commit activity by author and by week.
+// Authors are identified by their email address, the name is taken from their most recent commit.
+func aggregateCommitStats(activities []types.CommitActivity) *CommitStatsOutput {
+	type contributor struct {
+		stats ContributorStats
+		weeks map[time.Time]*WeeklyCommitStats
+	}
+
+	contributors := make(map[string]*contributor)
+	weeks := make(map[time.Time]*WeeklyCommitStats)
+
+	// activities are ordered newest first, so the first commit of an author has the most recent name.
+	for i := range activities {
+		activity := &activities[i]
+		week := startOfWeek(activity.Author.When)
+
+		key := strings.ToLower(activity.Author.Identity.Email)
+		c, ok := contributors[key]
+		if !ok {
+			c = &contributor{
+				stats: ContributorStats{
+					Author: Identity{
+						Name:  activity.Author.Identity.Name,
+						Email: activity.Author.Identity.Email,
+					},
+				},
+				weeks: make(map[time.Time]*WeeklyCommitStats),
+			}
+			contributors[key] = c
+		}
+
+		c.stats.Commits++
+		c.stats.Additions += activity.Additions
+		c.stats.Deletions += activity.Deletions
+
+		addWeeklyCommitStats(c.weeks, week, activity)
+		addWeeklyCommitStats(weeks, week, activity)
+	}
+
+	out := &CommitStatsOutput{
+		Contributors: make([]ContributorStats, 0, len(contributors)),
+		Weeks:        sortedWeeklyCommitStats(weeks),
+	}
+
+	for _, c := range contributors {
+		c.stats.Weeks = sortedWeeklyCommitStats(c.weeks)
+		out.Contributors = append(out.Contributors, c.stats)
+	}
+
+	sort.Slice(out.Contributors, func(i, j int) bool {
+		ci, cj := &out.Contributors[i], &out.Contributors[j]
+		if ci.Commits != cj.Commits {
+			return ci.Commits > cj.Commits
+		}
+		if ci.Additions+ci.Deletions != cj.Additions+cj.Deletions {
+			return ci.Additions+ci.Deletions > cj.Additions+cj.Deletions
+		}
+		return ci.Author.Email < cj.Author.Email
+	})
+
+	return out
+}
+
+func addWeeklyCommitStats(weeks map[time.Time]*WeeklyCommitStats, week time.Time, activity *types.CommitActivity) {
+	w, ok := weeks[week]
+	if !ok {
+		w = &WeeklyCommitStats{Week: week}
+		weeks[week] = w
+	}
+
+	w.Commits++
+	w.Additions += activity.Additions
+	w.Deletions += activity.Deletions
+}
+
+func sortedWeeklyCommitStats(weeks map[time.Time]*WeeklyCommitStats) []WeeklyCommitStats {
+	out := make([]WeeklyCommitStats, 0, len(weeks))
+	for _, w := range weeks {
+		out = append(out, *w)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Week.Before(out[j].Week)
+	})
+
+	return out
+}
+
+// startOfWeek returns the start of the week (Sunday 00:00 UTC) of the provided time.
+func startOfWeek(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -int(day.Weekday()))
+}
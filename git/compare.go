@@ -0,0 +1,75 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+)
+
+// compareRepoFunc is executed against a repository that contains the objects of both sides of a comparison.
+type compareRepoFunc func(repoPath string, baseRef string, headRef string) error
+
+// withCompareRepo runs fn against a repository that is able to compare baseRef of the repo with repoUID
+// with headRef of the repo with headRepoUID.
+// If both refs live in the same repository, fn is executed against the repository itself.
+// Otherwise, the refs are resolved to commit SHAs in their respective repository and fn is executed against a
+// temporary repository that borrows the objects of both repositories via alternates.
+func (s *Service) withCompareRepo(
+	ctx context.Context,
+	repoUID string,
+	baseRef string,
+	headRepoUID string,
+	headRef string,
+	fn compareRepoFunc,
+) error {
+	repoPath := getFullPathForRepo(s.reposRoot, repoUID)
+
+	if headRepoUID == "" || headRepoUID == repoUID {
+		return fn(repoPath, baseRef, headRef)
+	}
+
+	headRepoPath := getFullPathForRepo(s.reposRoot, headRepoUID)
+
+	baseSHA := ""
+	if baseRef != "" {
+		baseCommit, err := s.adapter.GetCommit(ctx, repoPath, baseRef)
+		if err != nil {
+			return fmt.Errorf("failed to get base commit '%s': %w", baseRef, err)
+		}
+		baseSHA = baseCommit.SHA
+	}
+
+	headCommit, err := s.adapter.GetCommit(ctx, headRepoPath, headRef)
+	if err != nil {
+		return fmt.Errorf("failed to get head commit '%s': %w", headRef, err)
+	}
+
+	shared, err := s.adapter.SharedRepository(s.tmpDir, repoUID, repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to create shared repository: %w", err)
+	}
+	defer shared.Close(ctx)
+
+	if err = shared.Init(ctx); err != nil {
+		return fmt.Errorf("failed to initialize shared repository: %w", err)
+	}
+
+	if err = shared.AddAlternates(ctx, repoPath, headRepoPath); err != nil {
+		return fmt.Errorf("failed to add alternates to shared repository: %w", err)
+	}
+
+	return fn(shared.Path(), baseSHA, headCommit.SHA)
+}
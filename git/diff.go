@@ -24,14 +24,28 @@ import (
 
 	"github.com/harness/gitness/errors"
 	"github.com/harness/gitness/git/diff"
+	"github.com/harness/gitness/git/enum"
 	"github.com/harness/gitness/git/types"
 
 	"golang.org/x/sync/errgroup"
 )
 
+// DiffOptions contains the options used to customize how a diff is computed.
+type DiffOptions struct {
+	IgnoreWhitespace enum.DiffIgnoreWhitespace
+	// ContextLines is the number of context lines around each change (nil uses the git default).
+	ContextLines *int
+	// RenameThreshold is the similarity percentage for rename and copy detection (0 uses the git default).
+	RenameThreshold int
+	DetectCopies    bool
+}
+
 type DiffParams struct {
 	ReadParams
-	BaseRef      string
+	DiffOptions
+	BaseRef string
+	// HeadRepoUID specifies the UID of the repo that contains the head ref (optional, default: RepoUID).
+	HeadRepoUID  string
 	HeadRef      string
 	MergeBase    bool
 	IncludePatch bool
@@ -57,21 +71,32 @@ func (s *Service) rawDiff(ctx context.Context, params *DiffParams, w io.Writer)
 		return err
 	}
 
-	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	return s.withCompareRepo(ctx, params.RepoUID, params.BaseRef, params.HeadRepoUID, params.HeadRef,
+		func(repoPath string, baseRef string, headRef string) error {
+			return s.adapter.RawDiff(ctx,
+				repoPath,
+				baseRef,
+				headRef,
+				params.MergeBase,
+				mapDiffOptions(params.DiffOptions),
+				w,
+			)
+		})
+}
 
-	err := s.adapter.RawDiff(ctx, repoPath, params.BaseRef, params.HeadRef, params.MergeBase, w)
-	if err != nil {
-		return err
-	}
-	return nil
+type CommitDiffParams struct {
+	ReadParams
+	DiffOptions
+	// SHA is the git commit sha
+	SHA string
 }
 
-func (s *Service) CommitDiff(ctx context.Context, params *GetCommitParams, out io.Writer) error {
+func (s *Service) CommitDiff(ctx context.Context, params *CommitDiffParams, out io.Writer) error {
 	if !isValidGitSHA(params.SHA) {
 		return errors.InvalidArgument("the provided commit sha '%s' is of invalid format.", params.SHA)
 	}
 	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
-	err := s.adapter.CommitDiff(ctx, repoPath, params.SHA, out)
+	err := s.adapter.CommitDiff(ctx, repoPath, params.SHA, mapDiffOptions(params.DiffOptions), out)
 	if err != nil {
 		return err
 	}
@@ -89,13 +114,19 @@ func (s *Service) DiffShortStat(ctx context.Context, params *DiffParams) (DiffSh
 	if err := params.Validate(); err != nil {
 		return DiffShortStatOutput{}, err
 	}
-	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
-	stat, err := s.adapter.DiffShortStat(ctx,
-		repoPath,
-		params.BaseRef,
-		params.HeadRef,
-		params.MergeBase,
-	)
+
+	var stat types.DiffShortStat
+	err := s.withCompareRepo(ctx, params.RepoUID, params.BaseRef, params.HeadRepoUID, params.HeadRef,
+		func(repoPath string, baseRef string, headRef string) error {
+			var err error
+			stat, err = s.adapter.DiffShortStat(ctx,
+				repoPath,
+				baseRef,
+				headRef,
+				params.MergeBase,
+			)
+			return err
+		})
 	if err != nil {
 		return DiffShortStatOutput{}, err
 	}
@@ -112,6 +143,10 @@ type DiffStatsOutput struct {
 }
 
 func (s *Service) DiffStats(ctx context.Context, params *DiffParams) (DiffStatsOutput, error) {
+	if err := params.Validate(); err != nil {
+		return DiffStatsOutput{}, err
+	}
+
 	// declare variables which will be used in go routines,
 	// no need for atomic operations because writing and reading variable
 	// doesn't happen at the same time
@@ -120,47 +155,36 @@ func (s *Service) DiffStats(ctx context.Context, params *DiffParams) (DiffStatsO
 		totalFiles   int
 	)
 
-	errGroup, groupCtx := errgroup.WithContext(ctx)
+	err := s.withCompareRepo(ctx, params.RepoUID, params.BaseRef, params.HeadRepoUID, params.HeadRef,
+		func(repoPath string, baseRef string, headRef string) error {
+			errGroup, groupCtx := errgroup.WithContext(ctx)
 
-	errGroup.Go(func() error {
-		// read total commits
-
-		options := &GetCommitDivergencesParams{
-			ReadParams: params.ReadParams,
-			Requests: []CommitDivergenceRequest{
-				{
-					From: params.HeadRef,
-					To:   params.BaseRef,
-				},
-			},
-		}
+			errGroup.Go(func() error {
+				// read total commits
+				divergences, err := s.adapter.GetCommitDivergences(groupCtx, repoPath,
+					[]types.CommitDivergenceRequest{{From: headRef, To: baseRef}}, 0)
+				if err != nil {
+					return err
+				}
+				if len(divergences) > 0 {
+					totalCommits = int(divergences[0].Ahead)
+				}
+				return nil
+			})
+
+			errGroup.Go(func() error {
+				// read short stat
+				// merge base must be true, because commitDivergences use tripple dot notation
+				stat, err := s.adapter.DiffShortStat(groupCtx, repoPath, baseRef, headRef, true)
+				if err != nil {
+					return err
+				}
+				totalFiles = stat.Files
+				return nil
+			})
 
-		rpcOutput, err := s.GetCommitDivergences(groupCtx, options)
-		if err != nil {
-			return err
-		}
-		if len(rpcOutput.Divergences) > 0 {
-			totalCommits = int(rpcOutput.Divergences[0].Ahead)
-		}
-		return nil
-	})
-
-	errGroup.Go(func() error {
-		// read short stat
-		stat, err := s.DiffShortStat(groupCtx, &DiffParams{
-			ReadParams: params.ReadParams,
-			BaseRef:    params.BaseRef,
-			HeadRef:    params.HeadRef,
-			MergeBase:  true, // must be true, because commitDivergences use tripple dot notation
+			return errGroup.Wait()
 		})
-		if err != nil {
-			return err
-		}
-		totalFiles = stat.Files
-		return nil
-	})
-
-	err := errGroup.Wait()
 	if err != nil {
 		return DiffStatsOutput{}, err
 	}
@@ -315,6 +339,7 @@ const (
 	FileDiffStatusModified  FileDiffStatus = "MODIFIED"
 	FileDiffStatusDeleted   FileDiffStatus = "DELETED"
 	FileDiffStatusRenamed   FileDiffStatus = "RENAMED"
+	FileDiffStatusCopied    FileDiffStatus = "COPIED"
 )
 
 func parseFileDiffStatus(ftype diff.FileType) FileDiffStatus {
@@ -327,6 +352,8 @@ func parseFileDiffStatus(ftype diff.FileType) FileDiffStatus {
 		return FileDiffStatusModified
 	case diff.FileRename:
 		return FileDiffStatusRenamed
+	case diff.FileCopy:
+		return FileDiffStatusCopied
 	default:
 		return FileDiffStatusUndefined
 	}
@@ -54,6 +54,7 @@ const (
 	FileChange
 	FileDelete
 	FileRename
+	FileCopy
 )
 
 // Line represents a line in diff.
@@ -158,6 +159,8 @@ func (f *File) Status() string {
 		return "deleted"
 	case f.Type == FileRename:
 		return "renamed"
+	case f.Type == FileCopy:
+		return "copied"
 	case f.Type == FileChange:
 		return "changed"
 	default:
@@ -262,6 +265,7 @@ func (p *Parser) parseFileHeader() (*File, error) {
 
 	// Check file diff type and submodule
 	var err error
+	var identical bool
 checkType:
 	for !p.isEOF {
 		if err = p.readLine(); err != nil {
@@ -317,9 +321,13 @@ checkType:
 			file.Type = FileRename
 			file.OldPath = a
 			file.Path = b
-
-			// No need to look for index if it's a pure rename
-			if strings.HasSuffix(subLine, "100%") {
+			identical = strings.HasSuffix(subLine, "100%")
+		case strings.HasPrefix(subLine, enum.DiffExtHeaderCopyFrom):
+			file.Type = FileCopy
+		case strings.HasPrefix(subLine, enum.DiffExtHeaderRenameTo),
+			strings.HasPrefix(subLine, enum.DiffExtHeaderCopyTo):
+			// No need to look for index if it's a pure rename or copy
+			if identical {
 				break checkType
 			}
 		case strings.HasPrefix(subLine, enum.DiffExtHeaderNewMode):
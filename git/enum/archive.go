@@ -0,0 +1,69 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enum
+
+import "strings"
+
+// ArchiveFormat represents the format of a repository archive.
+type ArchiveFormat string
+
+const (
+	ArchiveFormatTar   ArchiveFormat = "tar"
+	ArchiveFormatZip   ArchiveFormat = "zip"
+	ArchiveFormatTarGz ArchiveFormat = "tar.gz"
+	ArchiveFormatTgz   ArchiveFormat = "tgz"
+)
+
+var ArchiveFormats = []ArchiveFormat{
+	ArchiveFormatTar,
+	ArchiveFormatZip,
+	ArchiveFormatTarGz,
+	ArchiveFormatTgz,
+}
+
+func (f ArchiveFormat) Sanitize() (ArchiveFormat, bool) {
+	switch f {
+	case ArchiveFormatTar, ArchiveFormatZip, ArchiveFormatTarGz, ArchiveFormatTgz:
+		return f, true
+	default:
+		return ArchiveFormatTarGz, false
+	}
+}
+
+// ContentType returns the MIME type of the archive format.
+func (f ArchiveFormat) ContentType() string {
+	switch f {
+	case ArchiveFormatTar:
+		return "application/x-tar"
+	case ArchiveFormatZip:
+		return "application/zip"
+	case ArchiveFormatTarGz, ArchiveFormatTgz:
+		return "application/gzip"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// ParseArchiveFormatFromFileName returns the archive format based on the extension of the file name
+// and the file name without the extension.
+func ParseArchiveFormatFromFileName(fileName string) (ArchiveFormat, string, bool) {
+	for _, format := range ArchiveFormats {
+		if name, ok := strings.CutSuffix(fileName, "."+string(format)); ok && name != "" {
+			return format, name, true
+		}
+	}
+
+	return "", fileName, false
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enum
+
+// DiffIgnoreWhitespace defines how whitespace changes are treated when computing a diff.
+type DiffIgnoreWhitespace string
+
+const (
+	// DiffIgnoreWhitespaceNone shows all whitespace changes.
+	DiffIgnoreWhitespaceNone DiffIgnoreWhitespace = "none"
+	// DiffIgnoreWhitespaceAll ignores whitespace when comparing lines.
+	DiffIgnoreWhitespaceAll DiffIgnoreWhitespace = "all"
+	// DiffIgnoreWhitespaceChange ignores changes in the amount of whitespace.
+	DiffIgnoreWhitespaceChange DiffIgnoreWhitespace = "change"
+	// DiffIgnoreWhitespaceEOL ignores whitespace changes at the end of lines.
+	DiffIgnoreWhitespaceEOL DiffIgnoreWhitespace = "eol"
+)
+
+var DiffIgnoreWhitespaces = []DiffIgnoreWhitespace{
+	DiffIgnoreWhitespaceNone,
+	DiffIgnoreWhitespaceAll,
+	DiffIgnoreWhitespaceChange,
+	DiffIgnoreWhitespaceEOL,
+}
+
+func (w DiffIgnoreWhitespace) Sanitize() (DiffIgnoreWhitespace, bool) {
+	switch w {
+	case DiffIgnoreWhitespaceNone, DiffIgnoreWhitespaceAll, DiffIgnoreWhitespaceChange, DiffIgnoreWhitespaceEOL:
+		return w, true
+	case "":
+		return DiffIgnoreWhitespaceNone, true
+	default:
+		return DiffIgnoreWhitespaceNone, false
+	}
+}
+
+// Arg returns the git diff argument for the whitespace mode, or an empty string if none is needed.
+func (w DiffIgnoreWhitespace) Arg() string {
+	switch w {
+	case DiffIgnoreWhitespaceAll:
+		return "--ignore-all-space"
+	case DiffIgnoreWhitespaceChange:
+		return "--ignore-space-change"
+	case DiffIgnoreWhitespaceEOL:
+		return "--ignore-space-at-eol"
+	case DiffIgnoreWhitespaceNone:
+		return ""
+	default:
+		return ""
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 )
@@ -48,7 +49,8 @@ func (c *CLICore) PreReceive(ctx context.Context) error {
 	}
 
 	in := PreReceiveInput{
-		RefUpdates: refUpdates,
+		RefUpdates:  refUpdates,
+		Environment: getEnvironment(),
 	}
 
 	out, err := c.client.PreReceive(ctx, in)
@@ -87,6 +89,22 @@ func (c *CLICore) PostReceive(ctx context.Context) error {
 	return handleServerHookOutput(out, err)
 }
 
+// getEnvironment returns the git environment of the hook execution.
+// During a push git stores new objects in a quarantine directory, which is only accessible via the env variables.
+func getEnvironment() Environment {
+	var dirs []string
+	if dir := os.Getenv("GIT_OBJECT_DIRECTORY"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if alternates := os.Getenv("GIT_ALTERNATE_OBJECT_DIRECTORIES"); alternates != "" {
+		dirs = append(dirs, filepath.SplitList(alternates)...)
+	}
+
+	return Environment{
+		AlternateObjectDirs: dirs,
+	}
+}
+
 //nolint:forbidigo // outputing to CMD as that's where git reads the data
 func handleServerHookOutput(out Output, err error) error {
 	if err != nil {
@@ -43,6 +43,16 @@ type PostReceiveInput struct {
 type PreReceiveInput struct {
 	// RefUpdates contains all references that are being updated as part of the git operation.
 	RefUpdates []ReferenceUpdate `json:"ref_updates"`
+
+	// Environment contains the information required to access the objects that are being pushed.
+	Environment Environment `json:"environment"`
+}
+
+// Environment contains the git environment of a hook execution.
+type Environment struct {
+	// AlternateObjectDirs contains the object directories (e.g. quarantine) git uses for the push.
+	// They are required for reading any of the new objects before the push is accepted.
+	AlternateObjectDirs []string `json:"alternate_object_dirs,omitempty"`
 }
 
 // UpdateInput represents the input of the update git hook.
@@ -0,0 +1,105 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/harness/gitness/errors"
+
+	"github.com/rs/zerolog/log"
+)
+
+type MaintainRepositoryParams struct {
+	ReadParams
+	// PruneExpiry is the minimum age of unreachable objects before they are pruned.
+	PruneExpiry time.Duration
+}
+
+func (p *MaintainRepositoryParams) Validate() error {
+	if p == nil {
+		return ErrNoParamsProvided
+	}
+
+	if err := p.ReadParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.PruneExpiry < 0 {
+		return errors.InvalidArgument("prune expiry can't be negative")
+	}
+
+	return nil
+}
+
+// MaintainRepository runs the object housekeeping tasks on the repository: It prunes unreachable objects,
+// repacks the objects incrementally and writes the commit-graph. The references are packed separately (see PackRefs).
+// The caller is responsible for preventing concurrent maintenance of the same repository.
+func (s *Service) MaintainRepository(ctx context.Context, params *MaintainRepositoryParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+	log := log.Ctx(ctx).With().Str("repo_uid", params.RepoUID).Logger()
+
+	// prune before repacking to avoid packing objects that would be removed anyway.
+	if err := s.adapter.PruneObjects(ctx, repoPath, params.PruneExpiry); err != nil {
+		return fmt.Errorf("failed to prune objects: %w", err)
+	}
+	log.Debug().Msg("pruned objects")
+
+	if err := s.adapter.RepackObjects(ctx, repoPath); err != nil {
+		return fmt.Errorf("failed to repack objects: %w", err)
+	}
+	log.Debug().Msg("repacked objects")
+
+	if err := s.adapter.WriteCommitGraph(ctx, repoPath); err != nil {
+		return fmt.Errorf("failed to write commit graph: %w", err)
+	}
+	log.Debug().Msg("wrote commit graph")
+
+	return nil
+}
+
+type PackRefsParams struct {
+	ReadParams
+}
+
+func (p *PackRefsParams) Validate() error {
+	if p == nil {
+		return ErrNoParamsProvided
+	}
+
+	return p.ReadParams.Validate()
+}
+
+// PackRefs packs all loose references of the repository into the packed-refs file.
+// It's kept separate from the object housekeeping as it rewrites the references the pushes update.
+func (s *Service) PackRefs(ctx context.Context, params *PackRefsParams) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	if err := s.adapter.PackRefs(ctx, repoPath); err != nil {
+		return fmt.Errorf("failed to pack refs: %w", err)
+	}
+
+	return nil
+}
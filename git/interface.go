@@ -26,6 +26,7 @@ type Interface interface {
 	ListTreeNodes(ctx context.Context, params *ListTreeNodeParams) (*ListTreeNodeOutput, error)
 	GetSubmodule(ctx context.Context, params *GetSubmoduleParams) (*GetSubmoduleOutput, error)
 	GetBlob(ctx context.Context, params *GetBlobParams) (*GetBlobOutput, error)
+	GetLanguageStats(ctx context.Context, params *GetLanguageStatsParams) (*GetLanguageStatsOutput, error)
 	CreateBranch(ctx context.Context, params *CreateBranchParams) (*CreateBranchOutput, error)
 	CreateCommitTag(ctx context.Context, params *CreateCommitTagParams) (*CreateCommitTagOutput, error)
 	DeleteTag(ctx context.Context, params *DeleteTagParams) error
@@ -37,6 +38,11 @@ type Interface interface {
 
 	GetRepositorySize(ctx context.Context, params *GetRepositorySizeParams) (*GetRepositorySizeOutput, error)
 
+	// MaintainRepository runs the object housekeeping tasks (prune, repack, commit-graph) on a repository.
+	MaintainRepository(ctx context.Context, params *MaintainRepositoryParams) error
+	// PackRefs packs the loose references of a repository.
+	PackRefs(ctx context.Context, params *PackRefsParams) error
+
 	// UpdateRef creates, updates or deletes a git ref. If the OldValue is defined it must match the reference value
 	// prior to the call. To remove a ref use the zero ref as the NewValue. To require the creation of a new one and
 	// not update of an exiting one, set the zero ref as the OldValue.
@@ -46,16 +52,22 @@ type Interface interface {
 
 	MatchFiles(ctx context.Context, params *MatchFilesParams) (*MatchFilesOutput, error)
 
+	Archive(ctx context.Context, params *ArchiveParams, w io.Writer) error
+
 	/*
 	 * Commits service
 	 */
 	GetCommit(ctx context.Context, params *GetCommitParams) (*GetCommitOutput, error)
 	ListCommits(ctx context.Context, params *ListCommitsParams) (*ListCommitsOutput, error)
+	GetCommitStats(ctx context.Context, params *CommitStatsParams) (*CommitStatsOutput, error)
 	ListCommitTags(ctx context.Context, params *ListCommitTagsParams) (*ListCommitTagsOutput, error)
 	GetCommitDivergences(ctx context.Context, params *GetCommitDivergencesParams) (*GetCommitDivergencesOutput, error)
 	CommitFiles(ctx context.Context, params *CommitFilesParams) (CommitFilesResponse, error)
 	MergeBase(ctx context.Context, params MergeBaseParams) (MergeBaseOutput, error)
 	IsAncestor(ctx context.Context, params IsAncestorParams) (IsAncestorOutput, error)
+	ListPushedCommits(ctx context.Context, params *ListPushedCommitsParams) (ListPushedCommitsOutput, error)
+	VerifySignature(params *VerifySignatureParams) (VerifySignatureOutput, error)
+	GetSigningKey() *SigningKey
 
 	/*
 	 * Git Cli Service
@@ -69,7 +81,7 @@ type Interface interface {
 	RawDiff(ctx context.Context, in *DiffParams, w io.Writer) error
 	Diff(ctx context.Context, in *DiffParams) (<-chan *FileDiff, <-chan error)
 	DiffFileNames(ctx context.Context, in *DiffParams) (DiffFileNamesOutput, error)
-	CommitDiff(ctx context.Context, params *GetCommitParams, w io.Writer) error
+	CommitDiff(ctx context.Context, params *CommitDiffParams, w io.Writer) error
 	DiffShortStat(ctx context.Context, params *DiffParams) (DiffShortStatOutput, error)
 	DiffStats(ctx context.Context, params *DiffParams) (DiffStatsOutput, error)
 
@@ -80,6 +92,10 @@ type Interface interface {
 	 * Merge services
 	 */
 	Merge(ctx context.Context, in *MergeParams) (MergeOutput, error)
+	CherryPick(ctx context.Context, params *ApplyCommitParams) (ApplyCommitOutput, error)
+	Revert(ctx context.Context, params *ApplyCommitParams) (ApplyCommitOutput, error)
+	GetMergeConflicts(ctx context.Context, params *MergeConflictsParams) (MergeConflictsOutput, error)
+	ResolveMergeConflicts(ctx context.Context, params *ResolveMergeConflictsParams) (ResolveMergeConflictsOutput, error)
 
 	/*
 	 * Blame services
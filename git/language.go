@@ -0,0 +1,60 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/errors"
+)
+
+type GetLanguageStatsParams struct {
+	ReadParams
+	// SHA is the commit SHA whose tree is analyzed.
+	SHA string
+}
+
+type GetLanguageStatsOutput struct {
+	// Languages maps the name of each detected language to the number of bytes of code written in it.
+	Languages map[string]int64
+}
+
+// GetLanguageStats returns the language statistics of the tree of the provided commit.
+func (s *Service) GetLanguageStats(
+	ctx context.Context,
+	params *GetLanguageStatsParams,
+) (*GetLanguageStatsOutput, error) {
+	if params == nil {
+		return nil, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if !isValidGitSHA(params.SHA) {
+		return nil, errors.InvalidArgument("the provided commit sha '%s' is of invalid format.", params.SHA)
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	languages, err := s.adapter.GetLanguageStats(ctx, repoPath, params.SHA)
+	if err != nil {
+		return nil, fmt.Errorf("GetLanguageStats: failed to get language stats: %w", err)
+	}
+
+	return &GetLanguageStatsOutput{
+		Languages: languages,
+	}, nil
+}
@@ -62,9 +62,21 @@ func mapCommit(c *types.Commit) (*Commit, error) {
 		Message:   c.Message,
 		Author:    *author,
 		Committer: *comitter,
+		Signature: mapObjectSignature(c.Signature),
 	}, nil
 }
 
+func mapObjectSignature(s *types.ObjectSignature) *ObjectSignature {
+	if s == nil {
+		return nil
+	}
+
+	return &ObjectSignature{
+		Signature: s.Signature,
+		Payload:   s.Payload,
+	}
+}
+
 func mapSignature(s *types.Signature) (*Signature, error) {
 	if s == nil {
 		return nil, fmt.Errorf("rpc signature is nil")
@@ -114,6 +126,7 @@ func mapAnnotatedTag(tag *types.Tag) *CommitTag {
 		Title:       tag.Title,
 		Message:     tag.Message,
 		Tagger:      tagger,
+		Signature:   mapObjectSignature(tag.Signature),
 		IsAnnotated: true,
 		Commit:      nil,
 	}
@@ -231,3 +244,12 @@ func mapDiffFileHeader(h *types.DiffFileHeader) DiffFileHeader {
 		Extensions: h.Extensions,
 	}
 }
+
+func mapDiffOptions(o DiffOptions) types.DiffOptions {
+	return types.DiffOptions{
+		IgnoreWhitespace: o.IgnoreWhitespace,
+		ContextLines:     o.ContextLines,
+		RenameThreshold:  o.RenameThreshold,
+		DetectCopies:     o.DetectCopies,
+	}
+}
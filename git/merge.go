@@ -35,8 +35,7 @@ import (
 type MergeParams struct {
 	WriteParams
 	BaseBranch string
-	// HeadRepoUID specifies the UID of the repo that contains the head branch (required for forking).
-	// WARNING: This field is currently not supported yet!
+	// HeadRepoUID specifies the UID of the repo that contains the head branch (optional, default: RepoUID).
 	HeadRepoUID string
 	HeadBranch  string
 	Title       string
@@ -136,6 +135,9 @@ func (s *Service) Merge(ctx context.Context, params *MergeParams) (MergeOutput,
 		BaseBranch:   params.BaseBranch,
 		HeadBranch:   params.HeadBranch,
 	}
+	if params.HeadRepoUID != "" {
+		pr.HeadRepoPath = getFullPathForRepo(s.reposRoot, params.HeadRepoUID)
+	}
 
 	log.Debug().Msg("create temporary repository")
 
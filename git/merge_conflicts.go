@@ -0,0 +1,613 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git/types"
+
+	"code.gitea.io/gitea/modules/git"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// submoduleFileMode is the index mode of submodule entries.
+	submoduleFileMode = "160000"
+	// maxMergeConflictFileSize is the max size of a file version for which the content is returned.
+	maxMergeConflictFileSize = 1 << 20 // 1 MiB
+	// binaryDetectionSize is the number of bytes inspected to decide whether a file is binary (same as git).
+	binaryDetectionSize = 8000
+)
+
+// conflictMarkers are the markers git uses to start, split and end a conflicting hunk.
+// NOTE: Only the full sequence is treated as a conflict, as each marker on its own is commonly
+// used in text files (e.g. markdown headings or quoted emails).
+var conflictMarkers = [][]byte{[]byte("<<<<<<<"), []byte("======="), []byte(">>>>>>>")}
+
+// MergeConflictsParams holds the parameters for listing the conflicts of merging
+// the target branch into the source branch.
+type MergeConflictsParams struct {
+	ReadParams
+	// SourceBranch is the branch the target branch gets merged into.
+	SourceBranch string
+	// TargetBranch is the branch that gets merged into the source branch.
+	TargetBranch string
+}
+
+func (p *MergeConflictsParams) Validate() error {
+	if err := p.ReadParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.SourceBranch == "" {
+		return errors.InvalidArgument("source branch cannot be empty")
+	}
+
+	if p.TargetBranch == "" {
+		return errors.InvalidArgument("target branch cannot be empty")
+	}
+
+	return nil
+}
+
+// MergeConflictFileVersion is a single version (base, ours or theirs) of a conflicted file.
+type MergeConflictFileVersion struct {
+	SHA  string
+	Mode string
+	Size int64
+	// Content is the content of the file version.
+	// It's nil for binary files, submodules and files that exceed the size limit.
+	Content []byte
+}
+
+// MergeConflictFile is a file that couldn't be merged automatically.
+// Ours is the version of the source branch, Theirs the version of the target branch
+// and Base the version of the merge base. A version is nil if the file doesn't exist in it.
+type MergeConflictFile struct {
+	Path     string
+	Base     *MergeConflictFileVersion
+	Ours     *MergeConflictFileVersion
+	Theirs   *MergeConflictFileVersion
+	IsBinary bool
+	// Content is the result of the three-way merge including conflict markers.
+	// It's nil if the file can't be merged on content level (e.g. binary files or modify/delete conflicts).
+	Content []byte
+}
+
+type MergeConflictsOutput struct {
+	SourceSHA    string
+	TargetSHA    string
+	MergeBaseSHA string
+	Files        []MergeConflictFile
+}
+
+// ResolvedMergeConflictFile contains the resolution of a conflicted file.
+type ResolvedMergeConflictFile struct {
+	Path    string
+	Content []byte
+	// Delete resolves the conflict by deleting the file, Content is ignored.
+	Delete bool
+}
+
+// ResolveMergeConflictsParams holds the parameters for merging the target branch into the source branch
+// using the provided resolutions of all conflicted files.
+type ResolveMergeConflictsParams struct {
+	WriteParams
+	// SourceBranch is the branch the target branch gets merged into.
+	SourceBranch string
+	// SourceExpectedSHA (optional) is the expected head of the source branch.
+	SourceExpectedSHA string
+	// TargetBranch is the branch that gets merged into the source branch.
+	TargetBranch string
+	// TargetExpectedSHA (optional) is the expected head of the target branch.
+	TargetExpectedSHA string
+
+	// Files contains the resolutions of all conflicted files.
+	Files []ResolvedMergeConflictFile
+
+	// Message overwrites the generated merge commit message (optional).
+	Message string
+
+	// Committer overwrites the git committer used for the merge commit
+	// (optional, default: actor)
+	Committer *Identity
+	// CommitterDate overwrites the git committer date used for the merge commit
+	// (optional, default: current time on server)
+	CommitterDate *time.Time
+	// Author overwrites the git author used for the merge commit
+	// (optional, default: committer)
+	Author *Identity
+	// AuthorDate overwrites the git author date used for the merge commit
+	// (optional, default: committer date)
+	AuthorDate *time.Time
+}
+
+func (p *ResolveMergeConflictsParams) Validate() error {
+	if err := p.WriteParams.Validate(); err != nil {
+		return err
+	}
+
+	if p.SourceBranch == "" {
+		return errors.InvalidArgument("source branch cannot be empty")
+	}
+
+	if p.TargetBranch == "" {
+		return errors.InvalidArgument("target branch cannot be empty")
+	}
+
+	paths := make(map[string]struct{}, len(p.Files))
+	for _, file := range p.Files {
+		if file.Path == "" {
+			return errors.InvalidArgument("path of resolved file cannot be empty")
+		}
+		if _, ok := paths[file.Path]; ok {
+			return errors.InvalidArgument("file '%s' is resolved more than once", file.Path)
+		}
+		paths[file.Path] = struct{}{}
+	}
+
+	return nil
+}
+
+type ResolveMergeConflictsOutput struct {
+	// CommitSHA is the sha of the merge commit that was pushed to the source branch.
+	CommitSHA    string
+	SourceSHA    string
+	TargetSHA    string
+	MergeBaseSHA string
+}
+
+// GetMergeConflicts returns all files that conflict when merging the target branch into the source branch.
+func (s *Service) GetMergeConflicts(
+	ctx context.Context,
+	params *MergeConflictsParams,
+) (MergeConflictsOutput, error) {
+	if err := params.Validate(); err != nil {
+		return MergeConflictsOutput{}, err
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	merge, err := s.mergeConflictsTree(ctx, params.RepoUID, params.SourceBranch, params.TargetBranch, "", "")
+	if err != nil {
+		return MergeConflictsOutput{}, err
+	}
+	defer merge.shared.Close(ctx)
+
+	entries, err := merge.shared.ListUnmergedEntries(ctx)
+	if err != nil {
+		return MergeConflictsOutput{}, fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+
+	files := make([]MergeConflictFile, len(entries))
+	for i := range entries {
+		files[i], err = s.getMergeConflictFile(ctx, repoPath, merge, &entries[i],
+			params.SourceBranch, params.TargetBranch)
+		if err != nil {
+			return MergeConflictsOutput{}, fmt.Errorf("failed to get conflicted file '%s': %w", entries[i].Path, err)
+		}
+	}
+
+	return MergeConflictsOutput{
+		SourceSHA:    merge.sourceSHA,
+		TargetSHA:    merge.targetSHA,
+		MergeBaseSHA: merge.mergeBaseSHA,
+		Files:        files,
+	}, nil
+}
+
+// ResolveMergeConflicts merges the target branch into the source branch using the provided resolutions
+// for all conflicted files and pushes the resulting merge commit to the source branch.
+//
+//nolint:gocognit
+func (s *Service) ResolveMergeConflicts(
+	ctx context.Context,
+	params *ResolveMergeConflictsParams,
+) (ResolveMergeConflictsOutput, error) {
+	if err := params.Validate(); err != nil {
+		return ResolveMergeConflictsOutput{}, err
+	}
+
+	log := log.Ctx(ctx).With().Str("repo_uid", params.RepoUID).Logger()
+
+	merge, err := s.mergeConflictsTree(ctx, params.RepoUID, params.SourceBranch, params.TargetBranch,
+		params.SourceExpectedSHA, params.TargetExpectedSHA)
+	if err != nil {
+		return ResolveMergeConflictsOutput{}, err
+	}
+	defer merge.shared.Close(ctx)
+
+	entries, err := merge.shared.ListUnmergedEntries(ctx)
+	if err != nil {
+		return ResolveMergeConflictsOutput{}, fmt.Errorf("failed to list conflicted files: %w", err)
+	}
+
+	conflicts := make(map[string]*types.UnmergedEntry, len(entries))
+	for i := range entries {
+		conflicts[entries[i].Path] = &entries[i]
+	}
+
+	log.Debug().Msg("apply resolved files")
+
+	for _, file := range params.Files {
+		entry, ok := conflicts[file.Path]
+		if !ok {
+			return ResolveMergeConflictsOutput{}, errors.InvalidArgument(
+				"file '%s' doesn't have any merge conflicts", file.Path)
+		}
+		delete(conflicts, file.Path)
+
+		if file.Delete {
+			if err = merge.shared.RemoveFilesFromIndex(ctx, file.Path); err != nil {
+				return ResolveMergeConflictsOutput{}, fmt.Errorf("failed to delete file '%s': %w", file.Path, err)
+			}
+			continue
+		}
+
+		if containsConflictMarkers(file.Content) {
+			return ResolveMergeConflictsOutput{}, errors.InvalidArgument(
+				"resolution of file '%s' still contains conflict markers", file.Path)
+		}
+
+		sha, err := merge.shared.WriteGitObject(ctx, bytes.NewReader(file.Content))
+		if err != nil {
+			return ResolveMergeConflictsOutput{}, fmt.Errorf("failed to write file '%s': %w", file.Path, err)
+		}
+
+		if err = merge.shared.AddObjectToIndex(ctx, getResolvedFileMode(entry), sha, file.Path); err != nil {
+			return ResolveMergeConflictsOutput{}, fmt.Errorf("failed to add file '%s': %w", file.Path, err)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		unresolved := make([]string, 0, len(conflicts))
+		for i := range entries {
+			if _, ok := conflicts[entries[i].Path]; ok {
+				unresolved = append(unresolved, entries[i].Path)
+			}
+		}
+		return ResolveMergeConflictsOutput{}, errors.InvalidArgument(
+			"merge conflicts of files %s are not resolved", strings.Join(unresolved, ", "))
+	}
+
+	treeHash, err := merge.shared.WriteTree(ctx)
+	if err != nil {
+		return ResolveMergeConflictsOutput{}, fmt.Errorf("failed to write tree object: %w", err)
+	}
+
+	committer := params.Actor
+	if params.Committer != nil {
+		committer = *params.Committer
+	}
+	committerDate := time.Now().UTC()
+	if params.CommitterDate != nil {
+		committerDate = *params.CommitterDate
+	}
+
+	author := committer
+	if params.Author != nil {
+		author = *params.Author
+	}
+	authorDate := committerDate
+	if params.AuthorDate != nil {
+		authorDate = *params.AuthorDate
+	}
+
+	message := params.Message
+	if message == "" {
+		message = fmt.Sprintf("Merge branch '%s' into %s", params.TargetBranch, params.SourceBranch)
+	}
+
+	log.Debug().Msg("commit merge tree")
+
+	commitSHA, err := merge.shared.CommitTreeWithParents(
+		ctx,
+		[]string{merge.sourceSHA, merge.targetSHA},
+		&types.Identity{
+			Name:  author.Name,
+			Email: author.Email,
+		},
+		&types.Identity{
+			Name:  committer.Name,
+			Email: committer.Email,
+		},
+		treeHash,
+		message,
+		authorDate,
+		committerDate,
+	)
+	if err != nil {
+		return ResolveMergeConflictsOutput{}, fmt.Errorf("failed to commit the merge tree: %w", err)
+	}
+
+	log.Debug().Msg("push merge commit to source branch")
+
+	// the push isn't forced, so it fails in case the source branch got updated in the meantime.
+	env := CreateEnvironmentForPush(ctx, params.WriteParams)
+	if err = merge.shared.PushCommitToBranch(ctx, commitSHA, params.SourceBranch, false, env...); err != nil {
+		return ResolveMergeConflictsOutput{}, fmt.Errorf("failed to push merge commit to branch '%s': %w",
+			params.SourceBranch, err)
+	}
+
+	log.Debug().Msg("done")
+
+	return ResolveMergeConflictsOutput{
+		CommitSHA:    commitSHA,
+		SourceSHA:    merge.sourceSHA,
+		TargetSHA:    merge.targetSHA,
+		MergeBaseSHA: merge.mergeBaseSHA,
+	}, nil
+}
+
+// mergeConflictsResult holds the shared repository with the merged index of the target into the source branch.
+// Conflicted files remain as unmerged entries in the index.
+type mergeConflictsResult struct {
+	shared       SharedRepo
+	sourceSHA    string
+	targetSHA    string
+	mergeBaseSHA string
+}
+
+// mergeConflictsTree merges the target branch into the source branch in a shared repository.
+// The caller is responsible for closing the returned shared repository.
+func (s *Service) mergeConflictsTree(
+	ctx context.Context,
+	repoUID string,
+	sourceBranch string,
+	targetBranch string,
+	sourceExpectedSHA string,
+	targetExpectedSHA string,
+) (mergeConflictsResult, error) {
+	repoPath := getFullPathForRepo(s.reposRoot, repoUID)
+
+	repo, err := s.adapter.OpenRepository(ctx, repoPath)
+	if err != nil {
+		return mergeConflictsResult{}, fmt.Errorf("failed to open repo: %w", err)
+	}
+	defer repo.Close()
+
+	sourceSHA, err := s.getBranchCommitSHA(repo, sourceBranch, sourceExpectedSHA)
+	if err != nil {
+		return mergeConflictsResult{}, err
+	}
+
+	targetSHA, err := s.getBranchCommitSHA(repo, targetBranch, targetExpectedSHA)
+	if err != nil {
+		return mergeConflictsResult{}, err
+	}
+
+	mergeBaseSHA, _, err := s.adapter.GetMergeBase(ctx, repoPath, "", targetSHA, sourceSHA)
+	if err != nil {
+		return mergeConflictsResult{}, fmt.Errorf("failed to get merge base: %w", err)
+	}
+
+	if mergeBaseSHA == targetSHA {
+		return mergeConflictsResult{}, errors.PreconditionFailed(
+			"branch '%s' already contains all commits of branch '%s'", sourceBranch, targetBranch)
+	}
+
+	shared, err := s.adapter.SharedRepository(s.tmpDir, repoUID, repoPath)
+	if err != nil {
+		return mergeConflictsResult{}, fmt.Errorf("failed to create shared repository: %w", err)
+	}
+
+	if err = shared.Clone(ctx, sourceBranch); err != nil {
+		shared.Close(ctx)
+		return mergeConflictsResult{}, fmt.Errorf("failed to clone branch '%s': %w", sourceBranch, err)
+	}
+
+	if _, err = shared.MergeTree(ctx, mergeBaseSHA, sourceSHA, targetSHA); err != nil {
+		shared.Close(ctx)
+		return mergeConflictsResult{}, fmt.Errorf("failed to merge branch '%s' into '%s': %w",
+			targetBranch, sourceBranch, err)
+	}
+
+	return mergeConflictsResult{
+		shared:       shared,
+		sourceSHA:    sourceSHA,
+		targetSHA:    targetSHA,
+		mergeBaseSHA: mergeBaseSHA,
+	}, nil
+}
+
+func (s *Service) getBranchCommitSHA(repo *git.Repository, branch string, expectedSHA string) (string, error) {
+	sha, err := repo.GetBranchCommitID(branch)
+	if git.IsErrNotExist(err) {
+		return "", errors.NotFound("branch '%s' doesn't exist", branch)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get latest commit of branch '%s': %w", branch, err)
+	}
+
+	if expectedSHA != "" && expectedSHA != sha {
+		return "", errors.PreconditionFailed("branch '%s' is on SHA '%s' which doesn't match expected SHA '%s'",
+			branch, sha, expectedSHA)
+	}
+
+	return sha, nil
+}
+
+func (s *Service) getMergeConflictFile(
+	ctx context.Context,
+	repoPath string,
+	merge mergeConflictsResult,
+	entry *types.UnmergedEntry,
+	sourceBranch string,
+	targetBranch string,
+) (MergeConflictFile, error) {
+	file := MergeConflictFile{
+		Path: entry.Path,
+	}
+
+	versions := []struct {
+		entry   *types.IndexEntry
+		version **MergeConflictFileVersion
+	}{
+		{entry.Base, &file.Base},
+		{entry.Ours, &file.Ours},
+		{entry.Theirs, &file.Theirs},
+	}
+
+	mergeable := true
+	for _, v := range versions {
+		if v.entry == nil {
+			continue
+		}
+
+		version, isBinary, err := s.getMergeConflictFileVersion(ctx, repoPath, v.entry)
+		if err != nil {
+			return MergeConflictFile{}, err
+		}
+
+		*v.version = version
+		file.IsBinary = file.IsBinary || isBinary
+		mergeable = mergeable && version.Content != nil
+	}
+
+	// conflict markers can only be generated if the file exists in both branches and all versions are text files.
+	if file.Ours == nil || file.Theirs == nil || !mergeable {
+		return file, nil
+	}
+
+	baseSHA := ""
+	if file.Base != nil {
+		baseSHA = file.Base.SHA
+	} else {
+		// files added in both branches are merged against an empty file.
+		var err error
+		baseSHA, err = merge.shared.WriteGitObject(ctx, &bytes.Buffer{})
+		if err != nil {
+			return MergeConflictFile{}, fmt.Errorf("failed to write empty blob: %w", err)
+		}
+	}
+
+	content, _, err := merge.shared.MergeFile(ctx, baseSHA, file.Ours.SHA, file.Theirs.SHA,
+		sourceBranch, merge.mergeBaseSHA, targetBranch)
+	if err != nil {
+		return MergeConflictFile{}, fmt.Errorf("failed to merge file: %w", err)
+	}
+
+	file.Content = content
+
+	return file, nil
+}
+
+// getMergeConflictFileVersion returns a single version of a conflicted file and whether it's a binary file.
+func (s *Service) getMergeConflictFileVersion(
+	ctx context.Context,
+	repoPath string,
+	entry *types.IndexEntry,
+) (*MergeConflictFileVersion, bool, error) {
+	version := &MergeConflictFileVersion{
+		SHA:  entry.SHA,
+		Mode: entry.Mode,
+	}
+
+	// submodules reference commits and don't have any content.
+	if entry.Mode == submoduleFileMode {
+		return version, false, nil
+	}
+
+	reader, err := s.adapter.GetBlob(ctx, repoPath, entry.SHA, maxMergeConflictFileSize)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read blob '%s': %w", entry.SHA, err)
+	}
+	defer reader.Content.Close()
+
+	version.Size = reader.Size
+
+	content, err := io.ReadAll(reader.Content)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read content of blob '%s': %w", entry.SHA, err)
+	}
+
+	sniff := content
+	if len(sniff) > binaryDetectionSize {
+		sniff = sniff[:binaryDetectionSize]
+	}
+	if bytes.IndexByte(sniff, 0) >= 0 {
+		return version, true, nil
+	}
+
+	if reader.ContentSize < reader.Size {
+		return version, false, nil
+	}
+
+	version.Content = content
+
+	return version, false, nil
+}
+
+// getResolvedFileMode returns the mode used for the resolved version of a conflicted file.
+func getResolvedFileMode(entry *types.UnmergedEntry) string {
+	switch {
+	case entry.Ours != nil && entry.Ours.Mode != submoduleFileMode:
+		return entry.Ours.Mode
+	case entry.Theirs != nil && entry.Theirs.Mode != submoduleFileMode:
+		return entry.Theirs.Mode
+	default:
+		return defaultFilePermission
+	}
+}
+
+// containsConflictMarkers returns true if a text file contains lines with the start, separator and end
+// conflict markers in that order.
+func containsConflictMarkers(content []byte) bool {
+	sniff := content
+	if len(sniff) > binaryDetectionSize {
+		sniff = sniff[:binaryDetectionSize]
+	}
+	if bytes.IndexByte(sniff, 0) >= 0 {
+		return false
+	}
+
+	next := 0
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+
+		// a new start marker restarts the sequence.
+		if isConflictMarker(line, conflictMarkers[0]) {
+			next = 1
+			continue
+		}
+
+		if next > 0 && isConflictMarker(line, conflictMarkers[next]) {
+			next++
+			if next == len(conflictMarkers) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isConflictMarker returns true if the line starts with the marker.
+// Same as git, the marker has to be followed by a space or the end of the line.
+func isConflictMarker(line []byte, marker []byte) bool {
+	if !bytes.HasPrefix(line, marker) {
+		return false
+	}
+
+	rest := line[len(marker):]
+	return len(rest) == 0 || rest[0] == ' '
+}
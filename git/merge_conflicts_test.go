@@ -0,0 +1,233 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git/adapter"
+	"github.com/harness/gitness/git/storage"
+	"github.com/harness/gitness/git/types"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testMergeConflictsRepoUID = "mergeconflicts"
+
+func runTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(cmd.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@test.com")
+
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	return strings.TrimSpace(string(out))
+}
+
+// setupMergeConflictsRepo creates a repository with the branch "feature" that conflicts with "main":
+// a.txt is changed in both branches, b.txt is changed in feature and deleted in main
+// and c.txt is only changed in main.
+func setupMergeConflictsRepo(t *testing.T) (*Service, string) {
+	t.Helper()
+
+	root := t.TempDir()
+
+	gitAdapter, err := adapter.New(types.Config{}, adapter.NewInMemoryLastCommitCache(time.Minute), nil)
+	require.NoError(t, err)
+	s, err := New(types.Config{Root: root, TmpDir: t.TempDir()}, gitAdapter, storage.NewLocalStore())
+	require.NoError(t, err)
+
+	work := filepath.Join(root, "work")
+	runTestGit(t, root, "init", "-b", "main", work)
+
+	write := func(file, content string) {
+		cmd := exec.Command("sh", "-c", "printf '%s' \"$1\" > \"$2\"", "sh", content, file)
+		cmd.Dir = work
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	write("a.txt", "line1\nline2\nline3\n")
+	write("b.txt", "b\n")
+	write("c.txt", "c\n")
+	runTestGit(t, work, "add", "-A")
+	runTestGit(t, work, "commit", "-m", "base")
+
+	runTestGit(t, work, "checkout", "-b", "feature")
+	write("a.txt", "line1\nfeature\nline3\n")
+	write("b.txt", "feature b\n")
+	runTestGit(t, work, "commit", "-am", "feature")
+
+	runTestGit(t, work, "checkout", "main")
+	write("a.txt", "line1\nmain\nline3\n")
+	write("c.txt", "main c\n")
+	runTestGit(t, work, "rm", "b.txt")
+	runTestGit(t, work, "commit", "-am", "main")
+
+	repoPath := getFullPathForRepo(s.reposRoot, testMergeConflictsRepoUID)
+	runTestGit(t, root, "clone", "--bare", work, repoPath)
+
+	return s, repoPath
+}
+
+func TestService_GetMergeConflicts(t *testing.T) {
+	s, repoPath := setupMergeConflictsRepo(t)
+	ctx := context.Background()
+
+	out, err := s.GetMergeConflicts(ctx, &MergeConflictsParams{
+		ReadParams:   ReadParams{RepoUID: testMergeConflictsRepoUID},
+		SourceBranch: "feature",
+		TargetBranch: "main",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, runTestGit(t, repoPath, "rev-parse", "feature"), out.SourceSHA)
+	require.Equal(t, runTestGit(t, repoPath, "rev-parse", "main"), out.TargetSHA)
+	require.Equal(t, runTestGit(t, repoPath, "merge-base", "feature", "main"), out.MergeBaseSHA)
+	require.Len(t, out.Files, 2)
+
+	// content conflict
+	a := out.Files[0]
+	require.Equal(t, "a.txt", a.Path)
+	require.False(t, a.IsBinary)
+	require.Equal(t, "line1\nline2\nline3\n", string(a.Base.Content))
+	require.Equal(t, "line1\nfeature\nline3\n", string(a.Ours.Content))
+	require.Equal(t, "line1\nmain\nline3\n", string(a.Theirs.Content))
+	require.Contains(t, string(a.Content), "<<<<<<< feature\nfeature\n=======\nmain\n>>>>>>> main\n")
+	require.True(t, containsConflictMarkers(a.Content))
+
+	// delete/modify conflict
+	b := out.Files[1]
+	require.Equal(t, "b.txt", b.Path)
+	require.Equal(t, "b\n", string(b.Base.Content))
+	require.Equal(t, "feature b\n", string(b.Ours.Content))
+	require.Nil(t, b.Theirs)
+	require.Nil(t, b.Content)
+
+	// no conflicts are left once the target branch is merged.
+	_, err = s.GetMergeConflicts(ctx, &MergeConflictsParams{
+		ReadParams:   ReadParams{RepoUID: testMergeConflictsRepoUID},
+		SourceBranch: "main",
+		TargetBranch: "main",
+	})
+	require.True(t, errors.IsPreconditionFailed(err), "unexpected error: %v", err)
+}
+
+func TestService_ResolveMergeConflicts(t *testing.T) {
+	s, repoPath := setupMergeConflictsRepo(t)
+	ctx := context.Background()
+
+	sourceSHA := runTestGit(t, repoPath, "rev-parse", "feature")
+	targetSHA := runTestGit(t, repoPath, "rev-parse", "main")
+
+	resolve := func(files ...ResolvedMergeConflictFile) (ResolveMergeConflictsOutput, error) {
+		return s.ResolveMergeConflicts(ctx, &ResolveMergeConflictsParams{
+			WriteParams:       WriteParams{RepoUID: testMergeConflictsRepoUID, Actor: Identity{Name: "test", Email: "t@t.com"}},
+			SourceBranch:      "feature",
+			SourceExpectedSHA: sourceSHA,
+			TargetBranch:      "main",
+			TargetExpectedSHA: targetSHA,
+			Files:             files,
+		})
+	}
+
+	resolvedA := ResolvedMergeConflictFile{Path: "a.txt", Content: []byte("line1\nresolved\nline3\n")}
+	deletedB := ResolvedMergeConflictFile{Path: "b.txt", Delete: true}
+
+	tests := []struct {
+		name  string
+		files []ResolvedMergeConflictFile
+		err   string
+	}{
+		{
+			name:  "missing resolution",
+			files: []ResolvedMergeConflictFile{resolvedA},
+			err:   "merge conflicts of files b.txt are not resolved",
+		},
+		{
+			name: "conflict markers",
+			files: []ResolvedMergeConflictFile{
+				{Path: "a.txt", Content: []byte("line1\n<<<<<<< feature\nfeature\n=======\nmain\n>>>>>>> main\nline3\n")},
+				deletedB,
+			},
+			err: "resolution of file 'a.txt' still contains conflict markers",
+		},
+		{
+			name:  "file without conflicts",
+			files: []ResolvedMergeConflictFile{resolvedA, deletedB, {Path: "c.txt", Content: []byte("x\n")}},
+			err:   "file 'c.txt' doesn't have any merge conflicts",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := resolve(test.files...)
+			require.True(t, errors.IsInvalidArgument(err), "unexpected error: %v", err)
+			require.ErrorContains(t, err, test.err)
+			require.Equal(t, sourceSHA, runTestGit(t, repoPath, "rev-parse", "feature"))
+		})
+	}
+
+	out, err := resolve(resolvedA, deletedB)
+	require.NoError(t, err)
+	require.Equal(t, out.CommitSHA, runTestGit(t, repoPath, "rev-parse", "feature"))
+	require.Equal(t, sourceSHA+"\n"+targetSHA, runTestGit(t, repoPath, "rev-parse", "feature^1", "feature^2"))
+	require.Equal(t, "line1\nresolved\nline3", runTestGit(t, repoPath, "show", "feature:a.txt"))
+	require.Equal(t, "main c", runTestGit(t, repoPath, "show", "feature:c.txt"))
+	require.Equal(t, "a.txt\nc.txt", runTestGit(t, repoPath, "ls-tree", "--name-only", "feature"))
+	require.Equal(t, "Merge branch 'main' into feature", runTestGit(t, repoPath, "log", "-1", "--format=%s", "feature"))
+
+	// the resolution is rejected if the source branch got updated in the meantime.
+	_, err = resolve(resolvedA, deletedB)
+	require.True(t, errors.IsPreconditionFailed(err), "unexpected error: %v", err)
+}
+
+func TestContainsConflictMarkers(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected bool
+	}{
+		{content: "", expected: false},
+		{content: "a\nb\n", expected: false},
+		{content: "<<<<<<< ours\na\n=======\nb\n>>>>>>> theirs\n", expected: true},
+		{content: "x\r\n<<<<<<<\r\na\r\n=======\r\nb\r\n>>>>>>>\r\n", expected: true},
+		{content: "<<<<<<< ours\na\n||||||| base\nc\n=======\nb\n>>>>>>> theirs\n", expected: true},
+		{content: "<<<<<<< ours\na\n", expected: false},
+		{content: "a\n>>>>>>> theirs\n", expected: false},
+		{content: "<<<<<<< ours\na\n>>>>>>> theirs\n", expected: false},
+		{content: ">>>>>>> theirs\n=======\n<<<<<<< ours\n", expected: false},
+		{content: "Heading\n=======\n", expected: false},
+		{content: "a <<<<<<< b\n=======\n>>>>>>> c\n", expected: false},
+		{content: "<<<<<<<<\n=======\n>>>>>>>\n", expected: false},
+		{content: "\x00<<<<<<< ours\n=======\n>>>>>>> theirs\n", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.content, func(t *testing.T) {
+			require.Equal(t, test.expected, containsConflictMarkers([]byte(test.content)))
+		})
+	}
+}
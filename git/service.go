@@ -18,6 +18,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/harness/gitness/cache"
 	"github.com/harness/gitness/errors"
 	"github.com/harness/gitness/git/storage"
 	"github.com/harness/gitness/git/types"
@@ -35,6 +36,8 @@ type Service struct {
 	store          storage.Store
 	gitHookPath    string
 	reposGraveyard string
+
+	commitStatsCache cache.Cache[commitStatsKey, *CommitStatsOutput]
 }
 
 func New(
@@ -65,5 +68,9 @@ func New(
 		adapter:        adapter,
 		store:          storage,
 		gitHookPath:    config.HookPath,
+
+		commitStatsCache: cache.New[commitStatsKey, *CommitStatsOutput](
+			commitStatsGetter{adapter: adapter},
+			commitStatsCacheDuration),
 	}, nil
 }
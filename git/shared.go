@@ -30,6 +30,7 @@ type SharedRepo interface {
 	Close(ctx context.Context)
 	Clone(ctx context.Context, branchName string) error
 	Init(ctx context.Context) error
+	AddAlternates(ctx context.Context, repoPaths ...string) error
 	SetDefaultIndex(ctx context.Context) error
 	LsFiles(ctx context.Context, filenames ...string) ([]string, error)
 	RemoveFilesFromIndex(ctx context.Context, filenames ...string) error
@@ -37,6 +38,13 @@ type SharedRepo interface {
 	ShowFile(ctx context.Context, filePath, commitHash string, writer io.Writer) error
 	AddObjectToIndex(ctx context.Context, mode, objectHash, objectPath string) error
 	WriteTree(ctx context.Context) (string, error)
+	MergeTree(ctx context.Context, base, ours, theirs string) ([]string, error)
+	ListUnmergedEntries(ctx context.Context) ([]types.UnmergedEntry, error)
+	MergeFile(
+		ctx context.Context,
+		baseSHA, oursSHA, theirsSHA string,
+		oursLabel, baseLabel, theirsLabel string,
+	) ([]byte, bool, error)
 	GetLastCommit(ctx context.Context) (string, error)
 	GetLastCommitByRef(ctx context.Context, ref string) (string, error)
 	CommitTreeWithDate(
@@ -47,6 +55,13 @@ type SharedRepo interface {
 		signoff bool,
 		authorDate, committerDate time.Time,
 	) (string, error)
+	CommitTreeWithParents(
+		ctx context.Context,
+		parents []string,
+		author, committer *types.Identity,
+		treeHash, message string,
+		authorDate, committerDate time.Time,
+	) (string, error)
 	PushDeleteBranch(
 		ctx context.Context,
 		branch string,
@@ -0,0 +1,171 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git/types"
+)
+
+// ObjectSignature contains the raw (GPG or SSH) signature of a git object and the signed payload.
+type ObjectSignature struct {
+	Signature string
+	Payload   string
+}
+
+type SigningKeyType string
+
+const (
+	SigningKeyTypeGPG SigningKeyType = "gpg"
+	SigningKeyTypeSSH SigningKeyType = "ssh"
+)
+
+// SigningKey is a public key used to verify signatures of git objects.
+type SigningKey struct {
+	ID      int64
+	Type    SigningKeyType
+	Content string
+}
+
+type SignatureVerificationReason string
+
+const (
+	SignatureVerificationReasonValid              SignatureVerificationReason = "valid"
+	SignatureVerificationReasonUnsigned           SignatureVerificationReason = "unsigned"
+	SignatureVerificationReasonUnknownSignature   SignatureVerificationReason = "unknown_signature_type"
+	SignatureVerificationReasonMalformedSignature SignatureVerificationReason = "malformed_signature"
+	SignatureVerificationReasonUnknownKey         SignatureVerificationReason = "unknown_key"
+	SignatureVerificationReasonBadSignature       SignatureVerificationReason = "bad_signature"
+)
+
+type VerifySignatureParams struct {
+	Signature *ObjectSignature
+	Keys      []SigningKey
+}
+
+type VerifySignatureOutput struct {
+	Verified bool
+	Reason   SignatureVerificationReason
+	// KeyID is the id of the key that created the signature (gpg key id or ssh key fingerprint).
+	KeyID string
+	// SigningKeyID is the ID of the provided signing key that verified the signature.
+	SigningKeyID int64
+}
+
+// VerifySignature verifies the signature of a git object against the provided signing keys.
+func (s *Service) VerifySignature(params *VerifySignatureParams) (VerifySignatureOutput, error) {
+	if params == nil {
+		return VerifySignatureOutput{}, ErrNoParamsProvided
+	}
+
+	var signature *types.ObjectSignature
+	if params.Signature != nil {
+		signature = &types.ObjectSignature{
+			Signature: params.Signature.Signature,
+			Payload:   params.Signature.Payload,
+		}
+	}
+
+	keys := make([]types.SigningKey, len(params.Keys))
+	for i, key := range params.Keys {
+		keys[i] = types.SigningKey{
+			ID:      key.ID,
+			Type:    types.SigningKeyType(key.Type),
+			Content: key.Content,
+		}
+	}
+
+	result := s.adapter.VerifySignature(signature, keys)
+
+	return VerifySignatureOutput{
+		Verified:     result.Verified,
+		Reason:       SignatureVerificationReason(result.Reason),
+		KeyID:        result.KeyID,
+		SigningKeyID: result.SigningKeyID,
+	}, nil
+}
+
+// GetSigningKey returns the public key used to sign commits created by the server (merges, web edits).
+// It returns nil in case commit signing isn't configured.
+func (s *Service) GetSigningKey() *SigningKey {
+	key := s.adapter.SigningKey()
+	if key == nil {
+		return nil
+	}
+
+	return &SigningKey{
+		Type:    SigningKeyType(key.Type),
+		Content: key.Content,
+	}
+}
+
+type ListPushedCommitsParams struct {
+	ReadParams
+	// OldSHA is the old value of the reference that is being pushed (empty or nil SHA if the reference is new).
+	OldSHA string
+	// NewSHA is the new value of the reference that is being pushed.
+	NewSHA string
+	// AlternateObjectDirs are the object directories containing the pushed objects (e.g. quarantine).
+	AlternateObjectDirs []string
+}
+
+type ListPushedCommitsOutput struct {
+	Commits []Commit
+}
+
+// ListPushedCommits lists the commits of a push to a reference, see Adapter.ListPushedCommits for details.
+func (s *Service) ListPushedCommits(
+	ctx context.Context,
+	params *ListPushedCommitsParams,
+) (ListPushedCommitsOutput, error) {
+	if params == nil {
+		return ListPushedCommitsOutput{}, ErrNoParamsProvided
+	}
+	if err := params.Validate(); err != nil {
+		return ListPushedCommitsOutput{}, err
+	}
+	if !isValidGitSHA(params.NewSHA) {
+		return ListPushedCommitsOutput{}, errors.InvalidArgument("the provided commit sha '%s' is of invalid format.",
+			params.NewSHA)
+	}
+	if params.OldSHA != "" && !isValidGitSHA(params.OldSHA) {
+		return ListPushedCommitsOutput{}, errors.InvalidArgument("the provided commit sha '%s' is of invalid format.",
+			params.OldSHA)
+	}
+
+	repoPath := getFullPathForRepo(s.reposRoot, params.RepoUID)
+
+	result, err := s.adapter.ListPushedCommits(ctx, repoPath, params.OldSHA, params.NewSHA,
+		params.AlternateObjectDirs)
+	if err != nil {
+		return ListPushedCommitsOutput{}, err
+	}
+
+	commits := make([]Commit, len(result))
+	for i := range result {
+		commit, err := mapCommit(&result[i])
+		if err != nil {
+			return ListPushedCommitsOutput{}, fmt.Errorf("failed to map commit: %w", err)
+		}
+		commits[i] = *commit
+	}
+
+	return ListPushedCommitsOutput{
+		Commits: commits,
+	}, nil
+}
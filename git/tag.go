@@ -69,6 +69,7 @@ type CommitTag struct {
 	Title       string
 	Message     string
 	Tagger      *Signature
+	Signature   *ObjectSignature
 	Commit      *Commit
 }
 
@@ -34,6 +34,18 @@ type Config struct {
 
 	// LastCommitCache holds configuration options for the last commit cache.
 	LastCommitCache LastCommitCacheConfig
+
+	// SigningKey holds configuration options for signing commits created by the server.
+	SigningKey SigningKeyConfig
+}
+
+// SigningKeyConfig holds configuration options for the key used to sign commits created by the server.
+type SigningKeyConfig struct {
+	// Path points to a file containing an armored GPG private key or an OpenSSH private key.
+	Path string
+
+	// Passphrase (optional) is used to decrypt the private key.
+	Passphrase string
 }
 
 // LastCommitCacheConfig holds configuration options for the last commit cache.
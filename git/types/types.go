@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/harness/gitness/errors"
+	"github.com/harness/gitness/git/enum"
 )
 
 const NilSHA = "0000000000000000000000000000000000000000"
@@ -141,11 +142,58 @@ type WalkReferencesOptions struct {
 }
 
 type Commit struct {
-	SHA       string    `json:"sha"`
-	Title     string    `json:"title"`
-	Message   string    `json:"message,omitempty"`
-	Author    Signature `json:"author"`
-	Committer Signature `json:"committer"`
+	SHA       string           `json:"sha"`
+	Title     string           `json:"title"`
+	Message   string           `json:"message,omitempty"`
+	Author    Signature        `json:"author"`
+	Committer Signature        `json:"committer"`
+	Signature *ObjectSignature `json:"signature,omitempty"`
+}
+
+// ObjectSignature contains the raw (GPG or SSH) signature of a commit or tag.
+type ObjectSignature struct {
+	// Signature is the armored signature.
+	Signature string `json:"signature"`
+	// Payload is the signed content of the object.
+	Payload string `json:"payload"`
+}
+
+// SigningKeyType is the type of key used for signing git objects.
+type SigningKeyType string
+
+const (
+	SigningKeyTypeGPG SigningKeyType = "gpg"
+	SigningKeyTypeSSH SigningKeyType = "ssh"
+)
+
+// SigningKey is a public key that can be used to verify signatures of git objects.
+type SigningKey struct {
+	// ID is the (external) identifier of the key, returned as part of the verification result.
+	ID      int64
+	Type    SigningKeyType
+	Content string
+}
+
+// SignatureVerificationReason describes the result of a signature verification.
+type SignatureVerificationReason string
+
+const (
+	SignatureVerificationReasonValid              SignatureVerificationReason = "valid"
+	SignatureVerificationReasonUnsigned           SignatureVerificationReason = "unsigned"
+	SignatureVerificationReasonUnknownSignature   SignatureVerificationReason = "unknown_signature_type"
+	SignatureVerificationReasonMalformedSignature SignatureVerificationReason = "malformed_signature"
+	SignatureVerificationReasonUnknownKey         SignatureVerificationReason = "unknown_key"
+	SignatureVerificationReasonBadSignature       SignatureVerificationReason = "bad_signature"
+)
+
+// SignatureVerification is the result of verifying a signature against a set of signing keys.
+type SignatureVerification struct {
+	Verified bool
+	Reason   SignatureVerificationReason
+	// KeyID is the id of the key that created the signature (gpg key id or ssh key fingerprint).
+	KeyID string
+	// SigningKeyID is the ID of the signing key that verified the signature (0 if not verified).
+	SigningKeyID int64
 }
 
 type Branch struct {
@@ -171,6 +219,7 @@ type Tag struct {
 	Title      string
 	Message    string
 	Tagger     Signature
+	Signature  *ObjectSignature
 }
 
 type CreateTagOptions struct {
@@ -300,6 +349,31 @@ type PullRequest struct {
 	HeadBranch string
 }
 
+// DiffOptions contains the options used to customize how a diff is computed.
+type DiffOptions struct {
+	IgnoreWhitespace enum.DiffIgnoreWhitespace
+	// ContextLines is the number of context lines around each change (nil uses the git default).
+	ContextLines *int
+	// RenameThreshold is the similarity percentage for rename and copy detection (0 uses the git default).
+	RenameThreshold int
+	DetectCopies    bool
+}
+
+// IndexEntry is a single entry of the git index.
+type IndexEntry struct {
+	Mode string
+	SHA  string
+}
+
+// UnmergedEntry contains all stages (base, ours, theirs) of an unmerged path in the index.
+// A stage is nil in case the path doesn't exist in the corresponding version.
+type UnmergedEntry struct {
+	Path   string
+	Base   *IndexEntry
+	Ours   *IndexEntry
+	Theirs *IndexEntry
+}
+
 type DiffShortStat struct {
 	Files     int
 	Additions int
@@ -351,6 +425,14 @@ type CommitFilter struct {
 	Committer string
 }
 
+// CommitActivity contains the author and the number of changed lines of a single commit.
+type CommitActivity struct {
+	SHA       string
+	Author    Signature
+	Additions int64
+	Deletions int64
+}
+
 type TempRepository struct {
 	Path    string
 	BaseSHA string
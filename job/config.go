@@ -27,4 +27,6 @@ type Config struct {
 	// finished and failed jobs will be purged from the DB.
 	BackgroundJobsRetentionTime time.Duration `envconfig:"JOBS_RETENTION_TIME" default:"120h"` // 5 days
 
+	// MetricsEnabled enables collection of metrics that require additional database queries.
+	MetricsEnabled bool
 }
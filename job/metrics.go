@@ -0,0 +1,57 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricsOutcomeSuccess  = "success"
+	metricsOutcomeFailure  = "failure"
+	metricsOutcomeCanceled = "canceled"
+)
+
+var (
+	jobsRunning = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "gitness",
+		Subsystem: "job",
+		Name:      "running",
+		Help:      "Number of background jobs currently running on this instance.",
+	})
+
+	jobsReady = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "gitness",
+		Subsystem: "job",
+		Name:      "ready",
+		Help:      "Number of background jobs that are ready for execution but not yet started.",
+	})
+
+	jobExecutions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitness",
+		Subsystem: "job",
+		Name:      "executions_total",
+		Help:      "Total number of background job executions.",
+	}, []string{"type", "outcome"})
+
+	jobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "gitness",
+		Subsystem: "job",
+		Name:      "duration_seconds",
+		Help:      "Duration of background job executions.",
+		Buckets:   []float64{.1, .5, 1, 5, 10, 30, 60, 300, 600, 1800, 3600},
+	}, []string{"type"})
+)
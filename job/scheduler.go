@@ -42,6 +42,8 @@ type Scheduler struct {
 	instanceID    string
 	maxRunning    int
 	retentionTime time.Duration
+	// metricsEnabled enables collection of metrics that require additional database queries.
+	metricsEnabled bool
 
 	// synchronization stuff
 	signal       chan time.Time
@@ -59,6 +61,7 @@ func NewScheduler(
 	instanceID string,
 	maxRunning int,
 	retentionTime time.Duration,
+	metricsEnabled bool,
 ) (*Scheduler, error) {
 	if maxRunning < 1 {
 		maxRunning = 1
@@ -69,9 +72,10 @@ func NewScheduler(
 		mxManager:     mxManager,
 		pubsubService: pubsubService,
 
-		instanceID:    instanceID,
-		maxRunning:    maxRunning,
-		retentionTime: retentionTime,
+		instanceID:     instanceID,
+		maxRunning:     maxRunning,
+		retentionTime:  retentionTime,
+		metricsEnabled: metricsEnabled,
 
 		cancelJobMap: map[string]context.CancelFunc{},
 	}, nil
@@ -320,6 +324,21 @@ func (s *Scheduler) RunJobs(ctx context.Context, groupID string, defs []Definiti
 	return nil
 }
 
+// updateReadyJobsMetric updates the metric of jobs that are ready for execution but can't be started yet.
+func (s *Scheduler) updateReadyJobsMetric(ctx context.Context, now time.Time, availableCount int) {
+	countReady, err := s.store.CountReady(ctx, now)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to count ready jobs")
+		return
+	}
+
+	if countReady > availableCount {
+		jobsReady.Set(float64(countReady - availableCount))
+	} else {
+		jobsReady.Set(0)
+	}
+}
+
 // processReadyJobs executes jobs that are ready to run. This function is periodically run by the Scheduler.
 // The function returns the number of jobs it has is started, the next scheduled execution time (of this function)
 // and a bool value if all currently available ready jobs were started.
@@ -347,6 +366,10 @@ func (s *Scheduler) processReadyJobs(ctx context.Context, now time.Time) (int, t
 			fmt.Errorf("failed to count available slots for job execution: %w", err)
 	}
 
+	if s.metricsEnabled {
+		s.updateReadyJobsMetric(ctx, now, availableCount)
+	}
+
 	// get one over the limit to check if all ready jobs are fetched
 	jobs, err := s.store.ListReady(ctx, now, availableCount+1)
 	if err != nil {
@@ -425,6 +448,9 @@ func (s *Scheduler) runJob(ctx context.Context, j *Job) {
 	) {
 		defer s.wgRunning.Done()
 
+		jobsRunning.Inc()
+		defer jobsRunning.Dec()
+
 		log.Ctx(ctx).Debug().Msg("started job")
 
 		timeStart := time.Now()
@@ -432,6 +458,8 @@ func (s *Scheduler) runJob(ctx context.Context, j *Job) {
 		// Run the job
 		execResult, execFailure := s.doExec(ctx, jobUID, jobType, jobData, jobRunDeadline)
 
+		jobDuration.WithLabelValues(jobType).Observe(time.Since(timeStart).Seconds())
+
 		// Use the context.Background() because we want to update the job even if the job's context is done.
 		// The context can be done because the job exceeded its deadline or the server is shutting down.
 		backgroundCtx := context.Background()
@@ -456,6 +484,8 @@ func (s *Scheduler) runJob(ctx context.Context, j *Job) {
 		// Update the job fields, reschedule if necessary.
 		postExec(job, execResult, execFailure)
 
+		jobExecutions.WithLabelValues(jobType, metricsOutcome(job.State, execFailure)).Inc()
+
 		err = s.store.UpdateExecution(backgroundCtx, job)
 		if err != nil {
 			log.Ctx(ctx).Err(err).Msg("failed to update job after execution")
@@ -506,6 +536,18 @@ func (s *Scheduler) runJob(ctx context.Context, j *Job) {
 	}(ctx, j.UID, j.Type, j.Data, j.RunDeadline)
 }
 
+// metricsOutcome returns the outcome label of a job execution used for metrics.
+func metricsOutcome(state State, execFailure string) string {
+	switch {
+	case state == JobStateCanceled:
+		return metricsOutcomeCanceled
+	case execFailure != "":
+		return metricsOutcomeFailure
+	default:
+		return metricsOutcomeSuccess
+	}
+}
+
 // preExec updates the provided Job before execution.
 func (s *Scheduler) preExec(job *Job) {
 	if job.MaxDurationSeconds < 1 {
@@ -48,6 +48,9 @@ type Store interface {
 	// CountRunning returns number of jobs that are currently being run.
 	CountRunning(ctx context.Context) (int, error)
 
+	// CountReady returns number of jobs that are ready for execution.
+	CountReady(ctx context.Context, now time.Time) (int, error)
+
 	// ListReady returns a list of jobs that are ready for execution.
 	ListReady(ctx context.Context, now time.Time, limit int) ([]*Job, error)
 
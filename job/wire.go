@@ -51,5 +51,6 @@ func ProvideScheduler(
 		config.InstanceID,
 		config.BackgroundJobsMaxRunning,
 		config.BackgroundJobsRetentionTime,
+		config.MetricsEnabled,
 	)
 }
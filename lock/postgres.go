@@ -0,0 +1,243 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lock
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Postgres is a MutexManager backed by PostgreSQL session level advisory locks.
+// Every held lock occupies one connection of the database connection pool until it's released.
+// If the process dies, the connection gets closed and the database releases the lock.
+type Postgres struct {
+	config Config
+	db     *sqlx.DB
+}
+
+func NewPostgres(config Config, db *sqlx.DB) *Postgres {
+	return &Postgres{
+		config: config,
+		db:     db,
+	}
+}
+
+func (p *Postgres) NewMutex(key string, options ...Option) (Mutex, error) {
+	// copy default values
+	config := p.config
+
+	// set default delayFunc
+	if config.DelayFunc == nil {
+		config.DelayFunc = func(i int) time.Duration {
+			return config.RetryDelay
+		}
+	}
+
+	// override config with custom options
+	for _, opt := range options {
+		opt.Apply(&config)
+	}
+
+	// format key
+	key = formatKey(config.App, config.Namespace, key)
+
+	// waitTime logic is similar to redis implementation:
+	// https://github.com/go-redsync/redsync/blob/e1e5da6654c81a2069d6a360f1a31c21f05cd22d/mutex.go#LL81C4-L81C100
+	waitTime := config.Expiry
+	if config.TimeoutFactor > 0 {
+		waitTime = time.Duration(int64(float64(config.Expiry) * config.TimeoutFactor))
+	}
+
+	return &postgresMutex{
+		db:        p.db,
+		expiry:    config.Expiry,
+		waitTime:  waitTime,
+		tries:     config.Tries,
+		delayFunc: config.DelayFunc,
+		key:       key,
+		lockID:    advisoryLockID(key),
+	}, nil
+}
+
+// advisoryLockID converts the lock key into the 64bit integer used as advisory lock identifier.
+func advisoryLockID(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+type postgresMutex struct {
+	mutex sync.Mutex // Used while manipulating the internal state of the lock itself
+
+	db *sqlx.DB
+
+	expiry   time.Duration
+	waitTime time.Duration
+
+	tries     int
+	delayFunc DelayFunc
+
+	key    string
+	lockID int64
+
+	// conn is the database session that holds the advisory lock, nil if the lock isn't held.
+	conn *sql.Conn
+	// expiryTimer releases the lock once it expired.
+	expiryTimer *time.Timer
+}
+
+func (m *postgresMutex) Key() string {
+	return m.key
+}
+
+func (m *postgresMutex) Lock(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.conn != nil {
+		return NewError(ErrorKindLockHeld, m.key, nil)
+	}
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return NewError(ErrorKindProviderError, m.key, err)
+	}
+
+	acquired, err := m.tryAcquire(ctx, conn)
+	if err != nil {
+		m.closeConn(conn, err)
+		return err
+	}
+
+	if !acquired {
+		acquired, err = m.retry(ctx, conn)
+		if err != nil || !acquired {
+			m.closeConn(conn, err)
+			return err
+		}
+	}
+
+	m.conn = conn
+	if m.expiry > 0 {
+		m.expiryTimer = time.AfterFunc(m.expiry, m.expire)
+	}
+
+	return nil
+}
+
+func (m *postgresMutex) retry(ctx context.Context, conn *sql.Conn) (bool, error) {
+	timeout := time.NewTimer(m.waitTime)
+	defer timeout.Stop()
+
+	for attempt := 1; ; attempt++ {
+		if attempt >= m.tries {
+			return false, NewError(ErrorKindMaxRetriesExceeded, m.key, nil)
+		}
+
+		delay := time.NewTimer(m.delayFunc(attempt))
+
+		select {
+		case <-ctx.Done():
+			delay.Stop()
+			return false, NewError(ErrorKindContext, m.key, ctx.Err())
+		case <-timeout.C:
+			delay.Stop()
+			return false, NewError(ErrorKindCannotLock, m.key, nil)
+		case <-delay.C: // just wait
+		}
+
+		acquired, err := m.tryAcquire(ctx, conn)
+		if err != nil || acquired {
+			return acquired, err
+		}
+	}
+}
+
+func (m *postgresMutex) tryAcquire(ctx context.Context, conn *sql.Conn) (bool, error) {
+	var acquired bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", m.lockID).Scan(&acquired)
+	if err != nil {
+		return false, NewError(ErrorKindProviderError, m.key, err)
+	}
+
+	return acquired, nil
+}
+
+func (m *postgresMutex) Unlock(ctx context.Context) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.conn == nil {
+		return NewError(ErrorKindLockNotHeld, m.key, nil)
+	}
+
+	return m.release(ctx)
+}
+
+// expire releases the lock after it has been held longer than the configured expiry.
+func (m *postgresMutex) expire() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.conn == nil {
+		return
+	}
+
+	_ = m.release(context.Background())
+}
+
+// release releases the advisory lock and returns the connection to the pool.
+// The caller is expected to hold the mutex.
+func (m *postgresMutex) release(ctx context.Context) error {
+	if m.expiryTimer != nil {
+		m.expiryTimer.Stop()
+		m.expiryTimer = nil
+	}
+
+	conn := m.conn
+	m.conn = nil
+
+	var released bool
+	err := conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", m.lockID).Scan(&released)
+	if err != nil {
+		// the lock is still held by the session - closing the session releases it.
+		m.closeConn(conn, err)
+		return NewError(ErrorKindProviderError, m.key, err)
+	}
+
+	m.closeConn(conn, nil)
+
+	if !released {
+		return NewError(ErrorKindLockNotHeld, m.key, nil)
+	}
+
+	return nil
+}
+
+// closeConn returns the connection to the pool. In case of an error the connection is discarded,
+// which ends the database session and releases any advisory lock it might still hold.
+func (m *postgresMutex) closeConn(conn *sql.Conn, err error) {
+	if err != nil {
+		_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+	}
+
+	_ = conn.Close()
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+// testPostgresDatasourceEnv holds the datasource of the postgres database used by the tests.
+// The tests are skipped if it isn't set.
+const testPostgresDatasourceEnv = "GITNESS_TEST_POSTGRES_DATASOURCE"
+
+func setupPostgres(t *testing.T, config Config) *Postgres {
+	t.Helper()
+
+	datasource := os.Getenv(testPostgresDatasourceEnv)
+	if datasource == "" {
+		t.Skipf("%s isn't set", testPostgresDatasourceEnv)
+	}
+
+	db, err := sqlx.Open("postgres", datasource)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	config.App = "gitness"
+	config.Namespace = fmt.Sprintf("test-%d", time.Now().UnixNano())
+
+	return NewPostgres(config, db)
+}
+
+func requireLockErrorKind(t *testing.T, err error, kind ErrorKind) {
+	t.Helper()
+
+	var lockErr *Error
+	require.True(t, errors.As(err, &lockErr), "expected lock error, got: %v", err)
+	require.Equal(t, kind, lockErr.Kind)
+}
+
+func TestPostgresMutex_Lock(t *testing.T) {
+	manager := setupPostgres(t, Config{
+		Expiry:     time.Minute,
+		Tries:      1,
+		RetryDelay: 10 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	mx1, err := manager.NewMutex("key1")
+	require.NoError(t, err)
+	mx2, err := manager.NewMutex("key1")
+	require.NoError(t, err)
+	other, err := manager.NewMutex("key2")
+	require.NoError(t, err)
+
+	require.NoError(t, mx1.Lock(ctx))
+	requireLockErrorKind(t, mx1.Lock(ctx), ErrorKindLockHeld)
+
+	// the advisory lock is held by the session of the first mutex
+	requireLockErrorKind(t, mx2.Lock(ctx), ErrorKindMaxRetriesExceeded)
+
+	// other keys aren't affected
+	require.NoError(t, other.Lock(ctx))
+	require.NoError(t, other.Unlock(ctx))
+
+	require.NoError(t, mx1.Unlock(ctx))
+	requireLockErrorKind(t, mx1.Unlock(ctx), ErrorKindLockNotHeld)
+
+	require.NoError(t, mx2.Lock(ctx))
+	require.NoError(t, mx2.Unlock(ctx))
+}
+
+func TestPostgresMutex_Wait(t *testing.T) {
+	manager := setupPostgres(t, Config{
+		Expiry:     time.Minute,
+		Tries:      50,
+		RetryDelay: 20 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	mx1, err := manager.NewMutex("key")
+	require.NoError(t, err)
+	mx2, err := manager.NewMutex("key")
+	require.NoError(t, err)
+
+	require.NoError(t, mx1.Lock(ctx))
+
+	errCh := make(chan error, 1)
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		errCh <- mx1.Unlock(ctx)
+	}()
+
+	// the second mutex retries until the first one is released
+	require.NoError(t, mx2.Lock(ctx))
+	require.NoError(t, <-errCh)
+	require.NoError(t, mx2.Unlock(ctx))
+}
+
+func TestPostgresMutex_Expiry(t *testing.T) {
+	manager := setupPostgres(t, Config{
+		Expiry:     200 * time.Millisecond,
+		Tries:      1,
+		RetryDelay: 10 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	mx1, err := manager.NewMutex("key")
+	require.NoError(t, err)
+	mx2, err := manager.NewMutex("key")
+	require.NoError(t, err)
+
+	require.NoError(t, mx1.Lock(ctx))
+
+	time.Sleep(500 * time.Millisecond)
+
+	// the expired lock is released and can be acquired by others
+	require.NoError(t, mx2.Lock(ctx))
+	require.NoError(t, mx2.Unlock(ctx))
+
+	requireLockErrorKind(t, mx1.Unlock(ctx), ErrorKindLockNotHeld)
+}
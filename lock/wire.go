@@ -15,20 +15,29 @@
 package lock
 
 import (
+	"fmt"
+
 	"github.com/go-redis/redis/v8"
 	"github.com/google/wire"
+	"github.com/jmoiron/sqlx"
 )
 
 var WireSet = wire.NewSet(
 	ProvideMutexManager,
 )
 
-func ProvideMutexManager(config Config, client redis.UniversalClient) MutexManager {
+func ProvideMutexManager(config Config, client redis.UniversalClient, db *sqlx.DB) (MutexManager, error) {
 	switch config.Provider {
 	case MemoryProvider:
-		return NewInMemory(config)
+		return NewInMemory(config), nil
 	case RedisProvider:
-		return NewRedis(config, client)
+		return NewRedis(config, client), nil
+	case PostgresProvider:
+		if db.DriverName() != "postgres" {
+			return nil, fmt.Errorf("lock provider '%s' requires the postgres database driver, got '%s'",
+				config.Provider, db.DriverName())
+		}
+		return NewPostgres(config, db), nil
 	}
-	return nil
+	return nil, nil
 }
@@ -19,8 +19,9 @@ import "time"
 type Provider string
 
 const (
-	ProviderMemory Provider = "inmemory"
-	ProviderRedis  Provider = "redis"
+	ProviderMemory   Provider = "inmemory"
+	ProviderRedis    Provider = "redis"
+	ProviderPostgres Provider = "postgres"
 )
 
 type Config struct {
@@ -29,6 +30,9 @@ type Config struct {
 
 	Provider Provider
 
+	// Datasource is the database connection string used by the postgres provider.
+	Datasource string
+
 	HealthInterval time.Duration
 	SendTimeout    time.Duration
 	ChannelSize    int
@@ -0,0 +1,315 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// postgresMaxChannelLength is the max length of a channel name (identifier) in postgres.
+	postgresMaxChannelLength = 63
+
+	// postgresMaxInlinePayload is the max length of an encoded payload that is sent inline with a notification.
+	// Postgres limits notification payloads to 8000 bytes, bigger payloads are stored in the pubsub_payloads table.
+	postgresMaxInlinePayload = 7000
+
+	// postgresPayloadRetention is the time stored payloads are kept before they get removed.
+	postgresPayloadRetention = 5 * time.Minute
+
+	postgresPrefixInline    = "d:"
+	postgresPrefixReference = "r:"
+)
+
+// Postgres is a PubSub implementation using the LISTEN/NOTIFY functionality of PostgreSQL.
+type Postgres struct {
+	config     Config
+	db         *sqlx.DB
+	datasource string
+	mutex      sync.RWMutex
+	registry   []Consumer
+}
+
+// NewPostgres creates a new postgres pubsub. The datasource is used to open a dedicated
+// connection for every subscriber, as LISTEN requires a long-lived database session.
+func NewPostgres(db *sqlx.DB, datasource string, options ...Option) *Postgres {
+	config := Config{
+		App:            "app",
+		Namespace:      "default",
+		HealthInterval: 3 * time.Second,
+		SendTimeout:    60,
+		ChannelSize:    100,
+	}
+
+	for _, f := range options {
+		f.Apply(&config)
+	}
+	return &Postgres{
+		config:     config,
+		db:         db,
+		datasource: datasource,
+		registry:   make([]Consumer, 0, 16),
+	}
+}
+
+func (r *Postgres) Subscribe(
+	ctx context.Context,
+	topic string,
+	handler func(payload []byte) error,
+	options ...SubscribeOption,
+) Consumer {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	config := SubscribeConfig{
+		topics:         make([]string, 0, 8),
+		app:            r.config.App,
+		namespace:      r.config.Namespace,
+		healthInterval: r.config.HealthInterval,
+		sendTimeout:    r.config.SendTimeout,
+		channelSize:    r.config.ChannelSize,
+	}
+
+	for _, f := range options {
+		f.Apply(&config)
+	}
+
+	// create subscriber and map it to the registry
+	subscriber := &postgresSubscriber{
+		config:  &config,
+		db:      r.db,
+		handler: handler,
+	}
+
+	config.topics = append(config.topics, topic)
+
+	subscriber.listener = pq.NewListener(r.datasource, time.Second, time.Minute,
+		func(event pq.ListenerEventType, err error) {
+			switch event {
+			case pq.ListenerEventConnectionAttemptFailed, pq.ListenerEventDisconnected:
+				log.Ctx(ctx).Warn().Err(err).Msg("postgres pubsub listener lost connection")
+			case pq.ListenerEventReconnected:
+				log.Ctx(ctx).Info().Msg("postgres pubsub listener reconnected")
+			case pq.ListenerEventConnected:
+			}
+		})
+
+	if err := subscriber.Subscribe(ctx, config.topics...); err != nil {
+		log.Ctx(ctx).Err(err).Msg("failed to listen to postgres pubsub channels")
+	}
+
+	// start subscriber
+	go subscriber.start(ctx)
+
+	// register subscriber
+	r.registry = append(r.registry, subscriber)
+
+	return subscriber
+}
+
+func (r *Postgres) Publish(ctx context.Context, topic string, payload []byte, opts ...PublishOption) error {
+	pubConfig := PublishConfig{
+		app:       r.config.App,
+		namespace: r.config.Namespace,
+	}
+	for _, f := range opts {
+		f.Apply(&pubConfig)
+	}
+
+	topic = formatTopic(pubConfig.app, pubConfig.namespace, topic)
+
+	msg := postgresPrefixInline + base64.StdEncoding.EncodeToString(payload)
+	if len(msg) > postgresMaxInlinePayload {
+		id, err := r.storePayload(ctx, payload)
+		if err != nil {
+			return fmt.Errorf("failed to store payload for pubsub topic '%s'. Error: %w", topic, err)
+		}
+
+		msg = postgresPrefixReference + strconv.FormatInt(id, 10)
+	}
+
+	_, err := r.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", postgresChannel(topic), msg)
+	if err != nil {
+		return fmt.Errorf("failed to write to pubsub topic '%s'. Error: %w",
+			topic, err)
+	}
+	return nil
+}
+
+// storePayload stores a payload that is too big to be sent inline with a notification
+// and removes stored payloads that are past retention.
+func (r *Postgres) storePayload(ctx context.Context, payload []byte) (int64, error) {
+	now := time.Now()
+
+	_, err := r.db.ExecContext(ctx, "DELETE FROM pubsub_payloads WHERE payload_created < $1",
+		now.Add(-postgresPayloadRetention).UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old payloads: %w", err)
+	}
+
+	var id int64
+	err = r.db.QueryRowContext(ctx,
+		"INSERT INTO pubsub_payloads (payload_data, payload_created) VALUES ($1, $2) RETURNING payload_id",
+		payload, now.UnixMilli()).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert payload: %w", err)
+	}
+
+	return id, nil
+}
+
+func (r *Postgres) Close(_ context.Context) error {
+	for _, subscriber := range r.registry {
+		err := subscriber.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type postgresSubscriber struct {
+	config   *SubscribeConfig
+	db       *sqlx.DB
+	listener *pq.Listener
+	handler  func([]byte) error
+}
+
+func (s *postgresSubscriber) start(ctx context.Context) {
+	healthTicker := time.NewTicker(s.config.healthInterval)
+	defer healthTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-healthTicker.C:
+			if err := s.listener.Ping(); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("postgres pubsub listener health check failed")
+			}
+		case n, ok := <-s.listener.Notify:
+			if !ok {
+				log.Ctx(ctx).Debug().Msg("postgres listener channel was closed")
+				return
+			}
+			if n == nil {
+				// the listener reconnected, notifications sent in the meantime are lost.
+				continue
+			}
+
+			payload, err := s.payload(ctx, n.Extra)
+			if err != nil {
+				log.Ctx(ctx).Err(err).Msgf("failed to read payload of notification on channel '%s'", n.Channel)
+				continue
+			}
+
+			if err = s.handler(payload); err != nil {
+				log.Ctx(ctx).Err(err).Msg("received an error from handler function")
+			}
+		}
+	}
+}
+
+// payload decodes the payload of a notification.
+func (s *postgresSubscriber) payload(ctx context.Context, msg string) ([]byte, error) {
+	if data, ok := strings.CutPrefix(msg, postgresPrefixInline); ok {
+		return base64.StdEncoding.DecodeString(data)
+	}
+
+	ref, ok := strings.CutPrefix(msg, postgresPrefixReference)
+	if !ok {
+		return nil, errors.New("unknown notification payload format")
+	}
+
+	id, err := strconv.ParseInt(ref, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload reference '%s': %w", ref, err)
+	}
+
+	var payload []byte
+	err = s.db.QueryRowContext(ctx, "SELECT payload_data FROM pubsub_payloads WHERE payload_id = $1", id).
+		Scan(&payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored payload %d: %w", id, err)
+	}
+
+	return payload, nil
+}
+
+func (s *postgresSubscriber) Subscribe(_ context.Context, topics ...string) error {
+	for _, channel := range s.formatChannels(topics...) {
+		err := s.listener.Listen(channel)
+		if err != nil && !errors.Is(err, pq.ErrChannelAlreadyOpen) {
+			return fmt.Errorf("subscribe failed for chanels %v with error: %w",
+				strings.Join(topics, ","), err)
+		}
+	}
+	return nil
+}
+
+func (s *postgresSubscriber) Unsubscribe(_ context.Context, topics ...string) error {
+	for _, channel := range s.formatChannels(topics...) {
+		err := s.listener.Unlisten(channel)
+		if err != nil && !errors.Is(err, pq.ErrChannelNotOpen) {
+			return fmt.Errorf("unsubscribe failed for chanels %v with error: %w",
+				strings.Join(topics, ","), err)
+		}
+	}
+	return nil
+}
+
+func (s *postgresSubscriber) Close() error {
+	err := s.listener.Close()
+	if err != nil {
+		return fmt.Errorf("failed while closing subscriber with error: %w", err)
+	}
+	return nil
+}
+
+func (s *postgresSubscriber) formatChannels(topics ...string) []string {
+	result := make([]string, len(topics))
+	for i, topic := range topics {
+		result[i] = postgresChannel(formatTopic(s.config.app, s.config.namespace, topic))
+	}
+	return result
+}
+
+// postgresChannel converts a formatted topic to a postgres channel name.
+// Postgres truncates identifiers that are longer than 63 bytes, so long topics are shortened
+// and suffixed with a hash of the full topic to keep them unique.
+func postgresChannel(topic string) string {
+	if len(topic) <= postgresMaxChannelLength {
+		return topic
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(topic))
+	suffix := hex.EncodeToString(h.Sum(nil))
+
+	return topic[:postgresMaxChannelLength-len(suffix)-1] + "_" + suffix
+}
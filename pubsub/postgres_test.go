@@ -0,0 +1,114 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harness/gitness/app/store/database/migrate"
+	"github.com/harness/gitness/pubsub"
+	gitness_database "github.com/harness/gitness/store/database"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testPostgresDatasourceEnv holds the datasource of the postgres database used by the tests.
+// The tests are skipped if it isn't set.
+const testPostgresDatasourceEnv = "GITNESS_TEST_POSTGRES_DATASOURCE"
+
+func TestPostgres_PublishSubscribe(t *testing.T) {
+	datasource := os.Getenv(testPostgresDatasourceEnv)
+	if datasource == "" {
+		t.Skipf("%s isn't set", testPostgresDatasourceEnv)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db, err := gitness_database.ConnectAndMigrate(ctx, "postgres", datasource, migrate.Migrate)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ps := pubsub.NewPostgres(db, datasource,
+		pubsub.WithApp("gitness"),
+		pubsub.WithNamespace(fmt.Sprintf("test-%d", time.Now().UnixNano())))
+	defer func() { _ = ps.Close(ctx) }()
+
+	// topics longer than the max postgres identifier length must still be delivered
+	topic := "topic-" + strings.Repeat("x", 100)
+
+	received := make(chan []byte, 16)
+	ps.Subscribe(ctx, topic, func(payload []byte) error {
+		received <- payload
+		return nil
+	})
+
+	// the listener connects asynchronously, publish until the subscription is active.
+	require.Eventually(t, func() bool {
+		if err := ps.Publish(ctx, topic, []byte("ping")); err != nil {
+			return false
+		}
+		select {
+		case <-received:
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 10*time.Second, 10*time.Millisecond)
+
+	drain := func() {
+		for {
+			select {
+			case <-received:
+			case <-time.After(200 * time.Millisecond):
+				return
+			}
+		}
+	}
+	drain()
+
+	receive := func() []byte {
+		select {
+		case payload := <-received:
+			return payload
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for the message")
+			return nil
+		}
+	}
+
+	// inline payload
+	require.NoError(t, ps.Publish(ctx, topic, []byte("hello")))
+	require.Equal(t, []byte("hello"), receive())
+
+	// payloads over the notification size limit are stored in the database
+	big := bytes.Repeat([]byte("0123456789"), 1000)
+	require.NoError(t, ps.Publish(ctx, topic, big))
+	require.Equal(t, big, receive())
+
+	// messages of other topics aren't delivered
+	require.NoError(t, ps.Publish(ctx, "other", []byte("other")))
+	select {
+	case payload := <-received:
+		t.Fatalf("unexpected message: %q", payload)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
@@ -15,15 +15,18 @@
 package pubsub
 
 import (
+	"fmt"
+
 	"github.com/go-redis/redis/v8"
 	"github.com/google/wire"
+	"github.com/jmoiron/sqlx"
 )
 
 var WireSet = wire.NewSet(
 	ProvidePubSub,
 )
 
-func ProvidePubSub(config Config, client redis.UniversalClient) PubSub {
+func ProvidePubSub(config Config, client redis.UniversalClient, db *sqlx.DB) (PubSub, error) {
 	switch config.Provider {
 	case ProviderRedis:
 		return NewRedis(client,
@@ -32,7 +35,19 @@ func ProvidePubSub(config Config, client redis.UniversalClient) PubSub {
 			WithHealthCheckInterval(config.HealthInterval),
 			WithSendTimeout(config.SendTimeout),
 			WithSize(config.ChannelSize),
-		)
+		), nil
+	case ProviderPostgres:
+		if db.DriverName() != "postgres" {
+			return nil, fmt.Errorf("pubsub provider '%s' requires the postgres database driver, got '%s'",
+				config.Provider, db.DriverName())
+		}
+		return NewPostgres(db, config.Datasource,
+			WithApp(config.App),
+			WithNamespace(config.Namespace),
+			WithHealthCheckInterval(config.HealthInterval),
+			WithSendTimeout(config.SendTimeout),
+			WithSize(config.ChannelSize),
+		), nil
 	case ProviderMemory:
 		fallthrough
 	default:
@@ -42,6 +57,6 @@ func ProvidePubSub(config Config, client redis.UniversalClient) PubSub {
 			WithHealthCheckInterval(config.HealthInterval),
 			WithSendTimeout(config.SendTimeout),
 			WithSize(config.ChannelSize),
-		)
+		), nil
 	}
 }
@@ -0,0 +1,826 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DatabaseConsumer provides functionality to process streams stored in the database as part of a consumer group.
+// It mirrors the semantics of redis consumer groups: Every group keeps track of the last message delivered
+// to it, and delivered messages stay pending for the consumer until they are acknowledged.
+// Pending messages that aren't acknowledged within the idle timeout are reclaimed and retried.
+// Messages that exceed the max retries are moved to the dead letters of the group.
+// The stream tables are part of the primary database, which can be either sqlite or PostgreSQL.
+type DatabaseConsumer struct {
+	db *sqlx.DB
+	// namespace specifies the namespace of the keys - any stream key will be prefixed with it
+	namespace string
+	// groupName specifies the name of the consumer group.
+	groupName string
+	// consumerName specifies the name of the consumer.
+	consumerName string
+
+	// committedCondition restricts read messages to the ones that are guaranteed to be visible.
+	committedCondition string
+
+	// Config is the generic consumer configuration.
+	Config ConsumerConfig
+
+	// streams is a map of all registered streams and their handlers.
+	streams map[string]handler
+
+	isStarted    bool
+	messageQueue chan message
+	errorCh      chan error
+	infoCh       chan string
+}
+
+// NewDatabaseConsumer creates new database stream consumer.
+// It returns channels of info messages and errors. The caller should not block on these channels for too long.
+// These channels are provided mainly for logging.
+func NewDatabaseConsumer(db *sqlx.DB, namespace string,
+	groupName string, consumerName string) (*DatabaseConsumer, error) {
+	if groupName == "" {
+		return nil, errors.New("groupName can't be empty")
+	}
+	if consumerName == "" {
+		return nil, errors.New("consumerName can't be empty")
+	}
+
+	const queueCapacity = 500
+	const errorChCapacity = 64
+	const infoChCapacity = 64
+
+	return &DatabaseConsumer{
+		db:                 db,
+		namespace:          namespace,
+		groupName:          groupName,
+		consumerName:       consumerName,
+		committedCondition: committedMessagesCondition(db),
+		streams:            map[string]handler{},
+		Config:             defaultConfig,
+		isStarted:          false,
+		messageQueue:       make(chan message, queueCapacity),
+		errorCh:            make(chan error, errorChCapacity),
+		infoCh:             make(chan string, infoChCapacity),
+	}, nil
+}
+
+// committedMessagesCondition returns the sql condition that restricts messages to the ones inserted by
+// transactions that are guaranteed to be completed.
+// In PostgreSQL, message ids are assigned on insert but become visible on commit. The group reads messages
+// in the order of their transaction ids instead, and only once no older transaction could still insert a message.
+// sqlite serializes all writes, so messages always become visible in the order of their ids.
+func committedMessagesCondition(db *sqlx.DB) string {
+	if db.DriverName() == "postgres" {
+		return " AND message_txid < txid_snapshot_xmin(txid_current_snapshot())"
+	}
+
+	return ""
+}
+
+func (c *DatabaseConsumer) Configure(opts ...ConsumerOption) {
+	if c.isStarted {
+		return
+	}
+
+	for _, opt := range opts {
+		opt.apply(&c.Config)
+	}
+}
+
+func (c *DatabaseConsumer) Register(streamID string, fn HandlerFunc, opts ...HandlerOption) error {
+	if c.isStarted {
+		return ErrAlreadyStarted
+	}
+	if streamID == "" {
+		return errors.New("streamID can't be empty")
+	}
+	if fn == nil {
+		return errors.New("fn can't be empty")
+	}
+
+	// transpose streamID to key namespace - no need to keep inner streamID
+	transposedStreamID := transposeStreamID(c.namespace, streamID)
+	if _, ok := c.streams[transposedStreamID]; ok {
+		return fmt.Errorf("consumer is already registered for '%s' (database stream '%s')",
+			streamID, transposedStreamID)
+	}
+
+	// create final config for handler
+	config := c.Config.DefaultHandlerConfig
+	for _, opt := range opts {
+		opt.apply(&config)
+	}
+
+	c.streams[transposedStreamID] = handler{
+		handle: fn,
+		config: config,
+	}
+
+	return nil
+}
+
+func (c *DatabaseConsumer) Start(ctx context.Context) error {
+	if c.isStarted {
+		return ErrAlreadyStarted
+	}
+
+	if len(c.streams) == 0 {
+		return errors.New("no streams registered")
+	}
+
+	// Check if the database is accessible, fail if it's not.
+	err := c.db.PingContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	// Create consumer group for all streams.
+	err = c.createGroupForAllStreams(ctx)
+	if err != nil {
+		return err
+	}
+
+	// mark as started before starting go routines (can't error out from here)
+	c.isStarted = true
+
+	wg := &sync.WaitGroup{}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// launch stream reader, it will finish when the ctx is done
+		c.reader(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// launch message reclaimer, it will finish when the ctx is done.
+		const reclaimInterval = 10 * time.Second
+		c.reclaimer(ctx, reclaimInterval)
+	}()
+
+	for i := 0; i < c.Config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// launch message consumer, it will finish when the ctx is done
+			c.consumer(ctx)
+		}()
+	}
+
+	go func() {
+		// wait for all go routines to complete
+		wg.Wait()
+
+		// close all channels
+		close(c.messageQueue)
+		close(c.errorCh)
+		close(c.infoCh)
+	}()
+
+	return nil
+}
+
+// reader method polls the streams for messages that haven't been delivered to the group yet.
+// The messages are then sent to a go channel for processing.
+// On start, the reader first retrieves all messages that are still pending for this consumer
+// (to allow for seamless restarts).
+// The method terminates when the provided context finishes.
+func (c *DatabaseConsumer) reader(ctx context.Context) {
+	delays := []time.Duration{1 * time.Millisecond, 5 * time.Second, 15 * time.Second, 30 * time.Second, time.Minute}
+	consecutiveFailures := 0
+
+	// poll interval used in case there were no new messages in any of the streams.
+	const pollInterval = time.Second
+
+	// ASSUMPTION: only one consumer with a given groupName+consumerName is running at a time
+	scanHistory := true
+	idle := false
+
+	for {
+		var delay time.Duration
+		switch {
+		case consecutiveFailures >= len(delays):
+			delay = delays[len(delays)-1]
+		case consecutiveFailures > 0 || !idle:
+			delay = delays[consecutiveFailures]
+		default:
+			delay = pollInterval
+		}
+		readTimer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			readTimer.Stop()
+			return
+
+		case <-readTimer.C:
+			if scanHistory {
+				err := c.readHistory(ctx)
+				if err != nil {
+					consecutiveFailures++
+					c.pushError(fmt.Errorf("failed to read history (consecutive fails: %d): %w",
+						consecutiveFailures, err))
+					continue
+				}
+
+				scanHistory = false
+				c.pushInfo("completed scan of history")
+			}
+
+			const count = 100
+
+			read := 0
+			failed := false
+			for streamID := range c.streams {
+				n, err := c.readStream(ctx, streamID, count)
+				if errors.Is(err, context.Canceled) {
+					break
+				}
+				if err != nil {
+					failed = true
+					c.pushError(fmt.Errorf("failed to read database stream '%s' (consecutive fails: %d): %w",
+						streamID, consecutiveFailures+1, err))
+					continue
+				}
+
+				read += n
+			}
+
+			if failed {
+				consecutiveFailures++
+				continue
+			}
+
+			// reset fail count
+			consecutiveFailures = 0
+
+			// if no messages were read we wait for the poll interval before the next iteration
+			idle = read == 0
+		}
+	}
+}
+
+// readHistory retrieves all messages that are still pending for this consumer and enqueues them for processing.
+func (c *DatabaseConsumer) readHistory(ctx context.Context) error {
+	// similar to redis, redelivering a message increases its delivery count.
+	_, err := c.db.ExecContext(ctx, `
+		UPDATE stream_pending
+		SET pending_delivered = $1, pending_deliveries = pending_deliveries + 1
+		WHERE pending_group = $2 AND pending_consumer = $3`,
+		time.Now().UnixMilli(), c.groupName, c.consumerName)
+	if err != nil {
+		return fmt.Errorf("failed to update pending messages: %w", err)
+	}
+
+	const sqlQuery = `
+		SELECT message_id, message_stream, message_values, message_created
+		FROM stream_pending
+		INNER JOIN stream_messages ON message_id = pending_message_id
+		WHERE pending_group = $1 AND pending_consumer = $2
+		ORDER BY message_id`
+
+	var rows []databaseMessage
+	err = c.db.SelectContext(ctx, &rows, sqlQuery, c.groupName, c.consumerName)
+	if err != nil {
+		return fmt.Errorf("failed to read pending messages: %w", err)
+	}
+
+	for _, row := range rows {
+		if _, ok := c.streams[row.Stream]; !ok {
+			continue
+		}
+
+		if err = c.enqueue(row); err != nil {
+			c.pushError(err)
+		}
+	}
+
+	return nil
+}
+
+// readStream reads new messages of a stream for the group, marks them as pending for this consumer
+// and enqueues them for processing. It returns the number of messages that were read.
+func (c *DatabaseConsumer) readStream(ctx context.Context, streamID string, count int) (int, error) {
+	// Check for new messages before locking the group, as most polls of a stream won't find any.
+	cursor, ok, err := c.findGroupCursor(ctx, c.db, streamID)
+	if err != nil {
+		return 0, err
+	}
+
+	if !ok {
+		// group doesn't exist anymore - recreate it
+		if err = createDatabaseGroup(ctx, c.db, streamID, c.groupName); err != nil {
+			return 0, err
+		}
+
+		c.pushInfo(fmt.Sprintf("re-created group '%s' for stream '%s' where it got removed", c.groupName, streamID))
+		return 0, nil
+	}
+
+	var nextID int64
+	err = c.db.QueryRowContext(ctx, `
+		SELECT message_id
+		FROM stream_messages
+		WHERE message_stream = $1 AND
+			(message_txid > $2 OR (message_txid = $2 AND message_id > $3))`+c.committedCondition+`
+		LIMIT 1`, streamID, cursor.TxID, cursor.ID).Scan(&nextID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to check for new messages: %w", err)
+	}
+
+	rows, err := c.deliverNewMessages(ctx, streamID, count)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, row := range rows {
+		if err = c.enqueue(row); err != nil {
+			c.pushError(err)
+		}
+	}
+
+	return len(rows), nil
+}
+
+// deliverNewMessages marks the next messages of the stream that haven't been delivered to the group yet
+// as pending for this consumer and moves the group cursor past them.
+func (c *DatabaseConsumer) deliverNewMessages(
+	ctx context.Context,
+	streamID string,
+	count int,
+) ([]databaseMessage, error) {
+	tx, err := c.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	// Lock the group row before reading it, so the same message isn't delivered twice to the group.
+	// NOTE: A no-op update is used instead of SELECT FOR UPDATE as it locks the row in postgres
+	// and acquires the write lock upfront in sqlite (which doesn't support SELECT FOR UPDATE).
+	res, err := tx.ExecContext(ctx, `
+		UPDATE stream_groups
+		SET group_last_message_id = group_last_message_id
+		WHERE group_stream = $1 AND group_name = $2`, streamID, c.groupName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock group: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get number of locked groups: %w", err)
+	}
+
+	if n == 0 {
+		// group got removed in the meantime - it's re-created by the next read of the stream.
+		return nil, nil
+	}
+
+	cursor, _, err := c.findGroupCursor(ctx, tx, streamID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []databaseMessage
+	err = tx.SelectContext(ctx, &rows, `
+		SELECT message_id, message_txid, message_stream, message_values, message_created
+		FROM stream_messages
+		WHERE message_stream = $1 AND
+			(message_txid > $2 OR (message_txid = $2 AND message_id > $3))`+c.committedCondition+`
+		ORDER BY message_txid, message_id
+		LIMIT $4`, streamID, cursor.TxID, cursor.ID, count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read messages: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now().UnixMilli()
+	for _, row := range rows {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO stream_pending (pending_group, pending_message_id, pending_stream,
+				pending_consumer, pending_delivered, pending_deliveries)
+			VALUES ($1, $2, $3, $4, $5, 1)
+			ON CONFLICT DO NOTHING`, c.groupName, row.ID, streamID, c.consumerName, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to mark message %d as pending: %w", row.ID, err)
+		}
+	}
+
+	last := rows[len(rows)-1]
+	_, err = tx.ExecContext(ctx, `
+		UPDATE stream_groups
+		SET group_last_message_txid = $1, group_last_message_id = $2
+		WHERE group_stream = $3 AND group_name = $4`, last.TxID, last.ID, streamID, c.groupName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update last delivered message of group: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return rows, nil
+}
+
+// findGroupCursor returns the position of the last message of the stream that got delivered to the group.
+// The returned bool is false in case the group doesn't exist.
+func (c *DatabaseConsumer) findGroupCursor(
+	ctx context.Context,
+	q sqlx.QueryerContext,
+	streamID string,
+) (databaseCursor, bool, error) {
+	var cursor databaseCursor
+	err := sqlx.GetContext(ctx, q, &cursor, `
+		SELECT group_last_message_txid, group_last_message_id
+		FROM stream_groups
+		WHERE group_stream = $1 AND group_name = $2`, streamID, c.groupName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return databaseCursor{}, false, nil
+	}
+	if err != nil {
+		return databaseCursor{}, false, fmt.Errorf("failed to read group: %w", err)
+	}
+
+	return cursor, true, nil
+}
+
+// reclaimer periodically inspects pending messages of the group.
+// If a message sits longer than processingTimeout, we attempt to reclaim the message for this consumer
+// and enqueue it for processing.
+//
+//nolint:gocognit // refactor if needed
+func (c *DatabaseConsumer) reclaimer(ctx context.Context, reclaimInterval time.Duration) {
+	reclaimTimer := time.NewTimer(reclaimInterval)
+	defer func() {
+		reclaimTimer.Stop()
+	}()
+
+	const (
+		baseCount = 16
+		maxCount  = 1024
+	)
+
+	count := baseCount
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-reclaimTimer.C:
+			for streamID, handler := range c.streams {
+				var resPending []databasePending
+				err := c.db.SelectContext(ctx, &resPending, `
+					SELECT pending_message_id, pending_delivered, pending_deliveries
+					FROM stream_pending
+					WHERE pending_stream = $1 AND pending_group = $2 AND pending_delivered < $3
+					ORDER BY pending_message_id
+					LIMIT $4`,
+					streamID, c.groupName, time.Now().Add(-handler.config.idleTimeout).UnixMilli(), count)
+				if err != nil {
+					c.pushError(fmt.Errorf("failed to fetch pending messages: %w", err))
+					continue
+				}
+
+				for _, resMessage := range resPending {
+					if resMessage.Deliveries > int64(handler.config.maxRetries) {
+						// Large delivery count might mean there is something wrong with the message,
+						// so we'll move it to the dead letters of the group.
+						errDead := c.deadLetter(ctx, resMessage)
+						if errDead != nil {
+							c.pushError(fmt.Errorf(
+								"failed to move message '%d' (Retries: %d) in stream '%s' to dead letters: %w",
+								resMessage.MessageID, resMessage.Deliveries, streamID, errDead))
+						} else {
+							observeMessage(streamID, c.groupName, metricsResultDiscarded)
+							c.pushError(fmt.Errorf(
+								"moved message '%d' (Retries: %d) in stream '%s' to dead letters",
+								resMessage.MessageID, resMessage.Deliveries-1, streamID))
+						}
+						continue
+					}
+
+					// Otherwise, claim the message so we can retry it.
+					m, claimed, errClaim := c.claim(ctx, resMessage)
+					if errClaim != nil {
+						c.pushError(fmt.Errorf("failed to claim message '%d' in stream '%s': %w",
+							resMessage.MessageID, streamID, errClaim))
+						continue
+					}
+
+					if !claimed {
+						// the message got claimed by another consumer, acknowledged or removed from the stream.
+						continue
+					}
+
+					if err = c.enqueue(m); err != nil {
+						c.pushError(err)
+					}
+				}
+
+				// If number of messages that we got is equal to the number that we requested
+				// it means that there's a lot for processing, so we'll increase number of messages
+				// that we'll pull in the next iteration.
+				if len(resPending) == count {
+					count *= 2
+					if count > maxCount {
+						count = maxCount
+					}
+				} else {
+					count = baseCount
+				}
+			}
+
+			reclaimTimer.Reset(reclaimInterval)
+		}
+	}
+}
+
+// claim assigns a pending message to this consumer, unless somebody else claimed it in the meantime.
+func (c *DatabaseConsumer) claim(ctx context.Context, p databasePending) (databaseMessage, bool, error) {
+	res, err := c.db.ExecContext(ctx, `
+		UPDATE stream_pending
+		SET pending_consumer = $1, pending_delivered = $2, pending_deliveries = pending_deliveries + 1
+		WHERE pending_group = $3 AND pending_message_id = $4 AND pending_delivered = $5 AND pending_deliveries = $6`,
+		c.consumerName, time.Now().UnixMilli(), c.groupName, p.MessageID, p.Delivered, p.Deliveries)
+	if err != nil {
+		return databaseMessage{}, false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return databaseMessage{}, false, err
+	}
+
+	if n == 0 {
+		return databaseMessage{}, false, nil
+	}
+
+	var m databaseMessage
+	err = c.db.GetContext(ctx, &m, `
+		SELECT message_id, message_stream, message_values, message_created
+		FROM stream_messages
+		WHERE message_id = $1`, p.MessageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// the message is removed from the stream (because of max stream length).
+		// The only option is to acknowledge it.
+		return databaseMessage{}, false, c.ack(ctx, p.MessageID)
+	}
+	if err != nil {
+		return databaseMessage{}, false, err
+	}
+
+	return m, true, nil
+}
+
+// consumer method consumes messages coming from the streams. The method terminates when the context is done.
+func (c *DatabaseConsumer) consumer(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m := <-c.messageQueue:
+			if m.id == "" {
+				// id should never be empty, if it is then the channel is closed
+				return
+			}
+
+			handler, ok := c.streams[m.streamID]
+			if !ok {
+				// we don't want to ack the message
+				// maybe someone else can claim and process it (worst case it expires)
+				c.pushError(fmt.Errorf("received message '%s' in stream '%s' that doesn't belong to us, skip",
+					m.id, m.streamID))
+				continue
+			}
+
+			observeLag(m.streamID, c.groupName, m.timestamp)
+
+			err := func() (err error) {
+				// Ensure that handlers don't cause panic.
+				defer func() {
+					if r := recover(); r != nil {
+						c.pushError(fmt.Errorf("PANIC when processing message '%s' in stream '%s':\n%s",
+							m.id, m.streamID, debug.Stack()))
+					}
+				}()
+
+				return handler.handle(ctx, m.id, m.values)
+			}()
+			msgID, errID := strconv.ParseInt(m.id, 10, 64)
+			if errID != nil {
+				c.pushError(fmt.Errorf("invalid message id '%s' in stream '%s': %w", m.id, m.streamID, errID))
+				continue
+			}
+
+			if err != nil {
+				observeMessage(m.streamID, c.groupName, metricsResultFailure)
+				c.pushError(fmt.Errorf("failed to process message '%s' in stream '%s': %w", m.id, m.streamID, err))
+
+				// keep the error for the dead letters in case the message exceeds its retries.
+				if errFail := c.fail(ctx, msgID, err); errFail != nil {
+					c.pushError(fmt.Errorf("failed to store error of message '%s' in stream '%s': %w",
+						m.id, m.streamID, errFail))
+				}
+				continue
+			}
+
+			observeMessage(m.streamID, c.groupName, metricsResultSuccess)
+
+			err = c.ack(ctx, msgID)
+			if err != nil {
+				c.pushError(fmt.Errorf("failed to acknowledge message '%s' in stream '%s': %w", m.id, m.streamID, err))
+				continue
+			}
+		}
+	}
+}
+
+// ack acknowledges the message by removing it from the pending messages of the group.
+func (c *DatabaseConsumer) ack(ctx context.Context, msgID int64) error {
+	_, err := c.db.ExecContext(ctx, `
+		DELETE FROM stream_pending
+		WHERE pending_group = $1 AND pending_message_id = $2`, c.groupName, msgID)
+	return err
+}
+
+// fail stores the error of the last failed processing attempt of the message.
+func (c *DatabaseConsumer) fail(ctx context.Context, msgID int64, errProcess error) error {
+	_, err := c.db.ExecContext(ctx, `
+		UPDATE stream_pending
+		SET pending_error = $1
+		WHERE pending_group = $2 AND pending_message_id = $3`, errProcess.Error(), c.groupName, msgID)
+	return err
+}
+
+// deadLetter moves a pending message that exceeded its retries to the dead letters of the group.
+// Dead letters that are older than the retention period are removed.
+func (c *DatabaseConsumer) deadLetter(ctx context.Context, p databasePending) error {
+	const deadLetterRetention = 7 * 24 * time.Hour
+
+	tx, err := c.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	now := time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO stream_dead_letters (dead_letter_stream, dead_letter_group, dead_letter_message_id,
+			dead_letter_values, dead_letter_message_created, dead_letter_deliveries, dead_letter_error,
+			dead_letter_created)
+		SELECT pending_stream, pending_group, message_id,
+			message_values, message_created, pending_deliveries, pending_error,
+			$1
+		FROM stream_pending
+		INNER JOIN stream_messages ON message_id = pending_message_id
+		WHERE pending_group = $2 AND pending_message_id = $3`, now.UnixMilli(), c.groupName, p.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to insert dead letter: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM stream_pending
+		WHERE pending_group = $1 AND pending_message_id = $2`, c.groupName, p.MessageID)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending message: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM stream_dead_letters
+		WHERE dead_letter_group = $1 AND dead_letter_created < $2`,
+		c.groupName, now.Add(-deadLetterRetention).UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to delete old dead letters: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// enqueue converts the message and puts it into the message queue.
+func (c *DatabaseConsumer) enqueue(m databaseMessage) error {
+	values, err := unmarshalDatabaseValues(m.Values)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal values of message '%d' in stream '%s': %w", m.ID, m.Stream, err)
+	}
+
+	c.messageQueue <- message{
+		streamID:  m.Stream,
+		id:        strconv.FormatInt(m.ID, 10),
+		values:    values,
+		timestamp: time.UnixMilli(m.Created),
+	}
+
+	return nil
+}
+
+func (c *DatabaseConsumer) pushError(err error) {
+	select {
+	case c.errorCh <- err:
+	default:
+	}
+}
+
+func (c *DatabaseConsumer) pushInfo(s string) {
+	select {
+	case c.infoCh <- s:
+	default:
+	}
+}
+
+func (c *DatabaseConsumer) Errors() <-chan error { return c.errorCh }
+func (c *DatabaseConsumer) Infos() <-chan string { return c.infoCh }
+
+func (c *DatabaseConsumer) createGroupForAllStreams(ctx context.Context) error {
+	for streamID := range c.streams {
+		err := createDatabaseGroup(ctx, c.db, streamID, c.groupName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createDatabaseGroup(ctx context.Context, db *sqlx.DB, streamID string, groupName string) error {
+	// Creates a new consumer group that starts receiving messages from now on.
+	// Existing messages in the stream are ignored (we don't want to overload a group with old messages)
+	const sqlQuery = `
+		INSERT INTO stream_groups (group_stream, group_name, group_last_message_txid, group_last_message_id,
+			group_created)
+		VALUES ($1, $2, (
+			SELECT COALESCE(MAX(message_txid), 0)
+			FROM stream_messages
+			WHERE message_stream = $1
+		), (
+			SELECT COALESCE(MAX(message_id), 0)
+			FROM stream_messages
+			WHERE message_stream = $1
+		), $3)
+		ON CONFLICT DO NOTHING`
+
+	_, err := db.ExecContext(ctx, sqlQuery, streamID, groupName, time.Now().UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to create consumer group '%s' for stream '%s': %w", groupName, streamID, err)
+	}
+
+	return nil
+}
+
+// databaseMessage is a message as stored in the stream_messages table.
+type databaseMessage struct {
+	ID      int64  `db:"message_id"`
+	TxID    int64  `db:"message_txid"`
+	Stream  string `db:"message_stream"`
+	Values  string `db:"message_values"`
+	Created int64  `db:"message_created"`
+}
+
+// databaseCursor is the position of the last message of a stream that got delivered to a group.
+type databaseCursor struct {
+	TxID int64 `db:"group_last_message_txid"`
+	ID   int64 `db:"group_last_message_id"`
+}
+
+// databasePending is a pending message as stored in the stream_pending table.
+type databasePending struct {
+	MessageID  int64 `db:"pending_message_id"`
+	Delivered  int64 `db:"pending_delivered"`
+	Deliveries int64 `db:"pending_deliveries"`
+}
@@ -0,0 +1,352 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/maragudk/migrate"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNamespace = "test"
+	testStream    = "stream"
+	testGroup     = "group"
+)
+
+// setupDatabase creates a sqlite database with the schema of the primary database (which contains the stream tables).
+func setupDatabase(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite3", filepath.Join(t.TempDir(), "stream.sqlite3")+"?_foreign_keys=on")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	err = migrate.New(migrate.Options{
+		DB: db.DB,
+		FS: os.DirFS(filepath.Join("..", "app", "store", "database", "migrate", "sqlite")),
+	}).MigrateUp(context.Background())
+	require.NoError(t, err)
+
+	return db
+}
+
+func newTestDatabaseConsumer(t *testing.T, db *sqlx.DB, consumerName string) *DatabaseConsumer {
+	t.Helper()
+
+	c, err := NewDatabaseConsumer(db, testNamespace, testGroup, consumerName)
+	require.NoError(t, err)
+
+	err = c.Register(testStream, func(context.Context, string, map[string]interface{}) error { return nil })
+	require.NoError(t, err)
+
+	return c
+}
+
+func sendTestMessage(t *testing.T, p *DatabaseProducer, value string) int64 {
+	t.Helper()
+
+	id, err := p.Send(context.Background(), testStream, map[string]interface{}{"value": value})
+	require.NoError(t, err)
+
+	msgID, err := strconv.ParseInt(id, 10, 64)
+	require.NoError(t, err)
+
+	return msgID
+}
+
+func dequeueTestMessage(t *testing.T, c *DatabaseConsumer) message {
+	t.Helper()
+
+	select {
+	case m := <-c.messageQueue:
+		return m
+	default:
+		t.Fatal("expected a message in the queue")
+		return message{}
+	}
+}
+
+func findTestPending(t *testing.T, db *sqlx.DB, msgID int64) (string, int64) {
+	t.Helper()
+
+	var res struct {
+		Consumer   string `db:"pending_consumer"`
+		Deliveries int64  `db:"pending_deliveries"`
+	}
+	err := db.Get(&res, `
+		SELECT pending_consumer, pending_deliveries
+		FROM stream_pending
+		WHERE pending_group = $1 AND pending_message_id = $2`, testGroup, msgID)
+	require.NoError(t, err)
+
+	return res.Consumer, res.Deliveries
+}
+
+func countTestRows(t *testing.T, db *sqlx.DB, table string) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, db.Get(&count, "SELECT COUNT(*) FROM "+table))
+
+	return count
+}
+
+func TestDatabaseConsumer_GroupCreation(t *testing.T) {
+	ctx := context.Background()
+	db := setupDatabase(t)
+	p := NewDatabaseProducer(db, testNamespace, 0, false)
+	c := newTestDatabaseConsumer(t, db, "consumer")
+	streamID := transposeStreamID(testNamespace, testStream)
+
+	// messages sent before the group exists are ignored.
+	sendTestMessage(t, p, "old")
+	require.NoError(t, c.createGroupForAllStreams(ctx))
+
+	n, err := c.readStream(ctx, streamID, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	sendTestMessage(t, p, "new")
+
+	n, err = c.readStream(ctx, streamID, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, "new", dequeueTestMessage(t, c).values["value"])
+
+	// a removed group is re-created and starts with the messages sent afterwards.
+	_, err = db.Exec(`DELETE FROM stream_groups`)
+	require.NoError(t, err)
+	sendTestMessage(t, p, "lost")
+
+	n, err = c.readStream(ctx, streamID, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	require.Equal(t, 1, countTestRows(t, db, "stream_groups"))
+
+	sendTestMessage(t, p, "after")
+
+	n, err = c.readStream(ctx, streamID, 10)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, "after", dequeueTestMessage(t, c).values["value"])
+}
+
+func TestDatabaseConsumer_ReadStream(t *testing.T) {
+	ctx := context.Background()
+	db := setupDatabase(t)
+	p := NewDatabaseProducer(db, testNamespace, 0, false)
+	c := newTestDatabaseConsumer(t, db, "consumer")
+	streamID := transposeStreamID(testNamespace, testStream)
+
+	require.NoError(t, c.createGroupForAllStreams(ctx))
+
+	for _, v := range []string{"1", "2", "3"} {
+		sendTestMessage(t, p, v)
+	}
+
+	// messages are read in order, limited by the provided count.
+	n, err := c.readStream(ctx, streamID, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, "1", dequeueTestMessage(t, c).values["value"])
+	require.Equal(t, "2", dequeueTestMessage(t, c).values["value"])
+
+	n, err = c.readStream(ctx, streamID, 2)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, "3", dequeueTestMessage(t, c).values["value"])
+	require.Equal(t, 3, countTestRows(t, db, "stream_pending"))
+
+	// a second consumer of the group doesn't get the messages that are already delivered to the group.
+	c2 := newTestDatabaseConsumer(t, db, "consumer2")
+	n, err = c2.readStream(ctx, streamID, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestDatabaseConsumer_ReadStream_IdleWithoutWriteLock(t *testing.T) {
+	ctx := context.Background()
+	db := setupDatabase(t)
+	c := newTestDatabaseConsumer(t, db, "consumer")
+	streamID := transposeStreamID(testNamespace, testStream)
+
+	require.NoError(t, c.createGroupForAllStreams(ctx))
+
+	// hold the sqlite write lock on another connection.
+	tx, err := db.BeginTxx(ctx, nil)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+	_, err = tx.Exec(`UPDATE stream_groups SET group_created = group_created`)
+	require.NoError(t, err)
+
+	// polling a stream without new messages doesn't require the write lock.
+	readCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+
+	n, err := c.readStream(readCtx, streamID, 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestDatabaseConsumer_Ack(t *testing.T) {
+	ctx := context.Background()
+	db := setupDatabase(t)
+	p := NewDatabaseProducer(db, testNamespace, 0, false)
+	c := newTestDatabaseConsumer(t, db, "consumer")
+	streamID := transposeStreamID(testNamespace, testStream)
+
+	require.NoError(t, c.createGroupForAllStreams(ctx))
+	msgID := sendTestMessage(t, p, "1")
+
+	_, err := c.readStream(ctx, streamID, 10)
+	require.NoError(t, err)
+	dequeueTestMessage(t, c)
+
+	consumer, deliveries := findTestPending(t, db, msgID)
+	require.Equal(t, "consumer", consumer)
+	require.Equal(t, int64(1), deliveries)
+
+	require.NoError(t, c.ack(ctx, msgID))
+	require.Equal(t, 0, countTestRows(t, db, "stream_pending"))
+}
+
+func TestDatabaseConsumer_Redelivery(t *testing.T) {
+	ctx := context.Background()
+	db := setupDatabase(t)
+	p := NewDatabaseProducer(db, testNamespace, 0, false)
+	c := newTestDatabaseConsumer(t, db, "consumer")
+	streamID := transposeStreamID(testNamespace, testStream)
+
+	require.NoError(t, c.createGroupForAllStreams(ctx))
+	msgID := sendTestMessage(t, p, "1")
+
+	_, err := c.readStream(ctx, streamID, 10)
+	require.NoError(t, err)
+	dequeueTestMessage(t, c)
+	require.NoError(t, c.fail(ctx, msgID, errors.New("failed")))
+
+	// a restarted consumer gets its pending messages again.
+	restarted := newTestDatabaseConsumer(t, db, "consumer")
+	require.NoError(t, restarted.readHistory(ctx))
+	require.Equal(t, "1", dequeueTestMessage(t, restarted).values["value"])
+
+	_, deliveries := findTestPending(t, db, msgID)
+	require.Equal(t, int64(2), deliveries)
+}
+
+func TestDatabaseConsumer_Claim(t *testing.T) {
+	ctx := context.Background()
+	db := setupDatabase(t)
+	p := NewDatabaseProducer(db, testNamespace, 0, false)
+	c := newTestDatabaseConsumer(t, db, "consumer")
+	c2 := newTestDatabaseConsumer(t, db, "consumer2")
+	streamID := transposeStreamID(testNamespace, testStream)
+
+	require.NoError(t, c.createGroupForAllStreams(ctx))
+	msgID := sendTestMessage(t, p, "1")
+
+	_, err := c.readStream(ctx, streamID, 10)
+	require.NoError(t, err)
+
+	var pending databasePending
+	err = db.Get(&pending, `
+		SELECT pending_message_id, pending_delivered, pending_deliveries
+		FROM stream_pending`)
+	require.NoError(t, err)
+
+	m, claimed, err := c2.claim(ctx, pending)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.Equal(t, msgID, m.ID)
+
+	consumer, deliveries := findTestPending(t, db, msgID)
+	require.Equal(t, "consumer2", consumer)
+	require.Equal(t, int64(2), deliveries)
+
+	// the message can't be claimed again based on the outdated delivery.
+	_, claimed, err = c.claim(ctx, pending)
+	require.NoError(t, err)
+	require.False(t, claimed)
+}
+
+func TestDatabaseConsumer_DeadLetter(t *testing.T) {
+	ctx := context.Background()
+	db := setupDatabase(t)
+	p := NewDatabaseProducer(db, testNamespace, 0, false)
+	c := newTestDatabaseConsumer(t, db, "consumer")
+	streamID := transposeStreamID(testNamespace, testStream)
+
+	require.NoError(t, c.createGroupForAllStreams(ctx))
+	msgID := sendTestMessage(t, p, "1")
+
+	_, err := c.readStream(ctx, streamID, 10)
+	require.NoError(t, err)
+	require.NoError(t, c.fail(ctx, msgID, errors.New("failed")))
+
+	require.NoError(t, c.deadLetter(ctx, databasePending{MessageID: msgID}))
+	require.Equal(t, 0, countTestRows(t, db, "stream_pending"))
+
+	var deadLetter struct {
+		MessageID int64  `db:"dead_letter_message_id"`
+		Error     string `db:"dead_letter_error"`
+	}
+	err = db.Get(&deadLetter, `SELECT dead_letter_message_id, dead_letter_error FROM stream_dead_letters`)
+	require.NoError(t, err)
+	require.Equal(t, msgID, deadLetter.MessageID)
+	require.Equal(t, "failed", deadLetter.Error)
+}
+
+func TestDatabaseConsumer_Start(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	db := setupDatabase(t)
+	p := NewDatabaseProducer(db, testNamespace, 0, false)
+
+	c, err := NewDatabaseConsumer(db, testNamespace, testGroup, "consumer")
+	require.NoError(t, err)
+
+	handled := make(chan string, 10)
+	err = c.Register(testStream, func(_ context.Context, _ string, values map[string]interface{}) error {
+		handled <- values["value"].(string)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.Start(ctx))
+
+	sendTestMessage(t, p, "1")
+
+	select {
+	case v := <-handled:
+		require.Equal(t, "1", v)
+	case <-ctx.Done():
+		t.Fatal("message wasn't handled")
+	}
+
+	// handled messages are acknowledged.
+	require.Eventually(t, func() bool {
+		return countTestRows(t, db, "stream_pending") == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
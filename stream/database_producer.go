@@ -0,0 +1,135 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DatabaseProducer writes messages to streams stored in tables of the database (sqlite or PostgreSQL).
+type DatabaseProducer struct {
+	db *sqlx.DB
+	// namespace defines the namespace of the stream keys - any stream key will be prefixed with it.
+	namespace string
+	// maxStreamLength defines the maximum number of entries in each stream (ring buffer).
+	maxStreamLength int64
+	// approxMaxStreamLength specifies whether the maxStreamLength should be approximated.
+	// NOTE: enabling approximation of stream length trims the stream less frequently.
+	approxMaxStreamLength bool
+}
+
+func NewDatabaseProducer(db *sqlx.DB, namespace string,
+	maxStreamLength int64, approxMaxStreamLength bool) *DatabaseProducer {
+	return &DatabaseProducer{
+		db:                    db,
+		namespace:             namespace,
+		maxStreamLength:       maxStreamLength,
+		approxMaxStreamLength: approxMaxStreamLength,
+	}
+}
+
+func (p *DatabaseProducer) Send(ctx context.Context, streamID string, payload map[string]interface{}) (string, error) {
+	// ensure we transpose streamID using the key namespace
+	transposedStreamID := transposeStreamID(p.namespace, streamID)
+
+	values, err := marshalDatabaseValues(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal payload for stream '%s': %w", streamID, err)
+	}
+
+	const sqlQuery = `
+		INSERT INTO stream_messages (message_stream, message_values, message_created)
+		VALUES ($1, $2, $3)
+		RETURNING message_id`
+
+	var msgID int64
+	err = p.db.QueryRowContext(ctx, sqlQuery, transposedStreamID, values, time.Now().UnixMilli()).Scan(&msgID)
+	if err != nil {
+		return "", fmt.Errorf("failed to write to stream '%s' (database stream '%s'). Error: %w",
+			streamID, transposedStreamID, err)
+	}
+
+	// trim the stream to its max length - with approximation only every once in a while.
+	const approxTrimInterval = 100
+	if p.maxStreamLength > 0 && (!p.approxMaxStreamLength || msgID%approxTrimInterval == 0) {
+		if err = p.trim(ctx, transposedStreamID); err != nil {
+			return "", fmt.Errorf("failed to trim stream '%s' (database stream '%s'). Error: %w",
+				streamID, transposedStreamID, err)
+		}
+	}
+
+	return strconv.FormatInt(msgID, 10), nil
+}
+
+// trim removes the oldest messages of the stream that exceed the max stream length.
+func (p *DatabaseProducer) trim(ctx context.Context, streamID string) error {
+	const sqlQuery = `
+		DELETE FROM stream_messages
+		WHERE message_stream = $1 AND message_id < (
+			SELECT message_id
+			FROM stream_messages
+			WHERE message_stream = $1
+			ORDER BY message_id DESC
+			LIMIT 1 OFFSET $2
+		)`
+
+	_, err := p.db.ExecContext(ctx, sqlQuery, streamID, p.maxStreamLength-1)
+	return err
+}
+
+// marshalDatabaseValues converts the payload to json. Like in redis, all values are stored as raw bytes
+// (encoded as base64 in json to support binary data).
+func marshalDatabaseValues(payload map[string]interface{}) (string, error) {
+	values := make(map[string][]byte, len(payload))
+	for k, v := range payload {
+		switch v := v.(type) {
+		case string:
+			values[k] = []byte(v)
+		case []byte:
+			values[k] = v
+		default:
+			values[k] = []byte(fmt.Sprint(v))
+		}
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// unmarshalDatabaseValues converts the stored json back to a payload.
+// Like in redis, all values are returned as strings.
+func unmarshalDatabaseValues(data string) (map[string]interface{}, error) {
+	values := map[string][]byte{}
+	if err := json.Unmarshal([]byte(data), &values); err != nil {
+		return nil, err
+	}
+
+	payload := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		payload[k] = string(v)
+	}
+
+	return payload, nil
+}
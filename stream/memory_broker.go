@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	gonanoid "github.com/matoous/go-nanoid"
@@ -67,6 +68,10 @@ func (b *MemoryBroker) enqueue(streamID string, m message) (string, error) {
 		m.id = fmt.Sprintf("%s-%d", b.idPrefix, id)
 	}
 
+	if m.timestamp.IsZero() {
+		m.timestamp = time.Now()
+	}
+
 	// the lock is for reading from the messageQueues map
 	// NOTE: this method isn't blocking anywhere so we should be safe from deadlocking
 	// NOTE: might be possible to optimize (potentially not even required), but okay for initial local solution.
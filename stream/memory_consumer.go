@@ -187,6 +187,10 @@ func (c *MemoryConsumer) consume(ctx context.Context) {
 				continue
 			}
 
+			if m.retries == 0 {
+				observeLag(m.streamID, c.groupName, m.timestamp)
+			}
+
 			err := func() (err error) {
 				// Ensure that handlers don't cause panic.
 				defer func() {
@@ -200,10 +204,12 @@ func (c *MemoryConsumer) consume(ctx context.Context) {
 			}()
 
 			if err != nil {
+				observeMessage(m.streamID, c.groupName, metricsResultFailure)
 				c.pushError(fmt.Errorf("failed to process message with id '%s' in stream '%s' (retries: %d): %w",
 					m.id, m.streamID, m.retries, err))
 
 				if m.retries >= int64(handler.config.maxRetries) {
+					observeMessage(m.streamID, c.groupName, metricsResultDiscarded)
 					c.pushError(fmt.Errorf(
 						"discard message with id '%s' from stream '%s' - failed %d retries",
 						m.id, m.streamID, m.retries))
@@ -220,7 +226,11 @@ func (c *MemoryConsumer) consume(ctx context.Context) {
 					time.Sleep(handler.config.idleTimeout)
 					c.messageQueue <- m
 				}()
+
+				continue
 			}
+
+			observeMessage(m.streamID, c.groupName, metricsResultSuccess)
 		}
 	}
 }
@@ -0,0 +1,72 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	metricsResultSuccess   = "success"
+	metricsResultFailure   = "failure"
+	metricsResultDiscarded = "discarded"
+)
+
+var (
+	messagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "gitness",
+		Subsystem: "stream",
+		Name:      "messages_total",
+		Help:      "Total number of stream messages processed by consumers.",
+	}, []string{"stream", "group", "result"})
+
+	consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "gitness",
+		Subsystem: "stream",
+		Name:      "consumer_lag_seconds",
+		Help:      "Age of the most recent stream message at the time it was picked up by a consumer.",
+	}, []string{"stream", "group"})
+)
+
+// observeMessage records the result of processing a stream message.
+func observeMessage(streamID string, groupName string, result string) {
+	messagesTotal.WithLabelValues(streamID, groupName, result).Inc()
+}
+
+// observeLag records the time a message spent in the stream before it got picked up.
+func observeLag(streamID string, groupName string, timestamp time.Time) {
+	if timestamp.IsZero() {
+		return
+	}
+
+	consumerLag.WithLabelValues(streamID, groupName).Set(time.Since(timestamp).Seconds())
+}
+
+// redisMessageTime returns the creation time of a redis stream message.
+// Redis stream message IDs have the format '<millisecondsTime>-<sequenceNumber>'.
+func redisMessageTime(id string) time.Time {
+	ms, _, _ := strings.Cut(id, "-")
+	msec, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return time.UnixMilli(msec)
+}
@@ -314,9 +314,10 @@ func (c *RedisConsumer) reader(ctx context.Context) {
 			for _, stream := range resReadStream {
 				for _, m := range stream.Messages {
 					c.messageQueue <- message{
-						streamID: stream.Stream,
-						id:       m.ID,
-						values:   m.Values,
+						streamID:  stream.Stream,
+						id:        m.ID,
+						values:    m.Values,
+						timestamp: redisMessageTime(m.ID),
 					}
 				}
 			}
@@ -385,6 +386,7 @@ func (c *RedisConsumer) reclaimer(ctx context.Context, reclaimInterval time.Dura
 								"failed to force acknowledge (discard) message '%s' (Retries: %d) in stream '%s': %w",
 								resMessage.ID, resMessage.RetryCount, streamID, errAck))
 						} else {
+							observeMessage(streamID, c.groupName, metricsResultDiscarded)
 							retryCount := resMessage.RetryCount - 1 // redis is counting this execution as retry
 							c.pushError(fmt.Errorf(
 								"force acknowledged (discarded) message '%s' (Retries: %d) in stream '%s'",
@@ -438,9 +440,10 @@ func (c *RedisConsumer) reclaimer(ctx context.Context, reclaimInterval time.Dura
 					// we claimed only one message id so there is only one message in the slice
 					claimedMessage := claimedMessages[0]
 					c.messageQueue <- message{
-						streamID: streamID,
-						id:       claimedMessage.ID,
-						values:   claimedMessage.Values,
+						streamID:  streamID,
+						id:        claimedMessage.ID,
+						values:    claimedMessage.Values,
+						timestamp: redisMessageTime(claimedMessage.ID),
 					}
 				}
 
@@ -483,6 +486,8 @@ func (c *RedisConsumer) consumer(ctx context.Context) {
 				continue
 			}
 
+			observeLag(m.streamID, c.groupName, m.timestamp)
+
 			err := func() (err error) {
 				// Ensure that handlers don't cause panic.
 				defer func() {
@@ -495,10 +500,13 @@ func (c *RedisConsumer) consumer(ctx context.Context) {
 				return handler.handle(ctx, m.id, m.values)
 			}()
 			if err != nil {
+				observeMessage(m.streamID, c.groupName, metricsResultFailure)
 				c.pushError(fmt.Errorf("failed to process message '%s' in stream '%s': %w", m.id, m.streamID, err))
 				continue
 			}
 
+			observeMessage(m.streamID, c.groupName, metricsResultSuccess)
+
 			err = c.rdb.XAck(ctx, m.streamID, c.groupName, m.id).Err()
 			if err != nil {
 				c.pushError(fmt.Errorf("failed to acknowledge message '%s' in stream '%s': %w", m.id, m.streamID, err))
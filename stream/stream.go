@@ -68,6 +68,9 @@ type message struct {
 	streamID string
 	id       string
 	values   map[string]interface{}
+
+	// timestamp is the time the message was added to the stream (if known).
+	timestamp time.Time
 }
 
 // transposeStreamID transposes the provided streamID based on the namespace.
@@ -20,3 +20,12 @@ type CommitFilesResponse struct {
 	CommitID       string           `json:"commit_id"`
 	RuleViolations []RuleViolations `json:"rule_violations,omitempty"`
 }
+
+// ApplyCommitResponse is the result of cherry-picking or reverting a commit.
+type ApplyCommitResponse struct {
+	DryRunRules    bool             `json:"dry_run_rules,omitempty"`
+	CommitID       string           `json:"commit_id,omitempty"`
+	Branch         string           `json:"branch,omitempty"`
+	PullReq        *PullReq         `json:"pull_request,omitempty"`
+	RuleViolations []RuleViolations `json:"rule_violations,omitempty"`
+}
@@ -40,6 +40,10 @@ type Config struct {
 	UserSignupEnabled   bool `envconfig:"GITNESS_USER_SIGNUP_ENABLED" default:"true"`
 	NestedSpacesEnabled bool `envconfig:"GITNESS_NESTED_SPACES_ENABLED" default:"false"`
 
+	// PasswordLoginEnabled specifies whether users can login (and sign up) using a password.
+	// NOTE: Disable only if single sign-on is configured.
+	PasswordLoginEnabled bool `envconfig:"GITNESS_PASSWORD_LOGIN_ENABLED" default:"true"`
+
 	// PublicResourceCreationEnabled specifies whether a user can create publicly accessible resources.
 	PublicResourceCreationEnabled bool `envconfig:"GITNESS_PUBLIC_RESOURCE_CREATION_ENABLED" default:"true"`
 
@@ -107,12 +111,27 @@ type Config struct {
 			// Duration defines cache duration of last commit.
 			Duration time.Duration `envconfig:"GITNESS_GIT_LAST_COMMIT_CACHE_DURATION" default:"12h"`
 		}
+
+		// SigningKey holds configuration options for signing commits created by the server (merges, web edits).
+		SigningKey struct {
+			// Path points to a file containing an armored GPG private key or an OpenSSH private key.
+			Path string `envconfig:"GITNESS_GIT_SIGNING_KEY_PATH"`
+
+			// Passphrase (optional) is used to decrypt the private key.
+			Passphrase string `envconfig:"GITNESS_GIT_SIGNING_KEY_PASSPHRASE"`
+		}
 	}
 
 	// Encrypter defines the parameters for the encrypter
 	Encrypter struct {
 		Secret       string `envconfig:"GITNESS_ENCRYPTER_SECRET"` // key used for encryption
 		MixedContent bool   `envconfig:"GITNESS_ENCRYPTER_MIXED_CONTENT"`
+		// Keys is a comma separated list of encryption keys in the format "<keyID>:<key>".
+		// If provided, data is encrypted with the primary key and decrypted with any of the keys
+		// (or with Secret in case the data was encrypted before the keys were configured).
+		Keys []string `envconfig:"GITNESS_ENCRYPTER_KEYS"`
+		// PrimaryKeyID is the ID of the key used for encryption (optional in case of a single key).
+		PrimaryKeyID string `envconfig:"GITNESS_ENCRYPTER_PRIMARY_KEY_ID"`
 	}
 
 	// Server defines the server configuration parameters.
@@ -148,6 +167,31 @@ type Config struct {
 		// In that case, GITNESS_URL_CONTAINER should also be changed
 		// (eg to http://<gitness_container_name>:<port>).
 		ContainerNetworks []string `envconfig:"GITNESS_CI_CONTAINER_NETWORKS"`
+
+		// Environ contains environment variables (e.g. proxies or internal mirrors) injected into all pipelines,
+		// in the format "key1:value1,key2:value2". Environment variables of spaces take precedence.
+		Environ map[string]string `envconfig:"GITNESS_CI_ENVIRON"`
+	}
+
+	// SecretProviders defines the external secret providers pipeline secrets can reference
+	// using "<provider>://<path>#<key>" (e.g. "vault://ci/docker#password").
+	SecretProviders struct {
+		// SpaceScoped makes the paths of secret references relative to the path of the space of the secret,
+		// preventing spaces from reading secrets of their parent and sibling spaces.
+		SpaceScoped bool `envconfig:"GITNESS_SECRET_PROVIDERS_SPACE_SCOPED" default:"true"`
+
+		// Vault defines the HashiCorp Vault KV v2 secret provider ("vault://").
+		Vault struct {
+			Address   string `envconfig:"GITNESS_SECRET_PROVIDERS_VAULT_ADDRESS"`
+			Token     string `envconfig:"GITNESS_SECRET_PROVIDERS_VAULT_TOKEN"`
+			Namespace string `envconfig:"GITNESS_SECRET_PROVIDERS_VAULT_NAMESPACE"`
+			Mount     string `envconfig:"GITNESS_SECRET_PROVIDERS_VAULT_MOUNT" default:"secret"`
+		}
+
+		// File defines the file based secret provider ("file://"), mainly intended for testing.
+		File struct {
+			Path string `envconfig:"GITNESS_SECRET_PROVIDERS_FILE_PATH"`
+		}
 	}
 
 	// Database defines the database configuration parameters.
@@ -158,10 +202,10 @@ type Config struct {
 
 	// BlobStore defines the blob storage configuration parameters.
 	BlobStore struct {
-		// Provider is a name of blob storage service like filesystem or gcs
+		// Provider is a name of blob storage service like filesystem, gcs or s3
 		Provider blob.Provider `envconfig:"GITNESS_BLOBSTORE_PROVIDER" default:"filesystem"`
 		// Bucket is a path to the directory where the files will be stored when using filesystem blob storage,
-		// in case of gcs provider this will be the actual bucket where the images are stored.
+		// in case of gcs or s3 provider this will be the actual bucket where the images are stored.
 		Bucket string `envconfig:"GITNESS_BLOBSTORE_BUCKET"`
 
 		// In case of GCS provider, this is expected to be the path to the service account key file.
@@ -171,6 +215,16 @@ type Config struct {
 		TargetPrincipal string `envconfig:"GITNESS_BLOBSTORE_TARGET_PRINCIPAL" default:""`
 
 		ImpersonationLifetime time.Duration `envconfig:"GITNESS_BLOBSTORE_IMPERSONATION_LIFETIME" default:"12h"`
+
+		// Endpoint overrides the S3 endpoint, required for S3 compatible services like MinIO.
+		Endpoint string `envconfig:"GITNESS_BLOBSTORE_ENDPOINT"`
+		// PathStyle forces path style addressing of S3 buckets (required for MinIO).
+		PathStyle bool   `envconfig:"GITNESS_BLOBSTORE_PATH_STYLE"`
+		Region    string `envconfig:"GITNESS_BLOBSTORE_REGION"`
+		// AccessKeyID and SecretAccessKey are optional S3 credentials,
+		// if not provided the default AWS credential chain is used.
+		AccessKeyID     string `envconfig:"GITNESS_BLOBSTORE_ACCESS_KEY_ID"`
+		SecretAccessKey string `envconfig:"GITNESS_BLOBSTORE_SECRET_ACCESS_KEY"`
 	}
 
 	// Token defines token configuration parameters.
@@ -179,6 +233,66 @@ type Config struct {
 		Expire     time.Duration `envconfig:"GITNESS_TOKEN_EXPIRE" default:"720h"`
 	}
 
+	// OIDC defines the configuration of the OpenID Connect single sign-on (disabled if no issuer is provided).
+	OIDC struct {
+		Issuer       string   `envconfig:"GITNESS_OIDC_ISSUER"`
+		ClientID     string   `envconfig:"GITNESS_OIDC_CLIENT_ID"`
+		ClientSecret string   `envconfig:"GITNESS_OIDC_CLIENT_SECRET"`
+		Scopes       []string `envconfig:"GITNESS_OIDC_SCOPES"        default:"openid,email,profile"`
+
+		// AllowedDomains restricts the login to users with an email of one of the domains (all if empty).
+		AllowedDomains []string `envconfig:"GITNESS_OIDC_ALLOWED_DOMAINS"`
+
+		// RequireVerifiedEmail specifies whether the email of the user has to be verified by the provider.
+		// NOTE: Unknown identities are never linked to existing users with an unverified email.
+		RequireVerifiedEmail bool `envconfig:"GITNESS_OIDC_REQUIRE_VERIFIED_EMAIL" default:"true"`
+
+		// AutoCreateUsers specifies whether unknown users are created on their first login.
+		AutoCreateUsers bool `envconfig:"GITNESS_OIDC_AUTO_CREATE_USERS" default:"true"`
+
+		// GroupsClaim is the name of the claim containing the groups of the user.
+		GroupsClaim string `envconfig:"GITNESS_OIDC_GROUPS_CLAIM" default:"groups"`
+
+		// GroupMemberships maps groups to space memberships that are added on login.
+		// Each entry has the format "group:spaceRef:role", e.g. "developers:myspace:contributor".
+		GroupMemberships []string `envconfig:"GITNESS_OIDC_GROUP_MEMBERSHIPS"`
+	}
+
+	// LDAP defines the configuration of the LDAP authentication (disabled if no URL is provided).
+	LDAP struct {
+		URL                string `envconfig:"GITNESS_LDAP_URL"`
+		StartTLS           bool   `envconfig:"GITNESS_LDAP_START_TLS"`
+		InsecureSkipVerify bool   `envconfig:"GITNESS_LDAP_INSECURE_SKIP_VERIFY"`
+		BindDN             string `envconfig:"GITNESS_LDAP_BIND_DN"`
+		BindPassword       string `envconfig:"GITNESS_LDAP_BIND_PASSWORD"`
+
+		UserBaseDN           string `envconfig:"GITNESS_LDAP_USER_BASE_DN"`
+		UserFilter           string `envconfig:"GITNESS_LDAP_USER_FILTER"            default:"(objectClass=person)"`
+		UIDAttribute         string `envconfig:"GITNESS_LDAP_UID_ATTRIBUTE"          default:"uid"`
+		EmailAttribute       string `envconfig:"GITNESS_LDAP_EMAIL_ATTRIBUTE"        default:"mail"`
+		DisplayNameAttribute string `envconfig:"GITNESS_LDAP_DISPLAY_NAME_ATTRIBUTE" default:"cn"`
+
+		GroupBaseDN          string `envconfig:"GITNESS_LDAP_GROUP_BASE_DN"`
+		GroupFilter          string `envconfig:"GITNESS_LDAP_GROUP_FILTER"           default:"(objectClass=groupOfNames)"`
+		GroupMemberAttribute string `envconfig:"GITNESS_LDAP_GROUP_MEMBER_ATTRIBUTE" default:"member"`
+		GroupNameAttribute   string `envconfig:"GITNESS_LDAP_GROUP_NAME_ATTRIBUTE"   default:"cn"`
+
+		// AutoCreateUsers specifies whether users of the directory are created on their first login.
+		AutoCreateUsers bool `envconfig:"GITNESS_LDAP_AUTO_CREATE_USERS" default:"true"`
+
+		// GroupMemberships maps groups to space memberships that are kept in sync with the directory.
+		// Each entry has the format "group:spaceRef:role", e.g. "developers:myspace:contributor".
+		GroupMemberships []string `envconfig:"GITNESS_LDAP_GROUP_MEMBERSHIPS"`
+
+		// Sync defines the periodic job that syncs the group memberships and deactivates removed users.
+		Sync struct {
+			Enabled         bool          `envconfig:"GITNESS_LDAP_SYNC_ENABLED"          default:"true"`
+			CRON            string        `envconfig:"GITNESS_LDAP_SYNC_CRON"             default:"*/30 * * * *"`
+			MaxDuration     time.Duration `envconfig:"GITNESS_LDAP_SYNC_MAX_DURATION"     default:"10m"`
+			DeactivateUsers bool          `envconfig:"GITNESS_LDAP_SYNC_DEACTIVATE_USERS" default:"true"`
+		}
+	}
+
 	Logs struct {
 		// S3 provides optional storage option for logs.
 		S3 struct {
@@ -252,6 +366,7 @@ type Config struct {
 	}
 
 	Events struct {
+		// Mode is the event stream provider like inmemory, redis, postgres or database (uses the primary database).
 		Mode                  events.Mode `envconfig:"GITNESS_EVENTS_MODE"                     default:"inmemory"`
 		Namespace             string      `envconfig:"GITNESS_EVENTS_NAMESPACE"                default:"gitness"`
 		MaxStreamLength       int64       `envconfig:"GITNESS_EVENTS_MAX_STREAM_LENGTH"        default:"10000"`
@@ -259,7 +374,7 @@ type Config struct {
 	}
 
 	Lock struct {
-		// Provider is a name of distributed lock service like redis, memory, file etc...
+		// Provider is a name of distributed lock service like inmemory, redis or postgres
 		Provider      lock.Provider `envconfig:"GITNESS_LOCK_PROVIDER"          default:"inmemory"`
 		Expiry        time.Duration `envconfig:"GITNESS_LOCK_EXPIRE"            default:"8s"`
 		Tries         int           `envconfig:"GITNESS_LOCK_TRIES"             default:"8"`
@@ -273,7 +388,7 @@ type Config struct {
 	}
 
 	PubSub struct {
-		// Provider is a name of distributed lock service like redis, memory, file etc...
+		// Provider is a name of pubsub service like inmemory, redis or postgres
 		Provider pubsub.Provider `envconfig:"GITNESS_PUBSUB_PROVIDER"                default:"inmemory"`
 		// AppNamespace is just service app prefix to avoid conflicts on channel definition
 		AppNamespace string `envconfig:"GITNESS_PUBSUB_APP_NAMESPACE"                default:"gitness"`
@@ -319,6 +434,12 @@ type Config struct {
 		Token    string `envconfig:"GITNESS_METRIC_TOKEN"`
 	}
 
+	Prometheus struct {
+		Enabled bool `envconfig:"GITNESS_PROMETHEUS_ENABLED" default:"false"`
+		// Token is an optional bearer token that is required for accessing the metrics endpoint.
+		Token string `envconfig:"GITNESS_PROMETHEUS_TOKEN"`
+	}
+
 	RepoSize struct {
 		Enabled     bool          `envconfig:"GITNESS_REPO_SIZE_ENABLED" default:"true"`
 		CRON        string        `envconfig:"GITNESS_REPO_SIZE_CRON" default:"0 0 * * *"`
@@ -348,4 +469,25 @@ type Config struct {
 		Concurrency int `envconfig:"GITNESS_KEYWORD_SEARCH_CONCURRENCY" default:"4"`
 		MaxRetries  int `envconfig:"GITNESS_KEYWORD_SEARCH_MAX_RETRIES" default:"3"`
 	}
+
+	LanguageStats struct {
+		Concurrency int `envconfig:"GITNESS_LANGUAGE_STATS_CONCURRENCY" default:"4"`
+		MaxRetries  int `envconfig:"GITNESS_LANGUAGE_STATS_MAX_RETRIES" default:"3"`
+	}
+
+	Housekeeping struct {
+		Enabled     bool          `envconfig:"GITNESS_HOUSEKEEPING_ENABLED" default:"true"`
+		CRON        string        `envconfig:"GITNESS_HOUSEKEEPING_CRON" default:"30 */6 * * *"`
+		MaxDuration time.Duration `envconfig:"GITNESS_HOUSEKEEPING_MAX_DURATION" default:"1h"`
+		NumWorkers  int           `envconfig:"GITNESS_HOUSEKEEPING_NUM_WORKERS" default:"2"`
+		// RepoLimit is the maximum number of repositories that are maintained by a single run.
+		RepoLimit int `envconfig:"GITNESS_HOUSEKEEPING_REPO_LIMIT" default:"100"`
+		// MinPushes is the minimum number of pushes since the last maintenance for a repository to be maintained.
+		MinPushes   int64         `envconfig:"GITNESS_HOUSEKEEPING_MIN_PUSHES" default:"5"`
+		RepoTimeout time.Duration `envconfig:"GITNESS_HOUSEKEEPING_REPO_TIMEOUT" default:"15m"`
+		// PruneExpiry is the minimum age of unreachable objects before they are pruned (at least 1h).
+		PruneExpiry time.Duration `envconfig:"GITNESS_HOUSEKEEPING_PRUNE_EXPIRY" default:"24h"`
+		Concurrency int           `envconfig:"GITNESS_HOUSEKEEPING_CONCURRENCY" default:"4"`
+		MaxRetries  int           `envconfig:"GITNESS_HOUSEKEEPING_MAX_RETRIES" default:"3"`
+	}
 }
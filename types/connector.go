@@ -18,6 +18,7 @@ type Connector struct {
 	ID          int64  `db:"connector_id"              json:"id"`
 	Description string `db:"connector_description"     json:"description"`
 	SpaceID     int64  `db:"connector_space_id"        json:"space_id"`
+	RepoID      *int64 `db:"connector_repo_id"         json:"repo_id,omitempty"`
 	UID         string `db:"connector_uid"             json:"uid"`
 	Type        string `db:"connector_type"            json:"type"`
 	Data        string `db:"connector_data"            json:"data"`
@@ -25,3 +26,17 @@ type Connector struct {
 	Updated     int64  `db:"connector_updated"         json:"updated"`
 	Version     int64  `db:"connector_version"         json:"-"`
 }
+
+// ConnectorTypeDockerRegistry is the type of connectors providing credentials of a docker registry.
+// Pipelines in the space of the connector and its sub spaces (or only pipelines of the repository of the connector)
+// use the credentials to pull images from the registry.
+const ConnectorTypeDockerRegistry = "docker_registry"
+
+// DockerRegistryConnectorData is the data of a docker registry connector.
+type DockerRegistryConnectorData struct {
+	// URL is the address of the registry, e.g. "docker.io" or "https://registry.example.com".
+	URL      string `json:"url"`
+	Username string `json:"username"`
+	// PasswordSecret is the UID of the secret (in the same space) containing the password.
+	PasswordSecret string `json:"password_secret"`
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enum
+
+// LabelColor represents the color of a label.
+type LabelColor string
+
+// LabelColor enumeration.
+const (
+	LabelColorRed    LabelColor = "red"
+	LabelColorOrange LabelColor = "orange"
+	LabelColorYellow LabelColor = "yellow"
+	LabelColorLime   LabelColor = "lime"
+	LabelColorGreen  LabelColor = "green"
+	LabelColorMint   LabelColor = "mint"
+	LabelColorCyan   LabelColor = "cyan"
+	LabelColorBlue   LabelColor = "blue"
+	LabelColorIndigo LabelColor = "indigo"
+	LabelColorPurple LabelColor = "purple"
+	LabelColorPink   LabelColor = "pink"
+	LabelColorBrown  LabelColor = "brown"
+	LabelColorGray   LabelColor = "gray"
+)
+
+var labelColors = sortEnum([]LabelColor{
+	LabelColorRed,
+	LabelColorOrange,
+	LabelColorYellow,
+	LabelColorLime,
+	LabelColorGreen,
+	LabelColorMint,
+	LabelColorCyan,
+	LabelColorBlue,
+	LabelColorIndigo,
+	LabelColorPurple,
+	LabelColorPink,
+	LabelColorBrown,
+	LabelColorGray,
+})
+
+func (LabelColor) Enum() []interface{} { return toInterfaceSlice(labelColors) }
+func (c LabelColor) Sanitize() (LabelColor, bool) {
+	return Sanitize(c, GetAllLabelColors)
+}
+func GetAllLabelColors() ([]LabelColor, LabelColor) {
+	return labelColors, LabelColorGray
+}
+
+// PullReqLabelActivityType defines the type of the pull request label activity.
+type PullReqLabelActivityType string
+
+// PullReqLabelActivityType enumeration.
+const (
+	PullReqLabelActivityTypeAssign   PullReqLabelActivityType = "assign"
+	PullReqLabelActivityTypeUnassign PullReqLabelActivityType = "unassign"
+)
+
+var pullReqLabelActivityTypes = sortEnum([]PullReqLabelActivityType{
+	PullReqLabelActivityTypeAssign,
+	PullReqLabelActivityTypeUnassign,
+})
+
+func (PullReqLabelActivityType) Enum() []interface{} {
+	return toInterfaceSlice(pullReqLabelActivityTypes)
+}
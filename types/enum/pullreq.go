@@ -86,6 +86,7 @@ const (
 	PullReqActivityTypeBranchUpdate PullReqActivityType = "branch-update"
 	PullReqActivityTypeBranchDelete PullReqActivityType = "branch-delete"
 	PullReqActivityTypeMerge        PullReqActivityType = "merge"
+	PullReqActivityTypeLabelModify  PullReqActivityType = "label-modify"
 )
 
 var pullReqActivityTypes = sortEnum([]PullReqActivityType{
@@ -97,6 +98,7 @@ var pullReqActivityTypes = sortEnum([]PullReqActivityType{
 	PullReqActivityTypeBranchUpdate,
 	PullReqActivityTypeBranchDelete,
 	PullReqActivityTypeMerge,
+	PullReqActivityTypeLabelModify,
 })
 
 // PullReqActivityKind defines kind of pull request activity system message.
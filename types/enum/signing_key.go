@@ -0,0 +1,38 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enum
+
+// SigningKeyType defines the type of a key used for signing commits and tags.
+type SigningKeyType string
+
+// SigningKeyType enumeration.
+const (
+	SigningKeyTypeGPG SigningKeyType = "gpg"
+	SigningKeyTypeSSH SigningKeyType = "ssh"
+)
+
+var signingKeyTypes = sortEnum([]SigningKeyType{
+	SigningKeyTypeGPG,
+	SigningKeyTypeSSH,
+})
+
+func (SigningKeyType) Enum() []interface{} { return toInterfaceSlice(signingKeyTypes) }
+
+func (t SigningKeyType) Sanitize() (SigningKeyType, bool) {
+	return Sanitize(t, GetAllSigningKeyTypes)
+}
+func GetAllSigningKeyTypes() ([]SigningKeyType, SigningKeyType) {
+	return signingKeyTypes, "" // empty type is not valid
+}
@@ -144,6 +144,10 @@ const (
 	WebhookTriggerPullReqCommentCreated WebhookTrigger = "pullreq_comment_created"
 	// WebhookTriggerPullReqMerged gets triggered when a pull request is merged.
 	WebhookTriggerPullReqMerged WebhookTrigger = "pullreq_merged"
+	// WebhookTriggerPullReqLabelAdded gets triggered when a label is assigned to a pull request.
+	WebhookTriggerPullReqLabelAdded WebhookTrigger = "pullreq_label_added"
+	// WebhookTriggerPullReqLabelRemoved gets triggered when a label is removed from a pull request.
+	WebhookTriggerPullReqLabelRemoved WebhookTrigger = "pullreq_label_removed"
 )
 
 var webhookTriggers = sortEnum([]WebhookTrigger{
@@ -159,4 +163,6 @@ var webhookTriggers = sortEnum([]WebhookTrigger{
 	WebhookTriggerPullReqClosed,
 	WebhookTriggerPullReqCommentCreated,
 	WebhookTriggerPullReqMerged,
+	WebhookTriggerPullReqLabelAdded,
+	WebhookTriggerPullReqLabelRemoved,
 })
@@ -17,6 +17,7 @@ package types
 import (
 	"time"
 
+	gitenum "github.com/harness/gitness/git/enum"
 	"github.com/harness/gitness/types/enum"
 )
 
@@ -36,6 +37,23 @@ type CommitFilter struct {
 	Since     int64  `json:"since"`
 	Until     int64  `json:"until"`
 	Committer string `json:"committer"`
+	Follow    bool   `json:"follow"`
+}
+
+// CommitStatsFilter stores commit statistics query parameters.
+type CommitStatsFilter struct {
+	PaginationFilter
+	Since     int64  `json:"since"`
+	Until     int64  `json:"until"`
+	Committer string `json:"committer"`
+}
+
+// DiffOptions stores diff query parameters.
+type DiffOptions struct {
+	IgnoreWhitespace gitenum.DiffIgnoreWhitespace `json:"ignore_whitespace"`
+	ContextLines     *int                         `json:"context_lines"`
+	RenameThreshold  int                          `json:"rename_threshold"`
+	DetectCopies     bool                         `json:"detect_copies"`
 }
 
 // BranchFilter stores branch query parameters.
@@ -57,11 +75,14 @@ type TagFilter struct {
 }
 
 type Commit struct {
-	SHA       string    `json:"sha"`
-	Title     string    `json:"title"`
-	Message   string    `json:"message"`
-	Author    Signature `json:"author"`
-	Committer Signature `json:"committer"`
+	SHA          string              `json:"sha"`
+	Title        string              `json:"title"`
+	Message      string              `json:"message"`
+	Author       Signature           `json:"author"`
+	Committer    Signature           `json:"committer"`
+	Verification *CommitVerification `json:"verification,omitempty"`
+	// Path is the path the file had at the commit, it's only set when following the history of a file.
+	Path string `json:"path,omitempty"`
 }
 
 type Signature struct {
@@ -74,6 +95,24 @@ type Identity struct {
 	Email string `json:"email"`
 }
 
+// ContributorStats holds the commit statistics of a single commit author.
+type ContributorStats struct {
+	Author    Identity            `json:"author"`
+	Commits   int64               `json:"commits"`
+	Additions int64               `json:"additions"`
+	Deletions int64               `json:"deletions"`
+	Weeks     []WeeklyCommitStats `json:"weeks"`
+}
+
+// WeeklyCommitStats holds the commit statistics of a single week.
+type WeeklyCommitStats struct {
+	// Week is the start of the week (Sunday 00:00 UTC).
+	Week      time.Time `json:"week"`
+	Commits   int64     `json:"commits"`
+	Additions int64     `json:"additions"`
+	Deletions int64     `json:"deletions"`
+}
+
 type RenameDetails struct {
 	OldPath         string `json:"old_path"`
 	NewPath         string `json:"new_path"`
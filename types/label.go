@@ -0,0 +1,69 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"github.com/harness/gitness/types/enum"
+)
+
+// Label represents a label that can be assigned to pull requests.
+// A label is defined either in a space (and is available to all its repositories) or in a repository.
+type Label struct {
+	ID          int64           `json:"id"`
+	SpaceID     *int64          `json:"space_id,omitempty"`
+	RepoID      *int64          `json:"repo_id,omitempty"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Color       enum.LabelColor `json:"color"`
+	Created     int64           `json:"created"`
+	Updated     int64           `json:"updated"`
+	CreatedBy   int64           `json:"created_by"`
+	UpdatedBy   int64           `json:"updated_by"`
+}
+
+// LabelInfo contains the basic information about a label, used when a label is embedded in other objects.
+type LabelInfo struct {
+	ID      int64           `json:"id"`
+	SpaceID *int64          `json:"space_id,omitempty"`
+	RepoID  *int64          `json:"repo_id,omitempty"`
+	Name    string          `json:"name"`
+	Color   enum.LabelColor `json:"color"`
+}
+
+// ToInfo returns the label info of the label.
+func (l *Label) ToInfo() *LabelInfo {
+	return &LabelInfo{
+		ID:      l.ID,
+		SpaceID: l.SpaceID,
+		RepoID:  l.RepoID,
+		Name:    l.Name,
+		Color:   l.Color,
+	}
+}
+
+// LabelFilter stores label query parameters.
+type LabelFilter struct {
+	ListQueryFilter
+	// Inherited includes labels defined in the parent spaces.
+	Inherited bool `json:"inherited"`
+}
+
+// PullReqLabel represents an assignment of a label to a pull request.
+type PullReqLabel struct {
+	PullReqID int64 `json:"pullreq_id"`
+	LabelID   int64 `json:"label_id"`
+	Created   int64 `json:"created"`
+	CreatedBy int64 `json:"created_by"`
+}
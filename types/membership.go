@@ -33,6 +33,10 @@ type Membership struct {
 	Updated   int64 `json:"updated"`
 
 	Role enum.MembershipRole `json:"role"`
+
+	// Source is the identity provider whose group mapping added the membership,
+	// it's empty for memberships that were added manually.
+	Source string `json:"-"`
 }
 
 // MembershipUser adds user info to the Membership data.
@@ -0,0 +1,26 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// PrincipalIdentity links a principal to its identity at an external identity provider.
+type PrincipalIdentity struct {
+	// Provider identifies the identity provider, e.g. "oidc:https://accounts.example.com".
+	Provider string `db:"principal_identity_provider"     json:"provider"`
+	// Subject is the unique identifier of the principal at the identity provider.
+	Subject     string `db:"principal_identity_subject"      json:"subject"`
+	PrincipalID int64  `db:"principal_identity_principal_id" json:"principal_id"`
+	Created     int64  `db:"principal_identity_created"      json:"created"`
+	LastLogin   int64  `db:"principal_identity_last_login"   json:"last_login"`
+}
@@ -59,6 +59,9 @@ type PullReq struct {
 	Author PrincipalInfo  `json:"author"`
 	Merger *PrincipalInfo `json:"merger"`
 	Stats  PullReqStats   `json:"stats"`
+
+	Labels    []*LabelInfo            `json:"labels,omitempty"`
+	Reactions []*PullReqReactionCount `json:"reactions,omitempty"`
 }
 
 // DiffStats shows total number of commits and modified files.
@@ -95,6 +98,7 @@ type PullReqFilter struct {
 	TargetRepoID  int64               `json:"-"`
 	TargetBranch  string              `json:"target_branch"`
 	States        []enum.PullReqState `json:"state"`
+	LabelIDs      []int64             `json:"label_id"`
 	Sort          enum.PullReqSort    `json:"sort"`
 	Order         enum.Order          `json:"order"`
 }
@@ -160,3 +164,32 @@ type MergeViolations struct {
 	ConflictFiles  []string         `json:"conflict_files,omitempty"`
 	RuleViolations []RuleViolations `json:"rule_violations,omitempty"`
 }
+
+// MergeConflicts contains the files that conflict when merging the target branch into the source branch.
+type MergeConflicts struct {
+	SourceSHA    string              `json:"source_sha"`
+	TargetSHA    string              `json:"target_sha"`
+	MergeBaseSHA string              `json:"merge_base_sha"`
+	Files        []MergeConflictFile `json:"files"`
+}
+
+// MergeConflictFile is a single conflicted file. Ours is the version of the source branch,
+// theirs the version of the target branch and base the version of the merge base.
+type MergeConflictFile struct {
+	Path     string                    `json:"path"`
+	Base     *MergeConflictFileVersion `json:"base,omitempty"`
+	Ours     *MergeConflictFileVersion `json:"ours,omitempty"`
+	Theirs   *MergeConflictFileVersion `json:"theirs,omitempty"`
+	IsBinary bool                      `json:"is_binary"`
+	// Content contains the merged file with conflict markers.
+	// It's omitted if the conflict can't be resolved on content level (e.g. binary files, modify/delete).
+	Content *string `json:"content,omitempty"`
+}
+
+// MergeConflictFileVersion is a single version of a conflicted file.
+// Content is omitted for binary files, submodules and files that are too large.
+type MergeConflictFileVersion struct {
+	SHA     string  `json:"sha"`
+	Size    int64   `json:"size"`
+	Content *string `json:"content,omitempty"`
+}
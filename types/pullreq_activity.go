@@ -53,9 +53,9 @@ type PullReqActivity struct {
 	Type enum.PullReqActivityType `json:"type"`
 	Kind enum.PullReqActivityKind `json:"kind"`
 
-	Text       string                 `json:"text"`
-	PayloadRaw json.RawMessage        `json:"payload"`
-	Metadata   map[string]interface{} `json:"metadata"`
+	Text       string                   `json:"text"`
+	PayloadRaw json.RawMessage          `json:"payload"`
+	Metadata   *PullReqActivityMetadata `json:"metadata"`
 
 	ResolvedBy *int64 `json:"-"` // not returned, because the resolver info is in the Resolver field
 	Resolved   *int64 `json:"resolved,omitempty"`
@@ -64,6 +64,10 @@ type PullReqActivity struct {
 	Resolver *PrincipalInfo `json:"resolver,omitempty"`
 
 	CodeComment *CodeCommentFields `json:"code_comment,omitempty"`
+
+	Reactions []*PullReqReactionCount `json:"reactions,omitempty"`
+
+	Mentions map[int64]*PrincipalInfo `json:"mentions,omitempty"`
 }
 
 func (a *PullReqActivity) IsValidCodeComment() bool {
@@ -149,6 +153,28 @@ func (a *PullReqActivity) GetPayload() (PullReqActivityPayload, error) {
 	return payload, nil
 }
 
+// PullReqActivityMetadata contains metadata related to pull request activity.
+type PullReqActivityMetadata struct {
+	Suggestions *PullReqActivitySuggestionsMetadata `json:"suggestions,omitempty"`
+	Mentions    *PullReqActivityMentionsMetadata    `json:"mentions,omitempty"`
+}
+
+// PullReqActivityMentionsMetadata contains metadata for principals mentioned in the activity text.
+type PullReqActivityMentionsMetadata struct {
+	// IDs contains the IDs of all principals mentioned in the activity text.
+	IDs []int64 `json:"ids,omitempty"`
+}
+
+// PullReqActivitySuggestionsMetadata contains metadata for code comment suggestions.
+type PullReqActivitySuggestionsMetadata struct {
+	// CheckSums contains the check sums of all suggestions found in the comment text.
+	CheckSums []string `json:"check_sums,omitempty"`
+	// AppliedCheckSum is the check sum of the suggestion that was applied (if any).
+	AppliedCheckSum string `json:"applied_check_sum,omitempty"`
+	// AppliedCommitSHA is the SHA of the commit that applied the suggestion (if any).
+	AppliedCommitSHA string `json:"applied_commit_sha,omitempty"`
+}
+
 // PullReqActivityFilter stores pull request activity query parameters.
 type PullReqActivityFilter struct {
 	After  int64 `json:"after"`
@@ -190,6 +216,7 @@ var allPullReqActivityPayloads = func(
 	func() PullReqActivityPayload { return &PullRequestActivityPayloadReviewSubmit{} },
 	func() PullReqActivityPayload { return &PullRequestActivityPayloadBranchUpdate{} },
 	func() PullReqActivityPayload { return &PullRequestActivityPayloadBranchDelete{} },
+	func() PullReqActivityPayload { return &PullRequestActivityPayloadLabel{} },
 })
 
 // newPayloadForActivity returns a new payload instance for the requested activity type.
@@ -275,3 +302,13 @@ type PullRequestActivityPayloadBranchDelete struct {
 func (a *PullRequestActivityPayloadBranchDelete) ActivityType() enum.PullReqActivityType {
 	return enum.PullReqActivityTypeBranchDelete
 }
+
+type PullRequestActivityPayloadLabel struct {
+	Label string                        `json:"label"`
+	Color enum.LabelColor               `json:"color"`
+	Type  enum.PullReqLabelActivityType `json:"type"`
+}
+
+func (a *PullRequestActivityPayloadLabel) ActivityType() enum.PullReqActivityType {
+	return enum.PullReqActivityTypeLabelModify
+}
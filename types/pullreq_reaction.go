@@ -0,0 +1,50 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"github.com/harness/gitness/types/enum"
+)
+
+// PullReqReaction represents an emoji reaction of a principal
+// to a pull request description or to a pull request comment.
+type PullReqReaction struct {
+	PullReqID   int64                `json:"-"`
+	ActivityID  *int64               `json:"activity_id,omitempty"` // nil for reactions to the pull request description
+	PrincipalID int64                `json:"principal_id"`
+	Emoji       enum.PullReqReaction `json:"emoji"`
+	Created     int64                `json:"created"`
+}
+
+// PullReqReactionCount holds the aggregated number of reactions with a particular emoji.
+type PullReqReactionCount struct {
+	Emoji enum.PullReqReaction `json:"emoji"`
+	Count int                  `json:"count"`
+
+	// Reacted is true if the principal that made the request reacted with the emoji.
+	Reacted bool `json:"reacted,omitempty"`
+}
+
+// PullReqReactionsUpdate describes a change of reactions of a pull request description or a comment.
+// It's sent to the UI as a server sent event.
+type PullReqReactionsUpdate struct {
+	PullReqID   int64                   `json:"pullreq_id"`
+	Number      int64                   `json:"number"`
+	ActivityID  *int64                  `json:"activity_id,omitempty"`
+	PrincipalID int64                   `json:"principal_id"`
+	Emoji       enum.PullReqReaction    `json:"emoji"`
+	Added       bool                    `json:"added"`
+	Reactions   []*PullReqReactionCount `json:"reactions"`
+}
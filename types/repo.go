@@ -48,6 +48,9 @@ type Repository struct {
 
 	Importing bool `json:"importing"`
 
+	// Language is the primary language of the default branch.
+	Language string `json:"language"`
+
 	// git urls
 	GitURL string `json:"git_url"`
 }
@@ -59,6 +62,30 @@ type RepositorySizeInfo struct {
 	SizeUpdated int64  `json:"size_updated"`
 }
 
+// RepoLanguageStats holds the language statistics of a repository's default branch.
+type RepoLanguageStats struct {
+	RepoID    int64          `json:"-"`
+	CommitSHA string         `json:"commit_sha"`
+	Languages []LanguageStat `json:"languages"`
+	Updated   int64          `json:"updated"`
+}
+
+// RepoHousekeeping holds the housekeeping state of a repository.
+type RepoHousekeeping struct {
+	RepoID int64 `json:"-"`
+	// Pushes is the number of pushes since the last maintenance.
+	Pushes          int64 `json:"pushes"`
+	LastPush        int64 `json:"last_push"`
+	LastMaintenance int64 `json:"last_maintenance"`
+}
+
+// LanguageStat holds the amount of code written in a single language.
+type LanguageStat struct {
+	Language   string  `json:"language"`
+	Bytes      int64   `json:"bytes"`
+	Percentage float64 `json:"percentage"`
+}
+
 func (r Repository) GetGitUID() string {
 	return r.GitUID
 }
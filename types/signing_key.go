@@ -0,0 +1,45 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/harness/gitness/types/enum"
+
+// SigningKey is a public GPG or SSH key of a principal used to verify the signatures of commits and tags.
+type SigningKey struct {
+	ID          int64               `db:"signing_key_id"           json:"id"`
+	PrincipalID int64               `db:"signing_key_principal_id" json:"-"`
+	Type        enum.SigningKeyType `db:"signing_key_type"         json:"type"`
+	Identifier  string              `db:"signing_key_identifier"   json:"identifier"`
+	// Fingerprint is the fingerprint of the (primary) key, it's unique across all principals.
+	Fingerprint string `db:"signing_key_fingerprint" json:"fingerprint"`
+	Content     string `db:"signing_key_content"     json:"content"`
+	Created     int64  `db:"signing_key_created"     json:"created"`
+}
+
+// CommitVerification contains the result of verifying the signature of a commit or tag.
+type CommitVerification struct {
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason"`
+	// KeyID is the id of the key that signed the object (gpg key id or ssh key fingerprint).
+	KeyID string `json:"key_id,omitempty"`
+	// Signer is the principal owning the key that verified the signature.
+	Signer *PrincipalInfo `json:"signer,omitempty"`
+}
+
+// CommitSigningKey is the public key used by the server to sign the commits it creates.
+type CommitSigningKey struct {
+	Type      enum.SigningKeyType `json:"type"`
+	PublicKey string              `json:"public_key"`
+}
@@ -0,0 +1,36 @@
+// Copyright 2023 Harness, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// SpaceEnvVar is an environment variable defined in a space.
+// It's available to all pipelines of the space and its sub spaces,
+// with variables of sub spaces overriding the variables of their ancestors.
+type SpaceEnvVar struct {
+	ID      int64  `db:"space_env_var_id"         json:"-"`
+	SpaceID int64  `db:"space_env_var_space_id"   json:"space_id"`
+	Key     string `db:"space_env_var_key"        json:"key"`
+	Value   string `db:"space_env_var_value"      json:"value,omitempty"`
+	// SecretUID is the UID of the secret of the space holding the value of the variable.
+	// The values of secret-backed variables are masked in the pipeline logs.
+	SecretUID string `db:"space_env_var_secret_uid" json:"secret_uid,omitempty"`
+	CreatedBy int64  `db:"space_env_var_created_by" json:"created_by"`
+	Created   int64  `db:"space_env_var_created"    json:"created"`
+	Updated   int64  `db:"space_env_var_updated"    json:"updated"`
+}
+
+type SpaceEnvVarFilter struct {
+	// Inherited includes the variables of all parent spaces (overridden variables are excluded).
+	Inherited bool `json:"inherited"`
+}